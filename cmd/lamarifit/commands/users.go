@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"fmt"
+	"lamari-fit-api/config"
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"os"
+	"time"
+)
+
+func HandleUsersCommand(args []string) {
+	if len(args) == 0 {
+		printUsersUsage()
+		os.Exit(1)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf("Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	db, err := database.Connect(cfg)
+	if err != nil {
+		fmt.Printf("Error connecting to database: %v\n", err)
+		os.Exit(1)
+	}
+
+	subCommand := args[0]
+
+	switch subCommand {
+	case "purge-deleted":
+		now := time.Now()
+
+		if len(args) > 1 && args[1] == "--dry-run" {
+			var count int64
+			err = db.Model(&models.User{}).
+				Where("deletion_scheduled_at <= ? AND purged_at IS NULL", now).
+				Count(&count).Error
+			if err != nil {
+				fmt.Printf("Error counting accounts: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("%d account(s) past their deletion grace period would be purged\n", count)
+			return
+		}
+
+		purged, err := database.PurgeDeletedAccounts(db, now)
+		if err != nil {
+			fmt.Printf("Error purging accounts (%d purged before the error): %v\n", purged, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Purged %d deleted account(s)\n", purged)
+
+	default:
+		fmt.Printf("Unknown users command: %s\n", subCommand)
+		printUsersUsage()
+		os.Exit(1)
+	}
+}
+
+func printUsersUsage() {
+	fmt.Println(`Users Commands:
+  lamarifit users purge-deleted            Purge accounts whose deletion grace period has ended
+  lamarifit users purge-deleted --dry-run  Count the accounts that would be purged`)
+}
@@ -17,6 +17,8 @@ func main() {
 	switch command {
 	case "db":
 		commands.HandleDatabaseCommand(os.Args[2:])
+	case "users":
+		commands.HandleUsersCommand(os.Args[2:])
 	case "help", "-h", "--help":
 		printUsage()
 	case "version", "-v", "--version":
@@ -36,6 +38,7 @@ Usage:
 
 Available Commands:
   db        Database operations (seed, reset, migrate, etc.)
+  users     User account maintenance (purge deleted accounts)
   help      Show this help message
   version   Show version information
 
@@ -47,6 +50,10 @@ Database Commands:
   lamarifit db migrate:fresh     Drop all tables and re-run migrations
   lamarifit db migrate:rollback  Rollback last migration batch
 
+Users Commands:
+  lamarifit users purge-deleted  Purge accounts whose deletion grace period has ended
+                                 (run daily, e.g. from cron)
+
 Examples:
   lamarifit db seed              # Run all seeders
   lamarifit db reset             # Reset database and seed
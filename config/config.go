@@ -35,6 +35,8 @@ type Config struct {
 	SMTPFromEmail string
 	SMTPFromName  string
 	AppURL        string
+	// Account deletion
+	AccountDeletionGracePeriod string // How long a deleted account can be restored before its data is purged
 }
 
 var AppConfig *Config
@@ -73,6 +75,8 @@ func LoadConfig() {
 		SMTPFromEmail: getEnv("SMTP_FROM_EMAIL", "noreply@lamarifit.com"),
 		SMTPFromName:  getEnv("SMTP_FROM_NAME", "LamariFit"),
 		AppURL:        getEnv("APP_URL", "http://localhost:3000"),
+		// Account deletion
+		AccountDeletionGracePeriod: getEnv("ACCOUNT_DELETION_GRACE_PERIOD", "720h"),
 	}
 }
 
@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"lamari-fit-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DeleteAccount schedules the authenticated user's account for deletion. The account is locked
+// straight away: every refresh token is revoked, trainer-client links are ended and pending
+// invitations cancelled. Logging in again during the grace period cancels the deletion; after it,
+// `lamarifit users purge-deleted` purges the personal data and anonymises the comments and reviews
+// the user left for others.
+func DeleteAccount(c *gin.Context) {
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	// The body is optional for social logins, so an empty body (io.EOF) is not an error. The
+	// content length can't be used to detect one, as it is -1 for chunked requests.
+	var req models.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, "id = ?", authUserID).Error; err != nil {
+		utils.NotFoundResponse(c, "User not found.")
+		return
+	}
+
+	// Accounts with a password confirm it; social logins are confirmed by their access token
+	if user.Provider == "local" || user.Provider == "" {
+		if req.Password == "" {
+			utils.ValidationErrorResponse(c, utils.ValidationErrors{"password": {"Password is required to delete your account"}})
+			return
+		}
+		if !utils.CheckPasswordHash(req.Password, user.Password) {
+			utils.ValidationErrorResponse(c, utils.ValidationErrors{"password": {"Password is incorrect"}})
+			return
+		}
+	}
+
+	now := time.Now()
+	scheduledAt := now.Add(utils.GetAccountDeletionGracePeriod())
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"deletion_requested_at": now,
+			"deletion_scheduled_at": scheduledAt,
+		}).Error; err != nil {
+			return err
+		}
+		return lockAccountForDeletion(tx, user.ID, now)
+	})
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to delete account.")
+		return
+	}
+
+	utils.SuccessResponse(c, "Account scheduled for deletion.", models.AccountDeletionResponse{
+		DeletionScheduledAt: scheduledAt,
+	})
+}
+
+// lockAccountForDeletion signs the user out everywhere and ends their relationships with
+// trainers and clients
+func lockAccountForDeletion(tx *gorm.DB, userID uuid.UUID, now time.Time) error {
+	if err := tx.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(&models.TrainerClientLink{}).
+		Where("(trainer_id = ? OR client_id = ?) AND status IN ?", userID, userID, []string{"pending", "active"}).
+		Update("status", "inactive").Error; err != nil {
+		return err
+	}
+
+	return tx.Where("trainer_id = ? AND status = ?", userID, models.InvitationStatusPending).
+		Delete(&models.TrainerInvitation{}).Error
+}
+
+// cancelAccountDeletion restores an account scheduled for deletion when its owner logs in during
+// the grace period. Ended trainer-client links are not restored.
+func cancelAccountDeletion(user *models.User) error {
+	if !user.IsPendingDeletion() {
+		return nil
+	}
+	if err := database.DB.Model(user).Updates(map[string]interface{}{
+		"deletion_requested_at": nil,
+		"deletion_scheduled_at": nil,
+	}).Error; err != nil {
+		return err
+	}
+	user.DeletionRequestedAt = nil
+	user.DeletionScheduledAt = nil
+	return nil
+}
+
+// excludePendingDeletion hides accounts scheduled for deletion from discovery. column is the
+// column holding the user ID.
+func excludePendingDeletion(column string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(column+" NOT IN (?)",
+			database.DB.Model(&models.User{}).Select("id").Where("deletion_scheduled_at IS NOT NULL"))
+	}
+}
@@ -0,0 +1,520 @@
+package controllers
+
+import (
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"lamari-fit-api/utils"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// completedSetRow is a flattened view of a completed set joined with its session
+type completedSetRow struct {
+	SessionID             uuid.UUID
+	StartedAt             time.Time
+	ExerciseID            uuid.UUID
+	ActualReps            *int
+	ActualWeightKg        *float64
+	ActualDurationSeconds *int
+	RPE                   *int
+}
+
+// completedSetsQuery builds a query over a user's completed sets, joined through
+// exercises, blocks and sessions, optionally limited to a [from, to] date range
+func completedSetsQuery(userID uuid.UUID, from, to *time.Time) *gorm.DB {
+	query := database.DB.Table("session_sets").
+		Select(`workout_sessions.id AS session_id,
+			workout_sessions.started_at,
+			session_exercises.exercise_id,
+			session_sets.actual_reps,
+			session_sets.actual_weight_kg,
+			session_sets.actual_duration_seconds,
+			rpe_scale_values.value AS rpe`).
+		Joins("JOIN session_exercises ON session_exercises.id = session_sets.session_exercise_id AND session_exercises.deleted_at IS NULL").
+		Joins("JOIN session_blocks ON session_blocks.id = session_exercises.session_block_id AND session_blocks.deleted_at IS NULL").
+		Joins("JOIN workout_sessions ON workout_sessions.id = session_blocks.session_id AND workout_sessions.deleted_at IS NULL").
+		Joins("LEFT JOIN rpe_scale_values ON rpe_scale_values.id = session_sets.rpe_value_id").
+		Where("session_sets.deleted_at IS NULL AND session_sets.completed = ? AND workout_sessions.user_id = ?", true, userID)
+
+	if from != nil {
+		query = query.Where("workout_sessions.started_at >= ?", *from)
+	}
+	if to != nil {
+		// Add one day to include the entire end date
+		query = query.Where("workout_sessions.started_at < ?", to.AddDate(0, 0, 1))
+	}
+
+	return query
+}
+
+// parseDateRange parses the optional from/to query parameters
+func parseDateRange(c *gin.Context) (*time.Time, *time.Time, bool) {
+	from, ok := utils.ParseDateQuery(c, "from")
+	if !ok {
+		return nil, nil, false
+	}
+	to, ok := utils.ParseDateQuery(c, "to")
+	if !ok {
+		return nil, nil, false
+	}
+	if from != nil && to != nil && to.Before(*from) {
+		utils.ValidationErrorResponse(c, utils.ValidationErrors{
+			"to": []string{"to must not be before from"},
+		})
+		return nil, nil, false
+	}
+	return from, to, true
+}
+
+// maxAnalyticsRangeDays is the longest date range an aggregated analytics endpoint covers
+const maxAnalyticsRangeDays = 366
+
+// validateDateRangeLength rejects from/to ranges longer than maxAnalyticsRangeDays.
+// Automatically sends a validation error response.
+func validateDateRangeLength(c *gin.Context, from time.Time, to time.Time) bool {
+	if to.Sub(from) >= maxAnalyticsRangeDays*24*time.Hour {
+		utils.ValidationErrorResponse(c, utils.ValidationErrors{
+			"to": []string{"Date range cannot exceed 366 days"},
+		})
+		return false
+	}
+	return true
+}
+
+// GetExerciseHistory aggregates the authenticated user's completed sets for an exercise
+// Query params:
+//   - group_by: day|week|month (default day)
+//   - from, to: optional YYYY-MM-DD date range (inclusive)
+//   - formula: 1RM estimation formula (epley|brzycki, default epley)
+func GetExerciseHistory(c *gin.Context) {
+	var params IDParam
+	if err := c.ShouldBindUri(&params); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	exerciseID, ok := utils.ParseUUID(c, params.ID, "exercise")
+	if !ok {
+		return
+	}
+
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	groupBy := c.DefaultQuery("group_by", utils.GroupByDay)
+	if !utils.ValidGroupings[groupBy] {
+		utils.ValidationErrorResponse(c, utils.ValidationErrors{
+			"group_by": []string{"group_by must be one of: day, week, month"},
+		})
+		return
+	}
+
+	formula := c.DefaultQuery("formula", utils.OneRepMaxFormulaEpley)
+	if !utils.ValidOneRepMaxFormulas[formula] {
+		utils.ValidationErrorResponse(c, utils.ValidationErrors{
+			"formula": []string{"Formula must be one of: epley, brzycki"},
+		})
+		return
+	}
+
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+
+	var exercise models.Exercise
+	if err := database.DB.First(&exercise, "id = ?", exerciseID).Error; err != nil {
+		utils.NotFoundResponse(c, "Exercise not found")
+		return
+	}
+
+	var rows []completedSetRow
+	if err := completedSetsQuery(authUserID, from, to).
+		Where("session_exercises.exercise_id = ?", exerciseID).
+		Order("workout_sessions.started_at ASC").
+		Scan(&rows).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve exercise history")
+		return
+	}
+
+	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+
+	response := models.ExerciseHistoryResponse{
+		ExerciseID:   exercise.ID,
+		ExerciseName: exercise.Name,
+		GroupBy:      groupBy,
+		Formula:      formula,
+		History:      buildExerciseHistory(rows, groupBy, formula, preferredWeightUnit),
+	}
+	if from != nil {
+		value := from.Format(utils.DateFormat)
+		response.From = &value
+	}
+	if to != nil {
+		value := to.Format(utils.DateFormat)
+		response.To = &value
+	}
+
+	utils.SuccessResponse(c, "Exercise history retrieved successfully", response)
+}
+
+// exerciseHistoryAccumulator collects per-bucket totals before conversion to a response
+type exerciseHistoryAccumulator struct {
+	start       time.Time
+	sessions    map[uuid.UUID]bool
+	sets        int
+	totalReps   int
+	volumeKg    float64
+	hasVolume   bool
+	topWeightKg *float64
+	topReps     *int
+	best1RMKg   float64
+	rpeSum      int
+	rpeCount    int
+	longestHold *int
+}
+
+// add folds a single completed set into the bucket
+func (a *exerciseHistoryAccumulator) add(row completedSetRow, formula string) {
+	a.sessions[row.SessionID] = true
+	a.sets++
+
+	reps := 0
+	if row.ActualReps != nil {
+		reps = *row.ActualReps
+		a.totalReps += reps
+	}
+
+	if row.ActualWeightKg != nil && reps > 0 {
+		a.volumeKg += *row.ActualWeightKg * float64(reps)
+		a.hasVolume = true
+
+		if estimate := utils.EstimateOneRepMax(*row.ActualWeightKg, reps, formula); estimate > a.best1RMKg {
+			a.best1RMKg = estimate
+		}
+	}
+
+	// Top set: heaviest weight, ties broken by reps. Sets without weight only
+	// compete on reps (bodyweight exercises).
+	if isBetterTopSet(row.ActualWeightKg, row.ActualReps, a.topWeightKg, a.topReps) {
+		a.topWeightKg = row.ActualWeightKg
+		a.topReps = row.ActualReps
+	}
+
+	if row.RPE != nil {
+		a.rpeSum += *row.RPE
+		a.rpeCount++
+	}
+
+	if row.ActualDurationSeconds != nil && (a.longestHold == nil || *row.ActualDurationSeconds > *a.longestHold) {
+		a.longestHold = row.ActualDurationSeconds
+	}
+}
+
+// isBetterTopSet compares a candidate set against the current top set
+func isBetterTopSet(weightKg *float64, reps *int, topWeightKg *float64, topReps *int) bool {
+	if weightKg == nil && reps == nil {
+		return false
+	}
+	if topWeightKg == nil && topReps == nil {
+		return true
+	}
+
+	candidateWeight, currentWeight := 0.0, 0.0
+	if weightKg != nil {
+		candidateWeight = *weightKg
+	}
+	if topWeightKg != nil {
+		currentWeight = *topWeightKg
+	}
+	if candidateWeight != currentWeight {
+		return candidateWeight > currentWeight
+	}
+
+	candidateReps, currentReps := 0, 0
+	if reps != nil {
+		candidateReps = *reps
+	}
+	if topReps != nil {
+		currentReps = *topReps
+	}
+	return candidateReps > currentReps
+}
+
+// toResponse converts the accumulated totals to a response in the preferred unit
+func (a *exerciseHistoryAccumulator) toResponse(preferredWeightUnit string) models.ExerciseHistoryBucket {
+	bucket := models.ExerciseHistoryBucket{
+		PeriodStart:        a.start.Format(utils.DateFormat),
+		Sessions:           len(a.sessions),
+		Sets:               a.sets,
+		TotalReps:          a.totalReps,
+		LongestHoldSeconds: a.longestHold,
+	}
+
+	if a.topWeightKg != nil || a.topReps != nil {
+		bucket.TopSet = &models.TopSetResponse{
+			Weight: utils.ConvertWeightForResponse(a.topWeightKg, preferredWeightUnit),
+			Reps:   a.topReps,
+		}
+	}
+
+	if a.hasVolume {
+		volume := a.volumeKg
+		bucket.TotalVolume = utils.ConvertWeightForResponse(&volume, preferredWeightUnit)
+	}
+
+	if a.best1RMKg > 0 {
+		best := a.best1RMKg
+		bucket.EstimatedOneRepMax = utils.ConvertWeightForResponse(&best, preferredWeightUnit)
+	}
+
+	if a.rpeCount > 0 {
+		average := math.Round(float64(a.rpeSum)/float64(a.rpeCount)*10) / 10
+		bucket.AverageRPE = &average
+	}
+
+	return bucket
+}
+
+// buildExerciseHistory groups completed sets into day/week/month buckets ordered by date
+func buildExerciseHistory(rows []completedSetRow, groupBy string, formula string, preferredWeightUnit string) []models.ExerciseHistoryBucket {
+	buckets := make(map[time.Time]*exerciseHistoryAccumulator)
+	for _, row := range rows {
+		start := utils.BucketStart(row.StartedAt, groupBy)
+		acc, exists := buckets[start]
+		if !exists {
+			acc = &exerciseHistoryAccumulator{
+				start:    start,
+				sessions: make(map[uuid.UUID]bool),
+			}
+			buckets[start] = acc
+		}
+		acc.add(row, formula)
+	}
+
+	starts := make([]time.Time, 0, len(buckets))
+	for start := range buckets {
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+
+	history := make([]models.ExerciseHistoryBucket, len(starts))
+	for i, start := range starts {
+		history[i] = buckets[start].toResponse(preferredWeightUnit)
+	}
+
+	return history
+}
+
+// defaultSecondaryMuscleWeight is how much a set counts towards a secondary muscle group
+const defaultSecondaryMuscleWeight = 0.5
+
+// defaultMuscleVolumeWeeks is the number of ISO weeks returned when no from date is given
+const defaultMuscleVolumeWeeks = 4
+
+// GetMuscleVolume returns the authenticated user's weekly volume per muscle group
+// Query params:
+//   - from, to: optional YYYY-MM-DD date range of up to 366 days (defaults to the last 4 ISO weeks)
+//   - secondary_weight: weight applied to secondary muscles, 0-1 (default 0.5)
+func GetMuscleVolume(c *gin.Context) {
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	respondWithMuscleVolume(c, authUserID, authUserID)
+}
+
+// GetClientMuscleVolume returns weekly muscle group volume for one of the trainer's active clients
+func GetClientMuscleVolume(c *gin.Context) {
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	clientID, ok := utils.ParseUUID(c, c.Param("id"), "client")
+	if !ok {
+		return
+	}
+
+	if !hasClientConsent(authUserID, clientID, models.ConsentScopeSessions) {
+		utils.ForbiddenResponse(c, "Not authorized to view analytics for this user")
+		return
+	}
+
+	respondWithMuscleVolume(c, clientID, authUserID)
+}
+
+// respondWithMuscleVolume aggregates and sends muscle volume for userID,
+// converting tonnage to the viewer's preferred weight unit
+func respondWithMuscleVolume(c *gin.Context, userID uuid.UUID, viewerID uuid.UUID) {
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+
+	secondaryWeight := defaultSecondaryMuscleWeight
+	if value := c.Query("secondary_weight"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			utils.ValidationErrorResponse(c, utils.ValidationErrors{
+				"secondary_weight": []string{"secondary_weight must be a number between 0 and 1"},
+			})
+			return
+		}
+		secondaryWeight = parsed
+	}
+
+	// Default range: the current ISO week plus the previous weeks
+	if to == nil {
+		today := utils.StartOfDay(time.Now())
+		to = &today
+	}
+	if from == nil {
+		start := utils.StartOfISOWeek(*to).AddDate(0, 0, -7*(defaultMuscleVolumeWeeks-1))
+		from = &start
+	}
+	if !validateDateRangeLength(c, *from, *to) {
+		return
+	}
+
+	var rows []completedSetRow
+	if err := completedSetsQuery(userID, from, to).Scan(&rows).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve muscle volume")
+		return
+	}
+
+	linksByExercise, err := muscleGroupLinksForRows(rows)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve muscle volume")
+		return
+	}
+
+	response := models.MuscleVolumeResponse{
+		UserID:          userID,
+		From:            from.Format(utils.DateFormat),
+		To:              to.Format(utils.DateFormat),
+		SecondaryWeight: secondaryWeight,
+		Weeks:           buildMuscleVolumeWeeks(rows, linksByExercise, *from, *to, secondaryWeight, getUserPreferredWeightUnit(c, viewerID)),
+	}
+
+	utils.SuccessResponse(c, "Muscle volume retrieved successfully", response)
+}
+
+// muscleGroupLinksForRows loads the muscle group links of every exercise performed in rows, by exercise
+func muscleGroupLinksForRows(rows []completedSetRow) (map[uuid.UUID][]models.ExerciseMuscleGroup, error) {
+	exerciseIDs := make([]uuid.UUID, 0)
+	seen := make(map[uuid.UUID]bool)
+	for _, row := range rows {
+		if !seen[row.ExerciseID] {
+			seen[row.ExerciseID] = true
+			exerciseIDs = append(exerciseIDs, row.ExerciseID)
+		}
+	}
+
+	linksByExercise := make(map[uuid.UUID][]models.ExerciseMuscleGroup)
+	if len(exerciseIDs) == 0 {
+		return linksByExercise, nil
+	}
+
+	var links []models.ExerciseMuscleGroup
+	if err := database.DB.
+		Preload("MuscleGroup").
+		Where("exercise_id IN ?", exerciseIDs).
+		Find(&links).Error; err != nil {
+		return nil, err
+	}
+
+	for _, link := range links {
+		linksByExercise[link.ExerciseID] = append(linksByExercise[link.ExerciseID], link)
+	}
+	return linksByExercise, nil
+}
+
+// muscleVolumeAccumulator collects weighted totals for one muscle group in one week
+type muscleVolumeAccumulator struct {
+	muscleGroup   models.MuscleGroup
+	sets          float64
+	primarySets   int
+	secondarySets int
+	tonnageKg     float64
+}
+
+// buildMuscleVolumeWeeks distributes completed sets over ISO weeks and muscle groups.
+// Every week in [from, to] is returned, including weeks without training.
+func buildMuscleVolumeWeeks(rows []completedSetRow, linksByExercise map[uuid.UUID][]models.ExerciseMuscleGroup, from, to time.Time, secondaryWeight float64, preferredWeightUnit string) []models.MuscleVolumeWeek {
+	weeks := make(map[time.Time]map[uuid.UUID]*muscleVolumeAccumulator)
+	for _, row := range rows {
+		weekStart := utils.StartOfISOWeek(row.StartedAt)
+		if weeks[weekStart] == nil {
+			weeks[weekStart] = make(map[uuid.UUID]*muscleVolumeAccumulator)
+		}
+
+		tonnage := 0.0
+		if row.ActualWeightKg != nil && row.ActualReps != nil {
+			tonnage = *row.ActualWeightKg * float64(*row.ActualReps)
+		}
+
+		for _, link := range linksByExercise[row.ExerciseID] {
+			acc, exists := weeks[weekStart][link.MuscleGroupID]
+			if !exists {
+				acc = &muscleVolumeAccumulator{muscleGroup: link.MuscleGroup}
+				weeks[weekStart][link.MuscleGroupID] = acc
+			}
+
+			weight := secondaryWeight
+			if link.Primary {
+				weight = 1
+				acc.primarySets++
+			} else {
+				acc.secondarySets++
+			}
+			acc.sets += weight
+			acc.tonnageKg += tonnage * weight
+		}
+	}
+
+	result := make([]models.MuscleVolumeWeek, 0)
+	for weekStart := utils.StartOfISOWeek(from); !weekStart.After(to); weekStart = weekStart.AddDate(0, 0, 7) {
+		isoYear, isoWeek := weekStart.ISOWeek()
+		week := models.MuscleVolumeWeek{
+			WeekStart:    weekStart.Format(utils.DateFormat),
+			ISOYear:      isoYear,
+			ISOWeek:      isoWeek,
+			MuscleGroups: []models.MuscleGroupVolume{},
+		}
+
+		for muscleGroupID, acc := range weeks[weekStart] {
+			tonnage := acc.tonnageKg
+			week.MuscleGroups = append(week.MuscleGroups, models.MuscleGroupVolume{
+				MuscleGroupID: muscleGroupID,
+				Name:          acc.muscleGroup.Name,
+				Category:      acc.muscleGroup.Category,
+				Sets:          math.Round(acc.sets*100) / 100,
+				PrimarySets:   acc.primarySets,
+				SecondarySets: acc.secondarySets,
+				Tonnage:       utils.ConvertWeightForResponse(&tonnage, preferredWeightUnit),
+			})
+		}
+
+		// Most trained muscle groups first
+		sort.Slice(week.MuscleGroups, func(i, j int) bool {
+			if week.MuscleGroups[i].Sets != week.MuscleGroups[j].Sets {
+				return week.MuscleGroups[i].Sets > week.MuscleGroups[j].Sets
+			}
+			return week.MuscleGroups[i].Name < week.MuscleGroups[j].Name
+		})
+
+		result = append(result, week)
+	}
+
+	return result
+}
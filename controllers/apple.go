@@ -91,6 +91,12 @@ func AppleLogin(c *gin.Context) {
 		}
 	}
 
+	// Logging in during the deletion grace period restores the account
+	if err := cancelAccountDeletion(&user); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to restore account.")
+		return
+	}
+
 	if !user.IsActive {
 		utils.UnauthorizedResponse(c, "Account is deactivated.")
 		return
@@ -266,6 +266,12 @@ func Login(c *gin.Context) {
 		return
 	}
 
+	// Logging in during the deletion grace period restores the account
+	if err := cancelAccountDeletion(&user); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to restore account.")
+		return
+	}
+
 	if !user.IsActive {
 		utils.ForbiddenResponse(c, "Your account has been deactivated.")
 		return
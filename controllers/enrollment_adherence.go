@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"lamari-fit-api/utils"
+	"math"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// nominalSlotDates returns the date every workout of the enrollment is due.
+// Calendar enrollments use their fixed dates; rolling enrollments are paced evenly from the start date.
+func nominalSlotDates(enrollment models.PlanEnrollment, count int) []time.Time {
+	if enrollment.ScheduleMode == utils.ScheduleModeCalendar {
+		return calendarSlotDates(enrollment, count)
+	}
+	return utils.RollingScheduleDates(enrollment.StartDate, enrollment.DaysPerWeek, count)
+}
+
+// buildEnrollmentAdherence compares the workouts due by today against the sessions that
+// fulfilled them. A workout is missed once its due date has passed without a linked session;
+// streaks count consecutive completed workouts in schedule order.
+func buildEnrollmentAdherence(enrollment models.PlanEnrollment, slots []planSlot, fulfilled map[int]models.WorkoutSession, today time.Time) models.EnrollmentAdherenceResponse {
+	today = utils.StartOfDay(today)
+
+	response := models.EnrollmentAdherenceResponse{
+		EnrollmentID:  enrollment.ID,
+		PlanID:        enrollment.PlanID,
+		ScheduleMode:  enrollment.ScheduleMode,
+		Status:        enrollment.Status,
+		TotalWorkouts: len(slots),
+		Workouts:      make([]models.ScheduledWorkoutResponse, 0),
+	}
+
+	streak := 0
+	for index, due := range nominalSlotDates(enrollment, len(slots)) {
+		session, done := fulfilled[index]
+		if !done && due.After(today) {
+			continue
+		}
+
+		date := due
+		if done && enrollment.ScheduleMode != utils.ScheduleModeCalendar {
+			// Rolling workouts happen whenever the session was performed
+			date = utils.StartOfDay(session.StartedAt)
+		}
+		workout := buildScheduledWorkout(index, slots[index], date, today, done, session)
+		response.Workouts = append(response.Workouts, workout)
+
+		if !due.After(today) {
+			response.ScheduledWorkouts++
+		}
+
+		switch workout.Status {
+		case scheduleStatusCompleted:
+			response.CompletedWorkouts++
+			streak++
+			if streak > response.LongestStreak {
+				response.LongestStreak = streak
+			}
+		case scheduleStatusMissed:
+			response.MissedWorkouts++
+			streak = 0
+		}
+	}
+
+	response.CurrentStreak = streak
+	response.RemainingWorkouts = response.TotalWorkouts - response.CompletedWorkouts
+	if response.TotalWorkouts > 0 {
+		response.CompletionPercent = roundPercent(response.CompletedWorkouts, response.TotalWorkouts)
+	}
+	if response.ScheduledWorkouts > 0 {
+		response.AdherencePercent = roundPercent(response.CompletedWorkouts, response.ScheduledWorkouts)
+		if response.AdherencePercent > 100 {
+			response.AdherencePercent = 100
+		}
+	}
+
+	return response
+}
+
+// roundPercent returns part as a percentage of total, rounded to one decimal
+func roundPercent(part int, total int) float64 {
+	return math.Round(float64(part)/float64(total)*1000) / 10
+}
+
+// GetEnrollmentAdherence reports scheduled, completed and missed workouts for an enrollment
+func GetEnrollmentAdherence(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	enrollmentID, ok := utils.ParseUUID(c, c.Param("id"), "enrollment")
+	if !ok {
+		return
+	}
+
+	var enrollment models.PlanEnrollment
+	if err := database.DB.Where("id = ? AND user_id = ?", enrollmentID, userID).First(&enrollment).Error; err != nil {
+		utils.NotFoundResponse(c, "Enrollment not found.")
+		return
+	}
+
+	plan, slots, err := loadEnrollmentSlots(database.DB, enrollment)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load workout plan.")
+		return
+	}
+
+	fulfilled, err := fulfilledScheduledWorkouts(database.DB, enrollment.ID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch enrollment sessions.")
+		return
+	}
+
+	response := buildEnrollmentAdherence(enrollment, slots, fulfilled, time.Now())
+	response.PlanTitle = plan.Title
+
+	utils.SuccessResponse(c, "Enrollment adherence fetched successfully.", response)
+}
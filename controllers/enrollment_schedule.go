@@ -0,0 +1,492 @@
+package controllers
+
+import (
+	"errors"
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"lamari-fit-api/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultScheduleDays = 14
+	maxScheduleDays     = 366
+)
+
+// Statuses of a scheduled workout
+const (
+	scheduleStatusCompleted = "completed"
+	scheduleStatusMissed    = "missed"
+	scheduleStatusToday     = "today"
+	scheduleStatusUpcoming  = "upcoming"
+)
+
+// planSlot is one position in the sequence of workouts an enrollment works through
+type planSlot struct {
+	WeekIndex int
+	Workout   models.Workout
+}
+
+// buildPlanSlots expands plan items into the ordered workout sequence of an enrollment.
+// Every template week has daysPerWeek slots that rotate through the week's workouts.
+// Weeks without workouts of their own repeat the closest earlier week, continuing its rotation.
+// Items must be ordered by week_index, created_at.
+func buildPlanSlots(plan models.WorkoutPlan, items []models.WorkoutPlanItem, daysPerWeek int) []planSlot {
+	weeks := plan.TemplateWeeks
+	itemsByWeek := make(map[int][]models.WorkoutPlanItem)
+	for _, item := range items {
+		itemsByWeek[item.WeekIndex] = append(itemsByWeek[item.WeekIndex], item)
+		if item.WeekIndex+1 > weeks {
+			weeks = item.WeekIndex + 1
+		}
+	}
+
+	var slots []planSlot
+	rotation := make(map[int]int)
+	source := -1
+	for week := 0; week < weeks; week++ {
+		if len(itemsByWeek[week]) > 0 {
+			source = week
+		}
+		if source < 0 {
+			continue
+		}
+
+		weekItems := itemsByWeek[source]
+		for day := 0; day < daysPerWeek; day++ {
+			item := weekItems[rotation[source]%len(weekItems)]
+			rotation[source]++
+			slots = append(slots, planSlot{WeekIndex: week, Workout: item.Workout})
+		}
+	}
+
+	return slots
+}
+
+// loadEnrollmentSlots loads the enrollment's plan and expands it into workout slots
+func loadEnrollmentSlots(db *gorm.DB, enrollment models.PlanEnrollment) (models.WorkoutPlan, []planSlot, error) {
+	var plan models.WorkoutPlan
+	if err := db.First(&plan, "id = ?", enrollment.PlanID).Error; err != nil {
+		return plan, nil, err
+	}
+
+	var items []models.WorkoutPlanItem
+	if err := db.Preload("Workout").
+		Where("plan_id = ?", plan.ID).
+		Order("week_index, created_at").
+		Find(&items).Error; err != nil {
+		return plan, nil, err
+	}
+
+	return plan, buildPlanSlots(plan, items, enrollment.DaysPerWeek), nil
+}
+
+// calendarSlotDates returns the fixed date of every slot of a calendar enrollment
+// Enrollments without preferred weekdays are spread evenly from the start date
+func calendarSlotDates(enrollment models.PlanEnrollment, count int) []time.Time {
+	if dates := utils.CalendarScheduleDates(enrollment.StartDate, enrollment.PreferredWeekdays, count); dates != nil {
+		return dates
+	}
+	return utils.RollingScheduleDates(enrollment.StartDate, enrollment.DaysPerWeek, count)
+}
+
+// rollingAnchor returns the date of the next workout of a rolling enrollment:
+// the latest of the start date, today and the day after the last completed workout
+func rollingAnchor(enrollment models.PlanEnrollment, today time.Time) time.Time {
+	anchor := utils.StartOfDay(today)
+	if start := utils.StartOfDay(enrollment.StartDate); start.After(anchor) {
+		anchor = start
+	}
+	if enrollment.LastCompletedAt != nil {
+		if next := utils.StartOfDay(*enrollment.LastCompletedAt).AddDate(0, 0, 1); next.After(anchor) {
+			anchor = next
+		}
+	}
+	return anchor
+}
+
+// fulfilledScheduledWorkouts returns the earliest completed session linked to each
+// scheduled workout of the enrollment, keyed by scheduled index
+func fulfilledScheduledWorkouts(db *gorm.DB, enrollmentID uuid.UUID) (map[int]models.WorkoutSession, error) {
+	var sessions []models.WorkoutSession
+	if err := db.
+		Where("enrollment_id = ? AND completed = ? AND scheduled_index IS NOT NULL", enrollmentID, true).
+		Order("started_at").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	fulfilled := make(map[int]models.WorkoutSession, len(sessions))
+	for _, session := range sessions {
+		if _, exists := fulfilled[*session.ScheduledIndex]; !exists {
+			fulfilled[*session.ScheduledIndex] = session
+		}
+	}
+	return fulfilled, nil
+}
+
+// resolveEnrollmentSchedule assigns a date and status to the enrollment's workouts.
+// Calendar enrollments have fixed dates for every workout, so past workouts are reported
+// as completed when a linked session fulfilled them, or missed otherwise.
+// Rolling enrollments only project the remaining workouts forward from the next available day.
+func resolveEnrollmentSchedule(db *gorm.DB, enrollment models.PlanEnrollment, slots []planSlot, today time.Time) ([]models.ScheduledWorkoutResponse, error) {
+	today = utils.StartOfDay(today)
+
+	fulfilled, err := fulfilledScheduledWorkouts(db, enrollment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	first := 0
+	var dates []time.Time
+	if enrollment.ScheduleMode == utils.ScheduleModeCalendar {
+		dates = calendarSlotDates(enrollment, len(slots))
+	} else {
+		first = enrollment.CurrentIndex
+		if first > len(slots) {
+			first = len(slots)
+		}
+		dates = utils.RollingScheduleDates(rollingAnchor(enrollment, today), enrollment.DaysPerWeek, len(slots)-first)
+	}
+
+	scheduled := make([]models.ScheduledWorkoutResponse, 0, len(dates))
+	for i, date := range dates {
+		index := first + i
+		session, done := fulfilled[index]
+		scheduled = append(scheduled, buildScheduledWorkout(index, slots[index], date, today, done, session))
+	}
+
+	return scheduled, nil
+}
+
+// buildScheduledWorkout builds the response for one scheduled workout, reporting it as
+// completed when fulfilled by the given session, or by its date relative to today otherwise
+func buildScheduledWorkout(index int, slot planSlot, date time.Time, today time.Time, done bool, session models.WorkoutSession) models.ScheduledWorkoutResponse {
+	response := models.ScheduledWorkoutResponse{
+		Index:        index,
+		Date:         date.Format(utils.DateFormat),
+		WeekIndex:    slot.WeekIndex,
+		WorkoutID:    slot.Workout.ID,
+		WorkoutTitle: slot.Workout.Title,
+		Status:       scheduleStatusUpcoming,
+	}
+
+	switch {
+	case done:
+		response.Status = scheduleStatusCompleted
+		response.SessionID = &session.ID
+	case date.Before(today):
+		response.Status = scheduleStatusMissed
+	case date.Equal(today):
+		response.Status = scheduleStatusToday
+	}
+
+	return response
+}
+
+// filterScheduleByDate keeps the scheduled workouts falling within [from, to]
+func filterScheduleByDate(scheduled []models.ScheduledWorkoutResponse, from, to time.Time) []models.ScheduledWorkoutResponse {
+	fromDate := from.Format(utils.DateFormat)
+	toDate := to.Format(utils.DateFormat)
+
+	filtered := make([]models.ScheduledWorkoutResponse, 0)
+	for _, workout := range scheduled {
+		if workout.Date >= fromDate && workout.Date <= toDate {
+			filtered = append(filtered, workout)
+		}
+	}
+	return filtered
+}
+
+// GetEnrollmentSchedule returns the dated workouts of an enrollment
+// Query params:
+//   - from, to: optional YYYY-MM-DD date range (inclusive, defaults to the next 14 days)
+func GetEnrollmentSchedule(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	enrollmentID, ok := utils.ParseUUID(c, c.Param("id"), "enrollment")
+	if !ok {
+		return
+	}
+
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+
+	today := utils.StartOfDay(time.Now())
+	if from == nil {
+		if to != nil && to.Before(today) {
+			start := to.AddDate(0, 0, -(defaultScheduleDays - 1))
+			from = &start
+		} else {
+			from = &today
+		}
+	}
+	if to == nil {
+		end := from.AddDate(0, 0, defaultScheduleDays-1)
+		to = &end
+	}
+	if to.Sub(*from) >= maxScheduleDays*24*time.Hour {
+		utils.ValidationErrorResponse(c, utils.ValidationErrors{
+			"to": []string{"Date range cannot exceed 366 days."},
+		})
+		return
+	}
+
+	var enrollment models.PlanEnrollment
+	if err := database.DB.Where("id = ? AND user_id = ?", enrollmentID, userID).First(&enrollment).Error; err != nil {
+		utils.NotFoundResponse(c, "Enrollment not found.")
+		return
+	}
+
+	plan, slots, err := loadEnrollmentSlots(database.DB, enrollment)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load workout plan.")
+		return
+	}
+
+	scheduled, err := resolveEnrollmentSchedule(database.DB, enrollment, slots, today)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to build enrollment schedule.")
+		return
+	}
+
+	response := models.EnrollmentScheduleResponse{
+		EnrollmentID:  enrollment.ID,
+		PlanID:        plan.ID,
+		PlanTitle:     plan.Title,
+		ScheduleMode:  enrollment.ScheduleMode,
+		CurrentIndex:  enrollment.CurrentIndex,
+		TotalWorkouts: len(slots),
+		From:          from.Format(utils.DateFormat),
+		To:            to.Format(utils.DateFormat),
+		Workouts:      filterScheduleByDate(scheduled, *from, *to),
+	}
+
+	utils.SuccessResponse(c, "Enrollment schedule fetched successfully.", response)
+}
+
+// GetTodayWorkouts returns the workouts scheduled for today across the user's active enrollments
+func GetTodayWorkouts(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	var enrollments []models.PlanEnrollment
+	if err := database.DB.
+		Where("user_id = ? AND status = ?", userID, "active").
+		Order("created_at").
+		Find(&enrollments).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch enrollments.")
+		return
+	}
+
+	today := utils.StartOfDay(time.Now())
+	response := models.TodayResponse{
+		Date:     today.Format(utils.DateFormat),
+		Workouts: make([]models.TodayWorkoutResponse, 0),
+	}
+
+	for _, enrollment := range enrollments {
+		// An enrollment whose plan has been deleted has nothing scheduled
+		plan, slots, err := loadEnrollmentSlots(database.DB, enrollment)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to load workout plan.")
+			return
+		}
+
+		scheduled, err := resolveEnrollmentSchedule(database.DB, enrollment, slots, today)
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to build enrollment schedule.")
+			return
+		}
+		for _, workout := range filterScheduleByDate(scheduled, today, today) {
+			response.Workouts = append(response.Workouts, models.TodayWorkoutResponse{
+				EnrollmentID:             enrollment.ID,
+				PlanID:                   plan.ID,
+				PlanTitle:                plan.Title,
+				ScheduledWorkoutResponse: workout,
+			})
+		}
+	}
+
+	utils.SuccessResponse(c, "Today's workouts fetched successfully.", response)
+}
+
+// advanceEnrollmentsForSession records the scheduled workout a completed session fulfilled
+// and moves its enrollment past it. Sessions linked to an enrollment advance that enrollment;
+// unlinked sessions are matched against the user's active enrollments and linked to the first
+// one whose schedule they fit.
+func advanceEnrollmentsForSession(tx *gorm.DB, session models.WorkoutSession) error {
+	completedAt := time.Now()
+	if session.EndedAt != nil {
+		completedAt = *session.EndedAt
+	}
+
+	if session.EnrollmentID != nil {
+		if session.ScheduledIndex == nil {
+			return nil
+		}
+
+		var enrollment models.PlanEnrollment
+		if err := tx.Where("id = ? AND status = ?", *session.EnrollmentID, "active").First(&enrollment).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil
+			}
+			return err
+		}
+
+		_, slots, err := loadEnrollmentSlots(tx, enrollment)
+		if err != nil {
+			return err
+		}
+		return completeScheduledWorkout(tx, enrollment, len(slots), *session.ScheduledIndex, completedAt)
+	}
+
+	if session.WorkoutID == nil {
+		return nil
+	}
+	workoutID := *session.WorkoutID
+
+	var enrollments []models.PlanEnrollment
+	if err := tx.
+		Where("user_id = ? AND status = ?", session.UserID, "active").
+		Where("plan_id IN (?)", tx.Model(&models.WorkoutPlanItem{}).Select("plan_id").Where("workout_id = ?", workoutID)).
+		Order("created_at").
+		Find(&enrollments).Error; err != nil {
+		return err
+	}
+
+	for _, enrollment := range enrollments {
+		_, slots, err := loadEnrollmentSlots(tx, enrollment)
+		if err != nil {
+			return err
+		}
+
+		index := matchScheduledSlot(enrollment, slots, workoutID, session.StartedAt)
+		if index < 0 {
+			continue
+		}
+
+		if err := tx.Model(&session).Updates(map[string]interface{}{
+			"enrollment_id":   enrollment.ID,
+			"scheduled_index": index,
+		}).Error; err != nil {
+			return err
+		}
+		return completeScheduledWorkout(tx, enrollment, len(slots), index, completedAt)
+	}
+
+	return nil
+}
+
+// completeScheduledWorkout moves the enrollment past the scheduled workout at index,
+// marking the enrollment completed once every workout of the plan has been passed
+func completeScheduledWorkout(tx *gorm.DB, enrollment models.PlanEnrollment, totalWorkouts int, index int, completedAt time.Time) error {
+	next := enrollment.CurrentIndex
+	if index+1 > next {
+		next = index + 1
+	}
+
+	updates := map[string]interface{}{
+		"current_index":     next,
+		"last_completed_at": completedAt,
+	}
+	if next >= totalWorkouts {
+		updates["status"] = "completed"
+	}
+
+	return tx.Model(&enrollment).Updates(updates).Error
+}
+
+// matchScheduledSlot returns the index of the pending slot completed by a session
+// of the given workout, or -1 if the session does not match the schedule.
+// A nil workout ID matches any workout.
+func matchScheduledSlot(enrollment models.PlanEnrollment, slots []planSlot, workoutID uuid.UUID, performedAt time.Time) int {
+	next := enrollment.CurrentIndex
+	if next >= len(slots) {
+		return -1
+	}
+
+	if enrollment.ScheduleMode == utils.ScheduleModeCalendar {
+		dates := calendarSlotDates(enrollment, len(slots))
+		day := utils.StartOfDay(performedAt)
+		match := -1
+		for i := next; i < len(slots) && !dates[i].After(day); i++ {
+			if workoutID == uuid.Nil || slots[i].Workout.ID == workoutID {
+				match = i
+			}
+		}
+		if match >= 0 {
+			return match
+		}
+	}
+
+	if workoutID == uuid.Nil || slots[next].Workout.ID == workoutID {
+		return next
+	}
+	return -1
+}
+
+// resolveSessionEnrollment validates the enrollment a new session fulfils and resolves
+// which scheduled workout it performs, returning the workout ID and scheduled index.
+// Automatically sends an error response if the enrollment or scheduled workout is invalid.
+func resolveSessionEnrollment(c *gin.Context, userID uuid.UUID, req models.CreateWorkoutSessionRequest, startedAt time.Time) (uuid.UUID, int, bool) {
+	var enrollment models.PlanEnrollment
+	if err := database.DB.Where("id = ? AND user_id = ?", *req.EnrollmentID, userID).First(&enrollment).Error; err != nil {
+		utils.NotFoundResponse(c, "Enrollment not found")
+		return uuid.Nil, 0, false
+	}
+
+	if enrollment.Status != "active" {
+		utils.BadRequestResponse(c, "Enrollment is not active", nil)
+		return uuid.Nil, 0, false
+	}
+
+	_, slots, err := loadEnrollmentSlots(database.DB, enrollment)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load workout plan")
+		return uuid.Nil, 0, false
+	}
+
+	var index int
+	if req.ScheduledIndex != nil {
+		index = *req.ScheduledIndex
+		if index >= len(slots) {
+			utils.ValidationErrorResponse(c, utils.ValidationErrors{
+				"scheduled_index": []string{"scheduled_index is outside the enrollment's schedule"},
+			})
+			return uuid.Nil, 0, false
+		}
+	} else {
+		workoutID := uuid.Nil
+		if req.WorkoutID != nil {
+			workoutID = *req.WorkoutID
+		}
+		index = matchScheduledSlot(enrollment, slots, workoutID, startedAt)
+		if index < 0 {
+			utils.BadRequestResponse(c, "Workout is not scheduled next in this enrollment", nil)
+			return uuid.Nil, 0, false
+		}
+	}
+
+	workoutID := slots[index].Workout.ID
+	if req.WorkoutID != nil && *req.WorkoutID != workoutID {
+		utils.BadRequestResponse(c, "Workout does not match the scheduled workout", nil)
+		return uuid.Nil, 0, false
+	}
+
+	return workoutID, index, true
+}
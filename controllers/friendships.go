@@ -219,16 +219,20 @@ func RemoveFriend(c *gin.Context) {
 		return
 	}
 
-	result := database.DB.Where("id = ? AND (user_id = ? OR friend_id = ?)",
-		friendshipID, userID, userID).Delete(&models.Friendship{})
+	var friendship models.Friendship
+	if err := database.DB.Where("id = ? AND (user_id = ? OR friend_id = ?)",
+		friendshipID, userID, userID).First(&friendship).Error; err != nil {
+		utils.NotFoundResponse(c, "Friendship not found.")
+		return
+	}
 
-	if result.Error != nil {
+	if err := database.DB.Delete(&friendship).Error; err != nil {
 		utils.InternalServerErrorResponse(c, "Failed to remove friend.")
 		return
 	}
 
-	if result.RowsAffected == 0 {
-		utils.NotFoundResponse(c, "Friendship not found.")
+	if err := revokeWorkoutSharesBetween(friendship.UserID, friendship.FriendID); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to remove shared workouts.")
 		return
 	}
 
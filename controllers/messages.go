@@ -0,0 +1,613 @@
+package controllers
+
+import (
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"lamari-fit-api/utils"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// defaultMessagePollTimeout is how long a message poll waits for new messages unless the client
+// asks for a different timeout
+const defaultMessagePollTimeout = 25 * time.Second
+
+// StartConversation opens the conversation with a trainer, client or friend, or returns the
+// existing one
+func StartConversation(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.StartConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	otherID, ok := utils.ParseUUID(c, req.UserID, "user")
+	if !ok {
+		return
+	}
+
+	if otherID == userID {
+		utils.BadRequestResponse(c, "You cannot message yourself", nil)
+		return
+	}
+
+	var other models.User
+	if err := database.DB.First(&other, "id = ?", otherID).Error; err != nil {
+		utils.NotFoundResponse(c, "User not found")
+		return
+	}
+
+	if !canMessage(userID, otherID) {
+		utils.ForbiddenResponse(c, "You can only message your trainers, clients and friends")
+		return
+	}
+
+	// Both users may start the conversation at the same time, so the insert skips a conversation
+	// that already exists and the conversation is then read back
+	userOneID, userTwoID := conversationUserIDs(userID, otherID)
+	result := database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_one_id"}, {Name: "user_two_id"}},
+		DoNothing: true,
+	}).Create(&models.Conversation{UserOneID: userOneID, UserTwoID: userTwoID})
+	if result.Error != nil {
+		utils.InternalServerErrorResponse(c, "Failed to start conversation")
+		return
+	}
+	created := result.RowsAffected > 0
+
+	var conversation models.Conversation
+	if err := database.DB.Preload("UserOne").Preload("UserTwo").
+		Where("user_one_id = ? AND user_two_id = ?", userOneID, userTwoID).
+		First(&conversation).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to start conversation")
+		return
+	}
+
+	response, err := conversationResponse(conversation, userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve conversation")
+		return
+	}
+
+	if created {
+		utils.CreatedResponse(c, "Conversation started successfully", response)
+		return
+	}
+	utils.SuccessResponse(c, "Conversation retrieved successfully", response)
+}
+
+// GetConversations lists the user's conversations, most recently active first, with the last
+// message and how many messages the user has not read
+func GetConversations(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	var pagination PaginationQuery
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	SetDefaultPagination(&pagination)
+
+	base := database.DB.Model(&models.Conversation{}).Where("user_one_id = ? OR user_two_id = ?", userID, userID)
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to count conversations")
+		return
+	}
+
+	var conversations []models.Conversation
+	if err := base.Session(&gorm.Session{}).
+		Preload("UserOne").
+		Preload("UserTwo").
+		Offset(pagination.GetOffset()).
+		Limit(pagination.Limit).
+		Order("last_message_at DESC NULLS LAST, created_at DESC").
+		Find(&conversations).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve conversations")
+		return
+	}
+
+	responses, err := conversationResponses(conversations, userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve conversations")
+		return
+	}
+
+	utils.PaginatedResponse(c, "Conversations retrieved successfully", responses, pagination.Page, pagination.Limit, int(total))
+}
+
+// GetConversation retrieves one of the user's conversations
+func GetConversation(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	conversation, ok := findConversationForUser(c, userID)
+	if !ok {
+		return
+	}
+
+	response, err := conversationResponse(*conversation, userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve conversation")
+		return
+	}
+
+	utils.SuccessResponse(c, "Conversation retrieved successfully", response)
+}
+
+// GetMessages lists the messages of a conversation, newest first
+func GetMessages(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	conversation, ok := findConversationForUser(c, userID)
+	if !ok {
+		return
+	}
+
+	var pagination PaginationQuery
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	SetDefaultPagination(&pagination)
+
+	base := database.DB.Model(&models.Message{}).Where("conversation_id = ?", conversation.ID)
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to count messages")
+		return
+	}
+
+	var messages []models.Message
+	if err := messagePreloads(base.Session(&gorm.Session{})).
+		Offset(pagination.GetOffset()).
+		Limit(pagination.Limit).
+		Order("created_at DESC").
+		Find(&messages).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve messages")
+		return
+	}
+
+	utils.PaginatedResponse(c, "Messages retrieved successfully", buildMessageResponses(messages), pagination.Page, pagination.Limit, int(total))
+}
+
+// SendMessage sends a message in a conversation. A workout the sender owns, or a workout session
+// of either participant the sender can view, can be attached; an attached workout is shared with
+// the recipient so they can open it.
+func SendMessage(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	conversation, ok := findConversationForUser(c, userID)
+	if !ok {
+		return
+	}
+
+	var req models.SendMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	validationErrors := utils.ValidationErrors{}
+	if strings.TrimSpace(req.Content) == "" && req.WorkoutID == nil && req.WorkoutSessionID == nil {
+		validationErrors["content"] = []string{"A message needs content or an attachment"}
+	}
+	if req.WorkoutID != nil && req.WorkoutSessionID != nil {
+		validationErrors["workout_id"] = []string{"Attach either a workout or a workout session, not both"}
+	}
+	if len(validationErrors) > 0 {
+		utils.ValidationErrorResponse(c, validationErrors)
+		return
+	}
+
+	recipientID := conversation.OtherUserID(userID)
+	if !canMessage(userID, recipientID) {
+		utils.ForbiddenResponse(c, "You can no longer message this user")
+		return
+	}
+
+	message := models.Message{
+		ConversationID: conversation.ID,
+		SenderID:       userID,
+		Content:        req.Content,
+	}
+
+	if req.WorkoutID != nil {
+		var workout models.Workout
+		if err := database.DB.Where("id = ? AND user_id = ?", *req.WorkoutID, userID).First(&workout).Error; err != nil {
+			utils.NotFoundResponse(c, "Workout not found")
+			return
+		}
+		message.WorkoutID = &workout.ID
+	}
+
+	if req.WorkoutSessionID != nil {
+		var session models.WorkoutSession
+		if err := database.DB.First(&session, "id = ?", *req.WorkoutSessionID).Error; err != nil {
+			utils.NotFoundResponse(c, "Workout session not found")
+			return
+		}
+		isParticipantSession := session.UserID == userID || session.UserID == recipientID
+		if !isParticipantSession || !canViewSession(session, userID) {
+			utils.NotFoundResponse(c, "Workout session not found")
+			return
+		}
+		message.WorkoutSessionID = &session.ID
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&message).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(conversation).Update("last_message_at", message.CreatedAt).Error; err != nil {
+			return err
+		}
+		if message.WorkoutID != nil {
+			return shareWorkoutForViewing(tx, *message.WorkoutID, userID, recipientID)
+		}
+		return nil
+	})
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to send message")
+		return
+	}
+
+	conversationUpdates.notify(conversation.ID)
+
+	messagePreloads(database.DB).First(&message, "id = ?", message.ID)
+
+	utils.CreatedResponse(c, "Message sent successfully", message.ToResponse())
+}
+
+// MarkConversationRead marks the messages the user received in a conversation as read, which the
+// sender sees as read receipts
+func MarkConversationRead(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	conversation, ok := findConversationForUser(c, userID)
+	if !ok {
+		return
+	}
+
+	result := database.DB.Model(&models.Message{}).
+		Where("conversation_id = ? AND sender_id <> ? AND read_at IS NULL", conversation.ID, userID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		utils.InternalServerErrorResponse(c, "Failed to mark conversation as read")
+		return
+	}
+
+	if result.RowsAffected > 0 {
+		conversationUpdates.notify(conversation.ID)
+	}
+
+	utils.SuccessResponse(c, "Conversation marked as read", gin.H{"read_count": result.RowsAffected})
+}
+
+// PollMessages long-polls a conversation: it returns as soon as there are messages sent or read
+// since the given time, or with no messages once the timeout passes. Clients pass the returned
+// polled_at as the since time of their next poll and merge the messages by ID.
+func PollMessages(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	conversation, ok := findConversationForUser(c, userID)
+	if !ok {
+		return
+	}
+
+	var query MessagePollQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	since, err := time.Parse(time.RFC3339Nano, query.Since)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid since time. Use RFC 3339", nil)
+		return
+	}
+
+	timeout := defaultMessagePollTimeout
+	if query.Timeout != nil {
+		timeout = time.Duration(*query.Timeout) * time.Second
+	}
+
+	// Subscribe before looking for messages so none sent in between are missed
+	updates := conversationUpdates.subscribe(conversation.ID)
+	defer conversationUpdates.unsubscribe(conversation.ID, updates)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		// Timestamps are stored to the microsecond; comparing inclusively against the truncated
+		// poll time means a message is at worst returned twice, never missed
+		polledAt := time.Now().UTC().Truncate(time.Microsecond)
+
+		var messages []models.Message
+		if err := messagePreloads(database.DB).
+			Where("conversation_id = ? AND (created_at >= ? OR read_at >= ?)", conversation.ID, since, since).
+			Order("created_at ASC").
+			Find(&messages).Error; err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to retrieve messages")
+			return
+		}
+
+		if len(messages) > 0 {
+			utils.SuccessResponse(c, "Messages retrieved successfully", models.MessagePollResponse{
+				Messages: buildMessageResponses(messages),
+				PolledAt: polledAt,
+			})
+			return
+		}
+
+		select {
+		case <-updates:
+		case <-timer.C:
+			utils.SuccessResponse(c, "No new messages", models.MessagePollResponse{
+				Messages: []models.MessageResponse{},
+				PolledAt: polledAt,
+			})
+			return
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
+
+// GetUnreadMessageCounts returns how many messages the user has not read, in total and per
+// conversation
+func GetUnreadMessageCounts(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	counts, err := loadUnreadMessageCounts(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to count unread messages")
+		return
+	}
+
+	response := models.UnreadMessagesResponse{Conversations: counts}
+	for _, count := range counts {
+		response.Total += count.UnreadCount
+	}
+
+	utils.SuccessResponse(c, "Unread messages retrieved successfully", response)
+}
+
+// canMessage reports whether a user may message another: the same users who may share workouts
+// with each other, so friends and trainers with their active clients
+func canMessage(userID, otherID uuid.UUID) bool {
+	return canShareWorkoutWith(userID, otherID)
+}
+
+// messageableUsers reports which of the other users the user may message, like canMessage, with
+// one query for friendships and one for trainer-client links
+func messageableUsers(userID uuid.UUID, otherIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	messageable := make(map[uuid.UUID]bool, len(otherIDs))
+
+	var friendships []models.Friendship
+	if err := database.DB.
+		Where("((user_id = ? AND friend_id IN ?) OR (friend_id = ? AND user_id IN ?)) AND status = ?",
+			userID, otherIDs, userID, otherIDs, "accepted").
+		Find(&friendships).Error; err != nil {
+		return nil, err
+	}
+	for _, friendship := range friendships {
+		messageable[friendship.UserID] = true
+		messageable[friendship.FriendID] = true
+	}
+
+	var links []models.TrainerClientLink
+	if err := database.DB.
+		Where("((trainer_id = ? AND client_id IN ?) OR (client_id = ? AND trainer_id IN ?)) AND status = ?",
+			userID, otherIDs, userID, otherIDs, "active").
+		Find(&links).Error; err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		messageable[link.TrainerID] = true
+		messageable[link.ClientID] = true
+	}
+
+	delete(messageable, userID)
+	return messageable, nil
+}
+
+// conversationUserIDs orders two user IDs the way a conversation stores them
+func conversationUserIDs(userID, otherID uuid.UUID) (uuid.UUID, uuid.UUID) {
+	if otherID.String() < userID.String() {
+		return otherID, userID
+	}
+	return userID, otherID
+}
+
+// findConversationForUser loads the conversation in the id path parameter if the user takes part
+// in it. Automatically sends an error response and returns false if not.
+func findConversationForUser(c *gin.Context, userID uuid.UUID) (*models.Conversation, bool) {
+	conversationID, ok := utils.ParseUUID(c, c.Param("id"), "conversation")
+	if !ok {
+		return nil, false
+	}
+
+	var conversation models.Conversation
+	if err := database.DB.Preload("UserOne").Preload("UserTwo").
+		Where("id = ? AND (user_one_id = ? OR user_two_id = ?)", conversationID, userID, userID).
+		First(&conversation).Error; err != nil {
+		utils.NotFoundResponse(c, "Conversation not found")
+		return nil, false
+	}
+	return &conversation, true
+}
+
+// conversationResponse converts a conversation to response format as seen by the user, with its
+// last message and the user's unread count
+func conversationResponse(conversation models.Conversation, userID uuid.UUID) (models.ConversationResponse, error) {
+	responses, err := conversationResponses([]models.Conversation{conversation}, userID)
+	if err != nil {
+		return models.ConversationResponse{}, err
+	}
+	return responses[0], nil
+}
+
+// conversationResponses converts conversations to response format as seen by the user. The last
+// messages, unread counts and who the user can still message are each loaded in one query.
+func conversationResponses(conversations []models.Conversation, userID uuid.UUID) ([]models.ConversationResponse, error) {
+	responses := make([]models.ConversationResponse, len(conversations))
+	if len(conversations) == 0 {
+		return responses, nil
+	}
+
+	conversationIDs := make([]uuid.UUID, len(conversations))
+	otherIDs := make([]uuid.UUID, len(conversations))
+	for i, conversation := range conversations {
+		conversationIDs[i] = conversation.ID
+		otherIDs[i] = conversation.OtherUserID(userID)
+	}
+
+	counts, err := loadUnreadMessageCounts(userID)
+	if err != nil {
+		return nil, err
+	}
+	unreadCounts := make(map[uuid.UUID]int, len(counts))
+	for _, count := range counts {
+		unreadCounts[count.ConversationID] = count.UnreadCount
+	}
+
+	var messages []models.Message
+	if err := messagePreloads(database.DB).
+		Where("id IN (?)", database.DB.Model(&models.Message{}).
+			Select("DISTINCT ON (conversation_id) id").
+			Where("conversation_id IN ?", conversationIDs).
+			Order("conversation_id, created_at DESC, id DESC")).
+		Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	lastMessages := make(map[uuid.UUID]*models.Message, len(messages))
+	for i := range messages {
+		lastMessages[messages[i].ConversationID] = &messages[i]
+	}
+
+	messageable, err := messageableUsers(userID, otherIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, conversation := range conversations {
+		responses[i] = conversation.ToResponse(userID, messageable[otherIDs[i]], unreadCounts[conversation.ID], lastMessages[conversation.ID])
+	}
+	return responses, nil
+}
+
+// loadUnreadMessageCounts returns how many messages the user has not read in each conversation
+// that has any
+func loadUnreadMessageCounts(userID uuid.UUID) ([]models.ConversationUnreadCount, error) {
+	counts := []models.ConversationUnreadCount{}
+	err := database.DB.Model(&models.Message{}).
+		Select("messages.conversation_id, COUNT(*) AS unread_count").
+		Joins("JOIN conversations ON conversations.id = messages.conversation_id AND conversations.deleted_at IS NULL").
+		Where("conversations.user_one_id = ? OR conversations.user_two_id = ?", userID, userID).
+		Where("messages.sender_id <> ? AND messages.read_at IS NULL", userID).
+		Group("messages.conversation_id").
+		Order("messages.conversation_id").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// messagePreloads loads the attachments Message.ToResponse needs
+func messagePreloads(db *gorm.DB) *gorm.DB {
+	return db.Preload("Workout").Preload("WorkoutSession.Workout")
+}
+
+// buildMessageResponses converts messages to response format
+func buildMessageResponses(messages []models.Message) []models.MessageResponse {
+	responses := make([]models.MessageResponse, len(messages))
+	for i := range messages {
+		responses[i] = messages[i].ToResponse()
+	}
+	return responses
+}
+
+// conversationNotifier wakes up message polls waiting on a conversation when a message is sent or
+// read in it. It only reaches polls served by the same API instance; polls on other instances
+// pick the messages up when they time out and the client polls again.
+type conversationNotifier struct {
+	mu          sync.Mutex
+	subscribers map[uuid.UUID]map[chan struct{}]struct{}
+}
+
+var conversationUpdates = &conversationNotifier{
+	subscribers: make(map[uuid.UUID]map[chan struct{}]struct{}),
+}
+
+// subscribe returns a channel that receives a value whenever the conversation changes
+func (n *conversationNotifier) subscribe(conversationID uuid.UUID) chan struct{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	ch := make(chan struct{}, 1)
+	if n.subscribers[conversationID] == nil {
+		n.subscribers[conversationID] = make(map[chan struct{}]struct{})
+	}
+	n.subscribers[conversationID][ch] = struct{}{}
+	return ch
+}
+
+// unsubscribe stops sending conversation changes to the channel
+func (n *conversationNotifier) unsubscribe(conversationID uuid.UUID, ch chan struct{}) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	delete(n.subscribers[conversationID], ch)
+	if len(n.subscribers[conversationID]) == 0 {
+		delete(n.subscribers, conversationID)
+	}
+}
+
+// notify wakes up every poll waiting on the conversation. Channels that already hold a pending
+// change are skipped; their poll will look for messages anyway.
+func (n *conversationNotifier) notify(conversationID uuid.UUID) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for ch := range n.subscribers[conversationID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -105,6 +105,12 @@ func GoogleCallback(c *gin.Context) {
 		}
 	}
 
+	// Logging in during the deletion grace period restores the account
+	if err := cancelAccountDeletion(&user); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to restore account.")
+		return
+	}
+
 	if !user.IsActive {
 		utils.UnauthorizedResponse(c, "Account is deactivated.")
 		return
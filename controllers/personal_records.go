@@ -0,0 +1,389 @@
+package controllers
+
+import (
+	"errors"
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"lamari-fit-api/utils"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// personalRecordKeyColumns are the columns of the unique index idx_personal_record_key
+var personalRecordKeyColumns = []clause.Column{{Name: "user_id"}, {Name: "exercise_id"}, {Name: "record_type"}, {Name: "formula"}}
+
+// personalRecordValueColumns are the columns an upsert replaces when a record is beaten
+var personalRecordValueColumns = []string{"value", "weight_kg", "reps", "duration_seconds", "session_id", "session_set_id", "achieved_at", "updated_at"}
+
+// GetUserRecords lists the authenticated user's personal records across all exercises
+// Query params:
+//   - formula: 1RM estimation formula to return (epley|brzycki, default epley)
+//   - type: optional record type filter (max_weight|rep_max|estimated_1rm|longest_hold)
+func GetUserRecords(c *gin.Context) {
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	query, ok := buildPersonalRecordsQuery(c, authUserID)
+	if !ok {
+		return
+	}
+
+	var records []models.PersonalRecord
+	if err := query.Preload("Exercise").Find(&records).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve personal records")
+		return
+	}
+
+	sortPersonalRecords(records)
+
+	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+	utils.SuccessResponse(c, "Personal records retrieved successfully", buildPersonalRecordResponses(records, preferredWeightUnit))
+}
+
+// GetExerciseRecords lists the authenticated user's personal records for a single exercise
+func GetExerciseRecords(c *gin.Context) {
+	var params IDParam
+	if err := c.ShouldBindUri(&params); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	exerciseID, ok := utils.ParseUUID(c, params.ID, "exercise")
+	if !ok {
+		return
+	}
+
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	var exercise models.Exercise
+	if err := database.DB.First(&exercise, "id = ?", exerciseID).Error; err != nil {
+		utils.NotFoundResponse(c, "Exercise not found")
+		return
+	}
+
+	query, ok := buildPersonalRecordsQuery(c, authUserID)
+	if !ok {
+		return
+	}
+
+	var records []models.PersonalRecord
+	if err := query.Where("exercise_id = ?", exerciseID).Preload("Exercise").Find(&records).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve personal records")
+		return
+	}
+
+	sortPersonalRecords(records)
+
+	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+	utils.SuccessResponse(c, "Personal records retrieved successfully", buildPersonalRecordResponses(records, preferredWeightUnit))
+}
+
+// buildPersonalRecordsQuery applies the shared formula/type filters for record listings
+func buildPersonalRecordsQuery(c *gin.Context, userID uuid.UUID) (*gorm.DB, bool) {
+	formula := c.DefaultQuery("formula", utils.OneRepMaxFormulaEpley)
+	if !utils.ValidOneRepMaxFormulas[formula] {
+		utils.ValidationErrorResponse(c, utils.ValidationErrors{
+			"formula": []string{"Formula must be one of: epley, brzycki"},
+		})
+		return nil, false
+	}
+
+	query := database.DB.
+		Where("user_id = ?", userID).
+		Where("record_type <> ? OR formula = ?", models.RecordTypeEstimated1RM, formula)
+
+	if recordType := c.Query("type"); recordType != "" {
+		if !models.ValidPersonalRecordTypes[models.PersonalRecordType(recordType)] {
+			utils.ValidationErrorResponse(c, utils.ValidationErrors{
+				"type": []string{"Type must be one of: max_weight, rep_max, estimated_1rm, longest_hold"},
+			})
+			return nil, false
+		}
+		query = query.Where("record_type = ?", recordType)
+	}
+
+	return query, true
+}
+
+// sortPersonalRecords orders records by exercise name, then by record type
+func sortPersonalRecords(records []models.PersonalRecord) {
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Exercise.Name != records[j].Exercise.Name {
+			return records[i].Exercise.Name < records[j].Exercise.Name
+		}
+		return records[i].RecordType < records[j].RecordType
+	})
+}
+
+// buildPersonalRecordResponse converts a record to a response with weights in the preferred unit
+func buildPersonalRecordResponse(record models.PersonalRecord, preferredWeightUnit string) models.PersonalRecordResponse {
+	response := record.ToResponse()
+	response.Weight = utils.ConvertWeightForResponse(record.WeightKg, preferredWeightUnit)
+	if record.RecordType == models.RecordTypeEstimated1RM {
+		value := record.Value
+		response.EstimatedOneRepMax = utils.ConvertWeightForResponse(&value, preferredWeightUnit)
+	}
+	return response
+}
+
+// buildPersonalRecordResponses converts a list of records to responses
+func buildPersonalRecordResponses(records []models.PersonalRecord, preferredWeightUnit string) []models.PersonalRecordResponse {
+	responses := make([]models.PersonalRecordResponse, len(records))
+	for i, record := range records {
+		responses[i] = buildPersonalRecordResponse(record, preferredWeightUnit)
+	}
+	return responses
+}
+
+// personalRecordCandidates builds the record candidates a completed set could set
+func personalRecordCandidates(userID uuid.UUID, sessionID uuid.UUID, exerciseID uuid.UUID, set models.SessionSet, achievedAt time.Time) []models.PersonalRecord {
+	if !set.Completed {
+		return nil
+	}
+
+	setID := set.ID
+	base := models.PersonalRecord{
+		UserID:          userID,
+		ExerciseID:      exerciseID,
+		WeightKg:        set.ActualWeightKg,
+		Reps:            set.ActualReps,
+		DurationSeconds: set.ActualDurationSeconds,
+		SessionID:       &sessionID,
+		SessionSetID:    &setID,
+		AchievedAt:      achievedAt,
+	}
+
+	var candidates []models.PersonalRecord
+
+	hasWeight := set.ActualWeightKg != nil && *set.ActualWeightKg > 0
+	hasReps := set.ActualReps != nil && *set.ActualReps > 0
+
+	if hasWeight && hasReps {
+		record := base
+		record.RecordType = models.RecordTypeMaxWeight
+		record.Value = *set.ActualWeightKg
+		candidates = append(candidates, record)
+
+		for _, formula := range utils.OneRepMaxFormulas {
+			estimate := utils.EstimateOneRepMax(*set.ActualWeightKg, *set.ActualReps, formula)
+			if estimate <= 0 {
+				continue
+			}
+			record := base
+			record.RecordType = models.RecordTypeEstimated1RM
+			record.Formula = formula
+			record.Value = estimate
+			candidates = append(candidates, record)
+		}
+	}
+
+	if hasReps {
+		record := base
+		record.RecordType = models.RecordTypeRepMax
+		record.Value = float64(*set.ActualReps)
+		candidates = append(candidates, record)
+	}
+
+	if set.ActualDurationSeconds != nil && *set.ActualDurationSeconds > 0 {
+		record := base
+		record.RecordType = models.RecordTypeLongestHold
+		record.Value = float64(*set.ActualDurationSeconds)
+		candidates = append(candidates, record)
+	}
+
+	return candidates
+}
+
+// updatePersonalRecordsForSet compares a completed set against the user's existing records
+// and stores any that it beats. Returns the records that were created or improved.
+func updatePersonalRecordsForSet(tx *gorm.DB, userID uuid.UUID, sessionID uuid.UUID, exerciseID uuid.UUID, set models.SessionSet, achievedAt time.Time) ([]models.PersonalRecord, error) {
+	var newRecords []models.PersonalRecord
+
+	for _, candidate := range personalRecordCandidates(userID, sessionID, exerciseID, set, achievedAt) {
+		var existing models.PersonalRecord
+		err := tx.Where(
+			"user_id = ? AND exercise_id = ? AND record_type = ? AND formula = ?",
+			userID, exerciseID, candidate.RecordType, candidate.Formula,
+		).First(&existing).Error
+
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			// Another request may have stored a record for this key since the lookup, so the
+			// insert only replaces a conflicting record that it beats
+			result := tx.Clauses(clause.OnConflict{
+				Columns:   personalRecordKeyColumns,
+				Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "excluded.value > personal_records.value"}}},
+				DoUpdates: clause.AssignmentColumns(personalRecordValueColumns),
+			}).Create(&candidate)
+			if result.Error != nil {
+				return nil, result.Error
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+			// The upsert may have updated an existing row, which keeps its own ID
+			if err := tx.Where(
+				"user_id = ? AND exercise_id = ? AND record_type = ? AND formula = ?",
+				userID, exerciseID, candidate.RecordType, candidate.Formula,
+			).First(&candidate).Error; err != nil {
+				return nil, err
+			}
+		case err != nil:
+			return nil, err
+		default:
+			if !candidate.BeatsRecord(existing) {
+				continue
+			}
+			candidate.ID = existing.ID
+			candidate.CreatedAt = existing.CreatedAt
+			if err := tx.Save(&candidate).Error; err != nil {
+				return nil, err
+			}
+		}
+
+		newRecords = append(newRecords, candidate)
+	}
+
+	return newRecords, nil
+}
+
+// updatePersonalRecordsForSession checks every completed set in a session for new records
+func updatePersonalRecordsForSession(tx *gorm.DB, session models.WorkoutSession) error {
+	var sets []models.SessionSet
+	if err := tx.
+		Preload("SessionExercise").
+		Joins("JOIN session_exercises ON session_exercises.id = session_sets.session_exercise_id AND session_exercises.deleted_at IS NULL").
+		Joins("JOIN session_blocks ON session_blocks.id = session_exercises.session_block_id AND session_blocks.deleted_at IS NULL").
+		Where("session_blocks.session_id = ? AND session_sets.completed = ?", session.ID, true).
+		Order("session_blocks.block_order ASC, session_exercises.exercise_order ASC, session_sets.set_number ASC").
+		Find(&sets).Error; err != nil {
+		return err
+	}
+
+	achievedAt := session.StartedAt
+	if session.EndedAt != nil {
+		achievedAt = *session.EndedAt
+	}
+
+	for _, set := range sets {
+		if _, err := updatePersonalRecordsForSet(tx, session.UserID, session.ID, set.SessionExercise.ExerciseID, set, achievedAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recomputePersonalRecordsForExercise rebuilds the user's records for an exercise from their
+// completed sets. It is used when a set is edited, uncompleted or deleted, since the set may have
+// held a record that it no longer deserves. Records still held by the same set keep their
+// achieved time; records no set reaches any more are removed.
+func recomputePersonalRecordsForExercise(tx *gorm.DB, userID uuid.UUID, exerciseID uuid.UUID) error {
+	var sets []models.SessionSet
+	if err := tx.
+		Preload("SessionExercise.SessionBlock.Session").
+		Joins("JOIN session_exercises ON session_exercises.id = session_sets.session_exercise_id AND session_exercises.deleted_at IS NULL").
+		Joins("JOIN session_blocks ON session_blocks.id = session_exercises.session_block_id AND session_blocks.deleted_at IS NULL").
+		Joins("JOIN workout_sessions ON workout_sessions.id = session_blocks.session_id AND workout_sessions.deleted_at IS NULL").
+		Where("workout_sessions.user_id = ? AND session_exercises.exercise_id = ? AND session_sets.completed = ?", userID, exerciseID, true).
+		Order("workout_sessions.started_at ASC, session_blocks.block_order ASC, session_exercises.exercise_order ASC, session_sets.set_number ASC").
+		Find(&sets).Error; err != nil {
+		return err
+	}
+
+	// Earlier sets win ties, as they would have when the records were set
+	best := make(map[string]models.PersonalRecord)
+	for _, set := range sets {
+		session := set.SessionExercise.SessionBlock.Session
+		achievedAt := session.StartedAt
+		if session.EndedAt != nil {
+			achievedAt = *session.EndedAt
+		}
+		if set.CompletedAt != nil {
+			achievedAt = *set.CompletedAt
+		}
+
+		for _, candidate := range personalRecordCandidates(userID, session.ID, exerciseID, set, achievedAt) {
+			key := string(candidate.RecordType) + ":" + candidate.Formula
+			if current, ok := best[key]; ok && !candidate.BeatsRecord(current) {
+				continue
+			}
+			best[key] = candidate
+		}
+	}
+
+	var existing []models.PersonalRecord
+	if err := tx.Where("user_id = ? AND exercise_id = ?", userID, exerciseID).Find(&existing).Error; err != nil {
+		return err
+	}
+
+	for _, record := range existing {
+		key := string(record.RecordType) + ":" + record.Formula
+		candidate, ok := best[key]
+		if !ok {
+			if err := tx.Delete(&record).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if record.SessionSetID != nil && candidate.SessionSetID != nil && *record.SessionSetID == *candidate.SessionSetID {
+			candidate.AchievedAt = record.AchievedAt
+			best[key] = candidate
+		}
+	}
+
+	for _, candidate := range best {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   personalRecordKeyColumns,
+			DoUpdates: clause.AssignmentColumns(personalRecordValueColumns),
+		}).Create(&candidate).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// completedSetExerciseIDs returns the distinct exercises of the session exercises matched by the
+// condition that have completed sets, so their records can be recomputed once the sets are removed
+func completedSetExerciseIDs(tx *gorm.DB, condition string, args ...interface{}) ([]uuid.UUID, error) {
+	var exerciseIDs []uuid.UUID
+	err := tx.Model(&models.SessionExercise{}).
+		Joins("JOIN session_sets ON session_sets.session_exercise_id = session_exercises.id AND session_sets.deleted_at IS NULL AND session_sets.completed = ?", true).
+		Where(condition, args...).
+		Distinct().
+		Pluck("session_exercises.exercise_id", &exerciseIDs).Error
+	return exerciseIDs, err
+}
+
+// recomputePersonalRecordsForExercises rebuilds the user's records for each of the exercises
+func recomputePersonalRecordsForExercises(tx *gorm.DB, userID uuid.UUID, exerciseIDs []uuid.UUID) error {
+	for _, exerciseID := range exerciseIDs {
+		if err := recomputePersonalRecordsForExercise(tx, userID, exerciseID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getSessionPersonalRecords returns the records currently held by sets from the given session
+func getSessionPersonalRecords(sessionID uuid.UUID, userID uuid.UUID) []models.PersonalRecord {
+	var records []models.PersonalRecord
+	database.DB.
+		Preload("Exercise").
+		Where("session_id = ? AND user_id = ?", sessionID, userID).
+		Find(&records)
+
+	sortPersonalRecords(records)
+	return records
+}
@@ -39,13 +39,8 @@ func EnrollInPlan(c *gin.Context) {
 		return
 	}
 
-	// Parse start date
-	startDate, err := time.Parse("2006-01-02", req.StartDate)
-	if err != nil {
-		validationErrors := utils.ValidationErrors{
-			"start_date": []string{"Invalid date format. Use YYYY-MM-DD."},
-		}
-		utils.ValidationErrorResponse(c, validationErrors)
+	enrollment, ok := buildPlanEnrollment(c, userID, req)
+	if !ok {
 		return
 	}
 
@@ -63,6 +58,28 @@ func EnrollInPlan(c *gin.Context) {
 		return
 	}
 
+	if err := database.DB.Create(enrollment).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to enroll in workout plan.")
+		return
+	}
+
+	utils.CreatedResponse(c, "Successfully enrolled in workout plan.", enrollment)
+}
+
+// buildPlanEnrollment validates an enrollment's start date and schedule and returns the active
+// enrollment to create for the user.
+// Automatically sends a validation error response if they are invalid.
+func buildPlanEnrollment(c *gin.Context, userID uuid.UUID, req EnrollInPlanRequest) (*models.PlanEnrollment, bool) {
+	// Parse start date
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		validationErrors := utils.ValidationErrors{
+			"start_date": []string{"Invalid date format. Use YYYY-MM-DD."},
+		}
+		utils.ValidationErrorResponse(c, validationErrors)
+		return nil, false
+	}
+
 	// Set default schedule mode if not provided
 	scheduleMode := req.ScheduleMode
 	if scheduleMode == "" {
@@ -75,7 +92,7 @@ func EnrollInPlan(c *gin.Context) {
 			"schedule_mode": []string{"Schedule mode must be 'rolling' or 'calendar'."},
 		}
 		utils.ValidationErrorResponse(c, validationErrors)
-		return
+		return nil, false
 	}
 
 	// Validate preferred weekdays for calendar mode
@@ -84,10 +101,10 @@ func EnrollInPlan(c *gin.Context) {
 			"preferred_weekdays": []string{"Number of preferred weekdays must match days per week for calendar mode."},
 		}
 		utils.ValidationErrorResponse(c, validationErrors)
-		return
+		return nil, false
 	}
 
-	enrollment := models.PlanEnrollment{
+	return &models.PlanEnrollment{
 		PlanID:            req.PlanID,
 		UserID:            userID,
 		StartDate:         startDate,
@@ -96,14 +113,7 @@ func EnrollInPlan(c *gin.Context) {
 		PreferredWeekdays: pq.Int32Array(req.PreferredWeekdays),
 		Status:            "active",
 		CurrentIndex:      0,
-	}
-
-	if err := database.DB.Create(&enrollment).Error; err != nil {
-		utils.InternalServerErrorResponse(c, "Failed to enroll in workout plan.")
-		return
-	}
-
-	utils.CreatedResponse(c, "Successfully enrolled in workout plan.", enrollment)
+	}, true
 }
 
 func GetUserEnrollments(c *gin.Context) {
@@ -0,0 +1,225 @@
+package controllers
+
+import (
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"lamari-fit-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// buildProgressionRule validates a progression rule request and converts it for storage.
+// An empty type clears the rule. Automatically sends a validation error response if the
+// rule is invalid or misses the fields its type requires.
+func buildProgressionRule(c *gin.Context, req *models.ProgressionRuleRequest) (models.ProgressionRule, bool) {
+	var rule models.ProgressionRule
+	if req == nil || req.Type == "" {
+		return rule, true
+	}
+
+	if !models.IsValidProgressionType(req.Type) {
+		utils.ValidationErrorResponse(c, utils.ValidationErrors{
+			"progression.type": []string{"Invalid progression type."},
+		})
+		return rule, false
+	}
+
+	incrementKg, _, _ := utils.ProcessWeightInput(req.Increment)
+	if incrementKg != nil && *incrementKg <= 0 {
+		utils.ValidationErrorResponse(c, utils.ValidationErrors{
+			"progression.increment": []string{"Increment must be greater than 0."},
+		})
+		return rule, false
+	}
+
+	validationErrors := utils.ValidationErrors{}
+	switch req.Type {
+	case models.ProgressionTypeLinear:
+		if incrementKg == nil {
+			validationErrors["progression.increment"] = []string{"Increment is required for linear progression."}
+		}
+	case models.ProgressionTypeDouble:
+		if incrementKg == nil {
+			validationErrors["progression.increment"] = []string{"Increment is required for double progression."}
+		}
+		if req.MinReps == nil || req.MaxReps == nil {
+			validationErrors["progression.min_reps"] = []string{"Min and max reps are required for double progression."}
+		} else if *req.MinReps > *req.MaxReps {
+			validationErrors["progression.max_reps"] = []string{"Max reps must not be less than min reps."}
+		}
+	case models.ProgressionTypeRPE:
+		if req.TargetRPE == nil {
+			validationErrors["progression.target_rpe"] = []string{"Target RPE is required for RPE progression."}
+		}
+	case models.ProgressionTypePercent1RM:
+		if req.PercentOneRM == nil {
+			validationErrors["progression.percent_1rm"] = []string{"Percent of 1RM is required for percent_1rm progression."}
+		}
+	}
+	if len(validationErrors) > 0 {
+		utils.ValidationErrorResponse(c, validationErrors)
+		return rule, false
+	}
+
+	progressionType := req.Type
+	rule = models.ProgressionRule{
+		Type:         &progressionType,
+		IncrementKg:  incrementKg,
+		MinReps:      req.MinReps,
+		MaxReps:      req.MaxReps,
+		TargetRPE:    req.TargetRPE,
+		PercentOneRM: req.PercentOneRM,
+	}
+	return rule, true
+}
+
+// progressionColumns returns the group-level column updates that store a progression rule
+func progressionColumns(rule models.ProgressionRule) map[string]interface{} {
+	return map[string]interface{}{
+		"progression_type":           rule.Type,
+		"progression_increment_kg":   rule.IncrementKg,
+		"progression_min_reps":       rule.MinReps,
+		"progression_max_reps":       rule.MaxReps,
+		"progression_target_rpe":     rule.TargetRPE,
+		"progression_percent_one_rm": rule.PercentOneRM,
+	}
+}
+
+// lastExercisePerformance summarizes the weighted sets of the user's most recent session of an exercise
+type lastExercisePerformance struct {
+	TopWeightKg float64 // Heaviest completed load
+	TopReps     int     // Fewest reps completed at the heaviest load
+	TopRPE      *int    // RPE of the heaviest set, if recorded
+	MinReps     int     // Fewest reps across all completed sets
+}
+
+// findLastExercisePerformance returns the user's most recent weighted performance of an exercise
+// Returns nil if the user has never completed a weighted set of it
+func findLastExercisePerformance(userID uuid.UUID, exerciseID uuid.UUID) (*lastExercisePerformance, error) {
+	weightedSets := func() *gorm.DB {
+		return completedSetsQuery(userID, nil, nil).
+			Where("session_exercises.exercise_id = ?", exerciseID).
+			Where("session_sets.actual_weight_kg > 0 AND session_sets.actual_reps > 0")
+	}
+
+	var latest []completedSetRow
+	if err := weightedSets().Order("workout_sessions.started_at DESC").Limit(1).Scan(&latest).Error; err != nil {
+		return nil, err
+	}
+	if len(latest) == 0 {
+		return nil, nil
+	}
+
+	var rows []completedSetRow
+	if err := weightedSets().Where("workout_sessions.id = ?", latest[0].SessionID).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	var last *lastExercisePerformance
+	for _, row := range rows {
+		weight := *row.ActualWeightKg
+		reps := *row.ActualReps
+
+		if last == nil {
+			last = &lastExercisePerformance{TopWeightKg: weight, TopReps: reps, TopRPE: row.RPE, MinReps: reps}
+			continue
+		}
+		if reps < last.MinReps {
+			last.MinReps = reps
+		}
+		switch {
+		case weight > last.TopWeightKg:
+			last.TopWeightKg, last.TopReps, last.TopRPE = weight, reps, row.RPE
+		case weight == last.TopWeightKg && reps < last.TopReps:
+			last.TopReps, last.TopRPE = reps, row.RPE
+		}
+	}
+
+	return last, nil
+}
+
+// estimatedOneRepMax returns the user's best estimated 1RM for an exercise (Epley), falling back
+// to an estimate from the last performance. Returns 0 if neither is available.
+func estimatedOneRepMax(userID uuid.UUID, exerciseID uuid.UUID, last *lastExercisePerformance) float64 {
+	var record models.PersonalRecord
+	if err := database.DB.Where(
+		"user_id = ? AND exercise_id = ? AND record_type = ? AND formula = ?",
+		userID, exerciseID, models.RecordTypeEstimated1RM, utils.OneRepMaxFormulaEpley,
+	).First(&record).Error; err == nil && record.Value > 0 {
+		return record.Value
+	}
+
+	if last != nil {
+		return utils.EstimateOneRepMax(last.TopWeightKg, last.TopReps, utils.OneRepMaxFormulaEpley)
+	}
+	return 0
+}
+
+// progressionTarget returns the load and reps to pre-fill for a prescription, applying the
+// group's progression rule to the user's last performance. Falls back to the prescribed
+// targets when there is no rule, no history, or the rule cannot be applied.
+func progressionTarget(userID uuid.UUID, prescription models.WorkoutPrescription) (*float64, *int, error) {
+	weightKg := prescription.TargetWeightKg
+	reps := prescription.Reps
+
+	rule := prescription.Progression
+	if rule.Type == nil || prescription.HoldSeconds != nil {
+		return weightKg, reps, nil
+	}
+
+	last, err := findLastExercisePerformance(userID, prescription.ExerciseID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	increment := 0.0
+	if rule.IncrementKg != nil {
+		increment = *rule.IncrementKg
+	}
+
+	switch *rule.Type {
+	case models.ProgressionTypeLinear:
+		if last != nil {
+			hitTarget := reps == nil || last.MinReps >= *reps
+			load := utils.LinearProgressionLoad(last.TopWeightKg, hitTarget, increment)
+			weightKg = &load
+		}
+
+	case models.ProgressionTypeDouble:
+		if rule.MinReps == nil || rule.MaxReps == nil {
+			break
+		}
+		if last == nil {
+			reps = rule.MinReps
+			break
+		}
+		load, nextReps := utils.DoubleProgressionTarget(last.TopWeightKg, last.TopReps, *rule.MinReps, *rule.MaxReps, increment)
+		weightKg, reps = &load, &nextReps
+
+	case models.ProgressionTypeRPE:
+		if last == nil || last.TopRPE == nil || rule.TargetRPE == nil {
+			break
+		}
+		targetReps := last.TopReps
+		if reps != nil {
+			targetReps = *reps
+		}
+		if load := utils.RPETargetLoad(last.TopWeightKg, last.TopReps, *last.TopRPE, targetReps, *rule.TargetRPE); load > 0 {
+			load = utils.RoundToIncrement(load, increment)
+			weightKg = &load
+		}
+
+	case models.ProgressionTypePercent1RM:
+		if rule.PercentOneRM == nil {
+			break
+		}
+		if oneRepMax := estimatedOneRepMax(userID, prescription.ExerciseID, last); oneRepMax > 0 {
+			load := utils.RoundToIncrement(utils.PercentOfOneRepMax(oneRepMax, *rule.PercentOneRM), increment)
+			weightKg = &load
+		}
+	}
+
+	return weightKg, reps, nil
+}
@@ -51,7 +51,8 @@ func SearchUsers(c *gin.Context) {
 
 	SetDefaultPagination(&queryParams.PaginationQuery)
 
-	query := database.DB.Model(&models.User{}).Where("profile_visibility = ?", "public")
+	query := database.DB.Model(&models.User{}).Where("profile_visibility = ?", "public").
+		Scopes(excludePendingDeletion("id"))
 
 	// Apply is_looking_for_trainer filter
 	if queryParams.IsLookingForTrainer == "true" {
@@ -173,7 +174,8 @@ func SearchTrainers(c *gin.Context) {
 	query := database.DB.Model(&models.TrainerProfile{}).
 		Preload("User").
 		Preload("Specialties").
-		Where("visibility = ?", "public")
+		Where("visibility = ?", "public").
+		Scopes(excludePendingDeletion("trainer_profiles.user_id"), joinTrainerReviewStats)
 
 	// Apply is_looking_for_clients filter
 	if queryParams.IsLookingForClients == "true" {
@@ -242,6 +244,11 @@ func SearchTrainers(c *gin.Context) {
 				searchPattern, searchPattern, searchPattern, searchPattern, searchPattern, searchPattern)
 	}
 
+	// Filter by min_rating (trainers without reviews have a rating of 0)
+	if queryParams.MinRating > 0 {
+		query = query.Where("COALESCE(review_stats.average_rating, 0) >= ?", queryParams.MinRating)
+	}
+
 	// Get total count before pagination
 	var total int64
 	countQuery := query.Session(&gorm.Session{})
@@ -279,16 +286,15 @@ func SearchTrainers(c *gin.Context) {
 	}
 
 	// Build responses with review stats
-	responses := make([]TrainerSearchResponse, len(trainers))
+	trainerUserIDs := make([]uuid.UUID, len(trainers))
 	for i, trainer := range trainers {
-		// Calculate review stats
-		var reviewCount int64
-		var avgRating float64
+		trainerUserIDs[i] = trainer.UserID
+	}
+	stats := loadTrainerReviewStats(trainerUserIDs)
 
-		database.DB.Model(&models.TrainerReview{}).Where("trainer_id = ?", trainer.ID).Count(&reviewCount)
-		if reviewCount > 0 {
-			database.DB.Model(&models.TrainerReview{}).Where("trainer_id = ?", trainer.ID).Select("COALESCE(AVG(rating), 0)").Scan(&avgRating)
-		}
+	responses := make([]TrainerSearchResponse, len(trainers))
+	for i, trainer := range trainers {
+		trainerStats := stats[trainer.UserID]
 
 		// Convert specialties
 		specialties := make([]models.SpecialtyResponse, 0, len(trainer.Specialties))
@@ -305,8 +311,8 @@ func SearchTrainers(c *gin.Context) {
 			Location:            trainer.Location.ToResponse(),
 			Visibility:          trainer.Visibility,
 			IsLookingForClients: trainer.IsLookingForClients,
-			ReviewCount:         int(reviewCount),
-			AverageRating:       avgRating,
+			ReviewCount:         trainerStats.ReviewCount,
+			AverageRating:       trainerStats.AverageRating,
 			CreatedAt:           trainer.CreatedAt,
 		}
 
@@ -327,17 +333,5 @@ func SearchTrainers(c *gin.Context) {
 		responses[i] = resp
 	}
 
-	// Filter by min_rating if specified (post-query filter since we need to calculate ratings)
-	if queryParams.MinRating > 0 {
-		filteredResponses := make([]TrainerSearchResponse, 0)
-		for _, resp := range responses {
-			if resp.AverageRating >= queryParams.MinRating {
-				filteredResponses = append(filteredResponses, resp)
-			}
-		}
-		responses = filteredResponses
-		total = int64(len(responses))
-	}
-
 	utils.PaginatedResponse(c, "Trainers retrieved successfully", responses, queryParams.Page, queryParams.Limit, int(total))
 }
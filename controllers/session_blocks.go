@@ -9,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // GetSessionBlock retrieves a single session block
@@ -31,7 +32,14 @@ func GetSessionBlock(c *gin.Context) {
 
 	var block models.SessionBlock
 	if err := database.DB.
+		Preload("SessionExercises", func(db *gorm.DB) *gorm.DB {
+			return db.Order("exercise_order ASC")
+		}).
 		Preload("SessionExercises.Exercise").
+		Preload("SessionExercises.Prescription").
+		Preload("SessionExercises.SessionSets", func(db *gorm.DB) *gorm.DB {
+			return db.Order("set_number ASC")
+		}).
 		Preload("SessionExercises.SessionSets.RPEValue").
 		Preload("Session").
 		First(&block, "id = ?", blockID).Error; err != nil {
@@ -40,15 +48,16 @@ func GetSessionBlock(c *gin.Context) {
 	}
 
 	// Authorization: check session ownership
-	if !isAuthorizedForSession(block.Session, authUserID) {
+	if !canViewSession(block.Session, authUserID) {
 		utils.NotFoundResponse(c, "Session block not found")
 		return
 	}
 
 	// Get user's preferred weight unit for response conversion
 	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+	preferredDistanceUnit := getUserPreferredDistanceUnit(c, authUserID)
 
-	utils.SuccessResponse(c, "Session block retrieved successfully", block.ToResponse(preferredWeightUnit))
+	utils.SuccessResponse(c, "Session block retrieved successfully", block.ToResponse(preferredWeightUnit, preferredDistanceUnit))
 }
 
 // CompleteSessionBlock marks a session block as complete
@@ -99,8 +108,9 @@ func CompleteSessionBlock(c *gin.Context) {
 
 	// Get user's preferred weight unit for response conversion
 	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+	preferredDistanceUnit := getUserPreferredDistanceUnit(c, authUserID)
 
-	utils.SuccessResponse(c, "Session block completed successfully", block.ToResponse(preferredWeightUnit))
+	utils.SuccessResponse(c, "Session block completed successfully", block.ToResponse(preferredWeightUnit, preferredDistanceUnit))
 }
 
 // SkipSessionBlock marks a session block as skipped
@@ -150,8 +160,9 @@ func SkipSessionBlock(c *gin.Context) {
 
 	// Get user's preferred weight unit for response conversion
 	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+	preferredDistanceUnit := getUserPreferredDistanceUnit(c, authUserID)
 
-	utils.SuccessResponse(c, "Session block skipped successfully", block.ToResponse(preferredWeightUnit))
+	utils.SuccessResponse(c, "Session block skipped successfully", block.ToResponse(preferredWeightUnit, preferredDistanceUnit))
 }
 
 // UpdateSessionBlockRPE updates the perceived exertion for a block
@@ -207,17 +218,28 @@ func UpdateSessionBlockRPE(c *gin.Context) {
 
 	// Get user's preferred weight unit for response conversion
 	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+	preferredDistanceUnit := getUserPreferredDistanceUnit(c, authUserID)
 
-	utils.SuccessResponse(c, "Session block updated successfully", block.ToResponse(preferredWeightUnit))
+	utils.SuccessResponse(c, "Session block updated successfully", block.ToResponse(preferredWeightUnit, preferredDistanceUnit))
 }
 
 // Helper functions
 
-// isAuthorizedForSession checks if user owns or created the session.
+// isAuthorizedForSession checks if user may edit the session: they own it, or they are the
+// trainer who logged it and the client still lets them log sessions on their behalf.
 func isAuthorizedForSession(session models.WorkoutSession, authUserID uuid.UUID) bool {
-	isOwner := session.UserID == authUserID
+	if session.UserID == authUserID {
+		return true
+	}
 	isCreator := session.CreatedByID != nil && *session.CreatedByID == authUserID
-	return isOwner || isCreator
+	return isCreator && hasClientConsent(authUserID, session.UserID, models.ConsentScopeLogSessions)
+}
+
+// canViewSession checks if user may view the session: they may edit it, or they are a trainer
+// the client shares their sessions with.
+func canViewSession(session models.WorkoutSession, authUserID uuid.UUID) bool {
+	return isAuthorizedForSession(session, authUserID) ||
+		hasClientConsent(authUserID, session.UserID, models.ConsentScopeSessions)
 }
 
 // getUserPreferredWeightUnit retrieves user's preferred weight unit.
@@ -231,3 +253,12 @@ func getUserPreferredWeightUnit(c *gin.Context, userID uuid.UUID) string {
 	}
 	return user.PreferredWeightUnit
 }
+
+// getUserPreferredDistanceUnit retrieves user's preferred distance unit.
+func getUserPreferredDistanceUnit(c *gin.Context, userID uuid.UUID) string {
+	var user models.User
+	if err := database.DB.Select("preferred_distance_unit").First(&user, "id = ?", userID).Error; err != nil {
+		return "km" // default fallback
+	}
+	return utils.GetUserPreferredDistanceUnit(user.PreferredDistanceUnit)
+}
@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"lamari-fit-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// defaultComplianceTrendDays is the number of days, up to and including to, returned when no from date is given
+const defaultComplianceTrendDays = 90
+
+// GetComplianceTrend returns the authenticated user's prescription compliance over time
+// Query params:
+//   - group_by: day|week|month (default week)
+//   - from, to: optional YYYY-MM-DD date range of up to 366 days (defaults to the last 90 days)
+func GetComplianceTrend(c *gin.Context) {
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	respondWithComplianceTrend(c, authUserID)
+}
+
+// GetClientComplianceTrend returns prescription compliance over time for one of the trainer's active clients
+func GetClientComplianceTrend(c *gin.Context) {
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	clientID, ok := utils.ParseUUID(c, c.Param("id"), "client")
+	if !ok {
+		return
+	}
+
+	if !hasClientConsent(authUserID, clientID, models.ConsentScopeSessions) {
+		utils.ForbiddenResponse(c, "Not authorized to view analytics for this user")
+		return
+	}
+
+	respondWithComplianceTrend(c, clientID)
+}
+
+// respondWithComplianceTrend averages the compliance of userID's completed sessions per period.
+// Sessions without prescribed exercises are left out.
+func respondWithComplianceTrend(c *gin.Context, userID uuid.UUID) {
+	groupBy := c.DefaultQuery("group_by", utils.GroupByWeek)
+	if !utils.ValidGroupings[groupBy] {
+		utils.ValidationErrorResponse(c, utils.ValidationErrors{
+			"group_by": []string{"group_by must be one of: day, week, month"},
+		})
+		return
+	}
+
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+
+	// Every session is loaded with its full tree, so the range is always bounded
+	if to == nil {
+		today := utils.StartOfDay(time.Now())
+		to = &today
+	}
+	if from == nil {
+		start := to.AddDate(0, 0, -(defaultComplianceTrendDays - 1))
+		from = &start
+	}
+	if !validateDateRangeLength(c, *from, *to) {
+		return
+	}
+
+	var sessions []models.WorkoutSession
+	if err := database.DB.
+		Where("user_id = ? AND completed = ?", userID, true).
+		Where("started_at >= ? AND started_at < ?", *from, to.AddDate(0, 0, 1)).
+		Preload("SessionBlocks").
+		Preload("SessionBlocks.SessionExercises").
+		Preload("SessionBlocks.SessionExercises.Prescription.RPEValue").
+		Preload("SessionBlocks.SessionExercises.SessionSets.RPEValue").
+		Order("started_at ASC").
+		Find(&sessions).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve compliance trend")
+		return
+	}
+
+	response := models.ComplianceTrendResponse{
+		UserID:  userID,
+		GroupBy: groupBy,
+		From:    from.Format(utils.DateFormat),
+		To:      to.Format(utils.DateFormat),
+		Trend:   buildComplianceTrend(sessions, groupBy),
+	}
+
+	utils.SuccessResponse(c, "Compliance trend retrieved successfully", response)
+}
+
+// complianceTrendAccumulator collects per-bucket session compliance before averaging
+type complianceTrendAccumulator struct {
+	sessions         int
+	scoreTotal       float64
+	completionTotal  float64
+	skippedExercises int
+}
+
+// buildComplianceTrend groups session compliance into day/week/month buckets ordered by date
+func buildComplianceTrend(sessions []models.WorkoutSession, groupBy string) []models.ComplianceTrendBucket {
+	buckets := make(map[time.Time]*complianceTrendAccumulator)
+	for _, session := range sessions {
+		compliance := utils.BuildSessionCompliance(session, "kg")
+		if compliance == nil {
+			continue
+		}
+
+		start := utils.BucketStart(session.StartedAt, groupBy)
+		acc, exists := buckets[start]
+		if !exists {
+			acc = &complianceTrendAccumulator{}
+			buckets[start] = acc
+		}
+		acc.sessions++
+		acc.scoreTotal += compliance.Score
+		acc.completionTotal += compliance.CompletionPercent
+		acc.skippedExercises += compliance.SkippedExercises
+	}
+
+	starts := make([]time.Time, 0, len(buckets))
+	for start := range buckets {
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+
+	trend := make([]models.ComplianceTrendBucket, len(starts))
+	for i, start := range starts {
+		acc := buckets[start]
+		trend[i] = models.ComplianceTrendBucket{
+			PeriodStart:              start.Format(utils.DateFormat),
+			Sessions:                 acc.sessions,
+			AverageScore:             math.Round(acc.scoreTotal/float64(acc.sessions)*10) / 10,
+			AverageCompletionPercent: math.Round(acc.completionTotal/float64(acc.sessions)*10) / 10,
+			SkippedExercises:         acc.skippedExercises,
+		}
+	}
+
+	return trend
+}
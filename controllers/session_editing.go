@@ -0,0 +1,774 @@
+package controllers
+
+import (
+	"errors"
+	"time"
+
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"lamari-fit-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// errSessionExerciseNotFound is returned when an exercise to add or swap in does not exist
+var errSessionExerciseNotFound = errors.New("exercise not found")
+
+// CreateSessionBlock adds a block to a session, optionally with exercises.
+// The block is inserted at block_order (shifting later blocks down) or appended at the end.
+func CreateSessionBlock(c *gin.Context) {
+	var params IDParam
+	if err := c.ShouldBindUri(&params); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	sessionID, ok := utils.ParseUUID(c, params.ID, "workout session")
+	if !ok {
+		return
+	}
+
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	var session models.WorkoutSession
+	if err := database.DB.First(&session, "id = ?", sessionID).Error; err != nil {
+		utils.NotFoundResponse(c, "Workout session not found")
+		return
+	}
+
+	// Authorization
+	if !isAuthorizedForSession(session, authUserID) {
+		utils.ForbiddenResponse(c, "Not authorized to modify this session")
+		return
+	}
+
+	var req models.CreateSessionBlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	block := models.SessionBlock{
+		SessionID: session.ID,
+		GroupID:   uuid.New(),
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.SessionBlock{}).Where("session_id = ?", session.ID).Count(&count).Error; err != nil {
+			return err
+		}
+
+		block.BlockOrder = int(count) + 1
+		if req.BlockOrder != nil && *req.BlockOrder < block.BlockOrder {
+			block.BlockOrder = *req.BlockOrder
+			if err := tx.Model(&models.SessionBlock{}).
+				Where("session_id = ? AND block_order >= ?", session.ID, block.BlockOrder).
+				Update("block_order", gorm.Expr("block_order + 1")).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(&block).Error; err != nil {
+			return err
+		}
+
+		for _, exerciseReq := range req.Exercises {
+			if _, err := insertSessionExercise(tx, block.ID, exerciseReq); err != nil {
+				return err
+			}
+		}
+		return renumberSessionBlocks(tx, session.ID)
+	})
+	if errors.Is(err, errSessionExerciseNotFound) {
+		utils.ValidationErrorResponse(c, utils.ValidationErrors{"exercise_id": {"Exercise not found"}})
+		return
+	}
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to create session block")
+		return
+	}
+
+	reloadSessionBlock(&block)
+
+	// Get user's preferred weight unit for response conversion
+	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+	preferredDistanceUnit := getUserPreferredDistanceUnit(c, authUserID)
+
+	utils.CreatedResponse(c, "Session block created successfully", block.ToResponse(preferredWeightUnit, preferredDistanceUnit))
+}
+
+// ReorderSessionBlocks puts a session's blocks in the order given
+func ReorderSessionBlocks(c *gin.Context) {
+	var params IDParam
+	if err := c.ShouldBindUri(&params); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	sessionID, ok := utils.ParseUUID(c, params.ID, "workout session")
+	if !ok {
+		return
+	}
+
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	var session models.WorkoutSession
+	if err := database.DB.First(&session, "id = ?", sessionID).Error; err != nil {
+		utils.NotFoundResponse(c, "Workout session not found")
+		return
+	}
+
+	// Authorization
+	if !isAuthorizedForSession(session, authUserID) {
+		utils.ForbiddenResponse(c, "Not authorized to modify this session")
+		return
+	}
+
+	var req models.ReorderSessionBlocksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	var blockIDs []uuid.UUID
+	if err := database.DB.Model(&models.SessionBlock{}).Where("session_id = ?", session.ID).Pluck("id", &blockIDs).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to reorder session blocks")
+		return
+	}
+	if !isPermutation(req.BlockIDs, blockIDs) {
+		utils.BadRequestResponse(c, "Block IDs must list every block of the session exactly once", nil)
+		return
+	}
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		for i, id := range req.BlockIDs {
+			if err := tx.Model(&models.SessionBlock{}).Where("id = ?", id).Update("block_order", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to reorder session blocks")
+		return
+	}
+
+	// Reload with full relationships
+	database.DB.
+		Preload("User").
+		Preload("CreatedBy").
+		Preload("Workout").
+		Preload("SessionBlocks", func(db *gorm.DB) *gorm.DB {
+			return db.Order("block_order ASC")
+		}).
+		Preload("SessionBlocks.SessionExercises", func(db *gorm.DB) *gorm.DB {
+			return db.Order("exercise_order ASC")
+		}).
+		Preload("SessionBlocks.SessionExercises.Exercise").
+		Preload("SessionBlocks.SessionExercises.Prescription").
+		Preload("SessionBlocks.SessionExercises.SessionSets", func(db *gorm.DB) *gorm.DB {
+			return db.Order("set_number ASC")
+		}).
+		Preload("SessionBlocks.SessionExercises.SessionSets.RPEValue").
+		First(&session, "id = ?", session.ID)
+
+	utils.SuccessResponse(c, "Session blocks reordered successfully", models.BuildSessionResponse(session, getUserPreferredWeightUnit(c, authUserID), getUserPreferredDistanceUnit(c, authUserID)))
+}
+
+// DeleteSessionBlock deletes a block with its exercises and sets, closing the gap in block order
+func DeleteSessionBlock(c *gin.Context) {
+	var params IDParam
+	if err := c.ShouldBindUri(&params); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	blockID, ok := utils.ParseUUID(c, params.ID, "session block")
+	if !ok {
+		return
+	}
+
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	var block models.SessionBlock
+	if err := database.DB.Preload("Session").First(&block, "id = ?", blockID).Error; err != nil {
+		utils.NotFoundResponse(c, "Session block not found")
+		return
+	}
+
+	// Authorization
+	if !isAuthorizedForSession(block.Session, authUserID) {
+		utils.ForbiddenResponse(c, "Not authorized to delete this block")
+		return
+	}
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		recordExerciseIDs, err := completedSetExerciseIDs(tx, "session_exercises.session_block_id = ?", block.ID)
+		if err != nil {
+			return err
+		}
+
+		exerciseIDs := tx.Model(&models.SessionExercise{}).Select("id").Where("session_block_id = ?", block.ID)
+		if err := tx.Where("session_exercise_id IN (?)", exerciseIDs).Delete(&models.SessionSet{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("session_block_id = ?", block.ID).Delete(&models.SessionExercise{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&block).Error; err != nil {
+			return err
+		}
+		if err := renumberSessionBlocks(tx, block.SessionID); err != nil {
+			return err
+		}
+		return recomputePersonalRecordsForExercises(tx, block.Session.UserID, recordExerciseIDs)
+	}); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to delete session block")
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
+
+// AddExerciseToSessionBlock adds an exercise to a block, optionally with empty sets.
+// The exercise is inserted at exercise_order (shifting later exercises down) or appended at the end.
+func AddExerciseToSessionBlock(c *gin.Context) {
+	var params IDParam
+	if err := c.ShouldBindUri(&params); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	blockID, ok := utils.ParseUUID(c, params.ID, "session block")
+	if !ok {
+		return
+	}
+
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	var block models.SessionBlock
+	if err := database.DB.Preload("Session").First(&block, "id = ?", blockID).Error; err != nil {
+		utils.NotFoundResponse(c, "Session block not found")
+		return
+	}
+
+	// Authorization
+	if !isAuthorizedForSession(block.Session, authUserID) {
+		utils.ForbiddenResponse(c, "Not authorized to add exercises to this block")
+		return
+	}
+
+	var req models.AddSessionExerciseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	var sessionExercise models.SessionExercise
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		sessionExercise, err = insertSessionExercise(tx, block.ID, req)
+		return err
+	})
+	if errors.Is(err, errSessionExerciseNotFound) {
+		utils.ValidationErrorResponse(c, utils.ValidationErrors{"exercise_id": {"Exercise not found"}})
+		return
+	}
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to add exercise")
+		return
+	}
+
+	reloadSessionExercise(&sessionExercise)
+
+	// Get user's preferred weight unit for response conversion
+	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+	preferredDistanceUnit := getUserPreferredDistanceUnit(c, authUserID)
+
+	utils.CreatedResponse(c, "Exercise added successfully", sessionExercise.ToResponse(preferredWeightUnit, preferredDistanceUnit))
+}
+
+// ReorderSessionExercises puts a block's exercises in the order given
+func ReorderSessionExercises(c *gin.Context) {
+	var params IDParam
+	if err := c.ShouldBindUri(&params); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	blockID, ok := utils.ParseUUID(c, params.ID, "session block")
+	if !ok {
+		return
+	}
+
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	var block models.SessionBlock
+	if err := database.DB.Preload("Session").First(&block, "id = ?", blockID).Error; err != nil {
+		utils.NotFoundResponse(c, "Session block not found")
+		return
+	}
+
+	// Authorization
+	if !isAuthorizedForSession(block.Session, authUserID) {
+		utils.ForbiddenResponse(c, "Not authorized to modify this block")
+		return
+	}
+
+	var req models.ReorderSessionExercisesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	var exerciseIDs []uuid.UUID
+	if err := database.DB.Model(&models.SessionExercise{}).Where("session_block_id = ?", block.ID).Pluck("id", &exerciseIDs).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to reorder session exercises")
+		return
+	}
+	if !isPermutation(req.SessionExerciseIDs, exerciseIDs) {
+		utils.BadRequestResponse(c, "Session exercise IDs must list every exercise of the block exactly once", nil)
+		return
+	}
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		for i, id := range req.SessionExerciseIDs {
+			if err := tx.Model(&models.SessionExercise{}).Where("id = ?", id).Update("exercise_order", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return resequenceBlockSets(tx, block.ID)
+	}); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to reorder session exercises")
+		return
+	}
+
+	reloadSessionBlock(&block)
+
+	// Get user's preferred weight unit for response conversion
+	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+	preferredDistanceUnit := getUserPreferredDistanceUnit(c, authUserID)
+
+	utils.SuccessResponse(c, "Session exercises reordered successfully", block.ToResponse(preferredWeightUnit, preferredDistanceUnit))
+}
+
+// SwapSessionExercise substitutes another exercise for a session exercise mid-session.
+// If none of its sets are done the exercise is replaced in place. Otherwise the completed sets stay
+// with the original exercise and the remaining sets move to the substitute, placed right after it.
+// Loads planned for the original exercise are cleared from the sets that change exercise.
+func SwapSessionExercise(c *gin.Context) {
+	var params IDParam
+	if err := c.ShouldBindUri(&params); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	exerciseID, ok := utils.ParseUUID(c, params.ID, "session exercise")
+	if !ok {
+		return
+	}
+
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	var sessionExercise models.SessionExercise
+	if err := database.DB.
+		Preload("SessionBlock.Session").
+		Preload("SessionSets", func(db *gorm.DB) *gorm.DB {
+			return db.Order("set_number ASC")
+		}).
+		First(&sessionExercise, "id = ?", exerciseID).Error; err != nil {
+		utils.NotFoundResponse(c, "Session exercise not found")
+		return
+	}
+
+	// Authorization
+	if !isAuthorizedForSession(sessionExercise.SessionBlock.Session, authUserID) {
+		utils.ForbiddenResponse(c, "Not authorized to swap this exercise")
+		return
+	}
+
+	var req models.SwapSessionExerciseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	if req.ExerciseID == sessionExercise.ExerciseID {
+		utils.BadRequestResponse(c, "Session exercise already uses this exercise", nil)
+		return
+	}
+
+	var exercise models.Exercise
+	if err := database.DB.First(&exercise, "id = ?", req.ExerciseID).Error; err != nil {
+		utils.ValidationErrorResponse(c, utils.ValidationErrors{"exercise_id": {"Exercise not found"}})
+		return
+	}
+
+	var remaining []uuid.UUID
+	completedSets := 0
+	for _, set := range sessionExercise.SessionSets {
+		if set.Completed {
+			completedSets++
+		} else {
+			remaining = append(remaining, set.ID)
+		}
+	}
+
+	swapped := sessionExercise
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if completedSets == 0 {
+			if err := tx.Model(&models.SessionExercise{}).Where("id = ?", swapped.ID).Update("exercise_id", exercise.ID).Error; err != nil {
+				return err
+			}
+			return clearPlannedLoads(tx, remaining)
+		}
+
+		// Make room for the substitute right after the original
+		if err := tx.Model(&models.SessionExercise{}).
+			Where("session_block_id = ? AND exercise_order > ?", sessionExercise.SessionBlockID, sessionExercise.ExerciseOrder).
+			Update("exercise_order", gorm.Expr("exercise_order + 1")).Error; err != nil {
+			return err
+		}
+
+		swapped = models.SessionExercise{
+			SessionBlockID: sessionExercise.SessionBlockID,
+			PrescriptionID: sessionExercise.PrescriptionID,
+			ExerciseID:     exercise.ID,
+			ExerciseOrder:  sessionExercise.ExerciseOrder + 1,
+		}
+		if err := tx.Create(&swapped).Error; err != nil {
+			return err
+		}
+
+		// The original exercise is done with the sets completed so far
+		if sessionExercise.CompletedAt == nil {
+			now := time.Now()
+			if err := tx.Model(&models.SessionExercise{}).Where("id = ?", sessionExercise.ID).Update("completed_at", now).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(remaining) > 0 {
+			if err := tx.Model(&models.SessionSet{}).
+				Where("id IN ?", remaining).
+				Update("session_exercise_id", swapped.ID).Error; err != nil {
+				return err
+			}
+			if err := clearPlannedLoads(tx, remaining); err != nil {
+				return err
+			}
+		}
+
+		for _, id := range []uuid.UUID{sessionExercise.ID, swapped.ID} {
+			if err := renumberExerciseSets(tx, id); err != nil {
+				return err
+			}
+		}
+		return resequenceBlockSets(tx, sessionExercise.SessionBlockID)
+	})
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to swap session exercise")
+		return
+	}
+
+	reloadSessionExercise(&swapped)
+
+	// Get user's preferred weight unit for response conversion
+	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+	preferredDistanceUnit := getUserPreferredDistanceUnit(c, authUserID)
+
+	utils.SuccessResponse(c, "Session exercise swapped successfully", swapped.ToResponse(preferredWeightUnit, preferredDistanceUnit))
+}
+
+// DeleteSessionExercise deletes an exercise with its sets, closing the gap in exercise order
+func DeleteSessionExercise(c *gin.Context) {
+	var params IDParam
+	if err := c.ShouldBindUri(&params); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	exerciseID, ok := utils.ParseUUID(c, params.ID, "session exercise")
+	if !ok {
+		return
+	}
+
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	var sessionExercise models.SessionExercise
+	if err := database.DB.
+		Preload("SessionBlock.Session").
+		First(&sessionExercise, "id = ?", exerciseID).Error; err != nil {
+		utils.NotFoundResponse(c, "Session exercise not found")
+		return
+	}
+
+	// Authorization
+	if !isAuthorizedForSession(sessionExercise.SessionBlock.Session, authUserID) {
+		utils.ForbiddenResponse(c, "Not authorized to delete this exercise")
+		return
+	}
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		recordExerciseIDs, err := completedSetExerciseIDs(tx, "session_exercises.id = ?", sessionExercise.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("session_exercise_id = ?", sessionExercise.ID).Delete(&models.SessionSet{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&sessionExercise).Error; err != nil {
+			return err
+		}
+		if err := renumberSessionExercises(tx, sessionExercise.SessionBlockID); err != nil {
+			return err
+		}
+		if err := resequenceBlockSets(tx, sessionExercise.SessionBlockID); err != nil {
+			return err
+		}
+		return recomputePersonalRecordsForExercises(tx, sessionExercise.SessionBlock.Session.UserID, recordExerciseIDs)
+	}); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to delete session exercise")
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
+
+// insertSessionExercise creates an exercise in a block at the requested position along with its
+// empty sets, shifting later exercises down
+func insertSessionExercise(tx *gorm.DB, blockID uuid.UUID, req models.AddSessionExerciseRequest) (models.SessionExercise, error) {
+	var exercise models.Exercise
+	if err := tx.First(&exercise, "id = ?", req.ExerciseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.SessionExercise{}, errSessionExerciseNotFound
+		}
+		return models.SessionExercise{}, err
+	}
+
+	var count int64
+	if err := tx.Model(&models.SessionExercise{}).Where("session_block_id = ?", blockID).Count(&count).Error; err != nil {
+		return models.SessionExercise{}, err
+	}
+
+	sessionExercise := models.SessionExercise{
+		SessionBlockID: blockID,
+		ExerciseID:     exercise.ID,
+		ExerciseOrder:  int(count) + 1,
+	}
+	if req.ExerciseOrder != nil && *req.ExerciseOrder < sessionExercise.ExerciseOrder {
+		sessionExercise.ExerciseOrder = *req.ExerciseOrder
+		if err := tx.Model(&models.SessionExercise{}).
+			Where("session_block_id = ? AND exercise_order >= ?", blockID, sessionExercise.ExerciseOrder).
+			Update("exercise_order", gorm.Expr("exercise_order + 1")).Error; err != nil {
+			return models.SessionExercise{}, err
+		}
+	}
+	if req.Notes != nil {
+		sessionExercise.Notes = *req.Notes
+	}
+	if err := tx.Create(&sessionExercise).Error; err != nil {
+		return models.SessionExercise{}, err
+	}
+
+	if err := renumberSessionExercises(tx, blockID); err != nil {
+		return models.SessionExercise{}, err
+	}
+	if req.Sets == nil {
+		return sessionExercise, nil
+	}
+
+	// New sets go after everything else in the block until the block is resequenced
+	var lastSequenceOrder int
+	if err := tx.Model(&models.SessionSet{}).
+		Joins("JOIN session_exercises ON session_exercises.id = session_sets.session_exercise_id").
+		Where("session_exercises.session_block_id = ?", blockID).
+		Select("COALESCE(MAX(session_sets.sequence_order), 0)").
+		Scan(&lastSequenceOrder).Error; err != nil {
+		return models.SessionExercise{}, err
+	}
+
+	for i := 0; i < *req.Sets; i++ {
+		set := models.SessionSet{
+			SessionExerciseID: sessionExercise.ID,
+			SetNumber:         i + 1,
+			Round:             i + 1,
+			SequenceOrder:     lastSequenceOrder + i + 1,
+		}
+		if err := tx.Create(&set).Error; err != nil {
+			return models.SessionExercise{}, err
+		}
+	}
+	return sessionExercise, resequenceBlockSets(tx, blockID)
+}
+
+// renumberSessionBlocks numbers a session's blocks 1..n, keeping their current order
+func renumberSessionBlocks(tx *gorm.DB, sessionID uuid.UUID) error {
+	var blocks []models.SessionBlock
+	if err := tx.Where("session_id = ?", sessionID).Order("block_order ASC, created_at ASC").Find(&blocks).Error; err != nil {
+		return err
+	}
+	for i, block := range blocks {
+		if block.BlockOrder == i+1 {
+			continue
+		}
+		if err := tx.Model(&models.SessionBlock{}).Where("id = ?", block.ID).Update("block_order", i+1).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renumberSessionExercises numbers a block's exercises 1..n, keeping their current order
+func renumberSessionExercises(tx *gorm.DB, blockID uuid.UUID) error {
+	var exercises []models.SessionExercise
+	if err := tx.Where("session_block_id = ?", blockID).Order("exercise_order ASC, created_at ASC").Find(&exercises).Error; err != nil {
+		return err
+	}
+	for i, exercise := range exercises {
+		if exercise.ExerciseOrder == i+1 {
+			continue
+		}
+		if err := tx.Model(&models.SessionExercise{}).Where("id = ?", exercise.ID).Update("exercise_order", i+1).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renumberExerciseSets numbers an exercise's sets 1..n, keeping their current order
+func renumberExerciseSets(tx *gorm.DB, exerciseID uuid.UUID) error {
+	var sets []models.SessionSet
+	if err := tx.Where("session_exercise_id = ?", exerciseID).Order("set_number ASC, created_at ASC").Find(&sets).Error; err != nil {
+		return err
+	}
+	for i, set := range sets {
+		if set.SetNumber == i+1 {
+			continue
+		}
+		if err := tx.Model(&models.SessionSet{}).Where("id = ?", set.ID).Update("set_number", i+1).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resequenceBlockSets renumbers the order in which a block's sets are performed after its exercises
+// changed: round by round for superset, circuit and giant_set blocks, exercise by exercise otherwise.
+// Time-boxed blocks keep their sequence, which follows the clock.
+func resequenceBlockSets(tx *gorm.DB, blockID uuid.UUID) error {
+	groupType, _, err := blockRestRule(tx, blockID)
+	if err != nil {
+		return err
+	}
+	if utils.IsTimeBoxedType(groupType) {
+		return nil
+	}
+
+	order := "session_exercises.exercise_order ASC, session_sets.set_number ASC"
+	if utils.IsInterleavedType(groupType) {
+		order = "session_sets.round ASC, " + order
+	}
+
+	var sets []models.SessionSet
+	if err := tx.
+		Joins("JOIN session_exercises ON session_exercises.id = session_sets.session_exercise_id").
+		Where("session_exercises.session_block_id = ? AND session_exercises.deleted_at IS NULL", blockID).
+		Order(order).
+		Find(&sets).Error; err != nil {
+		return err
+	}
+	for i, set := range sets {
+		if set.SequenceOrder == i+1 {
+			continue
+		}
+		if err := tx.Model(&models.SessionSet{}).Where("id = ?", set.ID).Update("sequence_order", i+1).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clearPlannedLoads removes the loads filled in from another exercise's prescription
+func clearPlannedLoads(tx *gorm.DB, setIDs []uuid.UUID) error {
+	if len(setIDs) == 0 {
+		return nil
+	}
+	return tx.Model(&models.SessionSet{}).
+		Where("id IN ?", setIDs).
+		Updates(map[string]interface{}{
+			"actual_weight_kg":             nil,
+			"original_actual_weight_value": nil,
+			"original_actual_weight_unit":  nil,
+		}).Error
+}
+
+// isPermutation reports whether ids lists every one of existing exactly once
+func isPermutation(ids []uuid.UUID, existing []uuid.UUID) bool {
+	if len(ids) != len(existing) {
+		return false
+	}
+	remaining := make(map[uuid.UUID]bool, len(existing))
+	for _, id := range existing {
+		remaining[id] = true
+	}
+	for _, id := range ids {
+		if !remaining[id] {
+			return false
+		}
+		delete(remaining, id)
+	}
+	return true
+}
+
+// reloadSessionBlock reloads a block with its exercises and sets in order
+func reloadSessionBlock(block *models.SessionBlock) {
+	database.DB.
+		Preload("SessionExercises", func(db *gorm.DB) *gorm.DB {
+			return db.Order("exercise_order ASC")
+		}).
+		Preload("SessionExercises.Exercise").
+		Preload("SessionExercises.Prescription").
+		Preload("SessionExercises.SessionSets", func(db *gorm.DB) *gorm.DB {
+			return db.Order("set_number ASC")
+		}).
+		Preload("SessionExercises.SessionSets.RPEValue").
+		First(block, "id = ?", block.ID)
+}
+
+// reloadSessionExercise reloads a session exercise with its sets in order
+func reloadSessionExercise(sessionExercise *models.SessionExercise) {
+	*sessionExercise = models.SessionExercise{ID: sessionExercise.ID}
+	database.DB.
+		Preload("Exercise").
+		Preload("Prescription").
+		Preload("SessionSets", func(db *gorm.DB) *gorm.DB {
+			return db.Order("set_number ASC")
+		}).
+		Preload("SessionSets.RPEValue").
+		First(sessionExercise, "id = ?", sessionExercise.ID)
+}
@@ -39,15 +39,16 @@ func GetSessionExercise(c *gin.Context) {
 	}
 
 	// Authorization: check session ownership
-	if !isAuthorizedForSession(sessionExercise.SessionBlock.Session, authUserID) {
+	if !canViewSession(sessionExercise.SessionBlock.Session, authUserID) {
 		utils.NotFoundResponse(c, "Session exercise not found")
 		return
 	}
 
 	// Get user's preferred weight unit for response conversion
 	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+	preferredDistanceUnit := getUserPreferredDistanceUnit(c, authUserID)
 
-	utils.SuccessResponse(c, "Session exercise retrieved successfully", sessionExercise.ToResponse(preferredWeightUnit))
+	utils.SuccessResponse(c, "Session exercise retrieved successfully", sessionExercise.ToResponse(preferredWeightUnit, preferredDistanceUnit))
 }
 
 // CompleteSessionExercise marks a session exercise as complete
@@ -100,8 +101,9 @@ func CompleteSessionExercise(c *gin.Context) {
 
 	// Get user's preferred weight unit for response conversion
 	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+	preferredDistanceUnit := getUserPreferredDistanceUnit(c, authUserID)
 
-	utils.SuccessResponse(c, "Session exercise completed successfully", sessionExercise.ToResponse(preferredWeightUnit))
+	utils.SuccessResponse(c, "Session exercise completed successfully", sessionExercise.ToResponse(preferredWeightUnit, preferredDistanceUnit))
 }
 
 // SkipSessionExercise marks a session exercise as skipped
@@ -153,8 +155,9 @@ func SkipSessionExercise(c *gin.Context) {
 
 	// Get user's preferred weight unit for response conversion
 	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+	preferredDistanceUnit := getUserPreferredDistanceUnit(c, authUserID)
 
-	utils.SuccessResponse(c, "Session exercise skipped successfully", sessionExercise.ToResponse(preferredWeightUnit))
+	utils.SuccessResponse(c, "Session exercise skipped successfully", sessionExercise.ToResponse(preferredWeightUnit, preferredDistanceUnit))
 }
 
 // UpdateSessionExerciseNotes updates the notes for a session exercise
@@ -212,8 +215,9 @@ func UpdateSessionExerciseNotes(c *gin.Context) {
 
 	// Get user's preferred weight unit for response conversion
 	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+	preferredDistanceUnit := getUserPreferredDistanceUnit(c, authUserID)
 
-	utils.SuccessResponse(c, "Session exercise updated successfully", sessionExercise.ToResponse(preferredWeightUnit))
+	utils.SuccessResponse(c, "Session exercise updated successfully", sessionExercise.ToResponse(preferredWeightUnit, preferredDistanceUnit))
 }
 
 // AddSetToExercise adds a new set to a session exercise
@@ -255,8 +259,24 @@ func AddSetToExercise(c *gin.Context) {
 		return
 	}
 
-	// Determine next set number
+	// Determine next set number and round, and place the set after everything else in the block
 	nextSetNumber := len(sessionExercise.SessionSets) + 1
+	nextRound := 1
+	for _, existing := range sessionExercise.SessionSets {
+		if existing.Round >= nextRound {
+			nextRound = existing.Round + 1
+		}
+	}
+
+	var lastSequenceOrder int
+	if err := database.DB.Model(&models.SessionSet{}).
+		Joins("JOIN session_exercises ON session_exercises.id = session_sets.session_exercise_id").
+		Where("session_exercises.session_block_id = ?", sessionExercise.SessionBlockID).
+		Select("COALESCE(MAX(session_sets.sequence_order), 0)").
+		Scan(&lastSequenceOrder).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to add set")
+		return
+	}
 
 	// Process weight input using new unified weight system
 	actualWeightKg, originalValue, originalUnit := utils.ProcessWeightInput(req.ActualWeight)
@@ -264,6 +284,8 @@ func AddSetToExercise(c *gin.Context) {
 	set := models.SessionSet{
 		SessionExerciseID:         sessionExercise.ID,
 		SetNumber:                 nextSetNumber,
+		Round:                     nextRound,
+		SequenceOrder:             lastSequenceOrder + 1,
 		Completed:                 false,
 		ActualReps:                req.ActualReps,
 		ActualWeightKg:            actualWeightKg,
@@ -278,6 +300,7 @@ func AddSetToExercise(c *gin.Context) {
 	if req.Notes != nil {
 		set.Notes = *req.Notes
 	}
+	applyCardioActuals(&set, req.CardioActualsRequest)
 
 	if err := database.DB.Create(&set).Error; err != nil {
 		utils.InternalServerErrorResponse(c, "Failed to add set")
@@ -289,6 +312,7 @@ func AddSetToExercise(c *gin.Context) {
 
 	// Get user's preferred weight unit for response conversion
 	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+	preferredDistanceUnit := getUserPreferredDistanceUnit(c, authUserID)
 
-	utils.CreatedResponse(c, "Set added successfully", set.ToResponse(preferredWeightUnit))
+	utils.CreatedResponse(c, "Set added successfully", set.ToResponse(preferredWeightUnit, preferredDistanceUnit))
 }
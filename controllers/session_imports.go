@@ -0,0 +1,299 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"lamari-fit-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxActivityFileBytes caps the size of uploaded activity files
+const maxActivityFileBytes = 20 << 20
+
+// activityDuplicateWindow is how close two activity start times must be to count as the same
+// activity. Exports of one recording from different apps can disagree by a few seconds.
+const activityDuplicateWindow = time.Minute
+
+// errActivityAlreadyImported is returned when an activity starting at the same time was imported
+var errActivityAlreadyImported = errors.New("activity already imported")
+
+// ImportWorkoutSession creates a completed workout session from a GPX, TCX or FIT activity file.
+// Activities already imported (same start time) are rejected with the existing session's ID.
+// If exercise_id is given, the activity is also logged as a completed set of that exercise so it
+// shows up in the exercise's history.
+func ImportWorkoutSession(c *gin.Context) {
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.ImportActivityRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	if req.File.Size > maxActivityFileBytes {
+		utils.BadRequestResponse(c, "Activity file is too large (20 MB max)", nil)
+		return
+	}
+
+	var exerciseID *uuid.UUID
+	if req.ExerciseID != "" {
+		id, ok := utils.ParseUUID(c, req.ExerciseID, "exercise")
+		if !ok {
+			return
+		}
+		var exercise models.Exercise
+		if err := database.DB.Select("id").First(&exercise, "id = ?", id).Error; err != nil {
+			utils.ValidationErrorResponse(c, utils.ValidationErrors{"exercise_id": {"Exercise not found"}})
+			return
+		}
+		exerciseID = &id
+	}
+
+	file, err := req.File.Open()
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to read activity file", nil)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxActivityFileBytes))
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to read activity file", nil)
+		return
+	}
+
+	parsed, err := utils.ParseActivityFile(req.File.Filename, data)
+	if errors.Is(err, utils.ErrUnsupportedActivityFormat) {
+		utils.BadRequestResponse(c, "Unsupported activity file, expected GPX, TCX or FIT", nil)
+		return
+	}
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid activity file: "+err.Error(), nil)
+		return
+	}
+
+	var session models.WorkoutSession
+	var existing models.SessionActivity
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// Lock the user's row so concurrent imports of the same file can't both pass the
+		// duplicate check. A unique index can't express the start time window.
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&models.User{}, "id = ?", authUserID).Error; err != nil {
+			return err
+		}
+
+		// De-duplicate by start time
+		err := tx.
+			Joins("JOIN workout_sessions ON workout_sessions.id = session_activities.session_id AND workout_sessions.deleted_at IS NULL").
+			Where("session_activities.user_id = ? AND session_activities.started_at BETWEEN ? AND ?",
+				authUserID, parsed.StartedAt.Add(-activityDuplicateWindow), parsed.StartedAt.Add(activityDuplicateWindow)).
+			First(&existing).Error
+		if err == nil {
+			return errActivityAlreadyImported
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		session, err = createImportedSession(tx, authUserID, parsed, exerciseID, req)
+		return err
+	})
+	if errors.Is(err, errActivityAlreadyImported) {
+		utils.ErrorResponse(c, http.StatusConflict, "Activity already imported", gin.H{"session_id": existing.SessionID})
+		return
+	}
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to import activity")
+		return
+	}
+
+	loadSessionWithActivity(&session)
+
+	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+	preferredDistanceUnit := getUserPreferredDistanceUnit(c, authUserID)
+
+	utils.CreatedResponse(c, "Activity imported successfully", models.BuildSessionResponse(session, preferredWeightUnit, preferredDistanceUnit))
+}
+
+// createImportedSession stores a parsed activity as a completed session with its track, and
+// optionally a single completed set of the given exercise
+func createImportedSession(tx *gorm.DB, userID uuid.UUID, parsed *utils.ParsedActivity, exerciseID *uuid.UUID, req models.ImportActivityRequest) (models.WorkoutSession, error) {
+	duration := parsed.DurationSeconds
+	endedAt := parsed.EndedAt
+	session := models.WorkoutSession{
+		UserID:          userID,
+		CreatedByID:     &userID,
+		StartedAt:       parsed.StartedAt,
+		EndedAt:         &endedAt,
+		DurationSeconds: &duration,
+		Notes:           req.Notes,
+		Completed:       true,
+	}
+	if err := tx.Create(&session).Error; err != nil {
+		return session, err
+	}
+
+	if exerciseID != nil {
+		block := models.SessionBlock{
+			SessionID:   session.ID,
+			GroupID:     uuid.New(),
+			BlockOrder:  1,
+			StartedAt:   &session.StartedAt,
+			CompletedAt: &endedAt,
+		}
+		if err := tx.Create(&block).Error; err != nil {
+			return session, err
+		}
+
+		exercise := models.SessionExercise{
+			SessionBlockID: block.ID,
+			ExerciseID:     *exerciseID,
+			ExerciseOrder:  1,
+			StartedAt:      &session.StartedAt,
+			CompletedAt:    &endedAt,
+		}
+		if err := tx.Create(&exercise).Error; err != nil {
+			return session, err
+		}
+
+		set := models.SessionSet{
+			SessionExerciseID:      exercise.ID,
+			SetNumber:              1,
+			Round:                  1,
+			SequenceOrder:          1,
+			Completed:              true,
+			StartedAt:              &session.StartedAt,
+			CompletedAt:            &endedAt,
+			ActualDurationSeconds:  &duration,
+			ActualDistanceMeters:   parsed.DistanceMeters,
+			ActualAverageHeartRate: parsed.AverageHeartRate,
+			ActualMaxHeartRate:     parsed.MaxHeartRate,
+		}
+		if parsed.DistanceMeters != nil {
+			unit := "m"
+			set.OriginalActualDistanceValue = parsed.DistanceMeters
+			set.OriginalActualDistanceUnit = &unit
+		}
+		if err := tx.Create(&set).Error; err != nil {
+			return session, err
+		}
+	}
+
+	activity := models.SessionActivity{
+		SessionID:           session.ID,
+		UserID:              userID,
+		StartedAt:           parsed.StartedAt,
+		Source:              parsed.Format,
+		Sport:               parsed.Sport,
+		FileName:            req.File.Filename,
+		DurationSeconds:     parsed.DurationSeconds,
+		DistanceMeters:      parsed.DistanceMeters,
+		ElevationGainMeters: parsed.ElevationGainMeters,
+		ElevationLossMeters: parsed.ElevationLossMeters,
+		AverageHeartRate:    parsed.AverageHeartRate,
+		MaxHeartRate:        parsed.MaxHeartRate,
+	}
+	if err := tx.Create(&activity).Error; err != nil {
+		return session, err
+	}
+
+	samples := make([]models.ActivitySample, 0, len(parsed.Points))
+	for _, point := range parsed.Points {
+		samples = append(samples, models.ActivitySample{
+			ActivityID:      activity.ID,
+			OffsetSeconds:   int(point.Time.Sub(parsed.StartedAt).Seconds()),
+			DistanceMeters:  point.DistanceMeters,
+			ElevationMeters: point.ElevationMeters,
+			HeartRate:       point.HeartRate,
+		})
+	}
+	if len(samples) > 0 {
+		if err := tx.CreateInBatches(&samples, 500).Error; err != nil {
+			return session, err
+		}
+	}
+
+	return session, nil
+}
+
+// loadSessionWithActivity reloads a session with its blocks and activity summary for a response
+func loadSessionWithActivity(session *models.WorkoutSession) {
+	database.DB.
+		Preload("User").
+		Preload("CreatedBy").
+		Preload("Workout").
+		Preload("Activity").
+		Preload("SessionBlocks.SessionExercises.Exercise").
+		Preload("SessionBlocks.SessionExercises.Prescription").
+		Preload("SessionBlocks.SessionExercises.SessionSets.RPEValue").
+		First(session, "id = ?", session.ID)
+}
+
+// GetSessionActivity retrieves the recorded track of a session imported from an activity file
+func GetSessionActivity(c *gin.Context) {
+	var params IDParam
+	if err := c.ShouldBindUri(&params); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	sessionID, ok := utils.ParseUUID(c, params.ID, "workout session")
+	if !ok {
+		return
+	}
+
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	var session models.WorkoutSession
+	if err := database.DB.First(&session, "id = ?", sessionID).Error; err != nil {
+		utils.NotFoundResponse(c, "Workout session not found")
+		return
+	}
+
+	if !canViewSession(session, authUserID) {
+		utils.NotFoundResponse(c, "Workout session not found")
+		return
+	}
+
+	// Get distance unit from query param, default to the user's preference
+	distanceUnit := c.Query("distance_unit")
+	if distanceUnit != "km" && distanceUnit != "mi" {
+		distanceUnit = getUserPreferredDistanceUnit(c, authUserID)
+	}
+
+	var activity models.SessionActivity
+	if err := database.DB.
+		Preload("Samples", func(db *gorm.DB) *gorm.DB {
+			return db.Order("offset_seconds ASC")
+		}).
+		First(&activity, "session_id = ?", session.ID).Error; err != nil {
+		utils.NotFoundResponse(c, "Session has no recorded activity")
+		return
+	}
+
+	response := models.SessionActivityDetailResponse{
+		SessionActivityResponse: activity.ToResponse(distanceUnit),
+		Samples:                 make([]models.ActivitySampleResponse, 0, len(activity.Samples)),
+	}
+	for _, sample := range activity.Samples {
+		response.Samples = append(response.Samples, sample.ToResponse(distanceUnit))
+	}
+
+	utils.SuccessResponse(c, "Session activity retrieved successfully", response)
+}
@@ -4,8 +4,10 @@ import (
 	"lamari-fit-api/database"
 	"lamari-fit-api/models"
 	"lamari-fit-api/utils"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // GetSessionSet retrieves a single session set
@@ -36,15 +38,16 @@ func GetSessionSet(c *gin.Context) {
 	}
 
 	// Authorization: check session ownership
-	if !isAuthorizedForSession(set.SessionExercise.SessionBlock.Session, authUserID) {
+	if !canViewSession(set.SessionExercise.SessionBlock.Session, authUserID) {
 		utils.NotFoundResponse(c, "Session set not found")
 		return
 	}
 
 	// Get user's preferred weight unit for response conversion
 	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+	preferredDistanceUnit := getUserPreferredDistanceUnit(c, authUserID)
 
-	utils.SuccessResponse(c, "Session set retrieved successfully", set.ToResponse(preferredWeightUnit))
+	utils.SuccessResponse(c, "Session set retrieved successfully", set.ToResponse(preferredWeightUnit, preferredDistanceUnit))
 }
 
 // UpdateSessionSet updates a session set
@@ -79,6 +82,8 @@ func UpdateSessionSet(c *gin.Context) {
 		return
 	}
 
+	wasCompleted := set.Completed
+
 	var req models.UpdateSessionSetRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.HandleBindingError(c, err)
@@ -102,6 +107,8 @@ func UpdateSessionSet(c *gin.Context) {
 		set.ActualDurationSeconds = req.ActualDurationSeconds
 	}
 
+	applyCardioActuals(&set, req.CardioActualsRequest)
+
 	if req.RPEValueID != nil {
 		set.RPEValueID = req.RPEValueID
 	}
@@ -118,7 +125,42 @@ func UpdateSessionSet(c *gin.Context) {
 		set.Completed = *req.Completed
 	}
 
-	if err := database.DB.Save(&set).Error; err != nil {
+	if req.StartedAt != nil {
+		set.StartedAt = req.StartedAt
+	}
+
+	if req.CompletedAt != nil {
+		set.CompletedAt = req.CompletedAt
+	}
+
+	// Keep completion time and rest in step with the completed flag
+	if set.Completed && set.CompletedAt == nil {
+		now := time.Now()
+		set.CompletedAt = &now
+	}
+	if !set.Completed {
+		set.CompletedAt = nil
+	}
+
+	if !validateSetTimes(c, set) {
+		return
+	}
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := recordSetRest(tx, &set); err != nil {
+			return err
+		}
+		if err := tx.Save(&set).Error; err != nil {
+			return err
+		}
+
+		// The edit may raise, lower or remove a record held by this set
+		if wasCompleted || set.Completed {
+			session := set.SessionExercise.SessionBlock.Session
+			return recomputePersonalRecordsForExercise(tx, session.UserID, set.SessionExercise.ExerciseID)
+		}
+		return nil
+	}); err != nil {
 		utils.InternalServerErrorResponse(c, "Failed to update session set")
 		return
 	}
@@ -128,8 +170,9 @@ func UpdateSessionSet(c *gin.Context) {
 
 	// Get user's preferred weight unit for response conversion
 	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+	preferredDistanceUnit := getUserPreferredDistanceUnit(c, authUserID)
 
-	utils.SuccessResponse(c, "Session set updated successfully", set.ToResponse(preferredWeightUnit))
+	utils.SuccessResponse(c, "Session set updated successfully", set.ToResponse(preferredWeightUnit, preferredDistanceUnit))
 }
 
 // CompleteSessionSet marks a session set as complete
@@ -184,6 +227,8 @@ func CompleteSessionSet(c *gin.Context) {
 			set.ActualDurationSeconds = req.ActualDurationSeconds
 		}
 
+		applyCardioActuals(&set, req.CardioActualsRequest)
+
 		if req.RPEValueID != nil {
 			set.RPEValueID = req.RPEValueID
 		}
@@ -195,12 +240,54 @@ func CompleteSessionSet(c *gin.Context) {
 		if req.Notes != nil {
 			set.Notes = *req.Notes
 		}
+
+		if req.StartedAt != nil {
+			set.StartedAt = req.StartedAt
+		}
+
+		set.CompletedAt = req.CompletedAt
 	}
 
 	// Mark as completed
 	set.Completed = true
+	if set.CompletedAt == nil {
+		now := time.Now()
+		set.CompletedAt = &now
+	}
+
+	if !validateSetTimes(c, set) {
+		return
+	}
+
+	session := set.SessionExercise.SessionBlock.Session
+	exerciseID := set.SessionExercise.ExerciseID
+
+	// Save the set and check it against the user's personal records
+	var newRecords []models.PersonalRecord
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := recordSetRest(tx, &set); err != nil {
+			return err
+		}
+
+		if err := tx.Save(&set).Error; err != nil {
+			return err
+		}
 
-	if err := database.DB.Save(&set).Error; err != nil {
+		startedAt := *set.CompletedAt
+		if set.StartedAt != nil {
+			startedAt = *set.StartedAt
+		}
+		if err := markSetStarted(tx, set, startedAt); err != nil {
+			return err
+		}
+
+		records, err := updatePersonalRecordsForSet(tx, session.UserID, session.ID, exerciseID, set, time.Now())
+		if err != nil {
+			return err
+		}
+		newRecords = records
+		return nil
+	}); err != nil {
 		utils.InternalServerErrorResponse(c, "Failed to complete session set")
 		return
 	}
@@ -210,8 +297,27 @@ func CompleteSessionSet(c *gin.Context) {
 
 	// Get user's preferred weight unit for response conversion
 	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+	preferredDistanceUnit := getUserPreferredDistanceUnit(c, authUserID)
+
+	response := set.ToResponse(preferredWeightUnit, preferredDistanceUnit)
+	if len(newRecords) > 0 {
+		var exercise models.Exercise
+		database.DB.Select("id", "name").First(&exercise, "id = ?", exerciseID)
+		for i := range newRecords {
+			newRecords[i].Exercise = exercise
+		}
+		response.NewRecords = buildPersonalRecordResponses(newRecords, preferredWeightUnit)
+	}
 
-	utils.SuccessResponse(c, "Session set completed successfully", set.ToResponse(preferredWeightUnit))
+	// Tell the client what comes next and when, so it can run the rest timer
+	nextSet, err := findNextSetHint(database.DB, set, session.ID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to determine next set")
+		return
+	}
+	response.NextSet = nextSet
+
+	utils.SuccessResponse(c, "Session set completed successfully", response)
 }
 
 // DeleteSessionSet deletes a session set
@@ -246,7 +352,18 @@ func DeleteSessionSet(c *gin.Context) {
 		return
 	}
 
-	if err := database.DB.Delete(&set).Error; err != nil {
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&set).Error; err != nil {
+			return err
+		}
+
+		// Drop or replace any record the deleted set held
+		if set.Completed {
+			session := set.SessionExercise.SessionBlock.Session
+			return recomputePersonalRecordsForExercise(tx, session.UserID, set.SessionExercise.ExerciseID)
+		}
+		return nil
+	}); err != nil {
 		utils.InternalServerErrorResponse(c, "Failed to delete session set")
 		return
 	}
@@ -262,3 +379,23 @@ func DeleteSessionSet(c *gin.Context) {
 
 	utils.NoContentResponse(c)
 }
+
+// applyCardioActuals updates the distance, heart rate and incline logged for a set with the fields
+// provided in a request
+func applyCardioActuals(set *models.SessionSet, req models.CardioActualsRequest) {
+	if req.ActualDistance != nil {
+		actualMeters, originalValue, originalUnit := utils.ProcessDistanceInput(req.ActualDistance)
+		set.ActualDistanceMeters = actualMeters
+		set.OriginalActualDistanceValue = originalValue
+		set.OriginalActualDistanceUnit = originalUnit
+	}
+	if req.ActualAverageHeartRate != nil {
+		set.ActualAverageHeartRate = req.ActualAverageHeartRate
+	}
+	if req.ActualMaxHeartRate != nil {
+		set.ActualMaxHeartRate = req.ActualMaxHeartRate
+	}
+	if req.ActualInclinePercent != nil {
+		set.ActualInclinePercent = req.ActualInclinePercent
+	}
+}
@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"errors"
+	"time"
+
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"lamari-fit-api/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// buildSessionSummary summarizes the completed sets, block timing and estimated energy expenditure
+// of an ended session. EstimatedCalories is nil if the energy expenditure can't be estimated.
+func buildSessionSummary(session models.WorkoutSession, newRecordCount int, preferredWeightUnit string) (*models.SessionSummaryResponse, error) {
+	var rows []completedSetRow
+	if err := completedSetsQuery(session.UserID, nil, nil).
+		Where("workout_sessions.id = ?", session.ID).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	linksByExercise, err := muscleGroupLinksForRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	var blocks []models.SessionBlock
+	if err := database.DB.
+		Where("session_id = ?", session.ID).
+		Preload("SessionExercises").
+		Preload("SessionExercises.SessionSets").
+		Order("block_order ASC").
+		Find(&blocks).Error; err != nil {
+		return nil, err
+	}
+
+	summary := &models.SessionSummaryResponse{
+		TotalSets:      len(rows),
+		NewRecordCount: newRecordCount,
+		MuscleGroups:   []models.MuscleGroupVolume{},
+		Blocks:         []models.BlockDurationResponse{},
+	}
+
+	tonnageKg := 0.0
+	for _, row := range rows {
+		if row.ActualReps == nil {
+			continue
+		}
+		summary.TotalReps += *row.ActualReps
+		if row.ActualWeightKg != nil {
+			tonnageKg += *row.ActualWeightKg * float64(*row.ActualReps)
+		}
+	}
+	summary.Tonnage = utils.ConvertWeightForResponse(&tonnageKg, preferredWeightUnit)
+
+	// A single session falls in a single week
+	weeks := buildMuscleVolumeWeeks(rows, linksByExercise, session.StartedAt, session.StartedAt, defaultSecondaryMuscleWeight, preferredWeightUnit)
+	if len(weeks) > 0 {
+		summary.MuscleGroups = weeks[0].MuscleGroups
+	}
+
+	for _, block := range blocks {
+		summary.Blocks = append(summary.Blocks, models.BlockDurationResponse{
+			SessionBlockID:  block.ID,
+			BlockOrder:      block.BlockOrder,
+			Skipped:         block.Skipped,
+			DurationSeconds: blockDurationSeconds(block),
+		})
+	}
+
+	// Calories are an estimate on top of the summary, so the summary is returned without them when
+	// the body weight or exercise types can't be loaded
+	bodyWeightKg, err := bodyWeightAt(session.UserID, session.StartedAt)
+	if err != nil || bodyWeightKg == nil || session.DurationSeconds == nil {
+		return summary, nil
+	}
+
+	energy, err := sessionBlockEnergy(blocks)
+	if err != nil {
+		return summary, nil
+	}
+	calories := utils.EstimateSessionCalories(energy, *session.DurationSeconds, *bodyWeightKg)
+	summary.BodyWeight = utils.ConvertWeightForResponse(bodyWeightKg, preferredWeightUnit)
+	summary.EstimatedCalories = &calories
+
+	return summary, nil
+}
+
+// blockDurationSeconds returns the time spent on a block, from its start to its completion
+// (or its last completed set), or nil if the block wasn't timed
+func blockDurationSeconds(block models.SessionBlock) *int {
+	if block.StartedAt == nil {
+		return nil
+	}
+
+	end := block.CompletedAt
+	if end == nil {
+		for _, exercise := range block.SessionExercises {
+			for _, set := range exercise.SessionSets {
+				if set.CompletedAt != nil && (end == nil || set.CompletedAt.After(*end)) {
+					end = set.CompletedAt
+				}
+			}
+		}
+	}
+	if end == nil || end.Before(*block.StartedAt) {
+		return nil
+	}
+
+	seconds := int(end.Sub(*block.StartedAt).Seconds())
+	return &seconds
+}
+
+// sessionBlockEnergy returns the timing and MET of each block. A block's MET is the average MET of
+// its exercises with completed sets, from their exercise types.
+func sessionBlockEnergy(blocks []models.SessionBlock) ([]utils.BlockEnergy, error) {
+	var exerciseIDs []uuid.UUID
+	for _, block := range blocks {
+		for _, exercise := range block.SessionExercises {
+			exerciseIDs = append(exerciseIDs, exercise.ExerciseID)
+		}
+	}
+
+	typeSlugs := make(map[uuid.UUID][]string)
+	if len(exerciseIDs) > 0 {
+		var links []models.ExerciseExerciseType
+		if err := database.DB.
+			Preload("ExerciseType").
+			Where("exercise_id IN ?", exerciseIDs).
+			Find(&links).Error; err != nil {
+			return nil, err
+		}
+		for _, link := range links {
+			typeSlugs[link.ExerciseID] = append(typeSlugs[link.ExerciseID], link.ExerciseType.Slug)
+		}
+	}
+
+	energy := make([]utils.BlockEnergy, 0, len(blocks))
+	for _, block := range blocks {
+		totalMET := 0.0
+		worked := 0
+		for _, exercise := range block.SessionExercises {
+			if exercise.Skipped || !hasCompletedSet(exercise) {
+				continue
+			}
+			totalMET += utils.ExerciseMET(typeSlugs[exercise.ExerciseID])
+			worked++
+		}
+
+		blockEnergy := utils.BlockEnergy{}
+		if worked > 0 {
+			blockEnergy.MET = totalMET / float64(worked)
+		}
+		if seconds := blockDurationSeconds(block); seconds != nil {
+			blockEnergy.Seconds = *seconds
+		}
+		energy = append(energy, blockEnergy)
+	}
+
+	return energy, nil
+}
+
+// hasCompletedSet reports whether any set of the exercise was completed
+func hasCompletedSet(exercise models.SessionExercise) bool {
+	for _, set := range exercise.SessionSets {
+		if set.Completed {
+			return true
+		}
+	}
+	return false
+}
+
+// bodyWeightAt returns the user's body weight in kg at a point in time: the latest weight log up to
+// then, or the current weight of their fitness profile. Returns nil if neither is known.
+func bodyWeightAt(userID uuid.UUID, at time.Time) (*float64, error) {
+	var log models.WeightLog
+	err := database.DB.
+		Where("user_id = ? AND created_at <= ?", userID, at).
+		Order("created_at DESC").
+		First(&log).Error
+	if err == nil {
+		return &log.WeightKg, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var profile models.UserFitnessProfile
+	err = database.DB.Where("user_id = ?", userID).First(&profile).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return profile.CurrentWeightKg, nil
+}
@@ -0,0 +1,571 @@
+package controllers
+
+import (
+	"errors"
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"lamari-fit-api/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SyncWorkoutSession applies a session tree logged offline (blocks, exercises and sets with
+// client-generated IDs) in one transaction. Entities are created if new and updated if the client
+// copy is newer; a server copy modified after the client copy is kept and reported as a conflict.
+// Sending the same payload again changes nothing.
+func SyncWorkoutSession(c *gin.Context) {
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.SyncWorkoutSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	if !validateSyncTree(c, req) {
+		return
+	}
+
+	var session models.WorkoutSession
+	err := database.DB.Unscoped().First(&session, "id = ?", req.ID).Error
+	isNew := errors.Is(err, gorm.ErrRecordNotFound)
+	switch {
+	case isNew:
+		// Determine target user (who the session is for)
+		targetUserID := authUserID
+		if req.UserID != nil && *req.UserID != authUserID {
+			if !hasClientConsent(authUserID, *req.UserID, models.ConsentScopeLogSessions) {
+				utils.ForbiddenResponse(c, "Not authorized to create sessions for this user")
+				return
+			}
+			targetUserID = *req.UserID
+		}
+		session = models.WorkoutSession{ID: req.ID, UserID: targetUserID, CreatedByID: &authUserID}
+	case err != nil:
+		utils.InternalServerErrorResponse(c, "Failed to sync workout session")
+		return
+	case !isAuthorizedForSession(session, authUserID):
+		utils.ForbiddenResponse(c, "Not authorized to sync this session")
+		return
+	case session.DeletedAt.Valid:
+		utils.ConflictResponse(c, "Workout session has been deleted")
+		return
+	}
+
+	if req.WorkoutID != nil {
+		var workout models.Workout
+		if err := database.DB.Select("id").First(&workout, "id = ?", *req.WorkoutID).Error; err != nil {
+			utils.NotFoundResponse(c, "Workout not found")
+			return
+		}
+	}
+
+	wasCompleted := session.Completed
+	sync := &sessionSync{
+		result:             models.SyncWorkoutSessionResponse{Conflicts: []models.SyncConflict{}},
+		recomputeExercises: map[uuid.UUID]bool{},
+	}
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		sync.tx = tx
+		if err := sync.syncSession(&session, req, isNew); err != nil {
+			return err
+		}
+		for _, blockReq := range req.Blocks {
+			if err := sync.syncBlock(session.ID, blockReq); err != nil {
+				return err
+			}
+		}
+		if err := sync.recordRest(); err != nil {
+			return err
+		}
+
+		// Pick up personal records and, once the session has ended, advance its plan enrollment.
+		// Exercises whose sets were edited are rebuilt, as an edit can lower a record.
+		if err := updatePersonalRecordsForSession(tx, session); err != nil {
+			return err
+		}
+		for exerciseID := range sync.recomputeExercises {
+			if err := recomputePersonalRecordsForExercise(tx, session.UserID, exerciseID); err != nil {
+				return err
+			}
+		}
+		if !session.Completed || wasCompleted {
+			return nil
+		}
+		return advanceEnrollmentsForSession(tx, session)
+	}); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to sync workout session")
+		return
+	}
+
+	// Reload with full relationships
+	database.DB.
+		Preload("User").
+		Preload("CreatedBy").
+		Preload("Workout").
+		Preload("SessionBlocks", func(db *gorm.DB) *gorm.DB {
+			return db.Order("block_order ASC")
+		}).
+		Preload("SessionBlocks.SessionExercises", func(db *gorm.DB) *gorm.DB {
+			return db.Order("exercise_order ASC")
+		}).
+		Preload("SessionBlocks.SessionExercises.Exercise").
+		Preload("SessionBlocks.SessionExercises.Prescription").
+		Preload("SessionBlocks.SessionExercises.SessionSets", func(db *gorm.DB) *gorm.DB {
+			return db.Order("set_number ASC")
+		}).
+		Preload("SessionBlocks.SessionExercises.SessionSets.RPEValue").
+		First(&session, "id = ?", session.ID)
+
+	sync.result.Session = models.BuildSessionResponse(session, getUserPreferredWeightUnit(c, authUserID), getUserPreferredDistanceUnit(c, authUserID))
+	utils.SuccessResponse(c, "Workout session synced successfully", sync.result)
+}
+
+// validateSyncTree checks a session tree before anything is written: IDs must be unique, times
+// must be in order, and every exercise, prescription and RPE value must exist. Automatically sends
+// a validation error response.
+func validateSyncTree(c *gin.Context, req models.SyncWorkoutSessionRequest) bool {
+	validationErrors := utils.ValidationErrors{}
+	if req.EndedAt != nil && req.EndedAt.Before(req.StartedAt) {
+		validationErrors["ended_at"] = []string{"Ended at cannot be before started at"}
+	}
+
+	seen := map[uuid.UUID]bool{req.ID: true}
+	unique := func(id uuid.UUID) {
+		if seen[id] {
+			validationErrors["id"] = []string{"IDs must be unique within the session tree"}
+		}
+		seen[id] = true
+	}
+
+	exerciseIDs := map[uuid.UUID]bool{}
+	prescriptionRefs := map[uuid.UUID][]uuid.UUID{} // prescription ID -> session exercises using it
+	rpeValueRefs := map[uuid.UUID][]uuid.UUID{}     // RPE value ID -> sets using it
+	for _, block := range req.Blocks {
+		unique(block.ID)
+		for _, exercise := range block.Exercises {
+			unique(exercise.ID)
+			exerciseIDs[exercise.ExerciseID] = true
+			if exercise.PrescriptionID != nil {
+				prescriptionRefs[*exercise.PrescriptionID] = append(prescriptionRefs[*exercise.PrescriptionID], exercise.ID)
+			}
+			for _, set := range exercise.Sets {
+				unique(set.ID)
+				if set.RPEValueID != nil {
+					rpeValueRefs[*set.RPEValueID] = append(rpeValueRefs[*set.RPEValueID], set.ID)
+				}
+				if set.StartedAt != nil && set.CompletedAt != nil && set.CompletedAt.Before(*set.StartedAt) {
+					validationErrors["sets.completed_at"] = []string{"Completed time cannot be before start time"}
+				}
+			}
+		}
+	}
+
+	if len(exerciseIDs) > 0 {
+		ids := make([]uuid.UUID, 0, len(exerciseIDs))
+		for id := range exerciseIDs {
+			ids = append(ids, id)
+		}
+		var count int64
+		if err := database.DB.Model(&models.Exercise{}).Where("id IN ?", ids).Count(&count).Error; err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to sync workout session")
+			return false
+		}
+		if int(count) != len(ids) {
+			validationErrors["exercise_id"] = []string{"Exercise not found"}
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		utils.ValidationErrorResponse(c, validationErrors)
+		return false
+	}
+
+	// References to prescriptions and RPE values are checked before the transaction so that a
+	// bad reference names the entity using it instead of failing the whole sync on insert
+	referenceErrors := utils.ValidationErrors{}
+	if err := checkSyncReferences(&models.WorkoutPrescription{}, prescriptionRefs, func(id uuid.UUID) {
+		referenceErrors["exercises."+id.String()+".prescription_id"] = []string{"Prescription not found"}
+	}); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to sync workout session")
+		return false
+	}
+	if err := checkSyncReferences(&models.RPEScaleValue{}, rpeValueRefs, func(id uuid.UUID) {
+		referenceErrors["sets."+id.String()+".rpe_value_id"] = []string{"RPE value not found"}
+	}); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to sync workout session")
+		return false
+	}
+
+	if len(referenceErrors) > 0 {
+		utils.ErrorResponse(c, http.StatusUnprocessableEntity, "Synced session references records that do not exist", referenceErrors)
+		return false
+	}
+	return true
+}
+
+// checkSyncReferences looks up the referenced IDs of a model and calls missing with each entity
+// of the session tree whose reference does not exist
+func checkSyncReferences(model interface{}, refs map[uuid.UUID][]uuid.UUID, missing func(uuid.UUID)) error {
+	if len(refs) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(refs))
+	for id := range refs {
+		ids = append(ids, id)
+	}
+
+	var found []uuid.UUID
+	if err := database.DB.Model(model).Where("id IN ?", ids).Pluck("id", &found).Error; err != nil {
+		return err
+	}
+
+	exists := make(map[uuid.UUID]bool, len(found))
+	for _, id := range found {
+		exists[id] = true
+	}
+	for id, entities := range refs {
+		if exists[id] {
+			continue
+		}
+		for _, entityID := range entities {
+			missing(entityID)
+		}
+	}
+	return nil
+}
+
+// sessionSync applies one session tree and collects the outcome
+type sessionSync struct {
+	tx       *gorm.DB
+	result   models.SyncWorkoutSessionResponse
+	restSets []uuid.UUID // completed sets whose rest needs recording
+
+	// Exercises whose existing sets changed, which can lower or remove records
+	recomputeExercises map[uuid.UUID]bool
+}
+
+// compareSyncTimes compares a stored modification time with a client one, treating times within
+// a microsecond (the database's precision) as equal
+func compareSyncTimes(server time.Time, client time.Time) int {
+	diff := server.Sub(client)
+	switch {
+	case diff > time.Microsecond:
+		return 1
+	case diff < -time.Microsecond:
+		return -1
+	}
+	return 0
+}
+
+// conflict records an entity that was not applied
+func (s *sessionSync) conflict(entity string, id uuid.UUID, reason string, serverUpdatedAt *time.Time, clientUpdatedAt time.Time) {
+	s.result.Conflicts = append(s.result.Conflicts, models.SyncConflict{
+		Entity:          entity,
+		ID:              id,
+		Reason:          reason,
+		ServerUpdatedAt: serverUpdatedAt,
+		ClientUpdatedAt: clientUpdatedAt,
+	})
+}
+
+// update applies the client's columns to an existing entity, unless the server copy is the same
+// version or newer. The client's modification time is stored so a repeated sync is a no-op.
+// Returns whether the columns were written.
+func (s *sessionSync) update(model interface{}, entity string, id uuid.UUID, serverUpdatedAt time.Time, clientUpdatedAt time.Time, columns map[string]interface{}) (bool, error) {
+	switch compareSyncTimes(serverUpdatedAt, clientUpdatedAt) {
+	case 1:
+		s.conflict(entity, id, models.SyncConflictServerNewer, &serverUpdatedAt, clientUpdatedAt)
+		return false, nil
+	case 0:
+		s.result.Unchanged++
+		return false, nil
+	}
+
+	columns["updated_at"] = clientUpdatedAt
+	if err := s.tx.Model(model).UpdateColumns(columns).Error; err != nil {
+		return false, err
+	}
+	s.result.Updated++
+	return true, nil
+}
+
+// syncSession creates or updates the session itself. A session is only ever ended by a sync,
+// never reopened.
+func (s *sessionSync) syncSession(session *models.WorkoutSession, req models.SyncWorkoutSessionRequest, isNew bool) error {
+	if isNew {
+		session.WorkoutID = req.WorkoutID
+		session.StartedAt = req.StartedAt
+		session.PerceivedIntensity = req.PerceivedIntensity
+		session.Notes = req.Notes
+		session.UpdatedAt = req.ClientUpdatedAt
+		if req.EndedAt != nil {
+			duration := int(req.EndedAt.Sub(req.StartedAt).Seconds())
+			session.EndedAt = req.EndedAt
+			session.DurationSeconds = &duration
+			session.Completed = true
+		}
+		if err := s.tx.Create(session).Error; err != nil {
+			return err
+		}
+		s.result.Created++
+		return nil
+	}
+
+	columns := map[string]interface{}{
+		"workout_id":          req.WorkoutID,
+		"started_at":          req.StartedAt,
+		"perceived_intensity": req.PerceivedIntensity,
+		"notes":               req.Notes,
+	}
+	if req.EndedAt != nil {
+		columns["ended_at"] = req.EndedAt
+		columns["duration_seconds"] = int(req.EndedAt.Sub(req.StartedAt).Seconds())
+		columns["completed"] = true
+	}
+
+	written, err := s.update(&models.WorkoutSession{ID: session.ID}, models.SyncEntitySession, session.ID, session.UpdatedAt, req.ClientUpdatedAt, columns)
+	if err != nil || !written {
+		return err
+	}
+	return s.tx.First(session, "id = ?", session.ID).Error
+}
+
+// syncBlock creates or updates a block and syncs its exercises
+func (s *sessionSync) syncBlock(sessionID uuid.UUID, req models.SyncSessionBlockRequest) error {
+	groupID := uuid.Nil
+	if req.GroupID != nil {
+		groupID = *req.GroupID
+	}
+
+	var block models.SessionBlock
+	err := s.tx.Unscoped().First(&block, "id = ?", req.ID).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		block = models.SessionBlock{
+			ID:                req.ID,
+			SessionID:         sessionID,
+			GroupID:           groupID,
+			BlockOrder:        req.BlockOrder,
+			StartedAt:         req.StartedAt,
+			CompletedAt:       req.CompletedAt,
+			Skipped:           req.Skipped,
+			PerceivedExertion: req.PerceivedExertion,
+			UpdatedAt:         req.ClientUpdatedAt,
+		}
+		if err := s.tx.Create(&block).Error; err != nil {
+			return err
+		}
+		s.result.Created++
+	case err != nil:
+		return err
+	case block.SessionID != sessionID:
+		s.conflict(models.SyncEntityBlock, req.ID, models.SyncConflictIDInUse, nil, req.ClientUpdatedAt)
+		return nil
+	case block.DeletedAt.Valid:
+		s.conflict(models.SyncEntityBlock, req.ID, models.SyncConflictDeleted, &block.UpdatedAt, req.ClientUpdatedAt)
+		return nil
+	default:
+		if _, err := s.update(&models.SessionBlock{ID: block.ID}, models.SyncEntityBlock, block.ID, block.UpdatedAt, req.ClientUpdatedAt, map[string]interface{}{
+			"group_id":           groupID,
+			"block_order":        req.BlockOrder,
+			"started_at":         req.StartedAt,
+			"completed_at":       req.CompletedAt,
+			"skipped":            req.Skipped,
+			"perceived_exertion": req.PerceivedExertion,
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, exerciseReq := range req.Exercises {
+		if err := s.syncExercise(block.ID, exerciseReq); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncExercise creates or updates a session exercise and syncs its sets
+func (s *sessionSync) syncExercise(blockID uuid.UUID, req models.SyncSessionExerciseRequest) error {
+	var exercise models.SessionExercise
+	err := s.tx.Unscoped().First(&exercise, "id = ?", req.ID).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		exercise = models.SessionExercise{
+			ID:             req.ID,
+			SessionBlockID: blockID,
+			PrescriptionID: req.PrescriptionID,
+			ExerciseID:     req.ExerciseID,
+			ExerciseOrder:  req.ExerciseOrder,
+			StartedAt:      req.StartedAt,
+			CompletedAt:    req.CompletedAt,
+			Skipped:        req.Skipped,
+			Notes:          req.Notes,
+			UpdatedAt:      req.ClientUpdatedAt,
+		}
+		if err := s.tx.Create(&exercise).Error; err != nil {
+			return err
+		}
+		s.result.Created++
+	case err != nil:
+		return err
+	case exercise.SessionBlockID != blockID:
+		s.conflict(models.SyncEntityExercise, req.ID, models.SyncConflictIDInUse, nil, req.ClientUpdatedAt)
+		return nil
+	case exercise.DeletedAt.Valid:
+		s.conflict(models.SyncEntityExercise, req.ID, models.SyncConflictDeleted, &exercise.UpdatedAt, req.ClientUpdatedAt)
+		return nil
+	default:
+		written, err := s.update(&models.SessionExercise{ID: exercise.ID}, models.SyncEntityExercise, exercise.ID, exercise.UpdatedAt, req.ClientUpdatedAt, map[string]interface{}{
+			"prescription_id": req.PrescriptionID,
+			"exercise_id":     req.ExerciseID,
+			"exercise_order":  req.ExerciseOrder,
+			"started_at":      req.StartedAt,
+			"completed_at":    req.CompletedAt,
+			"skipped":         req.Skipped,
+			"notes":           req.Notes,
+		})
+		if err != nil {
+			return err
+		}
+		// Records of the exercise the sets were logged against before are rebuilt without them
+		if written && exercise.ExerciseID != req.ExerciseID {
+			s.recomputeExercises[exercise.ExerciseID] = true
+			exercise.ExerciseID = req.ExerciseID
+		}
+	}
+
+	for _, setReq := range req.Sets {
+		if err := s.syncSet(exercise, setReq); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncSet creates or updates a set of a session exercise
+func (s *sessionSync) syncSet(exercise models.SessionExercise, req models.SyncSessionSetRequest) error {
+	round := req.Round
+	if round == 0 {
+		round = 1
+	}
+	completedAt := req.CompletedAt
+	if !req.Completed {
+		completedAt = nil
+	}
+	actualWeightKg, originalValue, originalUnit := utils.ProcessWeightInput(req.ActualWeight)
+	actualMeters, originalDistanceValue, originalDistanceUnit := utils.ProcessDistanceInput(req.ActualDistance)
+
+	var set models.SessionSet
+	err := s.tx.Unscoped().First(&set, "id = ?", req.ID).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		set = models.SessionSet{
+			ID:                        req.ID,
+			SessionExerciseID:         exercise.ID,
+			SetNumber:                 req.SetNumber,
+			Round:                     round,
+			SequenceOrder:             req.SequenceOrder,
+			SubSetNumber:              req.SubSetNumber,
+			IntervalStartSeconds:      req.IntervalStartSeconds,
+			IntervalSeconds:           req.IntervalSeconds,
+			Completed:                 req.Completed,
+			StartedAt:                 req.StartedAt,
+			CompletedAt:               completedAt,
+			ActualReps:                req.ActualReps,
+			ActualWeightKg:            actualWeightKg,
+			OriginalActualWeightValue: originalValue,
+			OriginalActualWeightUnit:  originalUnit,
+			ActualDurationSeconds:     req.ActualDurationSeconds,
+			RPEValueID:                req.RPEValueID,
+			WasFailure:                req.WasFailure,
+			Notes:                     req.Notes,
+			UpdatedAt:                 req.ClientUpdatedAt,
+
+			ActualDistanceMeters:        actualMeters,
+			OriginalActualDistanceValue: originalDistanceValue,
+			OriginalActualDistanceUnit:  originalDistanceUnit,
+			ActualAverageHeartRate:      req.ActualAverageHeartRate,
+			ActualMaxHeartRate:          req.ActualMaxHeartRate,
+			ActualInclinePercent:        req.ActualInclinePercent,
+		}
+		if err := s.tx.Create(&set).Error; err != nil {
+			return err
+		}
+		s.result.Created++
+	case err != nil:
+		return err
+	case set.SessionExerciseID != exercise.ID:
+		s.conflict(models.SyncEntitySet, req.ID, models.SyncConflictIDInUse, nil, req.ClientUpdatedAt)
+		return nil
+	case set.DeletedAt.Valid:
+		s.conflict(models.SyncEntitySet, req.ID, models.SyncConflictDeleted, &set.UpdatedAt, req.ClientUpdatedAt)
+		return nil
+	default:
+		written, err := s.update(&models.SessionSet{ID: set.ID}, models.SyncEntitySet, set.ID, set.UpdatedAt, req.ClientUpdatedAt, map[string]interface{}{
+			"set_number":                     req.SetNumber,
+			"round":                          round,
+			"sequence_order":                 req.SequenceOrder,
+			"sub_set_number":                 req.SubSetNumber,
+			"interval_start_seconds":         req.IntervalStartSeconds,
+			"interval_seconds":               req.IntervalSeconds,
+			"completed":                      req.Completed,
+			"started_at":                     req.StartedAt,
+			"completed_at":                   completedAt,
+			"actual_reps":                    req.ActualReps,
+			"actual_weight_kg":               actualWeightKg,
+			"original_actual_weight_value":   originalValue,
+			"original_actual_weight_unit":    originalUnit,
+			"actual_duration_seconds":        req.ActualDurationSeconds,
+			"rpe_value_id":                   req.RPEValueID,
+			"was_failure":                    req.WasFailure,
+			"notes":                          req.Notes,
+			"actual_distance_meters":         actualMeters,
+			"original_actual_distance_value": originalDistanceValue,
+			"original_actual_distance_unit":  originalDistanceUnit,
+			"actual_average_heart_rate":      req.ActualAverageHeartRate,
+			"actual_max_heart_rate":          req.ActualMaxHeartRate,
+			"actual_incline_percent":         req.ActualInclinePercent,
+			"rest_seconds":                   nil,
+			"prescribed_rest_seconds":        nil,
+		})
+		if err != nil || !written {
+			return err
+		}
+		s.recomputeExercises[exercise.ExerciseID] = true
+	}
+
+	if completedAt != nil {
+		s.restSets = append(s.restSets, set.ID)
+	}
+	return nil
+}
+
+// recordRest records the rest taken before each synced completed set, once the whole tree is in place
+func (s *sessionSync) recordRest() error {
+	for _, setID := range s.restSets {
+		var set models.SessionSet
+		if err := s.tx.Preload("SessionExercise").First(&set, "id = ?", setID).Error; err != nil {
+			return err
+		}
+		if err := recordSetRest(s.tx, &set); err != nil {
+			return err
+		}
+		if err := s.tx.Model(&models.SessionSet{ID: set.ID}).UpdateColumns(map[string]interface{}{
+			"rest_seconds":            set.RestSeconds,
+			"prescribed_rest_seconds": set.PrescribedRestSeconds,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"lamari-fit-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SaveSessionAsWorkout turns what was done in a session into a new workout.
+// Each block with completed sets becomes a prescription group, keeping the group settings of the
+// prescription it came from (straight sets otherwise). Each exercise is prescribed as many sets as
+// were completed, with the reps (or duration) and load of its working set as targets.
+func SaveSessionAsWorkout(c *gin.Context) {
+	var params IDParam
+	if err := c.ShouldBindUri(&params); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	sessionID, ok := utils.ParseUUID(c, params.ID, "workout session")
+	if !ok {
+		return
+	}
+
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	// The body is optional
+	var req models.SaveSessionAsWorkoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	var session models.WorkoutSession
+	if err := database.DB.
+		Preload("Workout").
+		Preload("SessionBlocks", func(db *gorm.DB) *gorm.DB {
+			return db.Order("block_order ASC")
+		}).
+		Preload("SessionBlocks.SessionExercises", func(db *gorm.DB) *gorm.DB {
+			return db.Order("exercise_order ASC")
+		}).
+		Preload("SessionBlocks.SessionExercises.Prescription").
+		Preload("SessionBlocks.SessionExercises.SessionSets", func(db *gorm.DB) *gorm.DB {
+			return db.Order("set_number ASC")
+		}).
+		First(&session, "id = ?", sessionID).Error; err != nil {
+		utils.NotFoundResponse(c, "Workout session not found")
+		return
+	}
+
+	// Authorization: user owns the session OR trainer created it
+	if !canViewSession(session, authUserID) {
+		utils.NotFoundResponse(c, "Workout session not found")
+		return
+	}
+
+	workout := models.Workout{
+		UserID:            authUserID,
+		Title:             req.Title,
+		Description:       req.Description,
+		DifficultyLevel:   req.DifficultyLevel,
+		EstimatedDuration: sessionDurationMinutes(session),
+		IsTemplate:        req.IsTemplate,
+		Visibility:        req.Visibility,
+	}
+	if workout.Title == "" {
+		workout.Title = "Workout " + session.StartedAt.Format("2006-01-02")
+		if session.Workout != nil {
+			workout.Title = session.Workout.Title
+		}
+	}
+	if workout.Description == "" {
+		workout.Description = session.Notes
+	}
+	if workout.Visibility == "" {
+		workout.Visibility = "private"
+	}
+
+	prescriptions := sessionPrescriptions(session)
+	if len(prescriptions) == 0 {
+		utils.BadRequestResponse(c, "Session has no completed sets to save", nil)
+		return
+	}
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&workout).Error; err != nil {
+			return err
+		}
+		for i := range prescriptions {
+			prescriptions[i].WorkoutID = workout.ID
+		}
+		return tx.Create(&prescriptions).Error
+	}); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to save session as workout")
+		return
+	}
+
+	// Load the new workout with all relations
+	if err := database.DB.Where("id = ?", workout.ID).
+		Preload("Prescriptions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("group_order ASC, exercise_order ASC")
+		}).
+		Preload("Prescriptions.Exercise").
+		Preload("Prescriptions.RPEValue").
+		First(&workout).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load saved workout")
+		return
+	}
+
+	// Group prescriptions for response
+	groupedPrescriptions := models.GroupPrescriptionsByGroupID(workout.Prescriptions)
+
+	// Get user's preferred units and populate target weights and distances
+	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+	models.PopulatePrescriptionWeights(groupedPrescriptions, workout.Prescriptions, preferredWeightUnit)
+	models.PopulatePrescriptionDistances(groupedPrescriptions, workout.Prescriptions, getUserPreferredDistanceUnit(c, authUserID))
+
+	response := map[string]interface{}{
+		"id":                 workout.ID,
+		"user_id":            workout.UserID,
+		"title":              workout.Title,
+		"description":        workout.Description,
+		"difficulty_level":   workout.DifficultyLevel,
+		"estimated_duration": workout.EstimatedDuration,
+		"is_template":        workout.IsTemplate,
+		"visibility":         workout.Visibility,
+		"created_at":         workout.CreatedAt,
+		"updated_at":         workout.UpdatedAt,
+		"prescriptions":      groupedPrescriptions,
+	}
+
+	utils.CreatedResponse(c, "Workout saved from session successfully", response)
+}
+
+// sessionPrescriptions builds prescription rows (without a workout) from a session's completed
+// work. Skipped blocks and exercises, and exercises without a completed set, are left out.
+func sessionPrescriptions(session models.WorkoutSession) []models.WorkoutPrescription {
+	var prescriptions []models.WorkoutPrescription
+	groupOrder := 0
+	for _, block := range session.SessionBlocks {
+		if block.Skipped {
+			continue
+		}
+
+		var group []models.WorkoutPrescription
+		for _, exercise := range block.SessionExercises {
+			if exercise.Skipped {
+				continue
+			}
+			working := utils.WorkingSetIndex(exercise.SessionSets)
+			if working == -1 {
+				continue
+			}
+
+			sets := 0
+			for _, set := range exercise.SessionSets {
+				if set.Completed && set.SubSetNumber == 0 {
+					sets++
+				}
+			}
+
+			target := exercise.SessionSets[working]
+			reps, holdSeconds := workingSetVolume(target, exercise.Prescription)
+			if reps == nil && holdSeconds == nil {
+				continue
+			}
+
+			prescription := models.WorkoutPrescription{
+				ExerciseID:                exercise.ExerciseID,
+				RPEValueID:                target.RPEValueID,
+				ExerciseOrder:             len(group) + 1,
+				Sets:                      &sets,
+				Reps:                      reps,
+				HoldSeconds:               holdSeconds,
+				TargetWeightKg:            target.ActualWeightKg,
+				OriginalTargetWeightValue: target.OriginalActualWeightValue,
+				OriginalTargetWeightUnit:  target.OriginalActualWeightUnit,
+
+				TargetDistanceMeters:        target.ActualDistanceMeters,
+				OriginalTargetDistanceValue: target.OriginalActualDistanceValue,
+				OriginalTargetDistanceUnit:  target.OriginalActualDistanceUnit,
+				TargetInclinePercent:        target.ActualInclinePercent,
+			}
+			if exercise.Notes != "" {
+				notes := exercise.Notes
+				prescription.Notes = &notes
+			}
+			group = append(group, prescription)
+		}
+		if len(group) == 0 {
+			continue
+		}
+
+		// Group-level fields come from the block's original prescription, if it had one
+		groupOrder++
+		groupID := uuid.New()
+		source := blockPrescription(block)
+		for i := range group {
+			group[i].GroupID = groupID
+			group[i].GroupOrder = groupOrder
+			group[i].Type = models.PrescriptionTypeStraight
+			if source != nil {
+				group[i].Type = source.Type
+				group[i].GroupRounds = source.GroupRounds
+				group[i].RestBetweenSets = source.RestBetweenSets
+				group[i].IntervalSeconds = source.IntervalSeconds
+				group[i].GroupName = source.GroupName
+				group[i].GroupNotes = source.GroupNotes
+			}
+		}
+		prescriptions = append(prescriptions, group...)
+	}
+	return prescriptions
+}
+
+// workingSetVolume returns the rep or hold target for a working set: its reps, or its duration for
+// sets logged by time. Sets with neither fall back to the original prescription's target.
+func workingSetVolume(set models.SessionSet, prescription *models.WorkoutPrescription) (*int, *int) {
+	switch {
+	case set.ActualReps != nil && *set.ActualReps > 0:
+		return set.ActualReps, nil
+	case set.ActualDurationSeconds != nil && *set.ActualDurationSeconds > 0:
+		return nil, set.ActualDurationSeconds
+	case prescription != nil:
+		return prescription.Reps, prescription.HoldSeconds
+	}
+	return nil, nil
+}
+
+// blockPrescription returns the prescription of the first exercise in a block that has one
+func blockPrescription(block models.SessionBlock) *models.WorkoutPrescription {
+	for _, exercise := range block.SessionExercises {
+		if exercise.Prescription != nil {
+			return exercise.Prescription
+		}
+	}
+	return nil
+}
+
+// sessionDurationMinutes returns a session's duration in whole minutes, within the range a workout's
+// estimated duration allows, or nil if the session hasn't ended
+func sessionDurationMinutes(session models.WorkoutSession) *int {
+	if session.DurationSeconds == nil {
+		return nil
+	}
+	minutes := (*session.DurationSeconds + 30) / 60
+	if minutes < 1 {
+		minutes = 1
+	}
+	if minutes > 600 {
+		minutes = 600
+	}
+	return &minutes
+}
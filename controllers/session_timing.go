@@ -0,0 +1,227 @@
+package controllers
+
+import (
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"lamari-fit-api/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StartSessionSet records when a set was started
+func StartSessionSet(c *gin.Context) {
+	var params IDParam
+	if err := c.ShouldBindUri(&params); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	setID, ok := utils.ParseUUID(c, params.ID, "session set")
+	if !ok {
+		return
+	}
+
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	var set models.SessionSet
+	if err := database.DB.
+		Preload("SessionExercise.SessionBlock.Session").
+		First(&set, "id = ?", setID).Error; err != nil {
+		utils.NotFoundResponse(c, "Session set not found")
+		return
+	}
+
+	// Authorization
+	if !isAuthorizedForSession(set.SessionExercise.SessionBlock.Session, authUserID) {
+		utils.ForbiddenResponse(c, "Not authorized to start this set")
+		return
+	}
+
+	if set.Completed {
+		utils.BadRequestResponse(c, "Session set is already completed", nil)
+		return
+	}
+
+	// Optionally accept the start time
+	var req models.StartSessionSetRequest
+	if err := c.ShouldBindJSON(&req); err == nil && req.StartedAt != nil {
+		set.StartedAt = req.StartedAt
+	} else {
+		now := time.Now()
+		set.StartedAt = &now
+	}
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&set).Error; err != nil {
+			return err
+		}
+		return markSetStarted(tx, set, *set.StartedAt)
+	}); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to start session set")
+		return
+	}
+
+	// Reload with RPE value
+	database.DB.Preload("RPEValue").First(&set, "id = ?", set.ID)
+
+	// Get user's preferred weight unit for response conversion
+	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+	preferredDistanceUnit := getUserPreferredDistanceUnit(c, authUserID)
+
+	utils.SuccessResponse(c, "Session set started successfully", set.ToResponse(preferredWeightUnit, preferredDistanceUnit))
+}
+
+// validateSetTimes sends a bad request response if a set's completion time is before its start time
+func validateSetTimes(c *gin.Context, set models.SessionSet) bool {
+	if set.StartedAt != nil && set.CompletedAt != nil && set.CompletedAt.Before(*set.StartedAt) {
+		utils.BadRequestResponse(c, "Completed time cannot be before start time", nil)
+		return false
+	}
+	return true
+}
+
+// blockRestRule returns the prescription type and rest between sets of a session block's group.
+// Blocks without a prescription are treated as straight sets with no prescribed rest.
+func blockRestRule(db *gorm.DB, blockID uuid.UUID) (models.PrescriptionType, *int, error) {
+	var prescriptions []models.WorkoutPrescription
+	if err := db.
+		Joins("JOIN session_exercises ON session_exercises.prescription_id = workout_prescriptions.id").
+		Where("session_exercises.session_block_id = ? AND session_exercises.deleted_at IS NULL", blockID).
+		Order("session_exercises.exercise_order ASC").
+		Limit(1).
+		Find(&prescriptions).Error; err != nil {
+		return "", nil, err
+	}
+	if len(prescriptions) == 0 {
+		return models.PrescriptionTypeStraight, nil, nil
+	}
+	return prescriptions[0].Type, prescriptions[0].RestBetweenSets, nil
+}
+
+// markSetStarted marks the set's exercise and block as started if they haven't been yet.
+// A time-boxed block's start is placed so that the set's interval lines up with the clock.
+func markSetStarted(tx *gorm.DB, set models.SessionSet, startedAt time.Time) error {
+	if err := tx.Model(&models.SessionExercise{}).
+		Where("id = ? AND started_at IS NULL", set.SessionExerciseID).
+		Update("started_at", startedAt).Error; err != nil {
+		return err
+	}
+
+	blockStartedAt := startedAt
+	if set.IntervalStartSeconds != nil {
+		blockStartedAt = startedAt.Add(-time.Duration(*set.IntervalStartSeconds) * time.Second)
+	}
+	return tx.Model(&models.SessionBlock{}).
+		Where("id = ? AND started_at IS NULL", set.SessionExercise.SessionBlockID).
+		Update("started_at", blockStartedAt).Error
+}
+
+// recordSetRest fills in the rest taken before a completed set: the time from the previous set
+// completed in the block until this set was started (or completed, if it was never started),
+// along with the rest its prescription called for
+func recordSetRest(tx *gorm.DB, set *models.SessionSet) error {
+	set.RestSeconds = nil
+	set.PrescribedRestSeconds = nil
+	if set.CompletedAt == nil {
+		return nil
+	}
+
+	restEnd := *set.CompletedAt
+	if set.StartedAt != nil {
+		restEnd = *set.StartedAt
+	}
+
+	blockID := set.SessionExercise.SessionBlockID
+	var previous []models.SessionSet
+	if err := tx.
+		Joins("JOIN session_exercises ON session_exercises.id = session_sets.session_exercise_id").
+		Where("session_exercises.session_block_id = ? AND session_exercises.deleted_at IS NULL", blockID).
+		Where("session_sets.id <> ? AND session_sets.completed = ?", set.ID, true).
+		Where("session_sets.completed_at <= ?", restEnd).
+		Order("session_sets.completed_at DESC").
+		Limit(1).
+		Find(&previous).Error; err != nil {
+		return err
+	}
+	if len(previous) == 0 {
+		return nil
+	}
+
+	groupType, restBetweenSets, err := blockRestRule(tx, blockID)
+	if err != nil {
+		return err
+	}
+
+	rest := int(restEnd.Sub(*previous[0].CompletedAt).Seconds())
+	set.RestSeconds = &rest
+	set.PrescribedRestSeconds = utils.RestBeforeSet(groupType, restBetweenSets, previous[0].Round == set.Round, set.SubSetNumber)
+	return nil
+}
+
+// findNextSetHint returns the session's next incomplete set after a set was completed, and when
+// it is due: time-boxed sets are due at their interval on the block's clock, other sets once the
+// prescribed rest has passed. Returns nil if every set of the session is done.
+func findNextSetHint(db *gorm.DB, set models.SessionSet, sessionID uuid.UUID) (*models.NextSetHint, error) {
+	var candidates []models.SessionSet
+	if err := db.
+		Joins("JOIN session_exercises ON session_exercises.id = session_sets.session_exercise_id").
+		Joins("JOIN session_blocks ON session_blocks.id = session_exercises.session_block_id").
+		Where("session_blocks.session_id = ? AND session_blocks.deleted_at IS NULL AND session_exercises.deleted_at IS NULL", sessionID).
+		Where("session_blocks.skipped = ? AND session_exercises.skipped = ? AND session_sets.completed = ?", false, false, false).
+		Order("session_blocks.block_order ASC, session_sets.sequence_order ASC, session_exercises.exercise_order ASC, session_sets.set_number ASC").
+		Limit(1).
+		Preload("SessionExercise.Exercise").
+		Preload("SessionExercise.SessionBlock").
+		Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	next := candidates[0]
+
+	completedAt := *set.CompletedAt
+	hint := &models.NextSetHint{
+		SetID:             next.ID,
+		SessionExerciseID: next.SessionExerciseID,
+		SessionBlockID:    next.SessionExercise.SessionBlockID,
+		ExerciseName:      next.SessionExercise.Exercise.Name,
+		ReadyAt:           completedAt,
+	}
+
+	blockID := set.SessionExercise.SessionBlockID
+	groupType, restBetweenSets, err := blockRestRule(db, blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	nextBlock := next.SessionExercise.SessionBlock
+	if nextBlock.ID == blockID && utils.IsTimeBoxedType(groupType) {
+		if next.IntervalStartSeconds != nil && nextBlock.StartedAt != nil {
+			readyAt := nextBlock.StartedAt.Add(time.Duration(*next.IntervalStartSeconds) * time.Second)
+			if readyAt.After(completedAt) {
+				hint.ReadyAt = readyAt
+			}
+			rest := int(hint.ReadyAt.Sub(completedAt).Seconds())
+			hint.RestSeconds = &rest
+		}
+		return hint, nil
+	}
+
+	sameRound := nextBlock.ID == blockID && next.Round == set.Round
+	subSetNumber := 0
+	if nextBlock.ID == blockID {
+		subSetNumber = next.SubSetNumber
+	}
+	if rest := utils.RestBeforeSet(groupType, restBetweenSets, sameRound, subSetNumber); rest != nil {
+		hint.RestSeconds = rest
+		hint.ReadyAt = completedAt.Add(time.Duration(*rest) * time.Second)
+	}
+	return hint, nil
+}
@@ -0,0 +1,289 @@
+package controllers
+
+import (
+	"fmt"
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"lamari-fit-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ShareWorkout shares one of the user's workouts with a friend, a client or their trainer
+func ShareWorkout(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	workoutID, ok := utils.ParseUUID(c, c.Param("id"), "workout")
+	if !ok {
+		return
+	}
+
+	var req models.ShareWorkoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	var workout models.Workout
+	if err := database.DB.Where("id = ? AND user_id = ?", workoutID, userID).First(&workout).Error; err != nil {
+		utils.NotFoundResponse(c, "Workout not found.")
+		return
+	}
+
+	recipientID, ok := utils.ParseUUID(c, req.UserID, "user")
+	if !ok {
+		return
+	}
+	if recipientID == userID {
+		utils.BadRequestResponse(c, "Cannot share a workout with yourself.", nil)
+		return
+	}
+
+	var recipient models.User
+	if err := database.DB.Where("id = ?", recipientID).First(&recipient).Error; err != nil || recipient.IsPendingDeletion() {
+		utils.NotFoundResponse(c, "User not found.")
+		return
+	}
+
+	if !canShareWorkoutWith(userID, recipientID) {
+		utils.ForbiddenResponse(c, "You can only share workouts with friends, clients or trainers.")
+		return
+	}
+
+	var existing models.SharedWorkout
+	if err := database.DB.Where("workout_id = ? AND shared_with_id = ?", workoutID, recipientID).First(&existing).Error; err == nil {
+		utils.ConflictResponse(c, "Workout is already shared with this user.")
+		return
+	}
+
+	share := models.SharedWorkout{
+		WorkoutID:    workoutID,
+		SharedByID:   userID,
+		SharedWithID: recipientID,
+		Permission:   req.Permission,
+	}
+	if share.Permission == "" {
+		share.Permission = models.SharePermissionView
+	}
+
+	if err := database.DB.Create(&share).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to share workout.")
+		return
+	}
+
+	loadSharedWorkout(&share)
+
+	utils.CreatedResponse(c, "Workout shared successfully.", share.ToResponse())
+}
+
+// GetWorkoutShares lists the users one of the user's workouts is shared with
+func GetWorkoutShares(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	workoutID, ok := utils.ParseUUID(c, c.Param("id"), "workout")
+	if !ok {
+		return
+	}
+
+	var workout models.Workout
+	if err := database.DB.Where("id = ? AND user_id = ?", workoutID, userID).First(&workout).Error; err != nil {
+		utils.NotFoundResponse(c, "Workout not found.")
+		return
+	}
+
+	var shares []models.SharedWorkout
+	if err := sharedWorkoutPreloads(database.DB).
+		Where("workout_id = ?", workoutID).
+		Order("created_at ASC").
+		Find(&shares).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch workout shares.")
+		return
+	}
+
+	responses := make([]models.SharedWorkoutResponse, len(shares))
+	for i := range shares {
+		responses[i] = shares[i].ToResponse()
+	}
+
+	utils.SuccessResponse(c, "Workout shares fetched successfully.", responses)
+}
+
+// UpdateWorkoutShare changes the permission of a share of one of the user's workouts
+func UpdateWorkoutShare(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	workoutID, ok := utils.ParseUUID(c, c.Param("id"), "workout")
+	if !ok {
+		return
+	}
+
+	shareID, ok := utils.ParseUUID(c, c.Param("share_id"), "share")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateSharedWorkoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	var share models.SharedWorkout
+	if err := database.DB.Where("id = ? AND workout_id = ? AND shared_by_id = ?", shareID, workoutID, userID).
+		First(&share).Error; err != nil {
+		utils.NotFoundResponse(c, "Shared workout not found.")
+		return
+	}
+
+	if err := database.DB.Model(&share).Update("permission", req.Permission).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to update shared workout.")
+		return
+	}
+
+	loadSharedWorkout(&share)
+
+	utils.SuccessResponse(c, "Shared workout updated successfully.", share.ToResponse())
+}
+
+// UnshareWorkout removes a share. The owner can unshare a workout, and the recipient can remove it
+// from the workouts shared with them.
+func UnshareWorkout(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	workoutID, ok := utils.ParseUUID(c, c.Param("id"), "workout")
+	if !ok {
+		return
+	}
+
+	shareID, ok := utils.ParseUUID(c, c.Param("share_id"), "share")
+	if !ok {
+		return
+	}
+
+	result := database.DB.Where("id = ? AND workout_id = ? AND (shared_by_id = ? OR shared_with_id = ?)",
+		shareID, workoutID, userID, userID).Delete(&models.SharedWorkout{})
+
+	if result.Error != nil {
+		utils.InternalServerErrorResponse(c, "Failed to unshare workout.")
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		utils.NotFoundResponse(c, "Shared workout not found.")
+		return
+	}
+
+	utils.DeletedResponse(c, "Workout unshared successfully.")
+}
+
+// GetWorkoutsSharedWithMe lists the workouts other users shared with the user, newest first
+func GetWorkoutsSharedWithMe(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	var query PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	SetDefaultPagination(&query)
+	offset := (query.Page - 1) * query.Limit
+
+	// Shares of deleted workouts are left out
+	base := database.DB.Model(&models.SharedWorkout{}).
+		Where("shared_with_id = ?", userID).
+		Where("workout_id IN (?)", database.DB.Model(&models.Workout{}).Select("id"))
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to count shared workouts.")
+		return
+	}
+
+	var shares []models.SharedWorkout
+	if err := sharedWorkoutPreloads(base.Session(&gorm.Session{})).
+		Offset(offset).
+		Limit(query.Limit).
+		Order("created_at DESC").
+		Find(&shares).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch shared workouts.")
+		return
+	}
+
+	responses := make([]models.SharedWorkoutResponse, len(shares))
+	for i := range shares {
+		responses[i] = shares[i].ToResponse()
+	}
+
+	utils.PaginatedResponse(c, "Shared workouts retrieved successfully.", responses, query.Page, query.Limit, int(total))
+}
+
+// sharedWorkoutPreloads loads what SharedWorkout.ToResponse needs
+func sharedWorkoutPreloads(db *gorm.DB) *gorm.DB {
+	return db.Preload("Workout").Preload("SharedBy").Preload("SharedWith").Preload("Comments")
+}
+
+func loadSharedWorkout(share *models.SharedWorkout) {
+	sharedWorkoutPreloads(database.DB).First(share, "id = ?", share.ID)
+}
+
+// canShareWorkoutWith reports whether a user may share workouts with another: they must be friends
+// or have an active trainer-client link in either direction
+func canShareWorkoutWith(userID, otherID uuid.UUID) bool {
+	return areFriends(userID, otherID) || hasActiveClientLink(userID, otherID) || hasActiveClientLink(otherID, userID)
+}
+
+// revokeWorkoutSharesBetween removes the workouts two users shared with each other once they are no
+// longer allowed to share them
+func revokeWorkoutSharesBetween(userID, otherID uuid.UUID) error {
+	if canShareWorkoutWith(userID, otherID) {
+		return nil
+	}
+	return database.DB.Where("(shared_by_id = ? AND shared_with_id = ?) OR (shared_by_id = ? AND shared_with_id = ?)",
+		userID, otherID, otherID, userID).Delete(&models.SharedWorkout{}).Error
+}
+
+// findAccessibleWorkout loads a workout the user owns or that was shared with them, and returns the
+// user's permission on it. It responds with 404 when the user cannot see the workout and 403 when
+// their share does not grant the required permission.
+func findAccessibleWorkout(c *gin.Context, query *gorm.DB, workoutID, userID uuid.UUID, required string) (*models.Workout, string, bool) {
+	var workout models.Workout
+	if err := query.Where("id = ?", workoutID).First(&workout).Error; err != nil {
+		utils.NotFoundResponse(c, "Workout not found.")
+		return nil, "", false
+	}
+
+	if workout.UserID == userID {
+		return &workout, models.SharePermissionOwner, true
+	}
+
+	var share models.SharedWorkout
+	if err := database.DB.Where("workout_id = ? AND shared_with_id = ?", workoutID, userID).First(&share).Error; err != nil {
+		utils.NotFoundResponse(c, "Workout not found.")
+		return nil, "", false
+	}
+
+	if !models.SharePermissionAllows(share.Permission, required) {
+		utils.ForbiddenResponse(c, fmt.Sprintf("This workout was shared with you with %s permission only.", share.Permission))
+		return nil, "", false
+	}
+
+	return &workout, share.Permission, true
+}
@@ -0,0 +1,485 @@
+package controllers
+
+import (
+	"errors"
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"lamari-fit-api/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateClientAssignment lets a trainer assign one of their workouts, to be done by a due date, or
+// a workout plan to an active client. The workout is shared with the client, or the client is
+// enrolled in the plan, and the client is notified by email.
+func CreateClientAssignment(c *gin.Context) {
+	trainerID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	clientID, ok := utils.ParseUUID(c, c.Param("id"), "client")
+	if !ok {
+		return
+	}
+
+	if !hasActiveClientLink(trainerID, clientID) {
+		utils.ForbiddenResponse(c, "You can only assign workouts to your active clients")
+		return
+	}
+
+	var req models.CreateTrainerAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	validationErrors := utils.ValidationErrors{}
+	if (req.WorkoutID == nil) == (req.PlanID == nil) {
+		validationErrors["workout_id"] = []string{"Provide either a workout_id or a plan_id"}
+	}
+	if req.WorkoutID != nil && req.DueDate == "" {
+		validationErrors["due_date"] = []string{"A due date is required when assigning a workout"}
+	}
+	if req.PlanID != nil && req.DaysPerWeek == 0 {
+		validationErrors["days_per_week"] = []string{"Days per week is required when assigning a plan"}
+	}
+
+	var dueDate *time.Time
+	if req.DueDate != "" {
+		parsed, err := time.Parse(utils.DateFormat, req.DueDate)
+		if err != nil {
+			validationErrors["due_date"] = []string{"Invalid date format. Use YYYY-MM-DD"}
+		} else if parsed.Before(utils.StartOfDay(time.Now())) {
+			validationErrors["due_date"] = []string{"Due date cannot be in the past"}
+		} else {
+			dueDate = &parsed
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		utils.ValidationErrorResponse(c, validationErrors)
+		return
+	}
+
+	var client models.User
+	if err := database.DB.First(&client, "id = ?", clientID).Error; err != nil {
+		utils.NotFoundResponse(c, "Client not found")
+		return
+	}
+
+	var trainer models.User
+	if err := database.DB.First(&trainer, "id = ?", trainerID).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve trainer")
+		return
+	}
+
+	assignment := models.TrainerAssignment{
+		TrainerID: trainerID,
+		ClientID:  clientID,
+		DueDate:   dueDate,
+		Notes:     req.Notes,
+	}
+
+	var title string
+	if req.WorkoutID != nil {
+		var workout models.Workout
+		if err := database.DB.Where("id = ? AND user_id = ?", *req.WorkoutID, trainerID).First(&workout).Error; err != nil {
+			utils.NotFoundResponse(c, "Workout not found")
+			return
+		}
+
+		assignment.Type = models.AssignmentTypeWorkout
+		assignment.WorkoutID = &workout.ID
+		title = workout.Title
+
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := shareAssignedWorkout(tx, &assignment); err != nil {
+				return err
+			}
+			return tx.Create(&assignment).Error
+		})
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to assign workout")
+			return
+		}
+	} else {
+		var plan models.WorkoutPlan
+		if err := database.DB.Where("id = ? AND (user_id = ? OR visibility = ?)", *req.PlanID, trainerID, "public").
+			First(&plan).Error; err != nil {
+			utils.NotFoundResponse(c, "Workout plan not found")
+			return
+		}
+
+		startDate := req.StartDate
+		if startDate == "" {
+			startDate = utils.StartOfDay(time.Now()).Format(utils.DateFormat)
+		}
+
+		enrollment, ok := buildPlanEnrollment(c, clientID, EnrollInPlanRequest{
+			PlanID:            plan.ID,
+			StartDate:         startDate,
+			DaysPerWeek:       req.DaysPerWeek,
+			ScheduleMode:      req.ScheduleMode,
+			PreferredWeekdays: req.PreferredWeekdays,
+		})
+		if !ok {
+			return
+		}
+
+		var existingEnrollment models.PlanEnrollment
+		if err := database.DB.Where("user_id = ? AND plan_id = ? AND status = ?", clientID, plan.ID, "active").
+			First(&existingEnrollment).Error; err == nil {
+			utils.ConflictResponse(c, "This client is already enrolled in this plan")
+			return
+		}
+
+		assignment.Type = models.AssignmentTypePlan
+		assignment.PlanID = &plan.ID
+		title = plan.Title
+
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(enrollment).Error; err != nil {
+				return err
+			}
+			assignment.EnrollmentID = &enrollment.ID
+			return tx.Create(&assignment).Error
+		})
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to assign workout plan")
+			return
+		}
+	}
+
+	// The assignment stands even if the email fails; the client also sees it in their assignments
+	emailService := utils.NewEmailService()
+	emailService.SendTrainerAssignment(client.Email, trainer.FirstName+" "+trainer.LastName, title, dueDate)
+
+	assignmentPreloads(database.DB).First(&assignment, "id = ?", assignment.ID)
+
+	showProgress := hasClientConsent(trainerID, clientID, models.ConsentScopeSessions)
+	responses, err := assignmentResponses([]models.TrainerAssignment{assignment}, showProgress)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve assignment progress")
+		return
+	}
+
+	utils.CreatedResponse(c, "Assignment created successfully", responses[0])
+}
+
+// GetClientAssignments lists the assignments a trainer gave a client, newest first, with the
+// completion status of each if the client shares their sessions with the trainer
+func GetClientAssignments(c *gin.Context) {
+	trainerID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	clientID, ok := utils.ParseUUID(c, c.Param("id"), "client")
+	if !ok {
+		return
+	}
+
+	if !hasActiveClientLink(trainerID, clientID) {
+		utils.ForbiddenResponse(c, "Not authorized to view assignments for this user")
+		return
+	}
+
+	showProgress := hasClientConsent(trainerID, clientID, models.ConsentScopeSessions)
+	respondWithAssignments(c, database.DB.Where("trainer_id = ? AND client_id = ?", trainerID, clientID), showProgress)
+}
+
+// DeleteClientAssignment lets a trainer withdraw an assignment. The client's enrollment in an
+// assigned plan is cancelled, unless they already finished it, and the share of an assigned
+// workout is removed, unless another assignment still uses it.
+func DeleteClientAssignment(c *gin.Context) {
+	trainerID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	clientID, ok := utils.ParseUUID(c, c.Param("id"), "client")
+	if !ok {
+		return
+	}
+
+	assignmentID, ok := utils.ParseUUID(c, c.Param("assignment_id"), "assignment")
+	if !ok {
+		return
+	}
+
+	var assignment models.TrainerAssignment
+	if err := database.DB.Where("id = ? AND trainer_id = ? AND client_id = ?", assignmentID, trainerID, clientID).
+		First(&assignment).Error; err != nil {
+		utils.NotFoundResponse(c, "Assignment not found")
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if assignment.EnrollmentID != nil {
+			if err := tx.Model(&models.PlanEnrollment{}).
+				Where("id = ? AND status IN ?", *assignment.EnrollmentID, []string{"active", "paused"}).
+				Update("status", "cancelled").Error; err != nil {
+				return err
+			}
+		}
+		if err := releaseAssignedWorkoutShare(tx, assignment); err != nil {
+			return err
+		}
+		return tx.Delete(&assignment).Error
+	})
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to delete assignment")
+		return
+	}
+
+	utils.DeletedResponse(c, "Assignment deleted successfully")
+}
+
+// GetMyAssignments lists the workouts and plans the user's trainers assigned them, newest first
+func GetMyAssignments(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	respondWithAssignments(c, database.DB.Where("client_id = ?", userID), true)
+}
+
+// respondWithAssignments sends a page of the assignments matched by query, with the client's
+// progress if showProgress is set
+func respondWithAssignments(c *gin.Context, query *gorm.DB, showProgress bool) {
+	var pagination PaginationQuery
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	SetDefaultPagination(&pagination)
+
+	base := query.Model(&models.TrainerAssignment{})
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to count assignments")
+		return
+	}
+
+	var assignments []models.TrainerAssignment
+	if err := assignmentPreloads(base.Session(&gorm.Session{})).
+		Offset(pagination.GetOffset()).
+		Limit(pagination.Limit).
+		Order("created_at DESC").
+		Find(&assignments).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve assignments")
+		return
+	}
+
+	responses, err := assignmentResponses(assignments, showProgress)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve assignment progress")
+		return
+	}
+
+	utils.PaginatedResponse(c, "Assignments retrieved successfully", responses, pagination.Page, pagination.Limit, int(total))
+}
+
+// assignmentPreloads loads what TrainerAssignment.ToResponse needs
+func assignmentPreloads(db *gorm.DB) *gorm.DB {
+	return db.Preload("Trainer").Preload("Client").Preload("Workout").Preload("Plan").Preload("Enrollment")
+}
+
+// assignmentResponses converts assignments to response format with the clients' progress. Since
+// progress comes from the client's sessions, it is left out for trainers the client doesn't share
+// their sessions with (showProgress unset) and the status is hidden.
+func assignmentResponses(assignments []models.TrainerAssignment, showProgress bool) ([]models.TrainerAssignmentResponse, error) {
+	responses := make([]models.TrainerAssignmentResponse, len(assignments))
+	if !showProgress {
+		for i, assignment := range assignments {
+			responses[i] = assignment.ToResponse(models.AssignmentProgress{}, models.AssignmentStatusHidden)
+		}
+		return responses, nil
+	}
+
+	progress, err := loadAssignmentsProgress(assignments)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i, assignment := range assignments {
+		responses[i] = assignment.ToResponse(progress[assignment.ID], utils.AssignmentStatus(assignment.DueDate, progress[assignment.ID], now))
+	}
+	return responses, nil
+}
+
+// loadAssignmentsProgress works out what the clients have done toward the assignments, keyed by
+// assignment ID, with one grouped query per assignment type. A workout counts as done by the
+// first completed session of it since the day (UTC) it was assigned; a plan by its enrollment
+// being completed. The assignments' enrollments must be preloaded.
+func loadAssignmentsProgress(assignments []models.TrainerAssignment) (map[uuid.UUID]models.AssignmentProgress, error) {
+	var workoutAssignmentIDs, enrollmentIDs []uuid.UUID
+	for _, assignment := range assignments {
+		switch {
+		case assignment.Type == models.AssignmentTypeWorkout && assignment.WorkoutID != nil:
+			workoutAssignmentIDs = append(workoutAssignmentIDs, assignment.ID)
+		case assignment.Type == models.AssignmentTypePlan && assignment.Enrollment != nil:
+			enrollmentIDs = append(enrollmentIDs, assignment.Enrollment.ID)
+		}
+	}
+
+	type workoutProgressRow struct {
+		AssignmentID uuid.UUID
+		Count        int
+		CompletedAt  *time.Time
+	}
+	workoutProgress := make(map[uuid.UUID]workoutProgressRow)
+	if len(workoutAssignmentIDs) > 0 {
+		var rows []workoutProgressRow
+		if err := database.DB.Table("trainer_assignments").
+			Select("trainer_assignments.id AS assignment_id, COUNT(*) AS count, "+
+				"MIN(COALESCE(workout_sessions.ended_at, workout_sessions.started_at)) AS completed_at").
+			Joins("JOIN workout_sessions ON workout_sessions.user_id = trainer_assignments.client_id "+
+				"AND workout_sessions.workout_id = trainer_assignments.workout_id "+
+				"AND workout_sessions.completed = ? AND workout_sessions.deleted_at IS NULL "+
+				"AND workout_sessions.started_at >= date_trunc('day', trainer_assignments.created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'", true).
+			Where("trainer_assignments.id IN ?", workoutAssignmentIDs).
+			Group("trainer_assignments.id").
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			workoutProgress[row.AssignmentID] = row
+		}
+	}
+
+	enrollmentSessions := make(map[uuid.UUID]int)
+	if len(enrollmentIDs) > 0 {
+		var rows []struct {
+			EnrollmentID uuid.UUID
+			Count        int
+		}
+		if err := database.DB.Model(&models.WorkoutSession{}).
+			Select("enrollment_id, COUNT(*) AS count").
+			Where("enrollment_id IN ? AND completed = ?", enrollmentIDs, true).
+			Group("enrollment_id").
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			enrollmentSessions[row.EnrollmentID] = row.Count
+		}
+	}
+
+	progressByID := make(map[uuid.UUID]models.AssignmentProgress, len(assignments))
+	for _, assignment := range assignments {
+		var progress models.AssignmentProgress
+
+		switch assignment.Type {
+		case models.AssignmentTypeWorkout:
+			row := workoutProgress[assignment.ID]
+			progress.CompletedSessions = row.Count
+			progress.CompletedAt = row.CompletedAt
+		case models.AssignmentTypePlan:
+			if assignment.Enrollment == nil {
+				// The enrollment was removed along with the client's data
+				progress.Cancelled = true
+				break
+			}
+
+			progress.CompletedSessions = enrollmentSessions[assignment.Enrollment.ID]
+			switch assignment.Enrollment.Status {
+			case "completed":
+				completedAt := assignment.Enrollment.UpdatedAt
+				if assignment.Enrollment.LastCompletedAt != nil {
+					completedAt = *assignment.Enrollment.LastCompletedAt
+				}
+				progress.CompletedAt = &completedAt
+			case "cancelled":
+				progress.Cancelled = true
+			}
+		}
+
+		progressByID[assignment.ID] = progress
+	}
+
+	return progressByID, nil
+}
+
+// shareAssignedWorkout shares an assigned workout with the client so they can view it. A share
+// created for an assignment, or already used by another one, is recorded on the assignment so it
+// can be removed along with the last assignment using it. A share the trainer made themselves is
+// left alone.
+func shareAssignedWorkout(tx *gorm.DB, assignment *models.TrainerAssignment) error {
+	var share models.SharedWorkout
+	err := tx.Where("workout_id = ? AND shared_with_id = ?", *assignment.WorkoutID, assignment.ClientID).First(&share).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		share = models.SharedWorkout{
+			WorkoutID:    *assignment.WorkoutID,
+			SharedByID:   assignment.TrainerID,
+			SharedWithID: assignment.ClientID,
+			Permission:   models.SharePermissionView,
+		}
+		if err := tx.Create(&share).Error; err != nil {
+			return err
+		}
+		assignment.SharedWorkoutID = &share.ID
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var count int64
+	if err := tx.Model(&models.TrainerAssignment{}).Where("shared_workout_id = ?", share.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		assignment.SharedWorkoutID = &share.ID
+	}
+	return nil
+}
+
+// releaseAssignedWorkoutShare removes the share recorded on an assignment that is being deleted,
+// unless another assignment still uses it
+func releaseAssignedWorkoutShare(tx *gorm.DB, assignment models.TrainerAssignment) error {
+	if assignment.SharedWorkoutID == nil {
+		return nil
+	}
+
+	var count int64
+	if err := tx.Model(&models.TrainerAssignment{}).
+		Where("shared_workout_id = ? AND id <> ?", *assignment.SharedWorkoutID, assignment.ID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	return tx.Delete(&models.SharedWorkout{}, "id = ?", *assignment.SharedWorkoutID).Error
+}
+
+// shareWorkoutForViewing shares a workout with a user so they can view it, unless it is already
+// shared with them
+func shareWorkoutForViewing(tx *gorm.DB, workoutID, ownerID, userID uuid.UUID) error {
+	var count int64
+	if err := tx.Model(&models.SharedWorkout{}).
+		Where("workout_id = ? AND shared_with_id = ?", workoutID, userID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	return tx.Create(&models.SharedWorkout{
+		WorkoutID:    workoutID,
+		SharedByID:   ownerID,
+		SharedWithID: userID,
+		Permission:   models.SharePermissionView,
+	}).Error
+}
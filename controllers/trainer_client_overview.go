@@ -0,0 +1,341 @@
+package controllers
+
+import (
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"lamari-fit-api/utils"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// clientWeightTrendDays is how far back the overview's weight trend goes
+	clientWeightTrendDays = 30
+	// clientRecentRecordsLimit is how many of the latest personal records the overview shows
+	clientRecentRecordsLimit = 5
+)
+
+// clientActivity is when a client last completed a session and how many they completed this week
+type clientActivity struct {
+	LastSessionAt    *time.Time
+	SessionsThisWeek int
+}
+
+// GetClientOverview summarises an active client's training and body metrics for their trainer:
+// recent activity against their weekly target, weight trend, latest personal records, open
+// assignments and health notes. Weights are in the trainer's preferred unit. Activity, records,
+// open assignments, weight trend and health notes are only included if the client consented to
+// share them; open assignments come from the client's sessions.
+func GetClientOverview(c *gin.Context) {
+	trainerID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	clientID, ok := utils.ParseUUID(c, c.Param("id"), "client")
+	if !ok {
+		return
+	}
+
+	var link models.TrainerClientLink
+	if err := database.DB.Preload("Client").
+		Where("trainer_id = ? AND client_id = ? AND status = ?", trainerID, clientID, "active").
+		First(&link).Error; err != nil {
+		utils.ForbiddenResponse(c, "Not authorized to view this client")
+		return
+	}
+
+	now := time.Now()
+	preferredWeightUnit := getUserPreferredWeightUnit(c, trainerID)
+
+	response := models.ClientOverviewResponse{
+		Client: models.UserPublicResponse{
+			ID:        link.Client.ID,
+			FirstName: link.Client.FirstName,
+			LastName:  link.Client.LastName,
+		},
+		LinkedSince:     link.ActivatedAt,
+		ConsentScopes:   link.GrantedScopes(),
+		RecentRecords:   []models.PersonalRecordResponse{},
+		OpenAssignments: []models.TrainerAssignmentResponse{},
+	}
+
+	if link.HasConsent(models.ConsentScopeSessions) {
+		activityByClient, err := loadClientActivity([]uuid.UUID{clientID}, now)
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to retrieve client activity")
+			return
+		}
+		activity := activityByClient[clientID]
+		response.LastSessionAt = activity.LastSessionAt
+		response.SessionsThisWeek = activity.SessionsThisWeek
+		if activity.LastSessionAt != nil {
+			days := utils.DaysBetween(*activity.LastSessionAt, now)
+			response.DaysSinceLastSession = &days
+		}
+
+		var records []models.PersonalRecord
+		if err := database.DB.Preload("Exercise").
+			Where("user_id = ?", clientID).
+			Where("record_type <> ? OR formula = ?", models.RecordTypeEstimated1RM, utils.OneRepMaxFormulaEpley).
+			Order("achieved_at DESC").
+			Limit(clientRecentRecordsLimit).
+			Find(&records).Error; err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to retrieve personal records")
+			return
+		}
+		response.RecentRecords = buildPersonalRecordResponses(records, preferredWeightUnit)
+	}
+
+	var profile models.UserFitnessProfile
+	if err := database.DB.Where("user_id = ?", clientID).First(&profile).Error; err == nil {
+		response.TargetWeeklyWorkouts = &profile.TargetWeeklyWorkouts
+		if link.HasConsent(models.ConsentScopeHealth) {
+			response.HealthConditions = profile.HealthConditions
+			response.InjuriesNotes = profile.InjuriesNotes
+		}
+	}
+
+	if link.HasConsent(models.ConsentScopeBodyWeight) {
+		weightTrend, err := buildClientWeightTrend(clientID, now, preferredWeightUnit)
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to retrieve weight logs")
+			return
+		}
+		response.WeightTrend = &weightTrend
+	}
+
+	if link.HasConsent(models.ConsentScopeSessions) {
+		var assignments []models.TrainerAssignment
+		if err := assignmentPreloads(database.DB).
+			Where("trainer_id = ? AND client_id = ?", trainerID, clientID).
+			Order("due_date ASC NULLS LAST, created_at ASC").
+			Find(&assignments).Error; err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to retrieve assignments")
+			return
+		}
+		assignmentResps, err := assignmentResponses(assignments, true)
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to retrieve assignment progress")
+			return
+		}
+		for _, resp := range assignmentResps {
+			if isOpenAssignmentStatus(resp.Status) {
+				response.OpenAssignments = append(response.OpenAssignments, resp)
+			}
+		}
+	}
+
+	utils.SuccessResponse(c, "Client overview retrieved successfully", response)
+}
+
+// GetClientRoster summarises all of the trainer's active clients, least recently active first.
+// Clients who never completed a session come first; clients who don't share their sessions last,
+// without their activity or assignment counts.
+func GetClientRoster(c *gin.Context) {
+	trainerID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	var trainerProfile models.TrainerProfile
+	if err := database.DB.Where("user_id = ?", trainerID).First(&trainerProfile).Error; err != nil {
+		utils.ForbiddenResponse(c, "You must have a trainer profile to view clients")
+		return
+	}
+
+	var links []models.TrainerClientLink
+	if err := database.DB.Preload("Client").
+		Where("trainer_id = ? AND status = ?", trainerID, "active").
+		Find(&links).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve clients")
+		return
+	}
+
+	clientIDs := make([]uuid.UUID, len(links))
+	var sharingClientIDs []uuid.UUID
+	for i, link := range links {
+		clientIDs[i] = link.ClientID
+		if link.HasConsent(models.ConsentScopeSessions) {
+			sharingClientIDs = append(sharingClientIDs, link.ClientID)
+		}
+	}
+
+	now := time.Now()
+	activity, err := loadClientActivity(sharingClientIDs, now)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve client activity")
+		return
+	}
+
+	var profiles []models.UserFitnessProfile
+	if err := database.DB.Select("user_id", "target_weekly_workouts").
+		Where("user_id IN ?", clientIDs).
+		Find(&profiles).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve fitness profiles")
+		return
+	}
+	targets := make(map[uuid.UUID]int, len(profiles))
+	for _, profile := range profiles {
+		targets[profile.UserID] = profile.TargetWeeklyWorkouts
+	}
+
+	var assignments []models.TrainerAssignment
+	if len(sharingClientIDs) > 0 {
+		if err := database.DB.Preload("Enrollment").
+			Where("trainer_id = ? AND client_id IN ?", trainerID, sharingClientIDs).
+			Find(&assignments).Error; err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to retrieve assignments")
+			return
+		}
+	}
+	progress, err := loadAssignmentsProgress(assignments)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve assignment progress")
+		return
+	}
+
+	entries := make([]models.ClientRosterEntry, len(links))
+	entryIndex := make(map[uuid.UUID]int, len(links))
+	for i, link := range links {
+		entries[i] = models.ClientRosterEntry{
+			Client: models.UserPublicResponse{
+				ID:        link.Client.ID,
+				FirstName: link.Client.FirstName,
+				LastName:  link.Client.LastName,
+			},
+			LinkedSince:      link.ActivatedAt,
+			ConsentScopes:    link.GrantedScopes(),
+			LastSessionAt:    activity[link.ClientID].LastSessionAt,
+			SessionsThisWeek: activity[link.ClientID].SessionsThisWeek,
+		}
+		if entries[i].LastSessionAt != nil {
+			days := utils.DaysBetween(*entries[i].LastSessionAt, now)
+			entries[i].DaysSinceLastSession = &days
+		}
+		if target, ok := targets[link.ClientID]; ok {
+			entries[i].TargetWeeklyWorkouts = &target
+		}
+		entryIndex[link.ClientID] = i
+	}
+
+	for _, assignment := range assignments {
+		status := utils.AssignmentStatus(assignment.DueDate, progress[assignment.ID], now)
+		entry := &entries[entryIndex[assignment.ClientID]]
+		if isOpenAssignmentStatus(status) {
+			entry.OpenAssignments++
+		}
+		if status == models.AssignmentStatusOverdue {
+			entry.OverdueAssignments++
+		}
+	}
+
+	sortClientRoster(entries)
+
+	utils.SuccessResponse(c, "Client roster retrieved successfully", entries)
+}
+
+// loadClientActivity returns when each client last completed a session and how many sessions they
+// completed in the current ISO week. Clients without sessions are left out.
+func loadClientActivity(clientIDs []uuid.UUID, now time.Time) (map[uuid.UUID]clientActivity, error) {
+	activity := make(map[uuid.UUID]clientActivity, len(clientIDs))
+	if len(clientIDs) == 0 {
+		return activity, nil
+	}
+
+	var rows []struct {
+		UserID           uuid.UUID
+		LastSessionAt    time.Time
+		SessionsThisWeek int
+	}
+	if err := database.DB.Model(&models.WorkoutSession{}).
+		Select("user_id, MAX(started_at) AS last_session_at, COUNT(*) FILTER (WHERE started_at >= ?) AS sessions_this_week",
+			utils.StartOfISOWeek(now)).
+		Where("user_id IN ? AND completed = ?", clientIDs, true).
+		Group("user_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		lastSessionAt := row.LastSessionAt
+		activity[row.UserID] = clientActivity{
+			LastSessionAt:    &lastSessionAt,
+			SessionsThisWeek: row.SessionsThisWeek,
+		}
+	}
+	return activity, nil
+}
+
+// buildClientWeightTrend summarises the client's weight logs of the last clientWeightTrendDays
+func buildClientWeightTrend(clientID uuid.UUID, now time.Time, preferredWeightUnit string) (models.ClientWeightTrendResponse, error) {
+	trend := models.ClientWeightTrendResponse{
+		PeriodDays: clientWeightTrendDays,
+		Entries:    []models.ClientWeightPoint{},
+	}
+
+	var logs []models.WeightLog
+	if err := database.DB.
+		Where("user_id = ? AND created_at >= ?", clientID, now.AddDate(0, 0, -clientWeightTrendDays)).
+		Order("created_at ASC").
+		Find(&logs).Error; err != nil {
+		return trend, err
+	}
+	if len(logs) == 0 {
+		return trend, nil
+	}
+
+	for _, log := range logs {
+		weightKg := log.WeightKg
+		trend.Entries = append(trend.Entries, models.ClientWeightPoint{
+			LoggedAt: log.CreatedAt,
+			Weight:   utils.ConvertWeightForResponse(&weightKg, preferredWeightUnit),
+		})
+	}
+
+	latest := logs[len(logs)-1]
+	change := latest.WeightKg - logs[0].WeightKg
+	trend.Latest = utils.ConvertWeightForResponse(&latest.WeightKg, preferredWeightUnit)
+	trend.LatestLoggedAt = &latest.CreatedAt
+	trend.Change = utils.ConvertWeightForResponse(&change, preferredWeightUnit)
+
+	return trend, nil
+}
+
+// isOpenAssignmentStatus reports whether an assignment with the status still needs doing
+func isOpenAssignmentStatus(status string) bool {
+	return status == models.AssignmentStatusPending ||
+		status == models.AssignmentStatusInProgress ||
+		status == models.AssignmentStatusOverdue
+}
+
+// sortClientRoster orders roster entries by last session, oldest first with clients who never
+// trained before everyone else and clients who don't share their sessions after everyone else,
+// then by name
+func sortClientRoster(entries []models.ClientRosterEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		sharesI := slices.Contains(entries[i].ConsentScopes, models.ConsentScopeSessions)
+		sharesJ := slices.Contains(entries[j].ConsentScopes, models.ConsentScopeSessions)
+		if sharesI != sharesJ {
+			return sharesI
+		}
+
+		a, b := entries[i].LastSessionAt, entries[j].LastSessionAt
+		switch {
+		case a == nil && b != nil:
+			return true
+		case a != nil && b == nil:
+			return false
+		case a != nil && b != nil && !a.Equal(*b):
+			return a.Before(*b)
+		}
+		if entries[i].Client.FirstName != entries[j].Client.FirstName {
+			return entries[i].Client.FirstName < entries[j].Client.FirstName
+		}
+		return entries[i].Client.LastName < entries[j].Client.LastName
+	})
+}
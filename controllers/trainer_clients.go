@@ -4,8 +4,11 @@ import (
 	"lamari-fit-api/database"
 	"lamari-fit-api/models"
 	"lamari-fit-api/utils"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // InviteClient allows a trainer to invite a client
@@ -143,6 +146,11 @@ func RemoveClient(c *gin.Context) {
 		return
 	}
 
+	if err := revokeWorkoutSharesBetween(link.TrainerID, link.ClientID); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to remove shared workouts")
+		return
+	}
+
 	utils.SuccessResponse(c, "Client removed successfully", nil)
 }
 
@@ -233,6 +241,8 @@ func RespondToInvitation(c *gin.Context) {
 	// Process the response
 	if req.Action == "accept" {
 		link.Status = "active"
+		now := time.Now()
+		link.ActivatedAt = &now
 		if err := database.DB.Save(&link).Error; err != nil {
 			utils.InternalServerErrorResponse(c, "Failed to accept invitation")
 			return
@@ -250,3 +260,91 @@ func RespondToInvitation(c *gin.Context) {
 		utils.SuccessResponse(c, "Invitation rejected successfully", nil)
 	}
 }
+
+// UpdateTrainerConsent lets a client choose what one of their trainers may see or do with their
+// data. The scopes replace the ones granted so far.
+func UpdateTrainerConsent(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	linkID, ok := utils.ParseUUID(c, c.Param("id"), "link")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateTrainerConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	var link models.TrainerClientLink
+	if err := database.DB.Where("id = ? AND client_id = ? AND status IN ?", linkID, userID, []string{"pending", "active"}).
+		First(&link).Error; err != nil {
+		utils.NotFoundResponse(c, "Trainer relationship not found")
+		return
+	}
+
+	scopes := pq.StringArray{}
+	seen := make(map[string]bool, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	if err := database.DB.Model(&link).Update("consent_scopes", scopes).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to update consent")
+		return
+	}
+
+	database.DB.Preload("Trainer").Preload("Client").First(&link, "id = ?", link.ID)
+
+	utils.SuccessResponse(c, "Consent updated successfully", link.ToResponse())
+}
+
+// hasActiveClientLink reports whether the trainer has an active link with the client
+func hasActiveClientLink(trainerID, clientID uuid.UUID) bool {
+	var link models.TrainerClientLink
+	return database.DB.Where(
+		"trainer_id = ? AND client_id = ? AND status = ?",
+		trainerID, clientID, "active",
+	).First(&link).Error == nil
+}
+
+// hasClientConsent reports whether the trainer has an active link with the client that grants the
+// consent scope
+func hasClientConsent(trainerID, clientID uuid.UUID, scope string) bool {
+	var link models.TrainerClientLink
+	return database.DB.Where(
+		"trainer_id = ? AND client_id = ? AND status = ? AND ? = ANY(consent_scopes)",
+		trainerID, clientID, "active", scope,
+	).First(&link).Error == nil
+}
+
+// resolveClientDataOwner returns whose data a request is about: the client in the client_id query
+// parameter when given, or the authenticated user. Trainers need the client's consent for scope.
+// Automatically sends a forbidden response with the message if they lack it.
+func resolveClientDataOwner(c *gin.Context, authUserID uuid.UUID, scope string, message string) (uuid.UUID, bool) {
+	clientIDStr := c.Query("client_id")
+	if clientIDStr == "" {
+		return authUserID, true
+	}
+
+	clientID, ok := utils.ParseUUID(c, clientIDStr, "client")
+	if !ok {
+		return uuid.Nil, false
+	}
+	if clientID == authUserID {
+		return authUserID, true
+	}
+
+	if !hasClientConsent(authUserID, clientID, scope) {
+		utils.ForbiddenResponse(c, message)
+		return uuid.Nil, false
+	}
+	return clientID, true
+}
@@ -0,0 +1,366 @@
+package controllers
+
+import (
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"lamari-fit-api/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ListTrainerReviews retrieves a trainer's reviews, newest first. Admins also see hidden reviews.
+func ListTrainerReviews(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	trainerProfile, currentUser, ok := findTrainerForReviews(c, userID)
+	if !ok {
+		return
+	}
+
+	var query PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	SetDefaultPagination(&query)
+
+	base := database.DB.Model(&models.TrainerReview{}).Where("trainer_id = ?", trainerProfile.UserID)
+	if !currentUser.IsAdmin {
+		base = base.Where("hidden_at IS NULL")
+	}
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to count reviews")
+		return
+	}
+
+	var reviews []models.TrainerReview
+	if err := base.Session(&gorm.Session{}).
+		Preload("Reviewer").
+		Offset(query.GetOffset()).
+		Limit(query.Limit).
+		Order("created_at DESC").
+		Find(&reviews).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve reviews")
+		return
+	}
+
+	responses := make([]models.TrainerReviewResponse, len(reviews))
+	for i := range reviews {
+		responses[i] = reviews[i].ToResponse()
+	}
+
+	utils.PaginatedResponse(c, "Reviews retrieved successfully", responses, query.Page, query.Limit, int(total))
+}
+
+// CreateTrainerReview lets a current or former client review a trainer, once
+func CreateTrainerReview(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	trainerProfile, _, ok := findTrainerForReviews(c, userID)
+	if !ok {
+		return
+	}
+
+	var req models.CreateTrainerReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	if trainerProfile.UserID == userID {
+		utils.BadRequestResponse(c, "You cannot review yourself", nil)
+		return
+	}
+
+	if !hasBeenClientOf(trainerProfile.UserID, userID) {
+		utils.ForbiddenResponse(c, "Only clients of this trainer can review them")
+		return
+	}
+
+	var existing models.TrainerReview
+	if err := database.DB.Where("trainer_id = ? AND reviewer_id = ?", trainerProfile.UserID, userID).First(&existing).Error; err == nil {
+		utils.ConflictResponse(c, "You have already reviewed this trainer")
+		return
+	}
+
+	review := models.TrainerReview{
+		TrainerID:  trainerProfile.UserID,
+		ReviewerID: userID,
+		Rating:     req.Rating,
+		Comment:    req.Comment,
+	}
+
+	if err := database.DB.Create(&review).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to create review")
+		return
+	}
+
+	database.DB.Preload("Reviewer").First(&review, "id = ?", review.ID)
+
+	utils.CreatedResponse(c, "Review created successfully", review.ToResponse())
+}
+
+// UpdateTrainerReview lets a reviewer change their rating or comment
+func UpdateTrainerReview(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	trainerProfile, _, ok := findTrainerForReviews(c, userID)
+	if !ok {
+		return
+	}
+
+	review, ok := findTrainerReview(c, trainerProfile.UserID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateTrainerReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	if review.ReviewerID != userID {
+		utils.ForbiddenResponse(c, "You can only update your own reviews")
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if req.Rating != nil {
+		updates["rating"] = *req.Rating
+	}
+	if req.Comment != nil {
+		updates["comment"] = *req.Comment
+	}
+
+	if len(updates) > 0 {
+		if err := database.DB.Model(review).Updates(updates).Error; err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to update review")
+			return
+		}
+	}
+
+	database.DB.Preload("Reviewer").First(review, "id = ?", review.ID)
+
+	utils.SuccessResponse(c, "Review updated successfully", review.ToResponse())
+}
+
+// DeleteTrainerReview lets a reviewer, or an admin, delete a review
+func DeleteTrainerReview(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	trainerProfile, currentUser, ok := findTrainerForReviews(c, userID)
+	if !ok {
+		return
+	}
+
+	review, ok := findTrainerReview(c, trainerProfile.UserID)
+	if !ok {
+		return
+	}
+
+	if review.ReviewerID != userID && !currentUser.IsAdmin {
+		utils.ForbiddenResponse(c, "You can only delete your own reviews")
+		return
+	}
+
+	if err := database.DB.Delete(review).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to delete review")
+		return
+	}
+
+	utils.SuccessResponse(c, "Review deleted successfully", nil)
+}
+
+// ReplyToTrainerReview lets the reviewed trainer reply publicly to a review. Replying again
+// replaces the previous reply.
+func ReplyToTrainerReview(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	trainerProfile, _, ok := findTrainerForReviews(c, userID)
+	if !ok {
+		return
+	}
+
+	review, ok := findTrainerReview(c, trainerProfile.UserID)
+	if !ok {
+		return
+	}
+
+	var req models.ReplyToTrainerReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	if trainerProfile.UserID != userID {
+		utils.ForbiddenResponse(c, "Only the trainer can reply to their reviews")
+		return
+	}
+
+	if err := database.DB.Model(review).Updates(map[string]interface{}{
+		"reply":      req.Reply,
+		"replied_at": time.Now(),
+	}).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to reply to review")
+		return
+	}
+
+	database.DB.Preload("Reviewer").First(review, "id = ?", review.ID)
+
+	utils.SuccessResponse(c, "Reply saved successfully", review.ToResponse())
+}
+
+// ModerateTrainerReview lets an admin hide a review, which removes it from the trainer's public
+// reviews and rating, or show it again
+func ModerateTrainerReview(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	trainerProfile, currentUser, ok := findTrainerForReviews(c, userID)
+	if !ok {
+		return
+	}
+
+	if !currentUser.IsAdmin {
+		utils.ForbiddenResponse(c, "Admin access required")
+		return
+	}
+
+	review, ok := findTrainerReview(c, trainerProfile.UserID)
+	if !ok {
+		return
+	}
+
+	var req models.ModerateTrainerReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	updates := map[string]interface{}{
+		"hidden_at":     nil,
+		"hidden_reason": "",
+	}
+	if *req.Hidden {
+		updates["hidden_at"] = time.Now()
+		updates["hidden_reason"] = req.Reason
+	}
+
+	if err := database.DB.Model(review).Updates(updates).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to moderate review")
+		return
+	}
+
+	database.DB.Preload("Reviewer").First(review, "id = ?", review.ID)
+
+	utils.SuccessResponse(c, "Review moderated successfully", review.ToResponse())
+}
+
+// findTrainerForReviews loads the trainer profile in the :id parameter with the same visibility
+// rules as GetTrainerPublicProfile, along with the current user
+func findTrainerForReviews(c *gin.Context, userID uuid.UUID) (*models.TrainerProfile, *models.User, bool) {
+	trainerID, ok := utils.ParseUUID(c, c.Param("id"), "trainer")
+	if !ok {
+		return nil, nil, false
+	}
+
+	var currentUser models.User
+	if err := database.DB.First(&currentUser, "id = ?", userID).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to retrieve user")
+		return nil, nil, false
+	}
+
+	var trainerProfile models.TrainerProfile
+	if err := database.DB.Preload("User").First(&trainerProfile, "id = ?", trainerID).Error; err != nil ||
+		trainerProfile.User.IsPendingDeletion() {
+		utils.NotFoundResponse(c, "Trainer not found")
+		return nil, nil, false
+	}
+
+	if trainerProfile.Visibility == "private" && trainerProfile.UserID != userID && !currentUser.IsAdmin {
+		utils.NotFoundResponse(c, "Trainer not found")
+		return nil, nil, false
+	}
+
+	return &trainerProfile, &currentUser, true
+}
+
+// findTrainerReview loads the review in the :review_id parameter from a trainer's reviews
+func findTrainerReview(c *gin.Context, trainerUserID uuid.UUID) (*models.TrainerReview, bool) {
+	reviewID, ok := utils.ParseUUID(c, c.Param("review_id"), "review")
+	if !ok {
+		return nil, false
+	}
+
+	var review models.TrainerReview
+	if err := database.DB.Where("id = ? AND trainer_id = ?", reviewID, trainerUserID).First(&review).Error; err != nil {
+		utils.NotFoundResponse(c, "Review not found")
+		return nil, false
+	}
+	return &review, true
+}
+
+// hasBeenClientOf reports whether the client has, or had, an active link with the trainer
+func hasBeenClientOf(trainerID, clientID uuid.UUID) bool {
+	var count int64
+	database.DB.Model(&models.TrainerClientLink{}).
+		Where("trainer_id = ? AND client_id = ? AND (status = ? OR activated_at IS NOT NULL)", trainerID, clientID, "active").
+		Count(&count)
+	return count > 0
+}
+
+// ratedTrainerReviews returns the reviews that count toward trainers' ratings
+func ratedTrainerReviews() *gorm.DB {
+	return database.DB.Model(&models.TrainerReview{}).Where("hidden_at IS NULL")
+}
+
+// loadTrainerReviewStats returns the review stats of trainers, keyed by the trainers' user IDs
+func loadTrainerReviewStats(trainerUserIDs []uuid.UUID) map[uuid.UUID]models.TrainerReviewStats {
+	stats := make(map[uuid.UUID]models.TrainerReviewStats, len(trainerUserIDs))
+	if len(trainerUserIDs) == 0 {
+		return stats
+	}
+
+	var rows []models.TrainerReviewStats
+	ratedTrainerReviews().
+		Select("trainer_id, COUNT(*) AS review_count, AVG(rating) AS average_rating").
+		Where("trainer_id IN ?", trainerUserIDs).
+		Group("trainer_id").
+		Scan(&rows)
+
+	for _, row := range rows {
+		stats[row.TrainerID] = row
+	}
+	return stats
+}
+
+// joinTrainerReviewStats joins each trainer profile's average rating as review_stats.average_rating,
+// so trainers can be filtered and sorted by rating in the database
+func joinTrainerReviewStats(query *gorm.DB) *gorm.DB {
+	return query.Joins("LEFT JOIN (?) AS review_stats ON review_stats.trainer_id = trainer_profiles.user_id",
+		ratedTrainerReviews().Select("trainer_id, AVG(rating) AS average_rating").Group("trainer_id"))
+}
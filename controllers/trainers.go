@@ -6,6 +6,7 @@ import (
 	"lamari-fit-api/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -205,7 +206,7 @@ func DeleteTrainerProfile(c *gin.Context) {
 
 	// Check for active trainer-client relationships
 	var activeCount int64
-	database.DB.Model(&models.TrainerClientLink{}).Where("trainer_id = ? AND status = ?", trainerProfile.ID, "active").Count(&activeCount)
+	database.DB.Model(&models.TrainerClientLink{}).Where("trainer_id = ? AND status = ?", userID, "active").Count(&activeCount)
 	if activeCount > 0 {
 		utils.ConflictResponse(c, "Cannot delete trainer profile with active client relationships")
 		return
@@ -250,6 +251,11 @@ func GetTrainerPublicProfile(c *gin.Context) {
 		return
 	}
 
+	if trainerProfile.User.IsPendingDeletion() {
+		utils.NotFoundResponse(c, "Trainer not found")
+		return
+	}
+
 	// Check visibility access
 	// - public: anyone can view
 	// - link_only: anyone with the ID can view
@@ -261,16 +267,10 @@ func GetTrainerPublicProfile(c *gin.Context) {
 		}
 	}
 
-	// Calculate review statistics
-	var reviewCount int64
-	var avgRating float64
+	// Reviews are linked to the trainer's user, not their profile
+	stats := loadTrainerReviewStats([]uuid.UUID{trainerProfile.UserID})[trainerProfile.UserID]
 
-	database.DB.Model(&models.TrainerReview{}).Where("trainer_id = ?", trainerID).Count(&reviewCount)
-	if reviewCount > 0 {
-		database.DB.Model(&models.TrainerReview{}).Where("trainer_id = ?", trainerID).Select("COALESCE(AVG(rating), 0)").Scan(&avgRating)
-	}
-
-	utils.SuccessResponse(c, "Trainer retrieved successfully", trainerProfile.ToPublicResponse(int(reviewCount), avgRating))
+	utils.SuccessResponse(c, "Trainer retrieved successfully", trainerProfile.ToPublicResponse(stats.ReviewCount, stats.AverageRating))
 }
 
 // ListTrainers retrieves a paginated list of trainers with optional filtering
@@ -286,7 +286,8 @@ func ListTrainers(c *gin.Context) {
 	query := database.DB.Model(&models.TrainerProfile{}).Preload("User").Preload("Specialties")
 
 	// Only show public trainers in list
-	query = query.Where("visibility = ?", "public")
+	query = query.Where("visibility = ?", "public").
+		Scopes(excludePendingDeletion("trainer_profiles.user_id"), joinTrainerReviewStats)
 
 	// Search by user first_name, last_name, or location fields
 	if queryParams.Search != "" {
@@ -317,6 +318,11 @@ func ListTrainers(c *gin.Context) {
 		query = query.Where("is_looking_for_clients = ?", false)
 	}
 
+	// Filter by min_rating (trainers without reviews have a rating of 0)
+	if queryParams.MinRating > 0 {
+		query = query.Where("COALESCE(review_stats.average_rating, 0) >= ?", queryParams.MinRating)
+	}
+
 	// Get total count before pagination
 	var total int64
 	countQuery := query.Session(&gorm.Session{})
@@ -324,6 +330,8 @@ func ListTrainers(c *gin.Context) {
 
 	// Apply sorting
 	switch queryParams.SortBy {
+	case "rating":
+		query = query.Order("review_stats.average_rating DESC NULLS LAST").Order("trainer_profiles.created_at DESC")
 	case "rate":
 		query = query.Order("hourly_rate ASC")
 	case "recent":
@@ -341,29 +349,16 @@ func ListTrainers(c *gin.Context) {
 	}
 
 	// Build public responses with review stats
-	responses := make([]models.TrainerPublicResponse, len(trainers))
+	trainerUserIDs := make([]uuid.UUID, len(trainers))
 	for i, trainer := range trainers {
-		var reviewCount int64
-		var avgRating float64
-
-		database.DB.Model(&models.TrainerReview{}).Where("trainer_id = ?", trainer.ID).Count(&reviewCount)
-		if reviewCount > 0 {
-			database.DB.Model(&models.TrainerReview{}).Where("trainer_id = ?", trainer.ID).Select("COALESCE(AVG(rating), 0)").Scan(&avgRating)
-		}
-
-		responses[i] = trainer.ToPublicResponse(int(reviewCount), avgRating)
+		trainerUserIDs[i] = trainer.UserID
 	}
+	stats := loadTrainerReviewStats(trainerUserIDs)
 
-	// Filter by min_rating if specified (post-query filter since we need to calculate ratings)
-	if queryParams.MinRating > 0 {
-		filteredResponses := make([]models.TrainerPublicResponse, 0)
-		for _, resp := range responses {
-			if resp.AverageRating >= queryParams.MinRating {
-				filteredResponses = append(filteredResponses, resp)
-			}
-		}
-		responses = filteredResponses
-		total = int64(len(responses))
+	responses := make([]models.TrainerPublicResponse, len(trainers))
+	for i, trainer := range trainers {
+		trainerStats := stats[trainer.UserID]
+		responses[i] = trainer.ToPublicResponse(trainerStats.ReviewCount, trainerStats.AverageRating)
 	}
 
 	utils.PaginatedResponse(c, "Trainers retrieved successfully", responses, queryParams.Page, queryParams.Limit, int(total))
@@ -0,0 +1,478 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"lamari-fit-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxTrainingHistoryBytes caps the size of uploaded training history exports
+const maxTrainingHistoryBytes = 20 << 20
+
+// exerciseSuggestionLimit is how many catalogue exercises are suggested per exported exercise name
+const exerciseSuggestionLimit = 3
+
+// trainingImport is a parsed training history export with its exercise names mapped to the
+// catalogue
+type trainingImport struct {
+	history     *utils.ParsedTrainingHistory
+	mappings    []models.ExerciseMappingResponse
+	exerciseIDs map[string]uuid.UUID // Exported name to exercise, for matched and confirmed names
+	duplicates  map[int]bool         // Workouts (by index) already imported
+}
+
+// unmatched returns the mappings still waiting for the user to choose an exercise
+func (ti *trainingImport) unmatched() []models.ExerciseMappingResponse {
+	var unmatched []models.ExerciseMappingResponse
+	for _, mapping := range ti.mappings {
+		if mapping.Status == models.ExerciseMappingUnmatched {
+			unmatched = append(unmatched, mapping)
+		}
+	}
+	return unmatched
+}
+
+// PreviewTrainingHistoryImport parses a Strong or Hevy CSV export and reports how its exercise
+// names map to the catalogue, without importing anything. Names that can't be matched with
+// confidence come with suggestions for the user to confirm or override through mappings.
+func PreviewTrainingHistoryImport(c *gin.Context) {
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.ImportTrainingHistoryRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	ti, ok := prepareTrainingImport(c, authUserID, req)
+	if !ok {
+		return
+	}
+
+	workouts := ti.history.Workouts
+	response := models.TrainingImportPreviewResponse{
+		Format:         ti.history.Format,
+		WorkoutCount:   len(workouts),
+		DuplicateCount: len(ti.duplicates),
+		FirstWorkoutAt: workouts[0].StartedAt,
+		LastWorkoutAt:  workouts[len(workouts)-1].StartedAt,
+		Ready:          len(ti.unmatched()) == 0,
+		Exercises:      ti.mappings,
+	}
+	for _, workout := range workouts {
+		response.SetCount += len(workout.Sets)
+	}
+
+	utils.SuccessResponse(c, "Training history import previewed successfully", response)
+}
+
+// ImportTrainingHistory imports a Strong or Hevy CSV export as completed workout sessions.
+// Every exercise name must be matched automatically or mapped by the user (to an exercise, or to
+// "" to leave it out). Workouts already imported (same start time) are skipped.
+func ImportTrainingHistory(c *gin.Context) {
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.ImportTrainingHistoryRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	ti, ok := prepareTrainingImport(c, authUserID, req)
+	if !ok {
+		return
+	}
+
+	if unmatched := ti.unmatched(); len(unmatched) > 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Some exercises need to be mapped before importing", unmatched)
+		return
+	}
+
+	rpeValueIDs := globalRPEValueIDs()
+
+	result := models.TrainingImportResultResponse{
+		DuplicateCount: len(ti.duplicates),
+		SessionIDs:     []uuid.UUID{},
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// Oldest first, so personal records are set in the order they were achieved
+		for i, workout := range ti.history.Workouts {
+			if ti.duplicates[i] {
+				continue
+			}
+			session, skippedSets, err := createHistoricalSession(tx, authUserID, workout, ti.exerciseIDs, rpeValueIDs)
+			if err != nil {
+				return err
+			}
+			result.SkippedSets += skippedSets
+			if session == nil {
+				continue
+			}
+			if err := updatePersonalRecordsForSession(tx, *session); err != nil {
+				return err
+			}
+			result.ImportedCount++
+			result.SessionIDs = append(result.SessionIDs, session.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to import training history")
+		return
+	}
+
+	utils.CreatedResponse(c, "Training history imported successfully", result)
+}
+
+// prepareTrainingImport reads and parses the uploaded export and maps its exercise names. It
+// writes the error response and returns false if the request can't be processed.
+func prepareTrainingImport(c *gin.Context, userID uuid.UUID, req models.ImportTrainingHistoryRequest) (*trainingImport, bool) {
+	if req.File.Size > maxTrainingHistoryBytes {
+		utils.BadRequestResponse(c, "Training history file is too large (20 MB max)", nil)
+		return nil, false
+	}
+
+	loc := time.UTC
+	if req.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(req.Timezone); err != nil {
+			utils.ValidationErrorResponse(c, utils.ValidationErrors{"timezone": {"Unknown time zone"}})
+			return nil, false
+		}
+	}
+
+	var mappings map[string]string
+	if req.Mappings != "" {
+		if err := json.Unmarshal([]byte(req.Mappings), &mappings); err != nil {
+			utils.ValidationErrorResponse(c, utils.ValidationErrors{"mappings": {"Must be a JSON object of exercise name to exercise ID"}})
+			return nil, false
+		}
+	}
+
+	weightUnit := req.WeightUnit
+	if weightUnit == "" {
+		weightUnit = getUserPreferredWeightUnit(c, userID)
+	}
+	distanceUnit := req.DistanceUnit
+	if distanceUnit == "" {
+		distanceUnit = getUserPreferredDistanceUnit(c, userID)
+	}
+
+	file, err := req.File.Open()
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to read training history file", nil)
+		return nil, false
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxTrainingHistoryBytes))
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to read training history file", nil)
+		return nil, false
+	}
+
+	history, err := utils.ParseTrainingHistoryCSV(data, weightUnit, distanceUnit, loc)
+	if errors.Is(err, utils.ErrUnsupportedTrainingFormat) {
+		utils.BadRequestResponse(c, "Unsupported training history file, expected a Strong or Hevy CSV export", nil)
+		return nil, false
+	}
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid training history file: "+err.Error(), nil)
+		return nil, false
+	}
+
+	candidates, err := loadExerciseCandidates()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load exercises")
+		return nil, false
+	}
+
+	ti := &trainingImport{
+		history:     history,
+		exerciseIDs: make(map[string]uuid.UUID),
+	}
+	if !ti.mapExercises(c, candidates, mappings) {
+		return nil, false
+	}
+
+	ti.duplicates, err = findImportedWorkouts(userID, history.Workouts)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to check for duplicate workouts")
+		return nil, false
+	}
+
+	return ti, true
+}
+
+// mapExercises maps every exported exercise name to the catalogue, applying the user's mappings
+// first. It writes the error response and returns false if a mapping is invalid.
+func (ti *trainingImport) mapExercises(c *gin.Context, candidates []utils.ExerciseCandidate, mappings map[string]string) bool {
+	byID := make(map[uuid.UUID]utils.ExerciseCandidate, len(candidates))
+	for _, candidate := range candidates {
+		byID[candidate.ID] = candidate
+	}
+
+	setCounts := make(map[string]int)
+	for _, workout := range ti.history.Workouts {
+		for _, set := range workout.Sets {
+			setCounts[set.ExerciseName]++
+		}
+	}
+
+	validationErrors := utils.ValidationErrors{}
+	for _, name := range ti.history.ExerciseNames() {
+		mapping := models.ExerciseMappingResponse{
+			Name:        name,
+			SetCount:    setCounts[name],
+			Suggestions: []models.ExerciseMatchResponse{},
+		}
+		for _, match := range utils.MatchExerciseName(name, candidates, exerciseSuggestionLimit) {
+			mapping.Suggestions = append(mapping.Suggestions, models.ExerciseMatchResponse{
+				ExerciseID: match.ExerciseID,
+				Name:       match.ExerciseName,
+				Score:      match.Score,
+			})
+		}
+
+		override, mapped := mappings[name]
+		switch {
+		case mapped && override == "":
+			mapping.Status = models.ExerciseMappingSkipped
+		case mapped:
+			id, err := uuid.Parse(override)
+			candidate, found := byID[id]
+			if err != nil || !found {
+				validationErrors["mappings"] = append(validationErrors["mappings"], fmt.Sprintf("Exercise not found for %q", name))
+				continue
+			}
+			mapping.Status = models.ExerciseMappingConfirmed
+			mapping.Exercise = &models.ExerciseMatchResponse{
+				ExerciseID: candidate.ID,
+				Name:       candidate.Name,
+				Score:      math.Round(utils.ExerciseNameSimilarity(name, candidate.Name)*100) / 100,
+			}
+		case len(mapping.Suggestions) > 0 && mapping.Suggestions[0].Score >= utils.ExerciseMatchThreshold:
+			mapping.Status = models.ExerciseMappingMatched
+			best := mapping.Suggestions[0]
+			mapping.Exercise = &best
+		default:
+			mapping.Status = models.ExerciseMappingUnmatched
+		}
+
+		if mapping.Exercise != nil {
+			ti.exerciseIDs[name] = mapping.Exercise.ExerciseID
+		}
+		ti.mappings = append(ti.mappings, mapping)
+	}
+
+	if len(validationErrors) > 0 {
+		utils.ValidationErrorResponse(c, validationErrors)
+		return false
+	}
+	return true
+}
+
+// loadExerciseCandidates returns the catalogue exercises with the names they can be matched by:
+// name, slug and translated names
+func loadExerciseCandidates() ([]utils.ExerciseCandidate, error) {
+	var exercises []models.Exercise
+	if err := database.DB.Select("id", "name", "slug").Find(&exercises).Error; err != nil {
+		return nil, err
+	}
+
+	var translations []models.Translation
+	if err := database.DB.
+		Select("resource_id", "content").
+		Where("resource_type = ? AND field_name = ?", "exercise", "name").
+		Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	translatedNames := make(map[uuid.UUID][]string)
+	for _, translation := range translations {
+		translatedNames[translation.ResourceID] = append(translatedNames[translation.ResourceID], translation.Content)
+	}
+
+	candidates := make([]utils.ExerciseCandidate, 0, len(exercises))
+	for _, exercise := range exercises {
+		names := []string{exercise.Name, strings.ReplaceAll(exercise.Slug, "-", " ")}
+		candidates = append(candidates, utils.ExerciseCandidate{
+			ID:    exercise.ID,
+			Name:  exercise.Name,
+			Names: append(names, translatedNames[exercise.ID]...),
+		})
+	}
+	return candidates, nil
+}
+
+// findImportedWorkouts returns the indexes of the workouts the user already has a session for,
+// starting at the same time
+func findImportedWorkouts(userID uuid.UUID, workouts []utils.ImportedWorkout) (map[int]bool, error) {
+	var startTimes []time.Time
+	if err := database.DB.Model(&models.WorkoutSession{}).
+		Where("user_id = ? AND started_at BETWEEN ? AND ?", userID, workouts[0].StartedAt, workouts[len(workouts)-1].StartedAt).
+		Pluck("started_at", &startTimes).Error; err != nil {
+		return nil, err
+	}
+
+	existing := make(map[int64]bool, len(startTimes))
+	for _, startedAt := range startTimes {
+		existing[startedAt.Unix()] = true
+	}
+
+	duplicates := make(map[int]bool)
+	for i, workout := range workouts {
+		if existing[workout.StartedAt.Unix()] {
+			duplicates[i] = true
+		}
+	}
+	return duplicates, nil
+}
+
+// globalRPEValueIDs returns the values of the global RPE scale by their RPE
+func globalRPEValueIDs() map[int]uuid.UUID {
+	ids := make(map[int]uuid.UUID)
+	var scale models.RPEScale
+	if err := database.DB.Preload("Values").First(&scale, "is_global = ?", true).Error; err != nil {
+		return ids
+	}
+	for _, value := range scale.Values {
+		ids[value.Value] = value.ID
+	}
+	return ids
+}
+
+// createHistoricalSession stores an imported workout as a completed session, with a block per
+// run of consecutive sets of an exercise. Sets of skipped exercises are left out and counted; if
+// none are left, no session is created.
+func createHistoricalSession(tx *gorm.DB, userID uuid.UUID, workout utils.ImportedWorkout, exerciseIDs map[string]uuid.UUID, rpeValueIDs map[int]uuid.UUID) (*models.WorkoutSession, int, error) {
+	skippedSets := 0
+	var sets []utils.ImportedSet
+	for _, set := range workout.Sets {
+		if _, ok := exerciseIDs[set.ExerciseName]; ok {
+			sets = append(sets, set)
+		} else {
+			skippedSets++
+		}
+	}
+	if len(sets) == 0 {
+		return nil, skippedSets, nil
+	}
+
+	endedAt := workout.StartedAt
+	if workout.DurationSeconds != nil {
+		endedAt = workout.StartedAt.Add(time.Duration(*workout.DurationSeconds) * time.Second)
+	}
+	session := models.WorkoutSession{
+		UserID:          userID,
+		CreatedByID:     &userID,
+		StartedAt:       workout.StartedAt,
+		EndedAt:         &endedAt,
+		DurationSeconds: workout.DurationSeconds,
+		Notes:           strings.TrimSpace(workout.Title + "\n" + workout.Notes),
+		Completed:       true,
+	}
+	if err := tx.Create(&session).Error; err != nil {
+		return nil, skippedSets, err
+	}
+
+	var exercise models.SessionExercise
+	var sessionSets []models.SessionSet
+	blockOrder := 0
+	for i, set := range sets {
+		if i == 0 || set.ExerciseName != sets[i-1].ExerciseName {
+			blockOrder++
+			block := models.SessionBlock{
+				SessionID:   session.ID,
+				GroupID:     uuid.New(),
+				BlockOrder:  blockOrder,
+				StartedAt:   &session.StartedAt,
+				CompletedAt: &endedAt,
+			}
+			if err := tx.Create(&block).Error; err != nil {
+				return nil, skippedSets, err
+			}
+
+			exercise = models.SessionExercise{
+				SessionBlockID: block.ID,
+				ExerciseID:     exerciseIDs[set.ExerciseName],
+				ExerciseOrder:  1,
+				StartedAt:      &session.StartedAt,
+				CompletedAt:    &endedAt,
+			}
+			if err := tx.Create(&exercise).Error; err != nil {
+				return nil, skippedSets, err
+			}
+		}
+
+		setNumber := 1
+		if n := len(sessionSets); n > 0 && sessionSets[n-1].SessionExerciseID == exercise.ID {
+			setNumber = sessionSets[n-1].SetNumber + 1
+		}
+		sessionSets = append(sessionSets, buildHistoricalSet(exercise.ID, setNumber, set, endedAt, rpeValueIDs))
+	}
+
+	if err := tx.CreateInBatches(&sessionSets, 500).Error; err != nil {
+		return nil, skippedSets, err
+	}
+
+	return &session, skippedSets, nil
+}
+
+// buildHistoricalSet converts an imported set to a completed session set, keeping the weight and
+// distance in the units they were logged in
+func buildHistoricalSet(exerciseID uuid.UUID, setNumber int, set utils.ImportedSet, completedAt time.Time, rpeValueIDs map[int]uuid.UUID) models.SessionSet {
+	sessionSet := models.SessionSet{
+		SessionExerciseID:     exerciseID,
+		SetNumber:             setNumber,
+		Round:                 setNumber,
+		SequenceOrder:         setNumber,
+		Completed:             true,
+		CompletedAt:           &completedAt,
+		ActualReps:            set.Reps,
+		ActualDurationSeconds: set.DurationSeconds,
+		WasFailure:            set.WasFailure,
+		Notes:                 set.Notes,
+	}
+
+	if set.WeightValue != nil {
+		weightKg := utils.ConvertToKg(*set.WeightValue, set.WeightUnit)
+		unit := set.WeightUnit
+		sessionSet.ActualWeightKg = &weightKg
+		sessionSet.OriginalActualWeightValue = set.WeightValue
+		sessionSet.OriginalActualWeightUnit = &unit
+	}
+	if set.DistanceValue != nil {
+		meters := utils.ConvertToMeters(*set.DistanceValue, set.DistanceUnit)
+		unit := set.DistanceUnit
+		sessionSet.ActualDistanceMeters = &meters
+		sessionSet.OriginalActualDistanceValue = set.DistanceValue
+		sessionSet.OriginalActualDistanceUnit = &unit
+	}
+	// Half-point RPEs are rounded up to the whole-point scale
+	if set.RPE != nil {
+		if id, ok := rpeValueIDs[int(math.Round(*set.RPE))]; ok {
+			sessionSet.RPEValueID = &id
+		}
+	}
+
+	return sessionSet
+}
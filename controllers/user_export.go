@@ -0,0 +1,596 @@
+package controllers
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"lamari-fit-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// exportSessionBatchSize is how many sessions are loaded at a time while streaming an export
+const exportSessionBatchSize = 50
+
+// userExportData holds the parts of a data export small enough to load up front
+type userExportData struct {
+	user         models.User
+	weightUnit   string
+	distanceUnit string
+	profile      *models.UserFitnessProfileResponse
+	weightLogs   []models.ExportWeightLog
+	workouts     []models.ExportWorkout
+	plans        []models.ExportWorkoutPlan
+	favorites    []models.ExportFavorite
+}
+
+// userExport writes a user's data to a zip archive, one file per kind of data
+type userExport struct {
+	zip    *zip.Writer
+	format string // csv or json
+	data   *userExportData
+}
+
+// ExportUserData streams a zip archive of all of the authenticated user's training data: account,
+// fitness profile, weight logs, workouts, plans, favorites and the full tree of every session.
+// format=json (default) writes one JSON file per kind of data, format=csv flat CSV tables.
+func ExportUserData(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		utils.BadRequestResponse(c, "Invalid format. Use 'csv' or 'json'.", nil)
+		return
+	}
+
+	// Load everything but the sessions up front, so failures can still be reported as JSON
+	data, err := loadUserExportData(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		utils.NotFoundResponse(c, "User not found")
+		return
+	}
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to export data")
+		return
+	}
+
+	filename := fmt.Sprintf("lamarifit-export-%s.zip", time.Now().Format(utils.DateFormat))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	export := userExport{zip: zip.NewWriter(c.Writer), format: format, data: data}
+	if err := export.write(); err != nil {
+		// The archive is left unterminated, so the client sees a corrupt download
+		_ = c.Error(err)
+		return
+	}
+	_ = export.zip.Close()
+}
+
+// loadUserExportData loads the user's account, profile, weight logs, workouts, plans and
+// favorites, with weights and distances in the user's preferred units
+func loadUserExportData(userID uuid.UUID) (*userExportData, error) {
+	d := &userExportData{}
+	if err := database.DB.First(&d.user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	d.weightUnit = utils.GetUserPreferredWeightUnit(d.user.PreferredWeightUnit)
+	d.distanceUnit = utils.GetUserPreferredDistanceUnit(d.user.PreferredDistanceUnit)
+
+	var profile models.UserFitnessProfile
+	err := database.DB.
+		Preload("FitnessGoals.FitnessGoal").
+		Preload("FitnessLevel").
+		Where("user_id = ?", userID).
+		First(&profile).Error
+	if err == nil {
+		response := profile.ToResponse(d.weightUnit)
+		d.profile = &response
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var logs []models.WeightLog
+	if err := database.DB.Where("user_id = ?", userID).Order("created_at ASC").Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	d.weightLogs = make([]models.ExportWeightLog, 0, len(logs))
+	for _, log := range logs {
+		d.weightLogs = append(d.weightLogs, models.ExportWeightLog{
+			ID:        log.ID,
+			Weight:    utils.ConvertWeightForResponse(&log.WeightKg, d.weightUnit),
+			Notes:     log.Notes,
+			CreatedAt: log.CreatedAt,
+		})
+	}
+
+	var workouts []models.Workout
+	if err := database.DB.
+		Where("user_id = ?", userID).
+		Preload("Prescriptions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("group_order ASC, exercise_order ASC")
+		}).
+		Preload("Prescriptions.Exercise").
+		Preload("Prescriptions.RPEValue").
+		Order("created_at ASC").
+		Find(&workouts).Error; err != nil {
+		return nil, err
+	}
+	d.workouts = make([]models.ExportWorkout, 0, len(workouts))
+	for _, workout := range workouts {
+		groups := models.GroupPrescriptionsByGroupID(workout.Prescriptions)
+		models.PopulatePrescriptionWeights(groups, workout.Prescriptions, d.weightUnit)
+		models.PopulatePrescriptionDistances(groups, workout.Prescriptions, d.distanceUnit)
+		d.workouts = append(d.workouts, models.ExportWorkout{
+			ID:                workout.ID,
+			Title:             workout.Title,
+			Description:       workout.Description,
+			DifficultyLevel:   workout.DifficultyLevel,
+			EstimatedDuration: workout.EstimatedDuration,
+			IsTemplate:        workout.IsTemplate,
+			Visibility:        workout.Visibility,
+			Prescriptions:     groups,
+			CreatedAt:         workout.CreatedAt,
+			UpdatedAt:         workout.UpdatedAt,
+		})
+	}
+
+	var plans []models.WorkoutPlan
+	if err := database.DB.
+		Where("user_id = ?", userID).
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("week_index ASC, created_at ASC")
+		}).
+		Preload("Items.Workout").
+		Order("created_at ASC").
+		Find(&plans).Error; err != nil {
+		return nil, err
+	}
+	d.plans = make([]models.ExportWorkoutPlan, 0, len(plans))
+	for _, plan := range plans {
+		exportPlan := models.ExportWorkoutPlan{
+			ID:            plan.ID,
+			Title:         plan.Title,
+			Description:   plan.Description,
+			Visibility:    plan.Visibility,
+			TemplateWeeks: plan.TemplateWeeks,
+			Items:         make([]models.ExportWorkoutPlanItem, 0, len(plan.Items)),
+			CreatedAt:     plan.CreatedAt,
+			UpdatedAt:     plan.UpdatedAt,
+		}
+		for _, item := range plan.Items {
+			exportPlan.Items = append(exportPlan.Items, models.ExportWorkoutPlanItem{
+				WorkoutID:    item.WorkoutID,
+				WorkoutTitle: item.Workout.Title,
+				WeekIndex:    item.WeekIndex,
+			})
+		}
+		d.plans = append(d.plans, exportPlan)
+	}
+
+	var favoriteExercises []models.UserFavoriteExercise
+	if err := database.DB.Where("user_id = ?", userID).Preload("Exercise").Order("created_at ASC").Find(&favoriteExercises).Error; err != nil {
+		return nil, err
+	}
+	var favoriteWorkouts []models.UserFavoriteWorkout
+	if err := database.DB.Where("user_id = ?", userID).Preload("Workout").Order("created_at ASC").Find(&favoriteWorkouts).Error; err != nil {
+		return nil, err
+	}
+	d.favorites = make([]models.ExportFavorite, 0, len(favoriteExercises)+len(favoriteWorkouts))
+	for _, favorite := range favoriteExercises {
+		d.favorites = append(d.favorites, models.ExportFavorite{
+			Type:        "exercise",
+			ID:          favorite.ExerciseID,
+			Name:        favorite.Exercise.Name,
+			FavoritedAt: favorite.CreatedAt,
+		})
+	}
+	for _, favorite := range favoriteWorkouts {
+		d.favorites = append(d.favorites, models.ExportFavorite{
+			Type:        "workout",
+			ID:          favorite.WorkoutID,
+			Name:        favorite.Workout.Title,
+			FavoritedAt: favorite.CreatedAt,
+		})
+	}
+
+	return d, nil
+}
+
+// write adds every file of the export to the archive, streaming the sessions in batches
+func (e *userExport) write() error {
+	if e.format == "csv" {
+		return e.writeCSV()
+	}
+	return e.writeJSON()
+}
+
+// ===== JSON =====
+
+func (e *userExport) writeJSON() error {
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"account.json", e.data.user.ToResponse()},
+		{"fitness_profile.json", e.data.profile},
+		{"weight_logs.json", e.data.weightLogs},
+		{"workouts.json", e.data.workouts},
+		{"workout_plans.json", e.data.plans},
+		{"favorites.json", e.data.favorites},
+	}
+	for _, file := range files {
+		w, err := e.zip.Create(file.name)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return err
+		}
+	}
+
+	// Sessions are written as one JSON array, a batch at a time
+	w, err := e.zip.Create("workout_sessions.json")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	first := true
+	err = e.eachSessionBatch(true, func(sessions []models.WorkoutSession) error {
+		for _, session := range sessions {
+			encoded, err := json.MarshalIndent(models.BuildSessionResponse(session, e.data.weightUnit, e.data.distanceUnit), "  ", "  ")
+			if err != nil {
+				return err
+			}
+			separator := ",\n  "
+			if first {
+				separator = "\n  "
+				first = false
+			}
+			if _, err := io.WriteString(w, separator); err != nil {
+				return err
+			}
+			if _, err := w.Write(encoded); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n]\n")
+	return err
+}
+
+// ===== CSV =====
+
+func (e *userExport) writeCSV() error {
+	weightColumn := "weight_" + e.data.weightUnit
+	distanceColumn := "distance_" + e.data.distanceUnit
+
+	user := e.data.user
+	if err := e.csvFile("account.csv",
+		[]string{"id", "email", "first_name", "last_name", "provider", "preferred_weight_unit", "preferred_distance_unit", "profile_visibility", "bio", "created_at"},
+		[][]string{{user.ID.String(), user.Email, user.FirstName, user.LastName, user.Provider, user.PreferredWeightUnit, user.PreferredDistanceUnit, user.ProfileVisibility, user.Bio, csvTime(&user.CreatedAt)}},
+	); err != nil {
+		return err
+	}
+
+	var profileRows [][]string
+	if profile := e.data.profile; profile != nil {
+		goals := make([]string, 0, len(profile.FitnessGoals))
+		for _, goal := range profile.FitnessGoals {
+			goals = append(goals, goal.FitnessGoal.Name)
+		}
+		fitnessLevel := ""
+		if profile.FitnessLevel != nil {
+			fitnessLevel = profile.FitnessLevel.Name
+		}
+		profileRows = append(profileRows, []string{
+			profile.DateOfBirth,
+			profile.Gender,
+			csvFloat(&profile.HeightCm),
+			csvWeight(&profile.CurrentWeight),
+			csvWeight(profile.TargetWeight),
+			strconv.Itoa(profile.TargetWeeklyWorkouts),
+			profile.ActivityLevel,
+			strconv.Itoa(profile.PreferredWorkoutDurationMins),
+			fitnessLevel,
+			strings.Join(goals, ";"),
+			strings.Join(profile.TrainingLocations, ";"),
+			strings.Join(profile.AvailableDays, ";"),
+			profile.HealthConditions,
+			profile.InjuriesNotes,
+		})
+	}
+	if err := e.csvFile("fitness_profile.csv",
+		[]string{"date_of_birth", "gender", "height_cm", "current_" + weightColumn, "target_" + weightColumn, "target_weekly_workouts", "activity_level", "preferred_workout_duration_mins", "fitness_level", "fitness_goals", "training_locations", "available_days", "health_conditions", "injuries_notes"},
+		profileRows,
+	); err != nil {
+		return err
+	}
+
+	weightLogRows := make([][]string, 0, len(e.data.weightLogs))
+	for _, log := range e.data.weightLogs {
+		weightLogRows = append(weightLogRows, []string{log.ID.String(), csvTime(&log.CreatedAt), csvWeight(log.Weight), log.Notes})
+	}
+	if err := e.csvFile("weight_logs.csv", []string{"id", "logged_at", weightColumn, "notes"}, weightLogRows); err != nil {
+		return err
+	}
+
+	workoutRows := make([][]string, 0, len(e.data.workouts))
+	var prescriptionRows [][]string
+	for _, workout := range e.data.workouts {
+		workoutRows = append(workoutRows, []string{
+			workout.ID.String(), workout.Title, workout.Description, workout.DifficultyLevel,
+			csvInt(workout.EstimatedDuration), strconv.FormatBool(workout.IsTemplate), workout.Visibility, csvTime(&workout.CreatedAt),
+		})
+		for _, group := range workout.Prescriptions {
+			for _, exercise := range group.Exercises {
+				exerciseName := ""
+				if exercise.Exercise != nil {
+					exerciseName = exercise.Exercise.Name
+				}
+				notes := ""
+				if exercise.Notes != nil {
+					notes = *exercise.Notes
+				}
+				prescriptionRows = append(prescriptionRows, []string{
+					workout.ID.String(), workout.Title, strconv.Itoa(group.GroupOrder), string(group.Type),
+					strconv.Itoa(exercise.ExerciseOrder), exercise.ExerciseID.String(), exerciseName,
+					csvInt(exercise.Sets), csvInt(exercise.Reps), csvInt(exercise.HoldSeconds),
+					csvWeight(exercise.TargetWeight), csvDistance(exercise.TargetDistance), notes,
+				})
+			}
+		}
+	}
+	if err := e.csvFile("workouts.csv",
+		[]string{"id", "title", "description", "difficulty_level", "estimated_duration", "is_template", "visibility", "created_at"},
+		workoutRows,
+	); err != nil {
+		return err
+	}
+	if err := e.csvFile("workout_prescriptions.csv",
+		[]string{"workout_id", "workout_title", "group_order", "group_type", "exercise_order", "exercise_id", "exercise_name", "sets", "reps", "hold_seconds", "target_" + weightColumn, "target_" + distanceColumn, "notes"},
+		prescriptionRows,
+	); err != nil {
+		return err
+	}
+
+	var planRows [][]string
+	for _, plan := range e.data.plans {
+		for _, item := range plan.Items {
+			planRows = append(planRows, []string{
+				plan.ID.String(), plan.Title, plan.Visibility, strconv.Itoa(plan.TemplateWeeks),
+				strconv.Itoa(item.WeekIndex), item.WorkoutID.String(), item.WorkoutTitle,
+			})
+		}
+		if len(plan.Items) == 0 {
+			planRows = append(planRows, []string{plan.ID.String(), plan.Title, plan.Visibility, strconv.Itoa(plan.TemplateWeeks), "", "", ""})
+		}
+	}
+	if err := e.csvFile("workout_plans.csv",
+		[]string{"plan_id", "plan_title", "visibility", "template_weeks", "week_index", "workout_id", "workout_title"},
+		planRows,
+	); err != nil {
+		return err
+	}
+
+	favoriteRows := make([][]string, 0, len(e.data.favorites))
+	for _, favorite := range e.data.favorites {
+		favoriteRows = append(favoriteRows, []string{favorite.Type, favorite.ID.String(), favorite.Name, csvTime(&favorite.FavoritedAt)})
+	}
+	if err := e.csvFile("favorites.csv", []string{"type", "id", "name", "favorited_at"}, favoriteRows); err != nil {
+		return err
+	}
+
+	// Sessions and their sets are streamed in batches, one pass per file
+	sessionsCSV, err := e.csvStream("workout_sessions.csv",
+		[]string{"id", "started_at", "ended_at", "duration_seconds", "workout_id", "workout_title", "perceived_intensity", "completed", "activity_source", "activity_" + distanceColumn, "notes"})
+	if err != nil {
+		return err
+	}
+	err = e.eachSessionBatch(false, func(sessions []models.WorkoutSession) error {
+		for _, session := range sessions {
+			workoutID, workoutTitle := "", ""
+			if session.WorkoutID != nil {
+				workoutID = session.WorkoutID.String()
+			}
+			if session.Workout != nil {
+				workoutTitle = session.Workout.Title
+			}
+			activitySource, activityDistance := "", ""
+			if session.Activity != nil {
+				activity := session.Activity.ToResponse(e.data.distanceUnit)
+				activitySource = activity.Source
+				activityDistance = csvDistance(activity.Distance)
+			}
+			if err := sessionsCSV.Write([]string{
+				session.ID.String(), csvTime(&session.StartedAt), csvTime(session.EndedAt), csvInt(session.DurationSeconds),
+				workoutID, workoutTitle, csvInt(session.PerceivedIntensity), strconv.FormatBool(session.Completed),
+				activitySource, activityDistance, session.Notes,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := flushCSV(sessionsCSV); err != nil {
+		return err
+	}
+
+	setsCSV, err := e.csvStream("session_sets.csv",
+		[]string{"session_id", "session_started_at", "block_order", "block_type", "exercise_order", "exercise_id", "exercise_name", "set_number", "round", "sub_set_number", "completed", "completed_at", "reps", weightColumn, "duration_seconds", distanceColumn, "average_heart_rate", "max_heart_rate", "incline_percent", "rpe", "was_failure", "rest_seconds", "notes"})
+	if err != nil {
+		return err
+	}
+	err = e.eachSessionBatch(true, func(sessions []models.WorkoutSession) error {
+		for _, session := range sessions {
+			response := models.BuildSessionResponse(session, e.data.weightUnit, e.data.distanceUnit)
+			for _, block := range response.Blocks {
+				for _, exercise := range block.Exercises {
+					for _, set := range exercise.Sets {
+						rpe := ""
+						if set.RPEValue != nil {
+							rpe = strconv.Itoa(set.RPEValue.Value)
+						}
+						if err := setsCSV.Write([]string{
+							response.ID.String(), csvTime(&response.StartedAt), strconv.Itoa(block.BlockOrder), string(block.Type),
+							strconv.Itoa(exercise.ExerciseOrder), exercise.ExerciseID.String(), exercise.ExerciseName,
+							strconv.Itoa(set.SetNumber), strconv.Itoa(set.Round), strconv.Itoa(set.SubSetNumber),
+							strconv.FormatBool(set.Completed), csvTime(set.CompletedAt), csvInt(set.ActualReps),
+							csvWeight(set.ActualWeight), csvInt(set.ActualDurationSeconds), csvDistance(set.ActualDistance),
+							csvInt(set.ActualAverageHeartRate), csvInt(set.ActualMaxHeartRate), csvFloat(set.ActualInclinePercent),
+							rpe, strconv.FormatBool(set.WasFailure), csvInt(set.RestSeconds), set.Notes,
+						}); err != nil {
+							return err
+						}
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return flushCSV(setsCSV)
+}
+
+// csvFile adds a CSV file with a header and rows to the archive
+func (e *userExport) csvFile(name string, header []string, rows [][]string) error {
+	w, err := e.csvStream(name, header)
+	if err != nil {
+		return err
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return err
+	}
+	return w.Error()
+}
+
+// csvStream adds a CSV file to the archive and writes its header. Rows written to the returned
+// writer must be flushed before the next file is added.
+func (e *userExport) csvStream(name string, header []string) (*csv.Writer, error) {
+	file, err := e.zip.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	w := csv.NewWriter(file)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// flushCSV flushes a streamed CSV file
+func flushCSV(w *csv.Writer) error {
+	w.Flush()
+	return w.Error()
+}
+
+// eachSessionBatch loads the user's sessions oldest first, a batch at a time. With tree set,
+// the blocks, exercises and sets of the sessions are loaded too.
+func (e *userExport) eachSessionBatch(tree bool, fn func([]models.WorkoutSession) error) error {
+	for offset := 0; ; offset += exportSessionBatchSize {
+		query := database.DB.
+			Preload("Workout").
+			Preload("Activity").
+			Where("user_id = ?", e.data.user.ID)
+		if tree {
+			query = query.
+				Preload("CreatedBy").
+				Preload("SessionBlocks", func(db *gorm.DB) *gorm.DB {
+					return db.Order("block_order ASC")
+				}).
+				Preload("SessionBlocks.SessionExercises", func(db *gorm.DB) *gorm.DB {
+					return db.Order("exercise_order ASC")
+				}).
+				Preload("SessionBlocks.SessionExercises.Exercise").
+				Preload("SessionBlocks.SessionExercises.Prescription").
+				Preload("SessionBlocks.SessionExercises.Prescription.RPEValue").
+				Preload("SessionBlocks.SessionExercises.SessionSets", func(db *gorm.DB) *gorm.DB {
+					return db.Order("set_number ASC")
+				}).
+				Preload("SessionBlocks.SessionExercises.SessionSets.RPEValue")
+		}
+
+		var sessions []models.WorkoutSession
+		if err := query.
+			Order("started_at ASC, id ASC").
+			Offset(offset).
+			Limit(exportSessionBatchSize).
+			Find(&sessions).Error; err != nil {
+			return err
+		}
+		if len(sessions) > 0 {
+			if err := fn(sessions); err != nil {
+				return err
+			}
+		}
+		if len(sessions) < exportSessionBatchSize {
+			return nil
+		}
+	}
+}
+
+// ===== CSV value formatting =====
+
+func csvInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+func csvFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+func csvTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
+
+func csvWeight(weight *models.WeightOutput) string {
+	if weight == nil {
+		return ""
+	}
+	return csvFloat(weight.WeightValue)
+}
+
+func csvDistance(distance *models.DistanceOutput) string {
+	if distance == nil {
+		return ""
+	}
+	return csvFloat(distance.DistanceValue)
+}
@@ -181,27 +181,56 @@ func CreateUserFitnessProfile(c *gin.Context) {
 	utils.CreatedResponse(c, "Fitness profile created successfully", profile.ToResponse(preferredWeightUnit))
 }
 
-// GetUserFitnessProfile retrieves the fitness profile for the authenticated user
+// GetUserFitnessProfile retrieves the fitness profile for the authenticated user. Trainers can
+// pass client_id to view an active client's profile; body weights and health notes are only
+// included if the client consented to share them.
 func GetUserFitnessProfile(c *gin.Context) {
-	userID, ok := utils.GetAuthUserID(c)
+	authUserID, ok := utils.GetAuthUserID(c)
 	if !ok {
 		return
 	}
 
+	userID := authUserID
+	var link *models.TrainerClientLink
+	if clientIDStr := c.Query("client_id"); clientIDStr != "" {
+		clientID, ok := utils.ParseUUID(c, clientIDStr, "client")
+		if !ok {
+			return
+		}
+
+		if clientID != authUserID {
+			link = &models.TrainerClientLink{}
+			if err := database.DB.Where(
+				"trainer_id = ? AND client_id = ? AND status = ?",
+				authUserID, clientID, "active",
+			).First(link).Error; err != nil {
+				utils.ForbiddenResponse(c, "Not authorized to view this user's fitness profile")
+				return
+			}
+			userID = clientID
+		}
+	}
+
 	var profile models.UserFitnessProfile
 	if err := database.DB.Preload("FitnessGoals.FitnessGoal").Preload("FitnessLevel").Where("user_id = ?", userID).First(&profile).Error; err != nil {
 		utils.NotFoundResponse(c, "Fitness profile not found")
 		return
 	}
 
-	// Get user's preferred weight unit for response conversion
-	var user models.User
-	database.DB.Select("preferred_weight_unit").First(&user, "id = ?", userID)
-	preferredWeightUnit := user.PreferredWeightUnit
-	if preferredWeightUnit == "" {
-		preferredWeightUnit = "kg"
+	if link != nil {
+		if !link.HasConsent(models.ConsentScopeBodyWeight) {
+			profile.CurrentWeightKg = nil
+			profile.TargetWeightKg = nil
+		}
+		if !link.HasConsent(models.ConsentScopeHealth) {
+			profile.HealthConditions = ""
+			profile.InjuriesNotes = ""
+		}
 	}
 
+	// Get the viewer's preferred weight unit for response conversion
+	preferredWeightUnit := getUserPreferredWeightUnit(c, authUserID)
+
 	utils.SuccessResponse(c, "Fitness profile retrieved successfully", profile.ToResponse(preferredWeightUnit))
 }
 
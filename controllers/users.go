@@ -21,7 +21,8 @@ func DiscoverUsers(c *gin.Context) {
 
 	SetDefaultPagination(&queryParams.PaginationQuery)
 
-	query := database.DB.Model(&models.User{}).Where("profile_visibility = ?", "public")
+	query := database.DB.Model(&models.User{}).Where("profile_visibility = ?", "public").
+		Scopes(excludePendingDeletion("id"))
 
 	// Search by first_name, last_name, or email
 	if queryParams.Search != "" {
@@ -89,6 +90,11 @@ func GetUserPublicProfile(c *gin.Context) {
 		return
 	}
 
+	if targetUser.IsPendingDeletion() {
+		utils.NotFoundResponse(c, "User not found")
+		return
+	}
+
 	// Privacy check
 	if canViewProfile(currentUserID, targetID, &targetUser) {
 		// If viewing self, return full response
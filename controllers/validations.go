@@ -94,6 +94,18 @@ type UserDiscoveryQuery struct {
 	IsLookingForTrainer string `form:"is_looking_for_trainer" validate:"omitempty,oneof=true false" binding:"omitempty,oneof=true false"`
 }
 
+// WorkoutCommentQuery represents query parameters for shared workout comment endpoints
+type WorkoutCommentQuery struct {
+	PaginationQuery
+	ParentID string `form:"parent_id" validate:"omitempty,uuid" binding:"omitempty,uuid"`
+}
+
+// MessagePollQuery represents query parameters for long-polling a conversation's messages
+type MessagePollQuery struct {
+	Since   string `form:"since" validate:"required" binding:"required"`                               // RFC 3339 timestamp
+	Timeout *int   `form:"timeout" validate:"omitempty,min=0,max=60" binding:"omitempty,min=0,max=60"` // seconds
+}
+
 // IDParam represents common UUID path parameters
 type IDParam struct {
 	ID string `uri:"id" binding:"required,uuid"`
@@ -43,9 +43,16 @@ func CreateWeightLog(c *gin.Context) {
 	utils.CreatedResponse(c, "Weight logged successfully", weightLog.ToResponse())
 }
 
-// GetWeightLogs retrieves weight history with pagination and date filtering
+// GetWeightLogs retrieves weight history with pagination and date filtering. Trainers can pass
+// client_id to view the history of a client who shares their body weight.
 func GetWeightLogs(c *gin.Context) {
-	userID, ok := utils.GetAuthUserID(c)
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := resolveClientDataOwner(c, authUserID, models.ConsentScopeBodyWeight,
+		"Not authorized to view weight logs for this user")
 	if !ok {
 		return
 	}
@@ -189,9 +196,16 @@ func DeleteWeightLog(c *gin.Context) {
 	utils.NoContentResponse(c)
 }
 
-// GetWeightStats retrieves weight statistics for a given period
+// GetWeightStats retrieves weight statistics for a given period. Trainers can pass client_id to
+// view the statistics of a client who shares their body weight.
 func GetWeightStats(c *gin.Context) {
-	userID, ok := utils.GetAuthUserID(c)
+	authUserID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := resolveClientDataOwner(c, authUserID, models.ConsentScopeBodyWeight,
+		"Not authorized to view weight logs for this user")
 	if !ok {
 		return
 	}
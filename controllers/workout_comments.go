@@ -0,0 +1,288 @@
+package controllers
+
+import (
+	"errors"
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+	"lamari-fit-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateWorkoutComment posts a comment, or a reply to one, on a shared workout. Both the user who
+// shared the workout and the user it was shared with can comment.
+func CreateWorkoutComment(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	share, ok := findSharedWorkoutForUser(c, userID)
+	if !ok {
+		return
+	}
+
+	var req models.CreateWorkoutCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	comment := models.WorkoutComment{
+		SharedWorkoutID: share.ID,
+		UserID:          userID,
+		Content:         req.Content,
+	}
+
+	if req.ParentID != "" {
+		parentID, ok := utils.ParseUUID(c, req.ParentID, "parent comment")
+		if !ok {
+			return
+		}
+		var parent models.WorkoutComment
+		if err := database.DB.Where("id = ? AND shared_workout_id = ?", parentID, share.ID).First(&parent).Error; err != nil {
+			utils.NotFoundResponse(c, "Parent comment not found.")
+			return
+		}
+		comment.ParentID = &parent.ID
+	}
+
+	if err := database.DB.Create(&comment).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to post comment.")
+		return
+	}
+
+	workoutCommentPreloads(database.DB).First(&comment, "id = ?", comment.ID)
+
+	utils.CreatedResponse(c, "Comment posted successfully.", comment.ToResponse(userID))
+}
+
+// GetWorkoutComments lists the comments on a shared workout, oldest first. Without parent_id the
+// top-level comments are listed, with it the replies to that comment.
+func GetWorkoutComments(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	share, ok := findSharedWorkoutForUser(c, userID)
+	if !ok {
+		return
+	}
+
+	var query WorkoutCommentQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	SetDefaultPagination(&query.PaginationQuery)
+
+	base := database.DB.Model(&models.WorkoutComment{}).Where("shared_workout_id = ?", share.ID)
+	if query.ParentID != "" {
+		base = base.Where("parent_id = ?", query.ParentID)
+	} else {
+		base = base.Where("parent_id IS NULL")
+	}
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to count comments.")
+		return
+	}
+
+	var comments []models.WorkoutComment
+	if err := workoutCommentPreloads(base.Session(&gorm.Session{})).
+		Offset(query.GetOffset()).
+		Limit(query.Limit).
+		Order("created_at ASC").
+		Find(&comments).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch comments.")
+		return
+	}
+
+	responses := make([]models.WorkoutCommentResponse, len(comments))
+	for i := range comments {
+		responses[i] = comments[i].ToResponse(userID)
+	}
+
+	utils.PaginatedResponse(c, "Comments retrieved successfully.", responses, query.Page, query.Limit, int(total))
+}
+
+// UpdateWorkoutComment edits one of the user's comments
+func UpdateWorkoutComment(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	share, ok := findSharedWorkoutForUser(c, userID)
+	if !ok {
+		return
+	}
+
+	comment, ok := findWorkoutComment(c, share.ID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateWorkoutCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	if comment.UserID != userID {
+		utils.ForbiddenResponse(c, "You can only edit your own comments.")
+		return
+	}
+
+	if err := database.DB.Model(comment).Update("content", req.Content).Error; err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to update comment.")
+		return
+	}
+
+	workoutCommentPreloads(database.DB).First(comment, "id = ?", comment.ID)
+
+	utils.SuccessResponse(c, "Comment updated successfully.", comment.ToResponse(userID))
+}
+
+// DeleteWorkoutComment deletes a comment with its replies. Authors can delete their comments, and
+// the user who shared the workout can delete any comment on it.
+func DeleteWorkoutComment(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	share, ok := findSharedWorkoutForUser(c, userID)
+	if !ok {
+		return
+	}
+
+	comment, ok := findWorkoutComment(c, share.ID)
+	if !ok {
+		return
+	}
+
+	if comment.UserID != userID && share.SharedByID != userID {
+		utils.ForbiddenResponse(c, "You can only delete your own comments.")
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// Collect the whole thread below the comment
+		commentIDs := []uuid.UUID{comment.ID}
+		parentIDs := commentIDs
+		for len(parentIDs) > 0 {
+			var replyIDs []uuid.UUID
+			if err := tx.Model(&models.WorkoutComment{}).Where("parent_id IN ?", parentIDs).Pluck("id", &replyIDs).Error; err != nil {
+				return err
+			}
+			commentIDs = append(commentIDs, replyIDs...)
+			parentIDs = replyIDs
+		}
+
+		if err := tx.Where("comment_id IN ?", commentIDs).Delete(&models.WorkoutCommentReaction{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", commentIDs).Delete(&models.WorkoutComment{}).Error
+	})
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to delete comment.")
+		return
+	}
+
+	utils.DeletedResponse(c, "Comment deleted successfully.")
+}
+
+// ToggleCommentReaction reacts to a comment. Sending the user's current reaction again removes it,
+// sending a different one replaces it.
+func ToggleCommentReaction(c *gin.Context) {
+	userID, ok := utils.GetAuthUserID(c)
+	if !ok {
+		return
+	}
+
+	share, ok := findSharedWorkoutForUser(c, userID)
+	if !ok {
+		return
+	}
+
+	comment, ok := findWorkoutComment(c, share.ID)
+	if !ok {
+		return
+	}
+
+	var req models.ReactToCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleBindingError(c, err)
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var reaction models.WorkoutCommentReaction
+		err := tx.Where("comment_id = ? AND user_id = ?", comment.ID, userID).First(&reaction).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return tx.Create(&models.WorkoutCommentReaction{
+				CommentID: comment.ID,
+				UserID:    userID,
+				Reaction:  req.Reaction,
+			}).Error
+		case err != nil:
+			return err
+		case reaction.Reaction == req.Reaction:
+			return tx.Delete(&reaction).Error
+		default:
+			return tx.Model(&reaction).Update("reaction", req.Reaction).Error
+		}
+	})
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to update reaction.")
+		return
+	}
+
+	workoutCommentPreloads(database.DB).First(comment, "id = ?", comment.ID)
+
+	utils.SuccessResponse(c, "Reaction updated successfully.", comment.ToResponse(userID))
+}
+
+// workoutCommentPreloads loads what WorkoutComment.ToResponse needs
+func workoutCommentPreloads(db *gorm.DB) *gorm.DB {
+	return db.Preload("User").Preload("Replies").Preload("Reactions")
+}
+
+// findSharedWorkoutForUser loads the shared workout in the :id parameter when the user shared it
+// or it was shared with them
+func findSharedWorkoutForUser(c *gin.Context, userID uuid.UUID) (*models.SharedWorkout, bool) {
+	shareID, ok := utils.ParseUUID(c, c.Param("id"), "shared workout")
+	if !ok {
+		return nil, false
+	}
+
+	var share models.SharedWorkout
+	if err := database.DB.Where("id = ? AND (shared_by_id = ? OR shared_with_id = ?)", shareID, userID, userID).
+		First(&share).Error; err != nil {
+		utils.NotFoundResponse(c, "Shared workout not found.")
+		return nil, false
+	}
+	return &share, true
+}
+
+// findWorkoutComment loads the comment in the :comment_id parameter from a shared workout
+func findWorkoutComment(c *gin.Context, shareID uuid.UUID) (*models.WorkoutComment, bool) {
+	commentID, ok := utils.ParseUUID(c, c.Param("comment_id"), "comment")
+	if !ok {
+		return nil, false
+	}
+
+	var comment models.WorkoutComment
+	if err := database.DB.Where("id = ? AND shared_workout_id = ?", commentID, shareID).First(&comment).Error; err != nil {
+		utils.NotFoundResponse(c, "Comment not found.")
+		return nil, false
+	}
+	return &comment, true
+}
@@ -29,12 +29,8 @@ func CreateWorkoutSession(c *gin.Context) {
 	// Determine target user (who the session is for)
 	targetUserID := authUserID
 	if req.UserID != nil && *req.UserID != authUserID {
-		// Creating session for someone else - verify trainer-client relationship
-		var link models.TrainerClientLink
-		if err := database.DB.Where(
-			"trainer_id = ? AND client_id = ? AND status = ?",
-			authUserID, *req.UserID, "active",
-		).First(&link).Error; err != nil {
+		// Creating session for someone else - verify the client lets the trainer log sessions
+		if !hasClientConsent(authUserID, *req.UserID, models.ConsentScopeLogSessions) {
 			utils.ForbiddenResponse(c, "Not authorized to create sessions for this user")
 			return
 		}
@@ -47,14 +43,27 @@ func CreateWorkoutSession(c *gin.Context) {
 		startedAt = *req.StartedAt
 	}
 
+	// Resolve the scheduled workout when the session fulfils a plan enrollment
+	var scheduledIndex *int
+	if req.EnrollmentID != nil {
+		workoutID, index, ok := resolveSessionEnrollment(c, targetUserID, req, startedAt)
+		if !ok {
+			return
+		}
+		req.WorkoutID = &workoutID
+		scheduledIndex = &index
+	}
+
 	// Create the session
 	session := models.WorkoutSession{
-		UserID:      targetUserID,
-		CreatedByID: &authUserID,
-		WorkoutID:   req.WorkoutID,
-		StartedAt:   startedAt,
-		Notes:       "",
-		Completed:   false,
+		UserID:         targetUserID,
+		CreatedByID:    &authUserID,
+		WorkoutID:      req.WorkoutID,
+		EnrollmentID:   req.EnrollmentID,
+		ScheduledIndex: scheduledIndex,
+		StartedAt:      startedAt,
+		Notes:          "",
+		Completed:      false,
 	}
 
 	if req.Notes != nil {
@@ -72,7 +81,7 @@ func CreateWorkoutSession(c *gin.Context) {
 
 	// If workout_id provided, auto-create blocks/exercises/sets from prescriptions
 	if req.WorkoutID != nil {
-		if err := autoCreateSessionStructure(tx, session.ID, *req.WorkoutID); err != nil {
+		if err := autoCreateSessionStructure(tx, session.ID, targetUserID, *req.WorkoutID); err != nil {
 			tx.Rollback()
 			utils.InternalServerErrorResponse(c, "Failed to create session structure from prescriptions")
 			return
@@ -87,14 +96,18 @@ func CreateWorkoutSession(c *gin.Context) {
 		Preload("CreatedBy").
 		Preload("Workout").
 		Preload("SessionBlocks.SessionExercises.Exercise").
+		Preload("SessionBlocks.SessionExercises.Prescription").
 		Preload("SessionBlocks.SessionExercises.SessionSets.RPEValue").
 		First(&session, "id = ?", session.ID)
 
-	utils.CreatedResponse(c, "Workout session created successfully", models.BuildSessionResponse(session, "kg"))
+	utils.CreatedResponse(c, "Workout session created successfully", models.BuildSessionResponse(session, "kg", "km"))
 }
 
 // autoCreateSessionStructure creates session blocks, exercises, and sets from workout prescriptions
-func autoCreateSessionStructure(tx *gorm.DB, sessionID uuid.UUID, workoutID uuid.UUID) error {
+// Sets are pre-filled from the prescription targets, progressed from the user's last
+// performance when the prescription group has a progression rule, and laid out for the group's
+// type (rounds, drops, time-boxed intervals; see utils.PlanSessionSets)
+func autoCreateSessionStructure(tx *gorm.DB, sessionID uuid.UUID, userID uuid.UUID, workoutID uuid.UUID) error {
 	// Get all prescriptions for this workout, ordered by group_order and exercise_order
 	var prescriptions []models.WorkoutPrescription
 	if err := tx.
@@ -137,10 +150,19 @@ func autoCreateSessionStructure(tx *gorm.DB, sessionID uuid.UUID, workoutID uuid
 		}
 
 		// Create session exercises for each prescription in the group
-		for _, prescription := range groupPrescriptions {
+		sessionExerciseIDs := make([]uuid.UUID, len(groupPrescriptions))
+		plannedGroup := utils.PlannedGroup{
+			Type:            firstPrescription.Type,
+			Rounds:          firstPrescription.GroupRounds,
+			RestBetweenSets: firstPrescription.RestBetweenSets,
+			IntervalSeconds: firstPrescription.IntervalSeconds,
+			Exercises:       make([]utils.PlannedExercise, len(groupPrescriptions)),
+		}
+
+		for i, prescription := range groupPrescriptions {
 			sessionExercise := models.SessionExercise{
 				SessionBlockID: block.ID,
-				PrescriptionID: &prescription.ID,
+				PrescriptionID: &groupPrescriptions[i].ID,
 				ExerciseID:     prescription.ExerciseID,
 				ExerciseOrder:  prescription.ExerciseOrder,
 				Skipped:        false,
@@ -150,41 +172,51 @@ func autoCreateSessionStructure(tx *gorm.DB, sessionID uuid.UUID, workoutID uuid
 			if err := tx.Create(&sessionExercise).Error; err != nil {
 				return err
 			}
+			sessionExerciseIDs[i] = sessionExercise.ID
+
+			targetWeightKg, targetReps, err := progressionTarget(userID, prescription)
+			if err != nil {
+				return err
+			}
 
-			// Create session sets based on prescription
-			numSets := 1
-			if prescription.Sets != nil && *prescription.Sets > 0 {
-				numSets = *prescription.Sets
+			plannedGroup.Exercises[i] = utils.PlannedExercise{
+				Sets:        prescription.Sets,
+				Reps:        targetReps,
+				HoldSeconds: prescription.HoldSeconds,
+				WeightKg:    targetWeightKg,
 			}
+		}
 
-			// For types with group_rounds, multiply by rounds
-			if firstPrescription.GroupRounds != nil && *firstPrescription.GroupRounds > 1 {
-				// For circuits/supersets, we create sets based on rounds
-				// But for now, keep it simple - just use the sets value
+		// Create session sets laid out for the group's type, pre-filled with the targets
+		for _, planned := range utils.PlanSessionSets(plannedGroup) {
+			set := models.SessionSet{
+				SessionExerciseID:     sessionExerciseIDs[planned.ExerciseIndex],
+				SetNumber:             planned.SetNumber,
+				Round:                 planned.Round,
+				SequenceOrder:         planned.SequenceOrder,
+				SubSetNumber:          planned.SubSetNumber,
+				IntervalStartSeconds:  planned.IntervalStartSeconds,
+				IntervalSeconds:       planned.IntervalSeconds,
+				Completed:             false,
+				ActualReps:            planned.Reps,
+				ActualWeightKg:        planned.WeightKg,
+				ActualDurationSeconds: planned.DurationSeconds,
+				RPEValueID:            groupPrescriptions[planned.ExerciseIndex].RPEValueID,
+				WasFailure:            false,
+				Notes:                 "",
 			}
 
-			for setNum := 1; setNum <= numSets; setNum++ {
-				set := models.SessionSet{
-					SessionExerciseID: sessionExercise.ID,
-					SetNumber:         setNum,
-					Completed:         false,
-					ActualWeightKg:    prescription.TargetWeightKg, // Pre-fill with target
-					RPEValueID:        prescription.RPEValueID,
-					WasFailure:        false,
-					Notes:             "",
-				}
-
-				// Pre-fill reps or duration based on prescription
-				if prescription.Reps != nil {
-					set.ActualReps = prescription.Reps
-				}
-				if prescription.HoldSeconds != nil {
-					set.ActualDurationSeconds = prescription.HoldSeconds
-				}
-
-				if err := tx.Create(&set).Error; err != nil {
-					return err
-				}
+			// Pre-fill cardio targets on main sets
+			if planned.SubSetNumber == 0 {
+				prescription := groupPrescriptions[planned.ExerciseIndex]
+				set.ActualDistanceMeters = prescription.TargetDistanceMeters
+				set.OriginalActualDistanceValue = prescription.OriginalTargetDistanceValue
+				set.OriginalActualDistanceUnit = prescription.OriginalTargetDistanceUnit
+				set.ActualInclinePercent = prescription.TargetInclinePercent
+			}
+
+			if err := tx.Create(&set).Error; err != nil {
+				return err
 			}
 		}
 	}
@@ -216,11 +248,18 @@ func GetWorkoutSession(c *gin.Context) {
 		weightUnit = "kg"
 	}
 
+	// Get distance unit from query param, default to the user's preference
+	distanceUnit := c.Query("distance_unit")
+	if distanceUnit != "km" && distanceUnit != "mi" {
+		distanceUnit = getUserPreferredDistanceUnit(c, authUserID)
+	}
+
 	var session models.WorkoutSession
 	if err := database.DB.
 		Preload("User").
 		Preload("CreatedBy").
 		Preload("Workout").
+		Preload("Activity").
 		Preload("SessionBlocks", func(db *gorm.DB) *gorm.DB {
 			return db.Order("block_order ASC")
 		}).
@@ -228,6 +267,8 @@ func GetWorkoutSession(c *gin.Context) {
 			return db.Order("exercise_order ASC")
 		}).
 		Preload("SessionBlocks.SessionExercises.Exercise").
+		Preload("SessionBlocks.SessionExercises.Prescription").
+		Preload("SessionBlocks.SessionExercises.Prescription.RPEValue").
 		Preload("SessionBlocks.SessionExercises.SessionSets", func(db *gorm.DB) *gorm.DB {
 			return db.Order("set_number ASC")
 		}).
@@ -238,12 +279,15 @@ func GetWorkoutSession(c *gin.Context) {
 	}
 
 	// Authorization: user owns the session OR trainer created it
-	if !isAuthorizedForSession(session, authUserID) {
+	if !canViewSession(session, authUserID) {
 		utils.NotFoundResponse(c, "Workout session not found")
 		return
 	}
 
-	utils.SuccessResponse(c, "Workout session retrieved successfully", models.BuildSessionResponse(session, weightUnit))
+	response := models.BuildSessionResponse(session, weightUnit, distanceUnit)
+	response.Compliance = utils.BuildSessionCompliance(session, weightUnit)
+
+	utils.SuccessResponse(c, "Workout session retrieved successfully", response)
 }
 
 // GetWorkoutSessions lists workout sessions for the authenticated user
@@ -255,7 +299,8 @@ func GetWorkoutSessions(c *gin.Context) {
 
 	// Check if querying for a specific client (trainer view)
 	clientIDStr := c.Query("client_id")
-	var targetUserID uuid.UUID
+	targetUserID := authUserID
+	onlyCreatedByAuthUser := false
 
 	if clientIDStr != "" {
 		clientID, ok := utils.ParseUUID(c, clientIDStr, "client")
@@ -263,19 +308,20 @@ func GetWorkoutSessions(c *gin.Context) {
 			return
 		}
 
-		// Verify trainer-client relationship
-		var link models.TrainerClientLink
-		if err := database.DB.Where(
-			"trainer_id = ? AND client_id = ? AND status = ?",
-			authUserID, clientID, "active",
-		).First(&link).Error; err != nil {
-			utils.ForbiddenResponse(c, "Not authorized to view sessions for this user")
-			return
+		// Trainers see all sessions of clients who share them, or else only the sessions they
+		// logged for clients who let them
+		if clientID != authUserID {
+			switch {
+			case hasClientConsent(authUserID, clientID, models.ConsentScopeSessions):
+			case hasClientConsent(authUserID, clientID, models.ConsentScopeLogSessions):
+				onlyCreatedByAuthUser = true
+			default:
+				utils.ForbiddenResponse(c, "Not authorized to view sessions for this user")
+				return
+			}
 		}
 
 		targetUserID = clientID
-	} else {
-		targetUserID = authUserID
 	}
 
 	// Get weight unit preference from query param, default to kg
@@ -284,15 +330,21 @@ func GetWorkoutSessions(c *gin.Context) {
 		weightUnit = "kg"
 	}
 
+	// Get distance unit from query param, default to the user's preference
+	distanceUnit := c.Query("distance_unit")
+	if distanceUnit != "km" && distanceUnit != "mi" {
+		distanceUnit = getUserPreferredDistanceUnit(c, authUserID)
+	}
+
 	var sessions []models.WorkoutSession
 	query := database.DB.
 		Preload("User").
 		Preload("CreatedBy").
 		Preload("Workout").
+		Preload("Activity").
 		Where("user_id = ?", targetUserID)
 
-	// If trainer is viewing client sessions, only show sessions they created
-	if clientIDStr != "" {
+	if onlyCreatedByAuthUser {
 		query = query.Where("created_by_id = ?", authUserID)
 	}
 
@@ -303,7 +355,7 @@ func GetWorkoutSessions(c *gin.Context) {
 
 	responses := make([]models.WorkoutSessionResponse, len(sessions))
 	for i, session := range sessions {
-		responses[i] = models.BuildSessionResponse(session, weightUnit)
+		responses[i] = models.BuildSessionResponse(session, weightUnit, distanceUnit)
 	}
 
 	utils.SuccessResponse(c, "Workout sessions retrieved successfully", responses)
@@ -351,6 +403,7 @@ func EndWorkoutSession(c *gin.Context) {
 		endedAt = *req.EndedAt
 	}
 
+	wasCompleted := session.Completed
 	session.EndedAt = &endedAt
 	session.Completed = true
 
@@ -366,7 +419,20 @@ func EndWorkoutSession(c *gin.Context) {
 		session.PerceivedIntensity = req.PerceivedIntensity
 	}
 
-	if err := database.DB.Save(&session).Error; err != nil {
+	// Save the session, pick up personal records from all completed sets and
+	// advance any plan enrollment the session completes a scheduled workout for
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&session).Error; err != nil {
+			return err
+		}
+		if err := updatePersonalRecordsForSession(tx, session); err != nil {
+			return err
+		}
+		if wasCompleted {
+			return nil
+		}
+		return advanceEnrollmentsForSession(tx, session)
+	}); err != nil {
 		utils.InternalServerErrorResponse(c, "Failed to end workout session")
 		return
 	}
@@ -378,7 +444,21 @@ func EndWorkoutSession(c *gin.Context) {
 		Preload("Workout").
 		First(&session, "id = ?", session.ID)
 
-	utils.SuccessResponse(c, "Workout session ended successfully", models.BuildSessionResponse(session, "kg"))
+	weightUnit := getUserPreferredWeightUnit(c, authUserID)
+	response := models.BuildSessionResponse(session, "kg", "km")
+	records := getSessionPersonalRecords(session.ID, session.UserID)
+	if len(records) > 0 {
+		response.NewRecords = buildPersonalRecordResponses(records, weightUnit)
+	}
+
+	summary, err := buildSessionSummary(session, len(records), weightUnit)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to build workout session summary")
+		return
+	}
+	response.Summary = summary
+
+	utils.SuccessResponse(c, "Workout session ended successfully", response)
 }
 
 // UpdateWorkoutSession updates session notes and perceived intensity
@@ -437,7 +517,7 @@ func UpdateWorkoutSession(c *gin.Context) {
 		Preload("Workout").
 		First(&session, "id = ?", session.ID)
 
-	utils.SuccessResponse(c, "Workout session updated successfully", models.BuildSessionResponse(session, "kg"))
+	utils.SuccessResponse(c, "Workout session updated successfully", models.BuildSessionResponse(session, "kg", "km"))
 }
 
 // DeleteWorkoutSession deletes a workout session and all related data
@@ -470,8 +550,18 @@ func DeleteWorkoutSession(c *gin.Context) {
 		return
 	}
 
-	// Delete cascades through foreign keys in the database
-	if err := database.DB.Delete(&session).Error; err != nil {
+	// Delete cascades through foreign keys in the database. Records set in the session go with it.
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		exerciseIDs, err := completedSetExerciseIDs(tx,
+			"session_exercises.session_block_id IN (?)", tx.Model(&models.SessionBlock{}).Select("id").Where("session_id = ?", session.ID))
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(&session).Error; err != nil {
+			return err
+		}
+		return recomputePersonalRecordsForExercises(tx, session.UserID, exerciseIDs)
+	}); err != nil {
 		utils.InternalServerErrorResponse(c, "Failed to delete workout session")
 		return
 	}
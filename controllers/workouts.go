@@ -184,15 +184,14 @@ func GetWorkout(c *gin.Context) {
 		return
 	}
 
-	var workout models.Workout
-	if err := database.DB.Where("id = ? AND user_id = ?", workoutID, userUUID).
+	query := database.DB.
 		Preload("Prescriptions", func(db *gorm.DB) *gorm.DB {
 			return db.Order("group_order ASC, exercise_order ASC")
 		}).
 		Preload("Prescriptions.Exercise").
-		Preload("Prescriptions.RPEValue").
-		First(&workout).Error; err != nil {
-		utils.NotFoundResponse(c, "Workout not found.")
+		Preload("Prescriptions.RPEValue")
+	workout, permission, ok := findAccessibleWorkout(c, query, workoutID, userUUID, models.SharePermissionView)
+	if !ok {
 		return
 	}
 
@@ -206,9 +205,10 @@ func GetWorkout(c *gin.Context) {
 	// Group prescriptions by group_id for response
 	groupedPrescriptions := models.GroupPrescriptionsByGroupID(workout.Prescriptions)
 
-	// Get user's preferred weight unit and populate target weights
+	// Get user's preferred units and populate target weights and distances
 	preferredWeightUnit := getUserPreferredWeightUnit(c, userUUID)
 	models.PopulatePrescriptionWeights(groupedPrescriptions, workout.Prescriptions, preferredWeightUnit)
+	models.PopulatePrescriptionDistances(groupedPrescriptions, workout.Prescriptions, getUserPreferredDistanceUnit(c, userUUID))
 
 	response := map[string]interface{}{
 		"id":                 workout.ID,
@@ -220,6 +220,7 @@ func GetWorkout(c *gin.Context) {
 		"is_template":        workout.IsTemplate,
 		"visibility":         workout.Visibility,
 		"is_favorited":       isFavorited,
+		"permission":         permission,
 		"created_at":         workout.CreatedAt,
 		"updated_at":         workout.UpdatedAt,
 		"prescriptions":      groupedPrescriptions,
@@ -245,9 +246,13 @@ func UpdateWorkout(c *gin.Context) {
 		return
 	}
 
-	var workout models.Workout
-	if err := database.DB.Where("id = ? AND user_id = ?", workoutID, userUUID).First(&workout).Error; err != nil {
-		utils.NotFoundResponse(c, "Workout not found.")
+	workout, permission, ok := findAccessibleWorkout(c, database.DB, workoutID, userUUID, models.SharePermissionEdit)
+	if !ok {
+		return
+	}
+
+	if req.Visibility != "" && permission != models.SharePermissionOwner {
+		utils.ForbiddenResponse(c, "Only the workout's owner can change its visibility.")
 		return
 	}
 
@@ -272,13 +277,13 @@ func UpdateWorkout(c *gin.Context) {
 	}
 
 	if len(updates) > 0 {
-		if err := database.DB.Model(&workout).Updates(updates).Error; err != nil {
+		if err := database.DB.Model(workout).Updates(updates).Error; err != nil {
 			utils.InternalServerErrorResponse(c, "Failed to update workout.")
 			return
 		}
 	}
 
-	database.DB.Where("id = ?", workoutID).First(&workout)
+	database.DB.Where("id = ?", workoutID).First(workout)
 
 	utils.SuccessResponse(c, "Workout updated successfully.", workout)
 }
@@ -335,6 +340,11 @@ func CreatePrescriptionGroup(c *gin.Context) {
 		return
 	}
 
+	progression, ok := buildProgressionRule(c, req.Progression)
+	if !ok {
+		return
+	}
+
 	// Check if workout exists and belongs to user
 	var workout models.Workout
 	if err := database.DB.Where("id = ? AND user_id = ?", workoutID, userUUID).First(&workout).Error; err != nil {
@@ -372,8 +382,10 @@ func CreatePrescriptionGroup(c *gin.Context) {
 				GroupOrder:      req.GroupOrder,
 				GroupRounds:     req.GroupRounds,
 				RestBetweenSets: req.RestBetweenSets,
+				IntervalSeconds: req.IntervalSeconds,
 				GroupName:       req.GroupName,
 				GroupNotes:      req.GroupNotes,
+				Progression:     progression,
 				ExerciseOrder:   exerciseReq.ExerciseOrder,
 				Sets:            exerciseReq.Sets,
 				Reps:            exerciseReq.Reps,
@@ -389,11 +401,13 @@ func CreatePrescriptionGroup(c *gin.Context) {
 				prescription.OriginalTargetWeightValue = originalValue
 				prescription.OriginalTargetWeightUnit = originalUnit
 			}
+			applyCardioTargets(&prescription, exerciseReq.CardioTargetsRequest)
 
 			if err := tx.Create(&prescription).Error; err != nil {
 				// Check for validation errors from BeforeSave hook
 				if err.Error() == "prescription cannot have both reps and hold_seconds" ||
-					err.Error() == "prescription must have either reps or hold_seconds" ||
+					err.Error() == "prescription must have reps, hold_seconds or a target distance" ||
+					err.Error() == "target_heart_rate_min cannot be above target_heart_rate_max" ||
 					err.Error() == "group_order must be at least 1" ||
 					err.Error() == "exercise_order must be at least 1" ||
 					err.Error() == "invalid prescription type" {
@@ -432,9 +446,10 @@ func CreatePrescriptionGroup(c *gin.Context) {
 	// Format response as grouped prescriptions
 	groupedResponse := models.GroupPrescriptionsByGroupID(createdPrescriptions)
 
-	// Get user's preferred weight unit and populate target weights
+	// Get user's preferred units and populate target weights and distances
 	preferredWeightUnit := getUserPreferredWeightUnit(c, userUUID)
 	models.PopulatePrescriptionWeights(groupedResponse, createdPrescriptions, preferredWeightUnit)
+	models.PopulatePrescriptionDistances(groupedResponse, createdPrescriptions, getUserPreferredDistanceUnit(c, userUUID))
 
 	if len(groupedResponse) > 0 {
 		utils.CreatedResponse(c, "Prescription group created successfully.", groupedResponse[0])
@@ -466,6 +481,11 @@ func UpdatePrescriptionGroup(c *gin.Context) {
 		return
 	}
 
+	progression, ok := buildProgressionRule(c, req.Progression)
+	if !ok {
+		return
+	}
+
 	// Check if workout exists and belongs to user
 	var workout models.Workout
 	if err := database.DB.Where("id = ? AND user_id = ?", workoutID, userUUID).First(&workout).Error; err != nil {
@@ -506,12 +526,20 @@ func UpdatePrescriptionGroup(c *gin.Context) {
 		if req.RestBetweenSets != nil {
 			groupUpdates["rest_between_sets"] = *req.RestBetweenSets
 		}
+		if req.IntervalSeconds != nil {
+			groupUpdates["interval_seconds"] = *req.IntervalSeconds
+		}
 		if req.GroupName != nil {
 			groupUpdates["group_name"] = *req.GroupName
 		}
 		if req.GroupNotes != nil {
 			groupUpdates["group_notes"] = *req.GroupNotes
 		}
+		if req.Progression != nil {
+			for column, value := range progressionColumns(progression) {
+				groupUpdates[column] = value
+			}
+		}
 
 		// Update group-level fields on all rows
 		if len(groupUpdates) > 0 {
@@ -535,8 +563,10 @@ func UpdatePrescriptionGroup(c *gin.Context) {
 			groupOrder := existingPrescriptions[0].GroupOrder
 			groupRounds := existingPrescriptions[0].GroupRounds
 			restBetweenSets := existingPrescriptions[0].RestBetweenSets
+			intervalSeconds := existingPrescriptions[0].IntervalSeconds
 			groupName := existingPrescriptions[0].GroupName
 			groupNotes := existingPrescriptions[0].GroupNotes
+			groupProgression := existingPrescriptions[0].Progression
 
 			if req.Type != nil {
 				groupType = *req.Type
@@ -550,12 +580,18 @@ func UpdatePrescriptionGroup(c *gin.Context) {
 			if req.RestBetweenSets != nil {
 				restBetweenSets = req.RestBetweenSets
 			}
+			if req.IntervalSeconds != nil {
+				intervalSeconds = req.IntervalSeconds
+			}
 			if req.GroupName != nil {
 				groupName = req.GroupName
 			}
 			if req.GroupNotes != nil {
 				groupNotes = req.GroupNotes
 			}
+			if req.Progression != nil {
+				groupProgression = progression
+			}
 
 			// Create new prescriptions
 			for _, exerciseReq := range req.Exercises {
@@ -573,8 +609,10 @@ func UpdatePrescriptionGroup(c *gin.Context) {
 					GroupOrder:      groupOrder,
 					GroupRounds:     groupRounds,
 					RestBetweenSets: restBetweenSets,
+					IntervalSeconds: intervalSeconds,
 					GroupName:       groupName,
 					GroupNotes:      groupNotes,
+					Progression:     groupProgression,
 					ExerciseOrder:   exerciseReq.ExerciseOrder,
 					Sets:            exerciseReq.Sets,
 					Reps:            exerciseReq.Reps,
@@ -590,6 +628,7 @@ func UpdatePrescriptionGroup(c *gin.Context) {
 					prescription.OriginalTargetWeightValue = originalValue
 					prescription.OriginalTargetWeightUnit = originalUnit
 				}
+				applyCardioTargets(&prescription, exerciseReq.CardioTargetsRequest)
 
 				if err := tx.Create(&prescription).Error; err != nil {
 					return err
@@ -618,7 +657,8 @@ func UpdatePrescriptionGroup(c *gin.Context) {
 		// Check for validation errors from BeforeSave hook
 		errMsg := err.Error()
 		if errMsg == "prescription cannot have both reps and hold_seconds" ||
-			errMsg == "prescription must have either reps or hold_seconds" ||
+			errMsg == "prescription must have reps, hold_seconds or a target distance" ||
+			errMsg == "target_heart_rate_min cannot be above target_heart_rate_max" ||
 			errMsg == "group_order must be at least 1" ||
 			errMsg == "exercise_order must be at least 1" ||
 			errMsg == "invalid prescription type" {
@@ -645,9 +685,10 @@ func UpdatePrescriptionGroup(c *gin.Context) {
 
 	groupedResponse := models.GroupPrescriptionsByGroupID(updatedPrescriptions)
 
-	// Get user's preferred weight unit and populate target weights
+	// Get user's preferred units and populate target weights and distances
 	preferredWeightUnit := getUserPreferredWeightUnit(c, userUUID)
 	models.PopulatePrescriptionWeights(groupedResponse, updatedPrescriptions, preferredWeightUnit)
+	models.PopulatePrescriptionDistances(groupedResponse, updatedPrescriptions, getUserPreferredDistanceUnit(c, userUUID))
 
 	if len(groupedResponse) > 0 {
 		utils.SuccessResponse(c, "Prescription group updated successfully.", groupedResponse[0])
@@ -772,9 +813,10 @@ func GetWorkoutPrescriptions(c *gin.Context) {
 
 	groupedResponse := models.GroupPrescriptionsByGroupID(prescriptions)
 
-	// Get user's preferred weight unit and populate target weights
+	// Get user's preferred units and populate target weights and distances
 	preferredWeightUnit := getUserPreferredWeightUnit(c, userUUID)
 	models.PopulatePrescriptionWeights(groupedResponse, prescriptions, preferredWeightUnit)
+	models.PopulatePrescriptionDistances(groupedResponse, prescriptions, getUserPreferredDistanceUnit(c, userUUID))
 
 	utils.SuccessResponse(c, "Workout prescriptions fetched successfully.", groupedResponse)
 }
@@ -848,8 +890,10 @@ func AddExerciseToPrescriptionGroup(c *gin.Context) {
 		GroupOrder:      firstPrescription.GroupOrder,
 		GroupRounds:     firstPrescription.GroupRounds,
 		RestBetweenSets: firstPrescription.RestBetweenSets,
+		IntervalSeconds: firstPrescription.IntervalSeconds,
 		GroupName:       firstPrescription.GroupName,
 		GroupNotes:      firstPrescription.GroupNotes,
+		Progression:     firstPrescription.Progression,
 		ExerciseOrder:   nextOrder,
 		Sets:            req.Sets,
 		Reps:            req.Reps,
@@ -865,6 +909,7 @@ func AddExerciseToPrescriptionGroup(c *gin.Context) {
 		prescription.OriginalTargetWeightValue = originalValue
 		prescription.OriginalTargetWeightUnit = originalUnit
 	}
+	applyCardioTargets(&prescription, req.CardioTargetsRequest)
 
 	if err := database.DB.Create(&prescription).Error; err != nil {
 		utils.InternalServerErrorResponse(c, "Failed to add exercise to prescription group.")
@@ -890,14 +935,17 @@ func DuplicateWorkout(c *gin.Context) {
 	}
 
 	// Fetch the original workout with all prescriptions
-	var originalWorkout models.Workout
-	if err := database.DB.Where("id = ? AND user_id = ?", workoutID, userUUID).
-		Preload("Prescriptions").
-		First(&originalWorkout).Error; err != nil {
-		utils.NotFoundResponse(c, "Workout not found.")
+	originalWorkout, permission, ok := findAccessibleWorkout(c, database.DB.Preload("Prescriptions"), workoutID, userUUID, models.SharePermissionCopy)
+	if !ok {
 		return
 	}
 
+	// Copies of a shared workout start out private to the user who copied it
+	visibility := originalWorkout.Visibility
+	if permission != models.SharePermissionOwner {
+		visibility = "private"
+	}
+
 	var newWorkout models.Workout
 
 	// Start a transaction
@@ -910,7 +958,7 @@ func DuplicateWorkout(c *gin.Context) {
 			DifficultyLevel:   originalWorkout.DifficultyLevel,
 			EstimatedDuration: originalWorkout.EstimatedDuration,
 			IsTemplate:        originalWorkout.IsTemplate,
-			Visibility:        originalWorkout.Visibility,
+			Visibility:        visibility,
 		}
 
 		if err := tx.Create(&newWorkout).Error; err != nil {
@@ -938,8 +986,10 @@ func DuplicateWorkout(c *gin.Context) {
 				GroupOrder:      prescription.GroupOrder,
 				GroupRounds:     prescription.GroupRounds,
 				RestBetweenSets: prescription.RestBetweenSets,
+				IntervalSeconds: prescription.IntervalSeconds,
 				GroupName:       prescription.GroupName,
 				GroupNotes:      prescription.GroupNotes,
+				Progression:     prescription.Progression,
 				ExerciseOrder:   prescription.ExerciseOrder,
 				Sets:            prescription.Sets,
 				Reps:            prescription.Reps,
@@ -954,6 +1004,15 @@ func DuplicateWorkout(c *gin.Context) {
 				newPrescription.OriginalTargetWeightUnit = prescription.OriginalTargetWeightUnit
 			}
 
+			// Copy cardio targets
+			newPrescription.TargetDistanceMeters = prescription.TargetDistanceMeters
+			newPrescription.OriginalTargetDistanceValue = prescription.OriginalTargetDistanceValue
+			newPrescription.OriginalTargetDistanceUnit = prescription.OriginalTargetDistanceUnit
+			newPrescription.TargetPaceSecondsPerKm = prescription.TargetPaceSecondsPerKm
+			newPrescription.TargetHeartRateMin = prescription.TargetHeartRateMin
+			newPrescription.TargetHeartRateMax = prescription.TargetHeartRateMax
+			newPrescription.TargetInclinePercent = prescription.TargetInclinePercent
+
 			if err := tx.Create(&newPrescription).Error; err != nil {
 				return err
 			}
@@ -981,9 +1040,10 @@ func DuplicateWorkout(c *gin.Context) {
 	// Group prescriptions for response
 	groupedPrescriptions := models.GroupPrescriptionsByGroupID(newWorkout.Prescriptions)
 
-	// Get user's preferred weight unit and populate target weights
+	// Get user's preferred units and populate target weights and distances
 	preferredWeightUnit := getUserPreferredWeightUnit(c, userUUID)
 	models.PopulatePrescriptionWeights(groupedPrescriptions, newWorkout.Prescriptions, preferredWeightUnit)
+	models.PopulatePrescriptionDistances(groupedPrescriptions, newWorkout.Prescriptions, getUserPreferredDistanceUnit(c, userUUID))
 
 	response := map[string]interface{}{
 		"id":                 newWorkout.ID,
@@ -1002,6 +1062,22 @@ func DuplicateWorkout(c *gin.Context) {
 	utils.CreatedResponse(c, "Workout duplicated successfully.", response)
 }
 
+// applyCardioTargets sets a prescription's distance, pace, heart rate and incline targets from a request
+func applyCardioTargets(prescription *models.WorkoutPrescription, req models.CardioTargetsRequest) {
+	if req.TargetDistance != nil {
+		targetMeters, originalValue, originalUnit := utils.ProcessDistanceInput(req.TargetDistance)
+		prescription.TargetDistanceMeters = targetMeters
+		prescription.OriginalTargetDistanceValue = originalValue
+		prescription.OriginalTargetDistanceUnit = originalUnit
+	}
+	if req.TargetPace != nil {
+		prescription.TargetPaceSecondsPerKm = utils.ProcessPaceInput(req.TargetPace)
+	}
+	prescription.TargetHeartRateMin = req.TargetHeartRateMin
+	prescription.TargetHeartRateMax = req.TargetHeartRateMax
+	prescription.TargetInclinePercent = req.TargetInclinePercent
+}
+
 // applyOrModeFilters applies OR logic - workout matches ANY of the specified muscle groups OR exercises
 func applyOrModeFilters(query *gorm.DB, muscleGroupIDs, exerciseIDs []string) *gorm.DB {
 	var conditions []string
@@ -0,0 +1,308 @@
+package database
+
+import (
+	"fmt"
+	"lamari-fit-api/models"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PurgeDeletedAccounts purges the personal data of every account whose deletion grace period
+// ended before now, and returns how many accounts were purged
+func PurgeDeletedAccounts(db *gorm.DB, now time.Time) (int, error) {
+	var userIDs []uuid.UUID
+	if err := db.Model(&models.User{}).
+		Where("deletion_scheduled_at <= ? AND purged_at IS NULL", now).
+		Order("deletion_scheduled_at ASC").
+		Pluck("id", &userIDs).Error; err != nil {
+		return 0, fmt.Errorf("failed to find accounts to purge: %w", err)
+	}
+
+	for i, userID := range userIDs {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			return PurgeAccount(tx, userID, now)
+		})
+		if err != nil {
+			return i, fmt.Errorf("failed to purge account %s: %w", userID, err)
+		}
+		log.Printf("Purged account %s", userID)
+	}
+
+	return len(userIDs), nil
+}
+
+// PurgeAccount hard-deletes a user's personal data. The user row is kept, anonymised, so that
+// comments and reviews other users can see stay in place without naming the author. Workout
+// plans other users are enrolled in, and the workouts they use, are kept for the same reason.
+func PurgeAccount(tx *gorm.DB, userID uuid.UUID, now time.Time) error {
+	steps := []func(*gorm.DB, uuid.UUID) error{
+		purgeSessions,
+		purgeSharing,
+		purgeMessages,
+		purgeWorkouts,
+		purgeTrainerData,
+		purgeProfileData,
+	}
+	for _, step := range steps {
+		if err := step(tx, userID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"email":                  fmt.Sprintf("deleted-%s@deleted.invalid", userID),
+		"password":               "",
+		"first_name":             "Deleted",
+		"last_name":              "User",
+		"google_id":              nil,
+		"apple_id":               nil,
+		"is_active":              false,
+		"profile_visibility":     "private",
+		"is_looking_for_trainer": false,
+		"bio":                    "",
+		"location_latitude":      nil,
+		"location_longitude":     nil,
+		"location_country_code":  nil,
+		"location_region":        nil,
+		"location_city":          nil,
+		"location_district":      nil,
+		"location_postal_code":   nil,
+		"location_raw_address":   nil,
+		"purged_at":              now,
+	}).Error
+}
+
+// pluckIDs returns the IDs of the rows matched by a query, soft-deleted ones included
+func pluckIDs(tx *gorm.DB, model interface{}, query string, args ...interface{}) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := tx.Unscoped().Model(model).Where(query, args...).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// purgeDelete is a hard delete of the rows of a model matched by a query
+type purgeDelete struct {
+	model interface{}
+	query string
+	args  []interface{}
+}
+
+// runPurgeDeletes runs hard deletes in order
+func runPurgeDeletes(tx *gorm.DB, deletes []purgeDelete) error {
+	for _, d := range deletes {
+		if err := tx.Unscoped().Where(d.query, d.args...).Delete(d.model).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purgeSessions deletes the user's workout sessions with everything logged in them
+func purgeSessions(tx *gorm.DB, userID uuid.UUID) error {
+	sessionIDs, err := pluckIDs(tx, &models.WorkoutSession{}, "user_id = ?", userID)
+	if err != nil {
+		return err
+	}
+	blockIDs, err := pluckIDs(tx, &models.SessionBlock{}, "session_id IN ?", sessionIDs)
+	if err != nil {
+		return err
+	}
+	exerciseIDs, err := pluckIDs(tx, &models.SessionExercise{}, "session_block_id IN ?", blockIDs)
+	if err != nil {
+		return err
+	}
+	activityIDs, err := pluckIDs(tx, &models.SessionActivity{}, "session_id IN ?", sessionIDs)
+	if err != nil {
+		return err
+	}
+
+	deletes := []purgeDelete{
+		{&models.PersonalRecord{}, "user_id = ?", []interface{}{userID}},
+		{&models.ActivitySample{}, "activity_id IN ?", []interface{}{activityIDs}},
+		{&models.SessionActivity{}, "id IN ?", []interface{}{activityIDs}},
+		{&models.SessionSet{}, "session_exercise_id IN ?", []interface{}{exerciseIDs}},
+		{&models.SessionExercise{}, "id IN ?", []interface{}{exerciseIDs}},
+		{&models.SessionBlock{}, "id IN ?", []interface{}{blockIDs}},
+		{&models.WorkoutSession{}, "id IN ?", []interface{}{sessionIDs}},
+		{&models.PlanEnrollment{}, "user_id = ?", []interface{}{userID}},
+	}
+	if err := runPurgeDeletes(tx, deletes); err != nil {
+		return err
+	}
+
+	// Sessions the user logged for their clients stay with the clients
+	return tx.Unscoped().Model(&models.WorkoutSession{}).
+		Where("created_by_id = ?", userID).
+		Update("created_by_id", nil).Error
+}
+
+// purgeSharing deletes workouts shared by or with the user, with their comments, and the user's
+// reactions. The user's comments on other people's shared workouts are kept.
+func purgeSharing(tx *gorm.DB, userID uuid.UUID) error {
+	shareIDs, err := pluckIDs(tx, &models.SharedWorkout{}, "shared_by_id = ? OR shared_with_id = ?", userID, userID)
+	if err != nil {
+		return err
+	}
+	return deleteShares(tx, shareIDs, userID)
+}
+
+// deleteShares deletes shared workouts with their comments and reactions, and any other
+// reactions of the user
+func deleteShares(tx *gorm.DB, shareIDs []uuid.UUID, userID uuid.UUID) error {
+	commentIDs, err := pluckIDs(tx, &models.WorkoutComment{}, "shared_workout_id IN ?", shareIDs)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Unscoped().
+		Where("comment_id IN ? OR user_id = ?", commentIDs, userID).
+		Delete(&models.WorkoutCommentReaction{}).Error; err != nil {
+		return err
+	}
+	// Replies first: they reference their parent comment
+	if err := tx.Unscoped().Where("id IN ? AND parent_id IS NOT NULL", commentIDs).Delete(&models.WorkoutComment{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("id IN ?", commentIDs).Delete(&models.WorkoutComment{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("id IN ?", shareIDs).Delete(&models.SharedWorkout{}).Error
+}
+
+// purgeMessages deletes the user's conversations with all their messages, including the ones the
+// other participant sent
+func purgeMessages(tx *gorm.DB, userID uuid.UUID) error {
+	conversationIDs, err := pluckIDs(tx, &models.Conversation{}, "user_one_id = ? OR user_two_id = ?", userID, userID)
+	if err != nil {
+		return err
+	}
+
+	deletes := []purgeDelete{
+		{&models.Message{}, "conversation_id IN ?", []interface{}{conversationIDs}},
+		{&models.Conversation{}, "id IN ?", []interface{}{conversationIDs}},
+	}
+	return runPurgeDeletes(tx, deletes)
+}
+
+// purgeWorkouts deletes the user's workout plans and workouts, except plans other users are
+// enrolled in and workouts used by other users' plans
+func purgeWorkouts(tx *gorm.DB, userID uuid.UUID) error {
+	planIDs, err := pluckIDs(tx, &models.WorkoutPlan{},
+		"user_id = ? AND id NOT IN (?)", userID,
+		tx.Unscoped().Model(&models.PlanEnrollment{}).Select("plan_id").Where("user_id <> ?", userID))
+	if err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("plan_id IN ?", planIDs).Delete(&models.WorkoutPlanItem{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("id IN ?", planIDs).Delete(&models.WorkoutPlan{}).Error; err != nil {
+		return err
+	}
+
+	// Workouts still in a plan at this point belong to plans that are kept
+	workoutIDs, err := pluckIDs(tx, &models.Workout{},
+		"user_id = ? AND id NOT IN (?)", userID,
+		tx.Unscoped().Model(&models.WorkoutPlanItem{}).Select("workout_id"))
+	if err != nil {
+		return err
+	}
+	prescriptionIDs, err := pluckIDs(tx, &models.WorkoutPrescription{}, "workout_id IN ?", workoutIDs)
+	if err != nil {
+		return err
+	}
+	shareIDs, err := pluckIDs(tx, &models.SharedWorkout{}, "workout_id IN ?", workoutIDs)
+	if err != nil {
+		return err
+	}
+	if err := deleteShares(tx, shareIDs, userID); err != nil {
+		return err
+	}
+
+	// Other users' sessions of these workouts keep their logged sets
+	if err := tx.Unscoped().Model(&models.SessionExercise{}).
+		Where("prescription_id IN ?", prescriptionIDs).
+		Update("prescription_id", nil).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Model(&models.WorkoutSession{}).
+		Where("workout_id IN ?", workoutIDs).
+		Update("workout_id", nil).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Unscoped().Where("workout_id IN ?", workoutIDs).Delete(&models.UserFavoriteWorkout{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("id IN ?", prescriptionIDs).Delete(&models.WorkoutPrescription{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("id IN ?", workoutIDs).Delete(&models.Workout{}).Error
+}
+
+// purgeTrainerData deletes the user's trainer profile, client links, invitations, assignments, RPE
+// scales and the reviews written about them. Reviews the user wrote are kept.
+func purgeTrainerData(tx *gorm.DB, userID uuid.UUID) error {
+	profileIDs, err := pluckIDs(tx, &models.TrainerProfile{}, "user_id = ?", userID)
+	if err != nil {
+		return err
+	}
+	scaleIDs, err := pluckIDs(tx, &models.RPEScale{}, "trainer_id = ?", userID)
+	if err != nil {
+		return err
+	}
+	rpeValueIDs, err := pluckIDs(tx, &models.RPEScaleValue{}, "scale_id IN ?", scaleIDs)
+	if err != nil {
+		return err
+	}
+
+	var user models.User
+	if err := tx.Unscoped().Select("email").First(&user, "id = ?", userID).Error; err != nil {
+		return err
+	}
+
+	// Clients' sets and prescriptions keep their values without the trainer's scale
+	for _, model := range []interface{}{&models.SessionSet{}, &models.WorkoutPrescription{}} {
+		if err := tx.Unscoped().Model(model).
+			Where("rpe_value_id IN ?", rpeValueIDs).
+			Update("rpe_value_id", nil).Error; err != nil {
+			return err
+		}
+	}
+
+	deletes := []purgeDelete{
+		{&models.RPEScaleValue{}, "id IN ?", []interface{}{rpeValueIDs}},
+		{&models.RPEScale{}, "id IN ?", []interface{}{scaleIDs}},
+		{&models.TrainerReview{}, "trainer_id = ?", []interface{}{userID}},
+		{&models.TrainerAssignment{}, "trainer_id = ? OR client_id = ?", []interface{}{userID, userID}},
+		{&models.TrainerClientLink{}, "trainer_id = ? OR client_id = ?", []interface{}{userID, userID}},
+		{&models.TrainerInvitation{}, "trainer_id = ? OR invitee_email = ?", []interface{}{userID, user.Email}},
+		{&models.TrainerSpecialty{}, "trainer_profile_id IN ?", []interface{}{profileIDs}},
+		{&models.TrainerProfile{}, "id IN ?", []interface{}{profileIDs}},
+	}
+	return runPurgeDeletes(tx, deletes)
+}
+
+// purgeProfileData deletes the user's fitness profile, body weight, favorites, equipment,
+// friendships, roles and refresh tokens
+func purgeProfileData(tx *gorm.DB, userID uuid.UUID) error {
+	profileIDs, err := pluckIDs(tx, &models.UserFitnessProfile{}, "user_id = ?", userID)
+	if err != nil {
+		return err
+	}
+
+	deletes := []purgeDelete{
+		{&models.UserFitnessGoal{}, "user_fitness_profile_id IN ?", []interface{}{profileIDs}},
+		{&models.UserFitnessProfile{}, "id IN ?", []interface{}{profileIDs}},
+		{&models.WeightLog{}, "user_id = ?", []interface{}{userID}},
+		{&models.UserFavoriteExercise{}, "user_id = ?", []interface{}{userID}},
+		{&models.UserFavoriteWorkout{}, "user_id = ?", []interface{}{userID}},
+		{&models.UserEquipment{}, "user_id = ?", []interface{}{userID}},
+		{&models.Friendship{}, "user_id = ? OR friend_id = ?", []interface{}{userID, userID}},
+		{&models.UserRole{}, "user_id = ?", []interface{}{userID}},
+		{&models.RefreshToken{}, "user_id = ?", []interface{}{userID}},
+	}
+	return runPurgeDeletes(tx, deletes)
+}
@@ -60,6 +60,10 @@ func InitializeDB() {
 
 // AutoMigrate uses GORM's AutoMigrate feature (legacy/development mode)
 func AutoMigrate() {
+	// Trainer-client links created before activated_at was added need it backfilled
+	backfillLinkActivation := DB.Migrator().HasTable(&models.TrainerClientLink{}) &&
+		!DB.Migrator().HasColumn(&models.TrainerClientLink{}, "ActivatedAt")
+
 	err := DB.AutoMigrate(
 		// Core user & auth (no dependencies)
 		&models.User{},
@@ -113,21 +117,39 @@ func AutoMigrate() {
 		&models.Workout{},
 		&models.WorkoutPrescription{},
 		&models.PlanEnrollment{},
+		&models.TrainerAssignment{},
 
 		// Workout sessions
 		&models.WorkoutSession{},
 		&models.SessionBlock{},
 		&models.SessionExercise{},
 		&models.SessionSet{},
+		&models.SessionActivity{},
+		&models.ActivitySample{},
+		&models.PersonalRecord{},
 
 		// Social features
 		&models.SharedWorkout{},
 		&models.WorkoutComment{},
 		&models.WorkoutCommentReaction{},
+
+		// Messaging
+		&models.Conversation{},
+		&models.Message{},
 	)
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
+
+	if backfillLinkActivation {
+		// Links that are or were active count as activated when they were created; declined
+		// invitations are deleted, so only links a trainer cancelled while pending are overcounted
+		if err := DB.Model(&models.TrainerClientLink{}).
+			Where("activated_at IS NULL AND status IN ?", []string{"active", "inactive"}).
+			UpdateColumn("activated_at", gorm.Expr("created_at")).Error; err != nil {
+			log.Fatal("Failed to backfill trainer client link activation:", err)
+		}
+	}
 	log.Println("Database AutoMigrate completed")
 }
 
@@ -169,13 +191,19 @@ func DropAllData(db *gorm.DB) error {
 	// Delete in reverse order to respect foreign key constraints
 	tables := []interface{}{
 		&models.RefreshToken{},
+		&models.Message{},
+		&models.Conversation{},
 		&models.WorkoutCommentReaction{},
 		&models.WorkoutComment{},
 		&models.SharedWorkout{},
+		&models.PersonalRecord{},
+		&models.ActivitySample{},
+		&models.SessionActivity{},
 		&models.SessionSet{},
 		&models.SessionExercise{},
 		&models.SessionBlock{},
 		&models.WorkoutSession{},
+		&models.TrainerAssignment{},
 		&models.PlanEnrollment{},
 		&models.WorkoutPrescription{},
 		&models.WorkoutPlanItem{},
@@ -227,16 +255,23 @@ func DropAllTables(db *gorm.DB) error {
 	tables := []interface{}{
 		// Auth tokens (before User)
 		&models.RefreshToken{},
+		// Messaging
+		&models.Message{},
+		&models.Conversation{},
 		// Social
 		&models.WorkoutCommentReaction{},
 		&models.WorkoutComment{},
 		&models.SharedWorkout{},
 		// Workout logs
+		&models.PersonalRecord{},
+		&models.ActivitySample{},
+		&models.SessionActivity{},
 		&models.SessionSet{},
 		&models.SessionExercise{},
 		&models.SessionBlock{},
 		&models.WorkoutSession{},
 		// Workout structure
+		&models.TrainerAssignment{},
 		&models.WorkoutPrescription{},
 		&models.WorkoutPlanItem{},
 		&models.PlanEnrollment{},
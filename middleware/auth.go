@@ -40,6 +40,13 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Accounts scheduled for deletion are locked until their owner logs in again
+		if user.IsPendingDeletion() {
+			utils.UnauthorizedResponse(c, "Account is scheduled for deletion")
+			c.Abort()
+			return
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
 		c.Next()
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ===== SESSION ACTIVITY =====
+
+// SessionActivity holds the recorded track of a session imported from a GPX, TCX or FIT file
+type SessionActivity struct {
+	ID                  uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SessionID           uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"session_id"`
+	UserID              uuid.UUID `gorm:"type:uuid;not null;index:idx_session_activity_user_start" json:"user_id"`
+	StartedAt           time.Time `gorm:"not null;index:idx_session_activity_user_start" json:"started_at"` // Used to de-duplicate imports
+	Source              string    `gorm:"type:varchar(3);not null" json:"source"`                           // gpx, tcx or fit
+	Sport               string    `gorm:"type:varchar(50)" json:"sport,omitempty"`
+	FileName            string    `gorm:"type:varchar(255)" json:"file_name,omitempty"`
+	DurationSeconds     int       `gorm:"not null" json:"duration_seconds"`
+	DistanceMeters      *float64  `gorm:"type:decimal(9,2)" json:"-"`
+	ElevationGainMeters *float64  `gorm:"type:decimal(7,1)" json:"elevation_gain_meters,omitempty"`
+	ElevationLossMeters *float64  `gorm:"type:decimal(7,1)" json:"elevation_loss_meters,omitempty"`
+	AverageHeartRate    *int      `json:"average_heart_rate,omitempty"` // bpm
+	MaxHeartRate        *int      `json:"max_heart_rate,omitempty"`     // bpm
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+
+	// Relations
+	Session WorkoutSession   `gorm:"foreignKey:SessionID;constraint:OnDelete:CASCADE" json:"session,omitempty"`
+	User    User             `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+	Samples []ActivitySample `gorm:"foreignKey:ActivityID" json:"samples,omitempty"`
+}
+
+func (sa *SessionActivity) BeforeCreate(tx *gorm.DB) (err error) {
+	if sa.ID == uuid.Nil {
+		sa.ID = uuid.New()
+	}
+	return
+}
+
+// ActivitySample is a single point of an imported activity's track
+type ActivitySample struct {
+	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ActivityID      uuid.UUID `gorm:"type:uuid;not null;index" json:"activity_id"`
+	OffsetSeconds   int       `gorm:"not null" json:"offset_seconds"` // From the start of the activity
+	DistanceMeters  *float64  `gorm:"type:decimal(9,2)" json:"-"`     // Cumulative
+	ElevationMeters *float64  `gorm:"type:decimal(6,1)" json:"elevation_meters,omitempty"`
+	HeartRate       *int      `json:"heart_rate,omitempty"`
+
+	// Relations
+	Activity SessionActivity `gorm:"foreignKey:ActivityID;constraint:OnDelete:CASCADE" json:"activity,omitempty"`
+}
+
+func (as *ActivitySample) BeforeCreate(tx *gorm.DB) (err error) {
+	if as.ID == uuid.Nil {
+		as.ID = uuid.New()
+	}
+	return
+}
+
+// ===== REQUEST DTOs =====
+
+// ImportActivityRequest represents a GPX, TCX or FIT file upload (multipart form)
+type ImportActivityRequest struct {
+	File       *multipart.FileHeader `form:"file" binding:"required"`
+	ExerciseID string                `form:"exercise_id"` // Optional: logs the activity as a set of this exercise
+	Notes      string                `form:"notes" binding:"omitempty,max=1000"`
+}
+
+// ===== RESPONSE DTOs =====
+
+// SessionActivityResponse represents the summary of an imported activity
+type SessionActivityResponse struct {
+	ID                  uuid.UUID       `json:"id"`
+	Source              string          `json:"source"`
+	Sport               string          `json:"sport,omitempty"`
+	FileName            string          `json:"file_name,omitempty"`
+	StartedAt           time.Time       `json:"started_at"`
+	DurationSeconds     int             `json:"duration_seconds"`
+	Distance            *DistanceOutput `json:"distance,omitempty"`
+	Pace                *PaceOutput     `json:"pace,omitempty"`
+	ElevationGainMeters *float64        `json:"elevation_gain_meters,omitempty"`
+	ElevationLossMeters *float64        `json:"elevation_loss_meters,omitempty"`
+	AverageHeartRate    *int            `json:"average_heart_rate,omitempty"`
+	MaxHeartRate        *int            `json:"max_heart_rate,omitempty"`
+}
+
+// ActivitySampleResponse represents a point of an activity's track
+type ActivitySampleResponse struct {
+	OffsetSeconds   int             `json:"offset_seconds"`
+	Distance        *DistanceOutput `json:"distance,omitempty"`
+	ElevationMeters *float64        `json:"elevation_meters,omitempty"`
+	HeartRate       *int            `json:"heart_rate,omitempty"`
+}
+
+// SessionActivityDetailResponse represents an imported activity with its samples
+type SessionActivityDetailResponse struct {
+	SessionActivityResponse
+	Samples []ActivitySampleResponse `json:"samples"`
+}
+
+// ===== HELPER FUNCTIONS =====
+
+// ToResponse converts a SessionActivity to its summary response, with distances in the given unit
+func (sa *SessionActivity) ToResponse(distanceUnit string) SessionActivityResponse {
+	response := SessionActivityResponse{
+		ID:                  sa.ID,
+		Source:              sa.Source,
+		Sport:               sa.Sport,
+		FileName:            sa.FileName,
+		StartedAt:           sa.StartedAt,
+		DurationSeconds:     sa.DurationSeconds,
+		ElevationGainMeters: sa.ElevationGainMeters,
+		ElevationLossMeters: sa.ElevationLossMeters,
+		AverageHeartRate:    sa.AverageHeartRate,
+		MaxHeartRate:        sa.MaxHeartRate,
+	}
+
+	if sa.DistanceMeters != nil {
+		response.Distance = NewDistanceOutput(*sa.DistanceMeters, distanceUnit)
+		if *sa.DistanceMeters > 0 && sa.DurationSeconds > 0 {
+			secondsPerKm := float64(sa.DurationSeconds) / (*sa.DistanceMeters / 1000)
+			response.Pace = NewPaceOutput(secondsPerKm, distanceUnit)
+		}
+	}
+
+	return response
+}
+
+// ToResponse converts an ActivitySample to a response, with its distance in the given unit
+func (as *ActivitySample) ToResponse(distanceUnit string) ActivitySampleResponse {
+	response := ActivitySampleResponse{
+		OffsetSeconds:   as.OffsetSeconds,
+		ElevationMeters: as.ElevationMeters,
+		HeartRate:       as.HeartRate,
+	}
+	if as.DistanceMeters != nil {
+		response.Distance = NewDistanceOutput(*as.DistanceMeters, distanceUnit)
+	}
+	return response
+}
@@ -0,0 +1,191 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ===== EXERCISE HISTORY =====
+
+// TopSetResponse represents the best set performed within a history bucket
+type TopSetResponse struct {
+	Weight *WeightOutput `json:"weight,omitempty"`
+	Reps   *int          `json:"reps,omitempty"`
+}
+
+// ExerciseHistoryBucket represents aggregated performance for one day, week or month
+type ExerciseHistoryBucket struct {
+	PeriodStart        string          `json:"period_start"` // YYYY-MM-DD
+	Sessions           int             `json:"sessions"`
+	Sets               int             `json:"sets"`
+	TotalReps          int             `json:"total_reps"`
+	TopSet             *TopSetResponse `json:"top_set,omitempty"`
+	TotalVolume        *WeightOutput   `json:"total_volume,omitempty"` // Sum of reps × weight
+	EstimatedOneRepMax *WeightOutput   `json:"estimated_1rm,omitempty"`
+	AverageRPE         *float64        `json:"average_rpe,omitempty"`
+	LongestHoldSeconds *int            `json:"longest_hold_seconds,omitempty"`
+}
+
+// ExerciseHistoryResponse represents the progress history of a single exercise
+type ExerciseHistoryResponse struct {
+	ExerciseID   uuid.UUID               `json:"exercise_id"`
+	ExerciseName string                  `json:"exercise_name"`
+	GroupBy      string                  `json:"group_by"`
+	Formula      string                  `json:"formula"`
+	From         *string                 `json:"from,omitempty"`
+	To           *string                 `json:"to,omitempty"`
+	History      []ExerciseHistoryBucket `json:"history"`
+}
+
+// ===== MUSCLE GROUP VOLUME =====
+
+// MuscleGroupVolume represents the training volume attributed to one muscle group in a week
+// Sets and tonnage are weighted: primary muscles count fully, secondary muscles by the secondary weight
+type MuscleGroupVolume struct {
+	MuscleGroupID uuid.UUID     `json:"muscle_group_id"`
+	Name          string        `json:"name"`
+	Category      string        `json:"category,omitempty"`
+	Sets          float64       `json:"sets"`
+	PrimarySets   int           `json:"primary_sets"`
+	SecondarySets int           `json:"secondary_sets"`
+	Tonnage       *WeightOutput `json:"tonnage"`
+}
+
+// MuscleVolumeWeek represents the muscle group volume for one ISO week
+type MuscleVolumeWeek struct {
+	WeekStart    string              `json:"week_start"` // Monday, YYYY-MM-DD
+	ISOYear      int                 `json:"iso_year"`
+	ISOWeek      int                 `json:"iso_week"`
+	MuscleGroups []MuscleGroupVolume `json:"muscle_groups"`
+}
+
+// MuscleVolumeResponse represents weekly training volume per muscle group
+type MuscleVolumeResponse struct {
+	UserID          uuid.UUID          `json:"user_id"`
+	From            string             `json:"from"`
+	To              string             `json:"to"`
+	SecondaryWeight float64            `json:"secondary_weight"`
+	Weeks           []MuscleVolumeWeek `json:"weeks"`
+}
+
+// ===== PRESCRIPTION COMPLIANCE =====
+
+// ExerciseComplianceResponse compares what was logged for a session exercise with its prescription.
+// Averages are taken over the completed main sets; deltas are actual minus prescribed.
+type ExerciseComplianceResponse struct {
+	SessionExerciseID     uuid.UUID     `json:"session_exercise_id"`
+	ExerciseID            uuid.UUID     `json:"exercise_id"`
+	ExerciseName          string        `json:"exercise_name"`
+	Skipped               bool          `json:"skipped"`
+	PrescribedSets        int           `json:"prescribed_sets"`
+	CompletedSets         int           `json:"completed_sets"`
+	SetsDelta             int           `json:"sets_delta"`
+	CompletionPercent     float64       `json:"completion_percent"`
+	PrescribedReps        *int          `json:"prescribed_reps,omitempty"`
+	AverageReps           *float64      `json:"average_reps,omitempty"`
+	RepsDelta             *float64      `json:"reps_delta,omitempty"`
+	PrescribedHoldSeconds *int          `json:"prescribed_hold_seconds,omitempty"`
+	AverageHoldSeconds    *float64      `json:"average_hold_seconds,omitempty"`
+	HoldSecondsDelta      *float64      `json:"hold_seconds_delta,omitempty"`
+	PrescribedWeight      *WeightOutput `json:"prescribed_weight,omitempty"`
+	AverageWeight         *WeightOutput `json:"average_weight,omitempty"`
+	WeightDelta           *WeightOutput `json:"weight_delta,omitempty"`
+	PrescribedRPE         *int          `json:"prescribed_rpe,omitempty"`
+	AverageRPE            *float64      `json:"average_rpe,omitempty"`
+	RPEDelta              *float64      `json:"rpe_delta,omitempty"`
+	Score                 float64       `json:"score"` // 0-100
+}
+
+// BlockComplianceResponse summarises the compliance of a session block's prescribed exercises
+type BlockComplianceResponse struct {
+	SessionBlockID    uuid.UUID                    `json:"session_block_id"`
+	BlockOrder        int                          `json:"block_order"`
+	Type              PrescriptionType             `json:"type"`
+	Skipped           bool                         `json:"skipped"`
+	PrescribedSets    int                          `json:"prescribed_sets"`
+	CompletedSets     int                          `json:"completed_sets"`
+	CompletionPercent float64                      `json:"completion_percent"`
+	SkippedExercises  int                          `json:"skipped_exercises"`
+	Score             float64                      `json:"score"` // 0-100, weighted by prescribed sets
+	Exercises         []ExerciseComplianceResponse `json:"exercises"`
+}
+
+// SessionComplianceResponse summarises how closely a session followed its prescriptions
+type SessionComplianceResponse struct {
+	SessionID             uuid.UUID                 `json:"session_id"`
+	PrescribedSets        int                       `json:"prescribed_sets"`
+	CompletedSets         int                       `json:"completed_sets"`
+	CompletionPercent     float64                   `json:"completion_percent"`
+	SkippedBlocks         int                       `json:"skipped_blocks"`
+	SkippedExercises      int                       `json:"skipped_exercises"`
+	UnprescribedExercises int                       `json:"unprescribed_exercises"` // exercises added without a prescription
+	Score                 float64                   `json:"score"`                  // 0-100, weighted by prescribed sets
+	Blocks                []BlockComplianceResponse `json:"blocks"`
+}
+
+// ComplianceTrendBucket represents the average compliance of the sessions in one period
+type ComplianceTrendBucket struct {
+	PeriodStart              string  `json:"period_start"` // YYYY-MM-DD
+	Sessions                 int     `json:"sessions"`
+	AverageScore             float64 `json:"average_score"`
+	AverageCompletionPercent float64 `json:"average_completion_percent"`
+	SkippedExercises         int     `json:"skipped_exercises"`
+}
+
+// ComplianceTrendResponse represents a user's prescription compliance over time
+type ComplianceTrendResponse struct {
+	UserID  uuid.UUID               `json:"user_id"`
+	GroupBy string                  `json:"group_by"`
+	From    string                  `json:"from"`
+	To      string                  `json:"to"`
+	Trend   []ComplianceTrendBucket `json:"trend"`
+}
+
+// ===== TRAINER CLIENT DASHBOARD =====
+
+// ClientWeightPoint is one body weight entry in a client's weight trend
+type ClientWeightPoint struct {
+	LoggedAt time.Time     `json:"logged_at"`
+	Weight   *WeightOutput `json:"weight"`
+}
+
+// ClientWeightTrendResponse summarises a client's body weight over the last PeriodDays
+type ClientWeightTrendResponse struct {
+	PeriodDays     int                 `json:"period_days"`
+	Latest         *WeightOutput       `json:"latest,omitempty"`
+	LatestLoggedAt *time.Time          `json:"latest_logged_at,omitempty"`
+	Change         *WeightOutput       `json:"change,omitempty"` // latest minus the first entry of the period
+	Entries        []ClientWeightPoint `json:"entries"`
+}
+
+// ClientOverviewResponse summarises a client's training and body metrics for their trainer. Session
+// activity, open assignments, the weight trend and health notes are left out unless the client
+// consented to share them.
+type ClientOverviewResponse struct {
+	Client               UserPublicResponse          `json:"client"`
+	LinkedSince          *time.Time                  `json:"linked_since,omitempty"`
+	ConsentScopes        []string                    `json:"consent_scopes"`
+	LastSessionAt        *time.Time                  `json:"last_session_at,omitempty"`
+	DaysSinceLastSession *int                        `json:"days_since_last_session,omitempty"`
+	SessionsThisWeek     int                         `json:"sessions_this_week"`
+	TargetWeeklyWorkouts *int                        `json:"target_weekly_workouts,omitempty"` // nil without a fitness profile
+	WeightTrend          *ClientWeightTrendResponse  `json:"weight_trend,omitempty"`           // nil without body weight consent
+	RecentRecords        []PersonalRecordResponse    `json:"recent_records"`
+	OpenAssignments      []TrainerAssignmentResponse `json:"open_assignments"`
+	HealthConditions     string                      `json:"health_conditions,omitempty"`
+	InjuriesNotes        string                      `json:"injuries_notes,omitempty"`
+}
+
+// ClientRosterEntry summarises one of a trainer's active clients in their roster
+type ClientRosterEntry struct {
+	Client               UserPublicResponse `json:"client"`
+	LinkedSince          *time.Time         `json:"linked_since,omitempty"`
+	ConsentScopes        []string           `json:"consent_scopes"`
+	LastSessionAt        *time.Time         `json:"last_session_at,omitempty"`
+	DaysSinceLastSession *int               `json:"days_since_last_session,omitempty"` // nil if the client never trained
+	SessionsThisWeek     int                `json:"sessions_this_week"`
+	TargetWeeklyWorkouts *int               `json:"target_weekly_workouts,omitempty"`
+	OpenAssignments      int                `json:"open_assignments"`
+	OverdueAssignments   int                `json:"overdue_assignments"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "math"
+
 // WeightInput represents weight input from API requests
 // Used for any weight field across the entire API
 type WeightInput struct {
@@ -13,3 +15,66 @@ type WeightOutput struct {
 	WeightValue *float64 `json:"weight_value,omitempty"`
 	WeightUnit  *string  `json:"weight_unit,omitempty"`
 }
+
+// DistanceInput represents distance input from API requests
+// Used for any distance field across the entire API
+type DistanceInput struct {
+	DistanceValue *float64 `json:"distance_value,omitempty" binding:"omitempty,gte=0"`
+	DistanceUnit  *string  `json:"distance_unit,omitempty" binding:"omitempty,oneof=m km mi"`
+}
+
+// DistanceOutput represents distance output in API responses
+// Always converted to user's preferred unit (km or mi)
+type DistanceOutput struct {
+	DistanceValue *float64 `json:"distance_value,omitempty"`
+	DistanceUnit  *string  `json:"distance_unit,omitempty"`
+}
+
+// PaceInput represents a pace from API requests, in seconds per km or per mile
+type PaceInput struct {
+	SecondsPerUnit *float64 `json:"seconds_per_unit,omitempty" binding:"omitempty,gt=0"`
+	DistanceUnit   *string  `json:"distance_unit,omitempty" binding:"omitempty,oneof=km mi"`
+}
+
+// PaceOutput represents a pace in API responses, in seconds per the user's preferred distance unit
+type PaceOutput struct {
+	SecondsPerUnit *float64 `json:"seconds_per_unit,omitempty"`
+	DistanceUnit   *string  `json:"distance_unit,omitempty"`
+}
+
+// MetersPerMile is the exact length of a mile (1 mile = 1609.344 metres)
+const MetersPerMile = 1609.344
+
+// NewDistanceOutput converts from canonical metres to the user's preferred unit (km or mi).
+// Any other unit is treated as km.
+func NewDistanceOutput(meters float64, preferredDistanceUnit string) *DistanceOutput {
+	unit := "km"
+	value := meters / 1000
+	if preferredDistanceUnit == "mi" {
+		unit = "mi"
+		value = meters / MetersPerMile
+	}
+	value = math.Round(value*1000) / 1000
+
+	return &DistanceOutput{
+		DistanceValue: &value,
+		DistanceUnit:  &unit,
+	}
+}
+
+// NewPaceOutput converts a pace from canonical seconds per km to the user's preferred unit (km or mi).
+// Any other unit is treated as km.
+func NewPaceOutput(secondsPerKm float64, preferredDistanceUnit string) *PaceOutput {
+	unit := "km"
+	value := secondsPerKm
+	if preferredDistanceUnit == "mi" {
+		unit = "mi"
+		value = secondsPerKm * MetersPerMile / 1000
+	}
+	value = math.Round(value*10) / 10
+
+	return &PaceOutput{
+		SecondsPerUnit: &value,
+		DistanceUnit:   &unit,
+	}
+}
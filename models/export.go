@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ===== DATA EXPORT DTOs =====
+// Entries of the archive returned by GET /me/export. Weights and distances are in the user's
+// preferred units, like the rest of the API.
+
+// ExportWeightLog represents a weight log entry in a data export
+type ExportWeightLog struct {
+	ID        uuid.UUID     `json:"id"`
+	Weight    *WeightOutput `json:"weight"`
+	Notes     string        `json:"notes,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// ExportWorkout represents a workout with its prescriptions in a data export
+type ExportWorkout struct {
+	ID                uuid.UUID                   `json:"id"`
+	Title             string                      `json:"title"`
+	Description       string                      `json:"description"`
+	DifficultyLevel   string                      `json:"difficulty_level"`
+	EstimatedDuration *int                        `json:"estimated_duration"`
+	IsTemplate        bool                        `json:"is_template"`
+	Visibility        string                      `json:"visibility"`
+	Prescriptions     []PrescriptionGroupResponse `json:"prescriptions"`
+	CreatedAt         time.Time                   `json:"created_at"`
+	UpdatedAt         time.Time                   `json:"updated_at"`
+}
+
+// ExportWorkoutPlan represents a workout plan in a data export
+type ExportWorkoutPlan struct {
+	ID            uuid.UUID               `json:"id"`
+	Title         string                  `json:"title"`
+	Description   string                  `json:"description"`
+	Visibility    string                  `json:"visibility"`
+	TemplateWeeks int                     `json:"template_weeks"`
+	Items         []ExportWorkoutPlanItem `json:"items"`
+	CreatedAt     time.Time               `json:"created_at"`
+	UpdatedAt     time.Time               `json:"updated_at"`
+}
+
+// ExportWorkoutPlanItem represents a workout of a plan in a data export
+type ExportWorkoutPlanItem struct {
+	WorkoutID    uuid.UUID `json:"workout_id"`
+	WorkoutTitle string    `json:"workout_title"`
+	WeekIndex    int       `json:"week_index"`
+}
+
+// ExportFavorite represents a favorited exercise or workout in a data export
+type ExportFavorite struct {
+	Type        string    `json:"type"` // exercise or workout
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	FavoritedAt time.Time `json:"favorited_at"`
+}
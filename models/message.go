@@ -0,0 +1,206 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Conversation is the private message thread between two users. Users can message each other
+// while one is the other's active trainer or while they are friends; the thread stays readable
+// afterwards. UserOneID is the lower of the two user IDs so that each pair has one conversation.
+type Conversation struct {
+	ID            uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserOneID     uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_conversation_users" json:"user_one_id"`
+	UserTwoID     uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_conversation_users;index" json:"user_two_id"`
+	LastMessageAt *time.Time     `json:"last_message_at,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	UserOne User `gorm:"foreignKey:UserOneID;constraint:OnDelete:CASCADE" json:"user_one,omitempty"`
+	UserTwo User `gorm:"foreignKey:UserTwoID;constraint:OnDelete:CASCADE" json:"user_two,omitempty"`
+}
+
+// Message is a message in a conversation. It can attach one of the sender's workouts or a workout
+// session of either participant. ReadAt is set when the recipient marks the conversation read.
+type Message struct {
+	ID               uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ConversationID   uuid.UUID      `gorm:"type:uuid;not null;index:idx_message_conversation_created" json:"conversation_id"`
+	SenderID         uuid.UUID      `gorm:"type:uuid;not null" json:"sender_id"`
+	Content          string         `gorm:"type:text" json:"content"`
+	WorkoutID        *uuid.UUID     `gorm:"type:uuid" json:"workout_id,omitempty"`
+	WorkoutSessionID *uuid.UUID     `gorm:"type:uuid" json:"workout_session_id,omitempty"`
+	ReadAt           *time.Time     `json:"read_at,omitempty"`
+	CreatedAt        time.Time      `gorm:"index:idx_message_conversation_created" json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	Conversation   Conversation    `gorm:"foreignKey:ConversationID;constraint:OnDelete:CASCADE" json:"conversation,omitempty"`
+	Sender         User            `gorm:"foreignKey:SenderID;constraint:OnDelete:CASCADE" json:"sender,omitempty"`
+	Workout        *Workout        `gorm:"foreignKey:WorkoutID;constraint:OnDelete:SET NULL" json:"workout,omitempty"`
+	WorkoutSession *WorkoutSession `gorm:"foreignKey:WorkoutSessionID;constraint:OnDelete:SET NULL" json:"workout_session,omitempty"`
+}
+
+func (cv *Conversation) BeforeCreate(tx *gorm.DB) (err error) {
+	if cv.ID == uuid.Nil {
+		cv.ID = uuid.New()
+	}
+	return
+}
+
+func (m *Message) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return
+}
+
+// OtherUserID returns the ID of the participant of the conversation who is not the user
+func (cv *Conversation) OtherUserID(userID uuid.UUID) uuid.UUID {
+	if cv.UserOneID == userID {
+		return cv.UserTwoID
+	}
+	return cv.UserOneID
+}
+
+// OtherUser returns the participant of the conversation who is not the user
+func (cv *Conversation) OtherUser(userID uuid.UUID) User {
+	if cv.UserOneID == userID {
+		return cv.UserTwo
+	}
+	return cv.UserOne
+}
+
+// Request DTOs
+
+// StartConversationRequest opens the conversation with another user, or returns the existing one
+type StartConversationRequest struct {
+	UserID string `json:"user_id" binding:"required,uuid"`
+}
+
+// SendMessageRequest sends a message with text, an attachment, or both. At most one of the
+// workout and the workout session can be attached.
+type SendMessageRequest struct {
+	Content          string     `json:"content" binding:"omitempty,max=4000"`
+	WorkoutID        *uuid.UUID `json:"workout_id"`
+	WorkoutSessionID *uuid.UUID `json:"workout_session_id"`
+}
+
+// Response DTOs
+
+// MessageWorkoutAttachment describes a workout attached to a message
+type MessageWorkoutAttachment struct {
+	ID    uuid.UUID `json:"id"`
+	Title string    `json:"title"`
+}
+
+// MessageSessionAttachment describes a workout session attached to a message
+type MessageSessionAttachment struct {
+	ID           uuid.UUID  `json:"id"`
+	UserID       uuid.UUID  `json:"user_id"`
+	WorkoutTitle string     `json:"workout_title,omitempty"`
+	StartedAt    time.Time  `json:"started_at"`
+	EndedAt      *time.Time `json:"ended_at,omitempty"`
+	Completed    bool       `json:"completed"`
+}
+
+type MessageResponse struct {
+	ID             uuid.UUID                 `json:"id"`
+	ConversationID uuid.UUID                 `json:"conversation_id"`
+	SenderID       uuid.UUID                 `json:"sender_id"`
+	Content        string                    `json:"content"`
+	Workout        *MessageWorkoutAttachment `json:"workout,omitempty"`
+	WorkoutSession *MessageSessionAttachment `json:"workout_session,omitempty"`
+	ReadAt         *time.Time                `json:"read_at,omitempty"`
+	CreatedAt      time.Time                 `json:"created_at"`
+}
+
+type ConversationResponse struct {
+	ID            uuid.UUID          `json:"id"`
+	OtherUser     UserPublicResponse `json:"other_user"`
+	CanSend       bool               `json:"can_send"` // false once the trainer link or friendship ended
+	UnreadCount   int                `json:"unread_count"`
+	LastMessage   *MessageResponse   `json:"last_message,omitempty"`
+	LastMessageAt *time.Time         `json:"last_message_at,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+// MessagePollResponse lists the messages of a conversation sent or read since the poll's since
+// time. PolledAt is the since time for the next poll.
+type MessagePollResponse struct {
+	Messages []MessageResponse `json:"messages"`
+	PolledAt time.Time         `json:"polled_at"`
+}
+
+// ConversationUnreadCount is how many messages the user has not read in a conversation
+type ConversationUnreadCount struct {
+	ConversationID uuid.UUID `json:"conversation_id"`
+	UnreadCount    int       `json:"unread_count"`
+}
+
+// UnreadMessagesResponse is how many messages the user has not read, in total and per conversation
+type UnreadMessagesResponse struct {
+	Total         int                       `json:"total"`
+	Conversations []ConversationUnreadCount `json:"conversations"`
+}
+
+// ToResponse converts Message to response format. The attachment is included if it was preloaded
+// and still exists.
+func (m *Message) ToResponse() MessageResponse {
+	resp := MessageResponse{
+		ID:             m.ID,
+		ConversationID: m.ConversationID,
+		SenderID:       m.SenderID,
+		Content:        m.Content,
+		ReadAt:         m.ReadAt,
+		CreatedAt:      m.CreatedAt,
+	}
+
+	if m.Workout != nil {
+		resp.Workout = &MessageWorkoutAttachment{
+			ID:    m.Workout.ID,
+			Title: m.Workout.Title,
+		}
+	}
+
+	if m.WorkoutSession != nil {
+		resp.WorkoutSession = &MessageSessionAttachment{
+			ID:        m.WorkoutSession.ID,
+			UserID:    m.WorkoutSession.UserID,
+			StartedAt: m.WorkoutSession.StartedAt,
+			EndedAt:   m.WorkoutSession.EndedAt,
+			Completed: m.WorkoutSession.Completed,
+		}
+		if m.WorkoutSession.Workout != nil {
+			resp.WorkoutSession.WorkoutTitle = m.WorkoutSession.Workout.Title
+		}
+	}
+
+	return resp
+}
+
+// ToResponse converts Conversation to response format as seen by the user
+func (cv *Conversation) ToResponse(userID uuid.UUID, canSend bool, unreadCount int, lastMessage *Message) ConversationResponse {
+	other := cv.OtherUser(userID)
+	resp := ConversationResponse{
+		ID: cv.ID,
+		OtherUser: UserPublicResponse{
+			ID:        other.ID,
+			FirstName: other.FirstName,
+			LastName:  other.LastName,
+		},
+		CanSend:       canSend,
+		UnreadCount:   unreadCount,
+		LastMessageAt: cv.LastMessageAt,
+		CreatedAt:     cv.CreatedAt,
+	}
+
+	if lastMessage != nil {
+		msg := lastMessage.ToResponse()
+		resp.LastMessage = &msg
+	}
+
+	return resp
+}
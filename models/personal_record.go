@@ -0,0 +1,126 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PersonalRecordType identifies what kind of best performance a record tracks
+type PersonalRecordType string
+
+const (
+	RecordTypeMaxWeight    PersonalRecordType = "max_weight"    // Heaviest weight lifted in a single set
+	RecordTypeRepMax       PersonalRecordType = "rep_max"       // Most reps completed in a single set
+	RecordTypeEstimated1RM PersonalRecordType = "estimated_1rm" // Best estimated one-rep max (per formula)
+	RecordTypeLongestHold  PersonalRecordType = "longest_hold"  // Longest hold/duration in a single set
+)
+
+// ValidPersonalRecordTypes defines acceptable record type values
+var ValidPersonalRecordTypes = map[PersonalRecordType]bool{
+	RecordTypeMaxWeight:    true,
+	RecordTypeRepMax:       true,
+	RecordTypeEstimated1RM: true,
+	RecordTypeLongestHold:  true,
+}
+
+// PersonalRecord stores a user's best performance of a given type for an exercise.
+// There is at most one record per user, exercise, record type and formula
+// (formula is only set for estimated_1rm records).
+type PersonalRecord struct {
+	ID              uuid.UUID          `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID          uuid.UUID          `gorm:"type:uuid;not null;uniqueIndex:idx_personal_record_key" json:"user_id"`
+	ExerciseID      uuid.UUID          `gorm:"type:uuid;not null;uniqueIndex:idx_personal_record_key;index" json:"exercise_id"`
+	RecordType      PersonalRecordType `gorm:"type:varchar(20);not null;uniqueIndex:idx_personal_record_key" json:"record_type"`
+	Formula         string             `gorm:"type:varchar(20);not null;default:'';uniqueIndex:idx_personal_record_key" json:"formula,omitempty"`
+	Value           float64            `gorm:"type:decimal(8,2);not null" json:"-"` // Ranking value: kg, reps or seconds depending on type
+	WeightKg        *float64           `gorm:"type:decimal(6,2)" json:"-"`          // Weight of the set that set the record
+	Reps            *int               `json:"reps,omitempty"`
+	DurationSeconds *int               `json:"duration_seconds,omitempty"`
+	SessionID       *uuid.UUID         `gorm:"type:uuid;index" json:"session_id,omitempty"`
+	SessionSetID    *uuid.UUID         `gorm:"type:uuid" json:"session_set_id,omitempty"`
+	AchievedAt      time.Time          `gorm:"not null" json:"achieved_at"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+
+	// Relations
+	User     User            `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Exercise Exercise        `gorm:"foreignKey:ExerciseID;constraint:OnDelete:CASCADE" json:"exercise,omitempty"`
+	Session  *WorkoutSession `gorm:"foreignKey:SessionID;constraint:OnDelete:SET NULL" json:"-"`
+	Set      *SessionSet     `gorm:"foreignKey:SessionSetID;constraint:OnDelete:SET NULL" json:"-"`
+}
+
+func (pr *PersonalRecord) BeforeCreate(tx *gorm.DB) (err error) {
+	if pr.ID == uuid.Nil {
+		pr.ID = uuid.New()
+	}
+	return
+}
+
+// BeatsRecord reports whether this record is strictly better than an existing one of the same type.
+// Ties on the ranking value are broken by the secondary metric (reps for weight records,
+// weight for rep records) so that e.g. 100kg x 5 replaces 100kg x 3.
+func (pr *PersonalRecord) BeatsRecord(existing PersonalRecord) bool {
+	if pr.Value != existing.Value {
+		return pr.Value > existing.Value
+	}
+
+	switch pr.RecordType {
+	case RecordTypeMaxWeight:
+		return intValue(pr.Reps) > intValue(existing.Reps)
+	case RecordTypeRepMax:
+		return floatValue(pr.WeightKg) > floatValue(existing.WeightKg)
+	default:
+		return false
+	}
+}
+
+func intValue(v *int) int {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+func floatValue(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+// ===== RESPONSE DTOs =====
+
+// PersonalRecordResponse represents a personal record in the response
+type PersonalRecordResponse struct {
+	ID                 uuid.UUID          `json:"id"`
+	ExerciseID         uuid.UUID          `json:"exercise_id"`
+	ExerciseName       string             `json:"exercise_name,omitempty"`
+	RecordType         PersonalRecordType `json:"record_type"`
+	Formula            string             `json:"formula,omitempty"`
+	Weight             *WeightOutput      `json:"weight,omitempty"`
+	EstimatedOneRepMax *WeightOutput      `json:"estimated_1rm,omitempty"`
+	Reps               *int               `json:"reps,omitempty"`
+	DurationSeconds    *int               `json:"duration_seconds,omitempty"`
+	SessionID          *uuid.UUID         `json:"session_id,omitempty"`
+	SessionSetID       *uuid.UUID         `json:"session_set_id,omitempty"`
+	AchievedAt         time.Time          `json:"achieved_at"`
+}
+
+// ToResponse converts a PersonalRecord to a response DTO
+// Weight fields are populated by the caller in the user's preferred unit
+func (pr *PersonalRecord) ToResponse() PersonalRecordResponse {
+	return PersonalRecordResponse{
+		ID:              pr.ID,
+		ExerciseID:      pr.ExerciseID,
+		ExerciseName:    pr.Exercise.Name,
+		RecordType:      pr.RecordType,
+		Formula:         pr.Formula,
+		Reps:            pr.Reps,
+		DurationSeconds: pr.DurationSeconds,
+		SessionID:       pr.SessionID,
+		SessionSetID:    pr.SessionSetID,
+		AchievedAt:      pr.AchievedAt,
+	}
+}
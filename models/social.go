@@ -74,6 +74,52 @@ func (wcr *WorkoutCommentReaction) BeforeCreate(tx *gorm.DB) (err error) {
 	return
 }
 
+// Shared workout permissions. Each level includes the ones before it: copy lets the recipient
+// duplicate the workout into their own, edit also lets them change it.
+const (
+	SharePermissionView = "view"
+	SharePermissionCopy = "copy"
+	SharePermissionEdit = "edit"
+
+	// SharePermissionOwner is reported to the workout's owner, who can do everything
+	SharePermissionOwner = "owner"
+)
+
+var sharePermissionLevels = map[string]int{
+	SharePermissionView:  1,
+	SharePermissionCopy:  2,
+	SharePermissionEdit:  3,
+	SharePermissionOwner: 4,
+}
+
+// SharePermissionAllows reports whether a permission grants the required one
+func SharePermissionAllows(permission, required string) bool {
+	return sharePermissionLevels[permission] >= sharePermissionLevels[required]
+}
+
+// Request DTOs
+type ShareWorkoutRequest struct {
+	UserID     string `json:"user_id" binding:"required,uuid"`
+	Permission string `json:"permission" binding:"omitempty,oneof=view copy edit"`
+}
+
+type UpdateSharedWorkoutRequest struct {
+	Permission string `json:"permission" binding:"required,oneof=view copy edit"`
+}
+
+type CreateWorkoutCommentRequest struct {
+	Content  string `json:"content" binding:"required,min=1,max=2000"`
+	ParentID string `json:"parent_id" binding:"omitempty,uuid"`
+}
+
+type UpdateWorkoutCommentRequest struct {
+	Content string `json:"content" binding:"required,min=1,max=2000"`
+}
+
+type ReactToCommentRequest struct {
+	Reaction string `json:"reaction" binding:"required,oneof=like love laugh wow sad angry"`
+}
+
 // Response DTOs
 type SharedWorkoutResponse struct {
 	ID            uuid.UUID    `json:"id"`
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
@@ -25,33 +26,71 @@ type TrainerProfile struct {
 	Specialties []Specialty `gorm:"many2many:trainer_specialties;" json:"specialties,omitempty"`
 }
 
+// TrainerReview is a client's review of a trainer. TrainerID is the trainer's user ID, not their
+// trainer profile ID. A client reviews each trainer once; hidden reviews were moderated by an admin
+// and are left out of the trainer's rating.
 type TrainerReview struct {
-	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	TrainerID  uuid.UUID      `gorm:"type:uuid;not null" json:"trainer_id"`
-	ReviewerID uuid.UUID      `gorm:"type:uuid;not null" json:"reviewer_id"`
-	Rating     int            `gorm:"not null;check:rating >= 1 AND rating <= 5" json:"rating"`
-	Comment    string         `gorm:"type:text" json:"comment"`
-	CreatedAt  time.Time      `json:"created_at"`
-	UpdatedAt  time.Time      `json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	ID           uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TrainerID    uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:unique_trainer_review,where:deleted_at IS NULL" json:"trainer_id"`
+	ReviewerID   uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:unique_trainer_review,where:deleted_at IS NULL" json:"reviewer_id"`
+	Rating       int            `gorm:"not null;check:rating >= 1 AND rating <= 5" json:"rating"`
+	Comment      string         `gorm:"type:text" json:"comment"`
+	Reply        string         `gorm:"type:text" json:"reply"`
+	RepliedAt    *time.Time     `json:"replied_at,omitempty"`
+	HiddenAt     *time.Time     `json:"hidden_at,omitempty"`
+	HiddenReason string         `gorm:"type:text" json:"hidden_reason,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 
 	Trainer  User `gorm:"foreignKey:TrainerID;constraint:OnDelete:CASCADE" json:"trainer,omitempty"`
 	Reviewer User `gorm:"foreignKey:ReviewerID;constraint:OnDelete:CASCADE" json:"reviewer,omitempty"`
 }
 
+// TrainerClientLink links a trainer to a client. While the link is active, ConsentScopes lists
+// what the client lets the trainer see or do with their data. New links get the scopes trainers
+// had before clients could choose: viewing and logging sessions.
 type TrainerClientLink struct {
-	ID        uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	TrainerID uuid.UUID      `gorm:"type:uuid;not null" json:"trainer_id"`
-	ClientID  uuid.UUID      `gorm:"type:uuid;not null" json:"client_id"`
-	Status    string         `gorm:"type:varchar(20);default:'pending'" json:"status"` // pending, active, inactive
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	ID            uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TrainerID     uuid.UUID      `gorm:"type:uuid;not null" json:"trainer_id"`
+	ClientID      uuid.UUID      `gorm:"type:uuid;not null" json:"client_id"`
+	Status        string         `gorm:"type:varchar(20);default:'pending'" json:"status"` // pending, active, inactive
+	ActivatedAt   *time.Time     `json:"activated_at,omitempty"`                           // set when the client accepts, kept once the link ends
+	ConsentScopes pq.StringArray `gorm:"type:text[];default:ARRAY['sessions','log_sessions']::text[]" json:"consent_scopes"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 
 	Trainer User `gorm:"foreignKey:TrainerID;constraint:OnDelete:CASCADE" json:"trainer,omitempty"`
 	Client  User `gorm:"foreignKey:ClientID;constraint:OnDelete:CASCADE" json:"client,omitempty"`
 }
 
+// Consent scopes a client can grant a trainer
+const (
+	ConsentScopeSessions    = "sessions"     // view sessions, personal records and training analytics
+	ConsentScopeBodyWeight  = "body_weight"  // view weight logs and the weights in the fitness profile
+	ConsentScopeHealth      = "health"       // view health conditions and injuries notes
+	ConsentScopeLogSessions = "log_sessions" // log and edit sessions on the client's behalf
+)
+
+// HasConsent reports whether the client granted the trainer the scope
+func (tcl *TrainerClientLink) HasConsent(scope string) bool {
+	for _, granted := range tcl.ConsentScopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// GrantedScopes returns the consent scopes the client granted the trainer, never nil
+func (tcl *TrainerClientLink) GrantedScopes() []string {
+	if tcl.ConsentScopes == nil {
+		return []string{}
+	}
+	return tcl.ConsentScopes
+}
+
 func (tp *TrainerProfile) BeforeCreate(tx *gorm.DB) (err error) {
 	if tp.ID == uuid.Nil {
 		tp.ID = uuid.New()
@@ -128,6 +167,74 @@ type UserPublicResponse struct {
 	LastName  string    `json:"last_name"`
 }
 
+// TrainerReview Request DTOs
+type CreateTrainerReviewRequest struct {
+	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
+	Comment string `json:"comment" binding:"omitempty,max=2000"`
+}
+
+type UpdateTrainerReviewRequest struct {
+	Rating  *int    `json:"rating" binding:"omitempty,min=1,max=5"`
+	Comment *string `json:"comment" binding:"omitempty,max=2000"`
+}
+
+type ReplyToTrainerReviewRequest struct {
+	Reply string `json:"reply" binding:"required,min=1,max=2000"`
+}
+
+type ModerateTrainerReviewRequest struct {
+	Hidden *bool  `json:"hidden" binding:"required"`
+	Reason string `json:"reason" binding:"omitempty,max=500"`
+}
+
+// TrainerReview Response DTOs
+type TrainerReviewResponse struct {
+	ID           uuid.UUID           `json:"id"`
+	TrainerID    uuid.UUID           `json:"trainer_id"`
+	Reviewer     *UserPublicResponse `json:"reviewer,omitempty"`
+	Rating       int                 `json:"rating"`
+	Comment      string              `json:"comment"`
+	Reply        string              `json:"reply,omitempty"`
+	RepliedAt    *time.Time          `json:"replied_at,omitempty"`
+	Hidden       bool                `json:"hidden"`
+	HiddenReason string              `json:"hidden_reason,omitempty"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+}
+
+// ToResponse converts TrainerReview to response format
+func (tr *TrainerReview) ToResponse() TrainerReviewResponse {
+	resp := TrainerReviewResponse{
+		ID:           tr.ID,
+		TrainerID:    tr.TrainerID,
+		Rating:       tr.Rating,
+		Comment:      tr.Comment,
+		Reply:        tr.Reply,
+		RepliedAt:    tr.RepliedAt,
+		Hidden:       tr.HiddenAt != nil,
+		HiddenReason: tr.HiddenReason,
+		CreatedAt:    tr.CreatedAt,
+		UpdatedAt:    tr.UpdatedAt,
+	}
+
+	if tr.Reviewer.ID != uuid.Nil {
+		resp.Reviewer = &UserPublicResponse{
+			ID:        tr.Reviewer.ID,
+			FirstName: tr.Reviewer.FirstName,
+			LastName:  tr.Reviewer.LastName,
+		}
+	}
+
+	return resp
+}
+
+// TrainerReviewStats is a trainer's review count and average rating
+type TrainerReviewStats struct {
+	TrainerID     uuid.UUID
+	ReviewCount   int
+	AverageRating float64
+}
+
 // TrainerClientLink Request DTOs
 type InviteClientRequest struct {
 	ClientID uuid.UUID `json:"client_id" binding:"required"`
@@ -137,27 +244,34 @@ type RespondToInvitationRequest struct {
 	Action string `json:"action" binding:"required,oneof=accept reject"`
 }
 
+// UpdateTrainerConsentRequest replaces the consent scopes a client grants a trainer
+type UpdateTrainerConsentRequest struct {
+	Scopes []string `json:"scopes" binding:"required,dive,oneof=sessions body_weight health log_sessions"`
+}
+
 // TrainerClientLink Response DTOs
 type TrainerClientLinkResponse struct {
-	ID        uuid.UUID           `json:"id"`
-	TrainerID uuid.UUID           `json:"trainer_id"`
-	ClientID  uuid.UUID           `json:"client_id"`
-	Status    string              `json:"status"`
-	Trainer   *UserPublicResponse `json:"trainer,omitempty"`
-	Client    *UserPublicResponse `json:"client,omitempty"`
-	CreatedAt time.Time           `json:"created_at"`
-	UpdatedAt time.Time           `json:"updated_at"`
+	ID            uuid.UUID           `json:"id"`
+	TrainerID     uuid.UUID           `json:"trainer_id"`
+	ClientID      uuid.UUID           `json:"client_id"`
+	Status        string              `json:"status"`
+	ConsentScopes []string            `json:"consent_scopes"`
+	Trainer       *UserPublicResponse `json:"trainer,omitempty"`
+	Client        *UserPublicResponse `json:"client,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
 }
 
 // ToResponse converts TrainerClientLink to response format
 func (tcl *TrainerClientLink) ToResponse() TrainerClientLinkResponse {
 	resp := TrainerClientLinkResponse{
-		ID:        tcl.ID,
-		TrainerID: tcl.TrainerID,
-		ClientID:  tcl.ClientID,
-		Status:    tcl.Status,
-		CreatedAt: tcl.CreatedAt,
-		UpdatedAt: tcl.UpdatedAt,
+		ID:            tcl.ID,
+		TrainerID:     tcl.TrainerID,
+		ClientID:      tcl.ClientID,
+		Status:        tcl.Status,
+		ConsentScopes: tcl.GrantedScopes(),
+		CreatedAt:     tcl.CreatedAt,
+		UpdatedAt:     tcl.UpdatedAt,
 	}
 
 	if tcl.Trainer.ID != uuid.Nil {
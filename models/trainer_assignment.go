@@ -0,0 +1,139 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TrainerAssignment is a workout or workout plan a trainer assigned to one of their clients.
+// Assigning a workout shares it with the client, to be done by DueDate. Assigning a plan enrols
+// the client in it, and DueDate, if set, is when the plan should be finished.
+type TrainerAssignment struct {
+	ID              uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TrainerID       uuid.UUID      `gorm:"type:uuid;not null;index" json:"trainer_id"`
+	ClientID        uuid.UUID      `gorm:"type:uuid;not null;index" json:"client_id"`
+	Type            string         `gorm:"type:varchar(20);not null" json:"type"` // workout, plan
+	WorkoutID       *uuid.UUID     `gorm:"type:uuid" json:"workout_id,omitempty"`
+	PlanID          *uuid.UUID     `gorm:"type:uuid" json:"plan_id,omitempty"`
+	EnrollmentID    *uuid.UUID     `gorm:"type:uuid" json:"enrollment_id,omitempty"` // only for plan assignments
+	SharedWorkoutID *uuid.UUID     `gorm:"type:uuid;index" json:"-"`                 // share created for workout assignments, removed with the last of them
+	DueDate         *time.Time     `gorm:"type:date" json:"due_date,omitempty"`
+	Notes           string         `gorm:"type:text" json:"notes"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	Trainer    User            `gorm:"foreignKey:TrainerID;constraint:OnDelete:CASCADE" json:"trainer,omitempty"`
+	Client     User            `gorm:"foreignKey:ClientID;constraint:OnDelete:CASCADE" json:"client,omitempty"`
+	Workout    *Workout        `gorm:"foreignKey:WorkoutID;constraint:OnDelete:SET NULL" json:"workout,omitempty"`
+	Plan       *WorkoutPlan    `gorm:"foreignKey:PlanID;constraint:OnDelete:SET NULL" json:"plan,omitempty"`
+	Enrollment *PlanEnrollment `gorm:"foreignKey:EnrollmentID;constraint:OnDelete:SET NULL" json:"enrollment,omitempty"`
+}
+
+func (ta *TrainerAssignment) BeforeCreate(tx *gorm.DB) (err error) {
+	if ta.ID == uuid.Nil {
+		ta.ID = uuid.New()
+	}
+	return
+}
+
+// Assignment types
+const (
+	AssignmentTypeWorkout = "workout"
+	AssignmentTypePlan    = "plan"
+)
+
+// Assignment statuses, derived from the client's sessions when assignments are read
+const (
+	AssignmentStatusPending    = "pending"
+	AssignmentStatusInProgress = "in_progress"
+	AssignmentStatusCompleted  = "completed"
+	AssignmentStatusOverdue    = "overdue"
+	AssignmentStatusCancelled  = "cancelled"
+	AssignmentStatusHidden     = "hidden" // the client doesn't share their sessions with the trainer
+)
+
+// AssignmentProgress is what a client has done toward an assignment
+type AssignmentProgress struct {
+	CompletedSessions int        // completed sessions of the workout, or of the plan enrollment
+	CompletedAt       *time.Time // when the workout was first done, or the plan finished
+	Cancelled         bool       // the plan enrollment was cancelled
+}
+
+// Request DTOs
+
+// CreateTrainerAssignmentRequest assigns either a workout or a plan. The schedule fields are only
+// used for plans, like when enrolling in a plan.
+type CreateTrainerAssignmentRequest struct {
+	WorkoutID         *uuid.UUID `json:"workout_id"`
+	PlanID            *uuid.UUID `json:"plan_id"`
+	DueDate           string     `json:"due_date"`   // YYYY-MM-DD, required for workouts
+	StartDate         string     `json:"start_date"` // YYYY-MM-DD, defaults to today
+	DaysPerWeek       int        `json:"days_per_week" binding:"omitempty,min=1,max=7"`
+	ScheduleMode      string     `json:"schedule_mode" binding:"omitempty,oneof=rolling calendar"`
+	PreferredWeekdays []int32    `json:"preferred_weekdays" binding:"omitempty,dive,min=0,max=6"`
+	Notes             string     `json:"notes" binding:"omitempty,max=2000"`
+}
+
+// Response DTOs
+
+type TrainerAssignmentResponse struct {
+	ID                uuid.UUID           `json:"id"`
+	Type              string              `json:"type"`
+	Title             string              `json:"title"`
+	WorkoutID         *uuid.UUID          `json:"workout_id,omitempty"`
+	PlanID            *uuid.UUID          `json:"plan_id,omitempty"`
+	EnrollmentID      *uuid.UUID          `json:"enrollment_id,omitempty"`
+	DueDate           *time.Time          `json:"due_date,omitempty"`
+	Notes             string              `json:"notes"`
+	Status            string              `json:"status"`
+	CompletedSessions int                 `json:"completed_sessions"`
+	CompletedAt       *time.Time          `json:"completed_at,omitempty"`
+	Trainer           *UserPublicResponse `json:"trainer,omitempty"`
+	Client            *UserPublicResponse `json:"client,omitempty"`
+	CreatedAt         time.Time           `json:"created_at"`
+}
+
+// ToResponse converts TrainerAssignment to response format with the client's progress and the
+// status derived from it
+func (ta *TrainerAssignment) ToResponse(progress AssignmentProgress, status string) TrainerAssignmentResponse {
+	resp := TrainerAssignmentResponse{
+		ID:                ta.ID,
+		Type:              ta.Type,
+		WorkoutID:         ta.WorkoutID,
+		PlanID:            ta.PlanID,
+		EnrollmentID:      ta.EnrollmentID,
+		DueDate:           ta.DueDate,
+		Notes:             ta.Notes,
+		Status:            status,
+		CompletedSessions: progress.CompletedSessions,
+		CompletedAt:       progress.CompletedAt,
+		CreatedAt:         ta.CreatedAt,
+	}
+
+	if ta.Workout != nil {
+		resp.Title = ta.Workout.Title
+	} else if ta.Plan != nil {
+		resp.Title = ta.Plan.Title
+	}
+
+	if ta.Trainer.ID != uuid.Nil {
+		resp.Trainer = &UserPublicResponse{
+			ID:        ta.Trainer.ID,
+			FirstName: ta.Trainer.FirstName,
+			LastName:  ta.Trainer.LastName,
+		}
+	}
+
+	if ta.Client.ID != uuid.Nil {
+		resp.Client = &UserPublicResponse{
+			ID:        ta.Client.ID,
+			FirstName: ta.Client.FirstName,
+			LastName:  ta.Client.LastName,
+		}
+	}
+
+	return resp
+}
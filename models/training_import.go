@@ -0,0 +1,65 @@
+package models
+
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ===== REQUEST DTOs =====
+
+// ImportTrainingHistoryRequest represents a Strong or Hevy CSV export upload (multipart form)
+type ImportTrainingHistoryRequest struct {
+	File         *multipart.FileHeader `form:"file" binding:"required"`
+	WeightUnit   string                `form:"weight_unit" binding:"omitempty,oneof=kg lb lbs"` // Unit of the export when its columns don't say; defaults to the user's preference
+	DistanceUnit string                `form:"distance_unit" binding:"omitempty,oneof=km mi"`   // Unit of the export when its columns don't say; defaults to the user's preference
+	Timezone     string                `form:"timezone"`                                        // IANA zone the workouts were logged in; defaults to UTC
+	Mappings     string                `form:"mappings"`                                        // JSON object of exported exercise name to exercise ID, or "" to skip the exercise
+}
+
+// ===== RESPONSE DTOs =====
+
+// Exercise mapping statuses of a training history import
+const (
+	ExerciseMappingMatched   = "matched"   // Matched automatically
+	ExerciseMappingConfirmed = "confirmed" // Chosen by the user
+	ExerciseMappingSkipped   = "skipped"   // Left out by the user
+	ExerciseMappingUnmatched = "unmatched" // Needs the user to choose
+)
+
+// ExerciseMatchResponse represents a catalogue exercise suggested for an exported exercise name
+type ExerciseMatchResponse struct {
+	ExerciseID uuid.UUID `json:"exercise_id"`
+	Name       string    `json:"name"`
+	Score      float64   `json:"score"` // Name similarity, 0..1
+}
+
+// ExerciseMappingResponse represents how an exported exercise name maps to the catalogue
+type ExerciseMappingResponse struct {
+	Name        string                  `json:"name"`
+	SetCount    int                     `json:"set_count"`
+	Status      string                  `json:"status"`
+	Exercise    *ExerciseMatchResponse  `json:"exercise"` // Exercise the sets are imported as, null if skipped or unmatched
+	Suggestions []ExerciseMatchResponse `json:"suggestions"`
+}
+
+// TrainingImportPreviewResponse represents what importing a training history export would do
+type TrainingImportPreviewResponse struct {
+	Format         string                    `json:"format"` // strong or hevy
+	WorkoutCount   int                       `json:"workout_count"`
+	SetCount       int                       `json:"set_count"`
+	DuplicateCount int                       `json:"duplicate_count"` // Workouts already imported, which will be skipped
+	FirstWorkoutAt time.Time                 `json:"first_workout_at"`
+	LastWorkoutAt  time.Time                 `json:"last_workout_at"`
+	Ready          bool                      `json:"ready"` // Every exercise is matched, confirmed or skipped
+	Exercises      []ExerciseMappingResponse `json:"exercises"`
+}
+
+// TrainingImportResultResponse represents the outcome of a training history import
+type TrainingImportResultResponse struct {
+	ImportedCount  int         `json:"imported_count"`
+	DuplicateCount int         `json:"duplicate_count"` // Workouts skipped as already imported
+	SkippedSets    int         `json:"skipped_sets"`    // Sets of skipped exercises
+	SessionIDs     []uuid.UUID `json:"session_ids"`
+}
@@ -26,6 +26,11 @@ type User struct {
 	IsLookingForTrainer bool   `gorm:"default:false" json:"is_looking_for_trainer"`
 	Bio                 string `gorm:"type:text" json:"bio,omitempty"`
 	Location            `gorm:"embedded;embeddedPrefix:location_"`
+	// Account deletion: the account is locked from DeletionRequestedAt and its personal data
+	// purged once DeletionScheduledAt has passed. Logging in before then cancels the deletion.
+	DeletionRequestedAt *time.Time     `json:"deletion_requested_at,omitempty"`
+	DeletionScheduledAt *time.Time     `gorm:"index" json:"deletion_scheduled_at,omitempty"`
+	PurgedAt            *time.Time     `json:"purged_at,omitempty"` // Personal data purged; the row is kept anonymised for comments and reviews
 	CreatedAt           time.Time      `json:"created_at"`
 	UpdatedAt           time.Time      `json:"updated_at"`
 	DeletedAt           gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
@@ -41,6 +46,11 @@ func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
 	return
 }
 
+// IsPendingDeletion reports whether the user asked for their account to be deleted
+func (u *User) IsPendingDeletion() bool {
+	return u.DeletionScheduledAt != nil
+}
+
 type UserResponse struct {
 	ID                    uuid.UUID         `json:"id"`
 	Email                 string            `json:"email"`
@@ -57,6 +67,7 @@ type UserResponse struct {
 	IsLookingForTrainer   bool              `json:"is_looking_for_trainer"`
 	Bio                   string            `json:"bio,omitempty"`
 	Location              *LocationResponse `json:"location,omitempty"`
+	DeletionScheduledAt   *time.Time        `json:"deletion_scheduled_at,omitempty"`
 	CreatedAt             time.Time         `json:"created_at"`
 	UpdatedAt             time.Time         `json:"updated_at"`
 }
@@ -85,6 +96,16 @@ type UpdateUserSettingsRequest struct {
 	Location              *LocationUpdateRequest `json:"location,omitempty"`
 }
 
+// DeleteAccountRequest is used to request the deletion of the authenticated user's account
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"omitempty,max=128"` // Required for accounts with a password (local provider)
+}
+
+// AccountDeletionResponse tells when a deleted account's data will be purged
+type AccountDeletionResponse struct {
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"` // Logging in before then cancels the deletion
+}
+
 func (u *User) ToResponse() UserResponse {
 	response := UserResponse{
 		ID:                    u.ID,
@@ -101,6 +122,7 @@ func (u *User) ToResponse() UserResponse {
 		IsLookingForTrainer:   u.IsLookingForTrainer,
 		Bio:                   u.Bio,
 		Location:              u.Location.ToResponse(),
+		DeletionScheduledAt:   u.DeletionScheduledAt,
 		CreatedAt:             u.CreatedAt,
 		UpdatedAt:             u.UpdatedAt,
 	}
@@ -65,7 +65,8 @@ type PlanEnrollment struct {
 	StartDate   time.Time `gorm:"not null" json:"start_date"`
 	DaysPerWeek int       `gorm:"not null;check:days_per_week_check,days_per_week >= 1 AND days_per_week <= 7" json:"days_per_week"`
 
-	CurrentIndex int `gorm:"not null;default:0" json:"current_index"` // index in rolling mode
+	CurrentIndex    int        `gorm:"not null;default:0" json:"current_index"` // index of the next scheduled workout
+	LastCompletedAt *time.Time `json:"last_completed_at,omitempty"`             // when the last scheduled workout was completed
 
 	ScheduleMode      string        `gorm:"type:varchar(20);default:'rolling'" json:"schedule_mode"` // rolling | calendar
 	PreferredWeekdays pq.Int32Array `gorm:"type:int[];default:'{}'" json:"preferred_weekdays"`       // only used in calendar mode (0=Mon..6=Sun)
@@ -199,3 +200,61 @@ func (w *Workout) ToResponse(isFavorited bool) WorkoutResponse {
 		UpdatedAt:         w.UpdatedAt,
 	}
 }
+
+// ScheduledWorkoutResponse represents one dated workout in an enrollment schedule
+type ScheduledWorkoutResponse struct {
+	Index        int        `json:"index"`      // position in the enrollment's workout sequence
+	Date         string     `json:"date"`       // YYYY-MM-DD
+	WeekIndex    int        `json:"week_index"` // template week the workout belongs to
+	WorkoutID    uuid.UUID  `json:"workout_id"`
+	WorkoutTitle string     `json:"workout_title"`
+	Status       string     `json:"status"`               // completed, missed, today, upcoming
+	SessionID    *uuid.UUID `json:"session_id,omitempty"` // session that fulfilled the workout
+}
+
+// EnrollmentScheduleResponse represents the dated workouts of an enrollment within a range
+type EnrollmentScheduleResponse struct {
+	EnrollmentID  uuid.UUID                  `json:"enrollment_id"`
+	PlanID        uuid.UUID                  `json:"plan_id"`
+	PlanTitle     string                     `json:"plan_title"`
+	ScheduleMode  string                     `json:"schedule_mode"`
+	CurrentIndex  int                        `json:"current_index"`
+	TotalWorkouts int                        `json:"total_workouts"`
+	From          string                     `json:"from"`
+	To            string                     `json:"to"`
+	Workouts      []ScheduledWorkoutResponse `json:"workouts"`
+}
+
+// TodayWorkoutResponse represents a workout scheduled for today by one of the user's enrollments
+type TodayWorkoutResponse struct {
+	EnrollmentID uuid.UUID `json:"enrollment_id"`
+	PlanID       uuid.UUID `json:"plan_id"`
+	PlanTitle    string    `json:"plan_title"`
+	ScheduledWorkoutResponse
+}
+
+// TodayResponse represents the workouts scheduled for a single day across active enrollments
+type TodayResponse struct {
+	Date     string                 `json:"date"`
+	Workouts []TodayWorkoutResponse `json:"workouts"`
+}
+
+// EnrollmentAdherenceResponse reports how closely an enrollment has followed its schedule
+// Workouts lists every workout due so far: completed, missed, or due today
+type EnrollmentAdherenceResponse struct {
+	EnrollmentID      uuid.UUID                  `json:"enrollment_id"`
+	PlanID            uuid.UUID                  `json:"plan_id"`
+	PlanTitle         string                     `json:"plan_title"`
+	ScheduleMode      string                     `json:"schedule_mode"`
+	Status            string                     `json:"status"`
+	TotalWorkouts     int                        `json:"total_workouts"`
+	ScheduledWorkouts int                        `json:"scheduled_workouts"` // due on or before today
+	CompletedWorkouts int                        `json:"completed_workouts"`
+	MissedWorkouts    int                        `json:"missed_workouts"`
+	RemainingWorkouts int                        `json:"remaining_workouts"`
+	CompletionPercent float64                    `json:"completion_percent"` // completed of all workouts in the plan
+	AdherencePercent  float64                    `json:"adherence_percent"`  // completed of workouts due so far
+	CurrentStreak     int                        `json:"current_streak"`
+	LongestStreak     int                        `json:"longest_streak"`
+	Workouts          []ScheduledWorkoutResponse `json:"workouts"`
+}
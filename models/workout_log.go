@@ -15,6 +15,8 @@ type WorkoutSession struct {
 	UserID             uuid.UUID      `gorm:"type:uuid;not null" json:"user_id"`
 	CreatedByID        *uuid.UUID     `gorm:"type:uuid" json:"created_by_id,omitempty"` // Who logged the session (trainer or self)
 	WorkoutID          *uuid.UUID     `gorm:"type:uuid" json:"workout_id"`              // nullable for free-form workouts
+	EnrollmentID       *uuid.UUID     `gorm:"type:uuid;index" json:"enrollment_id"`     // plan enrollment the session fulfils, if any
+	ScheduledIndex     *int           `json:"scheduled_index"`                          // index of the scheduled workout within the enrollment
 	StartedAt          time.Time      `gorm:"not null" json:"started_at"`
 	EndedAt            *time.Time     `json:"ended_at"`
 	DurationSeconds    *int           `json:"duration_seconds,omitempty"`
@@ -26,10 +28,12 @@ type WorkoutSession struct {
 	DeletedAt          gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 
 	// Relations
-	User          User           `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
-	CreatedBy     *User          `gorm:"foreignKey:CreatedByID;constraint:OnDelete:SET NULL" json:"created_by,omitempty"`
-	Workout       *Workout       `gorm:"foreignKey:WorkoutID;constraint:OnDelete:SET NULL" json:"workout,omitempty"`
-	SessionBlocks []SessionBlock `gorm:"foreignKey:SessionID" json:"session_blocks,omitempty"`
+	User          User             `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+	CreatedBy     *User            `gorm:"foreignKey:CreatedByID;constraint:OnDelete:SET NULL" json:"created_by,omitempty"`
+	Workout       *Workout         `gorm:"foreignKey:WorkoutID;constraint:OnDelete:SET NULL" json:"workout,omitempty"`
+	Enrollment    *PlanEnrollment  `gorm:"foreignKey:EnrollmentID;constraint:OnDelete:SET NULL" json:"enrollment,omitempty"`
+	SessionBlocks []SessionBlock   `gorm:"foreignKey:SessionID" json:"session_blocks,omitempty"`
+	Activity      *SessionActivity `gorm:"foreignKey:SessionID" json:"activity,omitempty"` // Only for sessions imported from an activity file
 }
 
 func (ws *WorkoutSession) BeforeCreate(tx *gorm.DB) (err error) {
@@ -102,21 +106,39 @@ func (se *SessionExercise) BeforeCreate(tx *gorm.DB) (err error) {
 
 // SessionSet represents an actual performed set within a session exercise
 type SessionSet struct {
-	ID                        uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	SessionExerciseID         uuid.UUID      `gorm:"type:uuid;not null;index" json:"session_exercise_id"`
-	SetNumber                 int            `gorm:"not null" json:"set_number"`
-	Completed                 bool           `gorm:"default:false" json:"completed"`
-	ActualReps                *int           `json:"actual_reps,omitempty"`
-	ActualWeightKg            *float64       `gorm:"type:decimal(6,2)" json:"-"`
-	OriginalActualWeightValue *float64       `gorm:"type:decimal(6,2)" json:"-"`
-	OriginalActualWeightUnit  *string        `gorm:"type:varchar(2)" json:"-"`
-	ActualDurationSeconds     *int           `json:"actual_duration_seconds,omitempty"`
-	RPEValueID                *uuid.UUID     `gorm:"type:uuid" json:"rpe_value_id,omitempty"`
-	WasFailure                bool           `gorm:"default:false" json:"was_failure"`
-	Notes                     string         `gorm:"type:text" json:"notes"`
-	CreatedAt                 time.Time      `json:"created_at"`
-	UpdatedAt                 time.Time      `json:"updated_at"`
-	DeletedAt                 gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	ID                        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SessionExerciseID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"session_exercise_id"`
+	SetNumber                 int        `gorm:"not null" json:"set_number"`
+	Round                     int        `gorm:"not null;default:1" json:"round"`          // Round (grouped types) or working set (drop sets) the set belongs to
+	SequenceOrder             int        `gorm:"not null;default:0" json:"sequence_order"` // Order in which the set is performed within its block
+	SubSetNumber              int        `gorm:"not null;default:0" json:"sub_set_number"` // 0 for the main set, 1.. for drops and rest-pause mini-sets
+	IntervalStartSeconds      *int       `json:"interval_start_seconds,omitempty"`         // Time-boxed types: offset from the start of the block
+	IntervalSeconds           *int       `json:"interval_seconds,omitempty"`               // Time-boxed types: length of the set's time box
+	Completed                 bool       `gorm:"default:false" json:"completed"`
+	StartedAt                 *time.Time `json:"started_at,omitempty"`
+	CompletedAt               *time.Time `json:"completed_at,omitempty"`
+	RestSeconds               *int       `json:"rest_seconds,omitempty"`            // Rest actually taken since the previous completed set of the block
+	PrescribedRestSeconds     *int       `json:"prescribed_rest_seconds,omitempty"` // Rest the prescription called for before this set
+	ActualReps                *int       `json:"actual_reps,omitempty"`
+	ActualWeightKg            *float64   `gorm:"type:decimal(6,2)" json:"-"`
+	OriginalActualWeightValue *float64   `gorm:"type:decimal(6,2)" json:"-"`
+	OriginalActualWeightUnit  *string    `gorm:"type:varchar(2)" json:"-"`
+	ActualDurationSeconds     *int       `json:"actual_duration_seconds,omitempty"`
+	RPEValueID                *uuid.UUID `gorm:"type:uuid" json:"rpe_value_id,omitempty"`
+	WasFailure                bool       `gorm:"default:false" json:"was_failure"`
+
+	// Cardio actuals: distance is stored in metres with the original value/unit
+	ActualDistanceMeters        *float64 `gorm:"type:decimal(9,2)" json:"-"`
+	OriginalActualDistanceValue *float64 `gorm:"type:decimal(9,3)" json:"-"`
+	OriginalActualDistanceUnit  *string  `gorm:"type:varchar(2)" json:"-"`
+	ActualAverageHeartRate      *int     `json:"actual_average_heart_rate,omitempty"` // bpm
+	ActualMaxHeartRate          *int     `json:"actual_max_heart_rate,omitempty"`     // bpm
+	ActualInclinePercent        *float64 `gorm:"type:decimal(4,1)" json:"actual_incline_percent,omitempty"`
+
+	Notes     string         `gorm:"type:text" json:"notes"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 
 	// Relations
 	SessionExercise SessionExercise `gorm:"foreignKey:SessionExerciseID;constraint:OnDelete:CASCADE" json:"session_exercise,omitempty"`
@@ -138,6 +160,11 @@ type CreateWorkoutSessionRequest struct {
 	WorkoutID *uuid.UUID `json:"workout_id,omitempty"` // Optional: for free-form workouts
 	StartedAt *time.Time `json:"started_at,omitempty"` // Optional: defaults to now
 	Notes     *string    `json:"notes,omitempty"`
+
+	// Optional: the plan enrollment and scheduled workout this session fulfils.
+	// When the index is omitted it is matched from the enrollment's schedule.
+	EnrollmentID   *uuid.UUID `json:"enrollment_id,omitempty"`
+	ScheduledIndex *int       `json:"scheduled_index,omitempty" binding:"omitempty,min=0"`
 }
 
 // EndWorkoutSessionRequest represents the request to end a workout session
@@ -169,6 +196,46 @@ type UpdateSessionExerciseRequest struct {
 	Notes string `json:"notes,omitempty"`
 }
 
+// SaveSessionAsWorkoutRequest represents the request to save a session as a new workout
+type SaveSessionAsWorkoutRequest struct {
+	Title           string `json:"title" binding:"omitempty,min=1,max=200"` // Optional: defaults to the session's workout title or date
+	Description     string `json:"description" binding:"omitempty,max=1000"`
+	DifficultyLevel string `json:"difficulty_level" binding:"omitempty,oneof=beginner intermediate advanced"`
+	IsTemplate      bool   `json:"is_template"`
+	Visibility      string `json:"visibility" binding:"omitempty,oneof=public private friends"`
+}
+
+// CreateSessionBlockRequest represents the request to add a block to a session
+type CreateSessionBlockRequest struct {
+	BlockOrder *int                        `json:"block_order,omitempty" binding:"omitempty,min=1"` // Optional: defaults to after the last block
+	Exercises  []AddSessionExerciseRequest `json:"exercises,omitempty" binding:"omitempty,dive"`
+}
+
+// ReorderSessionBlocksRequest represents the request to reorder a session's blocks.
+// Every block of the session is listed in its new order.
+type ReorderSessionBlocksRequest struct {
+	BlockIDs []uuid.UUID `json:"block_ids" binding:"required,min=1"`
+}
+
+// AddSessionExerciseRequest represents the request to add an exercise to a session block
+type AddSessionExerciseRequest struct {
+	ExerciseID    uuid.UUID `json:"exercise_id" binding:"required"`
+	ExerciseOrder *int      `json:"exercise_order,omitempty" binding:"omitempty,min=1"` // Optional: defaults to after the last exercise
+	Sets          *int      `json:"sets,omitempty" binding:"omitempty,min=1,max=20"`    // Optional: number of empty sets to create
+	Notes         *string   `json:"notes,omitempty"`
+}
+
+// ReorderSessionExercisesRequest represents the request to reorder a block's exercises.
+// Every exercise of the block is listed in its new order.
+type ReorderSessionExercisesRequest struct {
+	SessionExerciseIDs []uuid.UUID `json:"session_exercise_ids" binding:"required,min=1"`
+}
+
+// SwapSessionExerciseRequest represents the request to substitute another exercise mid-session
+type SwapSessionExerciseRequest struct {
+	ExerciseID uuid.UUID `json:"exercise_id" binding:"required"`
+}
+
 // CreateSessionSetRequest represents the request to add a set to an exercise
 type CreateSessionSetRequest struct {
 	ActualReps            *int         `json:"actual_reps,omitempty"`
@@ -176,6 +243,7 @@ type CreateSessionSetRequest struct {
 	ActualDurationSeconds *int         `json:"actual_duration_seconds,omitempty"`
 	RPEValueID            *uuid.UUID   `json:"rpe_value_id,omitempty"`
 	Notes                 *string      `json:"notes,omitempty"`
+	CardioActualsRequest
 }
 
 // UpdateSessionSetRequest represents the request to update a logged set
@@ -186,7 +254,87 @@ type UpdateSessionSetRequest struct {
 	RPEValueID            *uuid.UUID   `json:"rpe_value_id,omitempty"`
 	WasFailure            *bool        `json:"was_failure,omitempty"`
 	Completed             *bool        `json:"completed,omitempty"`
+	StartedAt             *time.Time   `json:"started_at,omitempty"`
+	CompletedAt           *time.Time   `json:"completed_at,omitempty"` // Optional when completing: defaults to now
 	Notes                 *string      `json:"notes,omitempty"`
+	CardioActualsRequest
+}
+
+// CardioActualsRequest represents the distance, heart rate and incline logged for a set
+type CardioActualsRequest struct {
+	ActualDistance         *DistanceInput `json:"actual_distance,omitempty"`
+	ActualAverageHeartRate *int           `json:"actual_average_heart_rate,omitempty" binding:"omitempty,min=30,max=250"`
+	ActualMaxHeartRate     *int           `json:"actual_max_heart_rate,omitempty" binding:"omitempty,min=30,max=250"`
+	ActualInclinePercent   *float64       `json:"actual_incline_percent,omitempty" binding:"omitempty,gte=-20,lte=40"`
+}
+
+// StartSessionSetRequest represents the request to start a set
+type StartSessionSetRequest struct {
+	StartedAt *time.Time `json:"started_at,omitempty"` // Optional: defaults to now
+}
+
+// SyncWorkoutSessionRequest represents a whole session tree logged offline. Every entity carries a
+// client-generated ID and the client time it was last modified, so the same payload can be sent
+// again safely and concurrent server changes are detected.
+type SyncWorkoutSessionRequest struct {
+	ID                 uuid.UUID                 `json:"id" binding:"required"`
+	UserID             *uuid.UUID                `json:"user_id,omitempty"` // Optional: trainers logging for a client
+	WorkoutID          *uuid.UUID                `json:"workout_id,omitempty"`
+	StartedAt          time.Time                 `json:"started_at" binding:"required"`
+	EndedAt            *time.Time                `json:"ended_at,omitempty"` // Ends the session when set
+	PerceivedIntensity *int                      `json:"perceived_intensity,omitempty" binding:"omitempty,min=1,max=10"`
+	Notes              string                    `json:"notes,omitempty"`
+	ClientUpdatedAt    time.Time                 `json:"client_updated_at" binding:"required"`
+	Blocks             []SyncSessionBlockRequest `json:"blocks,omitempty" binding:"dive"`
+}
+
+// SyncSessionBlockRequest represents a session block in a synced session tree
+type SyncSessionBlockRequest struct {
+	ID                uuid.UUID                    `json:"id" binding:"required"`
+	GroupID           *uuid.UUID                   `json:"group_id,omitempty"`
+	BlockOrder        int                          `json:"block_order" binding:"required,min=1"`
+	StartedAt         *time.Time                   `json:"started_at,omitempty"`
+	CompletedAt       *time.Time                   `json:"completed_at,omitempty"`
+	Skipped           bool                         `json:"skipped"`
+	PerceivedExertion *int                         `json:"perceived_exertion,omitempty" binding:"omitempty,min=1,max=10"`
+	ClientUpdatedAt   time.Time                    `json:"client_updated_at" binding:"required"`
+	Exercises         []SyncSessionExerciseRequest `json:"exercises,omitempty" binding:"dive"`
+}
+
+// SyncSessionExerciseRequest represents a session exercise in a synced session tree
+type SyncSessionExerciseRequest struct {
+	ID              uuid.UUID               `json:"id" binding:"required"`
+	PrescriptionID  *uuid.UUID              `json:"prescription_id,omitempty"`
+	ExerciseID      uuid.UUID               `json:"exercise_id" binding:"required"`
+	ExerciseOrder   int                     `json:"exercise_order" binding:"required,min=1"`
+	StartedAt       *time.Time              `json:"started_at,omitempty"`
+	CompletedAt     *time.Time              `json:"completed_at,omitempty"`
+	Skipped         bool                    `json:"skipped"`
+	Notes           string                  `json:"notes,omitempty"`
+	ClientUpdatedAt time.Time               `json:"client_updated_at" binding:"required"`
+	Sets            []SyncSessionSetRequest `json:"sets,omitempty" binding:"dive"`
+}
+
+// SyncSessionSetRequest represents a session set in a synced session tree
+type SyncSessionSetRequest struct {
+	ID                    uuid.UUID    `json:"id" binding:"required"`
+	SetNumber             int          `json:"set_number" binding:"required,min=1"`
+	Round                 int          `json:"round,omitempty" binding:"omitempty,min=1"`
+	SequenceOrder         int          `json:"sequence_order,omitempty" binding:"omitempty,min=0"`
+	SubSetNumber          int          `json:"sub_set_number,omitempty" binding:"omitempty,min=0"`
+	IntervalStartSeconds  *int         `json:"interval_start_seconds,omitempty"`
+	IntervalSeconds       *int         `json:"interval_seconds,omitempty"`
+	Completed             bool         `json:"completed"`
+	StartedAt             *time.Time   `json:"started_at,omitempty"`
+	CompletedAt           *time.Time   `json:"completed_at,omitempty"`
+	ActualReps            *int         `json:"actual_reps,omitempty"`
+	ActualWeight          *WeightInput `json:"actual_weight,omitempty"`
+	ActualDurationSeconds *int         `json:"actual_duration_seconds,omitempty"`
+	RPEValueID            *uuid.UUID   `json:"rpe_value_id,omitempty"`
+	WasFailure            bool         `json:"was_failure"`
+	Notes                 string       `json:"notes,omitempty"`
+	ClientUpdatedAt       time.Time    `json:"client_updated_at" binding:"required"`
+	CardioActualsRequest
 }
 
 // ===== RESPONSE DTOs =====
@@ -195,7 +343,16 @@ type UpdateSessionSetRequest struct {
 type SessionSetResponse struct {
 	ID                    uuid.UUID      `json:"id"`
 	SetNumber             int            `json:"set_number"`
+	Round                 int            `json:"round"`
+	SequenceOrder         int            `json:"sequence_order"`
+	SubSetNumber          int            `json:"sub_set_number"`
+	IntervalStartSeconds  *int           `json:"interval_start_seconds,omitempty"`
+	IntervalSeconds       *int           `json:"interval_seconds,omitempty"`
 	Completed             bool           `json:"completed"`
+	StartedAt             *time.Time     `json:"started_at,omitempty"`
+	CompletedAt           *time.Time     `json:"completed_at,omitempty"`
+	RestSeconds           *int           `json:"rest_seconds,omitempty"`
+	PrescribedRestSeconds *int           `json:"prescribed_rest_seconds,omitempty"`
 	ActualReps            *int           `json:"actual_reps,omitempty"`
 	ActualWeight          *WeightOutput  `json:"actual_weight,omitempty"`
 	ActualDurationSeconds *int           `json:"actual_duration_seconds,omitempty"`
@@ -204,6 +361,59 @@ type SessionSetResponse struct {
 	WasFailure            bool           `json:"was_failure"`
 	Notes                 string         `json:"notes,omitempty"`
 	CreatedAt             time.Time      `json:"created_at"`
+
+	ActualDistance         *DistanceOutput `json:"actual_distance,omitempty"`
+	ActualPace             *PaceOutput     `json:"actual_pace,omitempty"` // From distance and duration
+	ActualAverageHeartRate *int            `json:"actual_average_heart_rate,omitempty"`
+	ActualMaxHeartRate     *int            `json:"actual_max_heart_rate,omitempty"`
+	ActualInclinePercent   *float64        `json:"actual_incline_percent,omitempty"`
+
+	// Personal records set by this set (only populated when completing a set)
+	NewRecords []PersonalRecordResponse `json:"new_records,omitempty"`
+
+	// The set to perform next and when it is due (only populated when completing a set)
+	NextSet *NextSetHint `json:"next_set,omitempty"`
+}
+
+// NextSetHint tells the client which set comes next and when to start it, so it can drive a rest timer
+type NextSetHint struct {
+	SetID             uuid.UUID `json:"set_id"`
+	SessionExerciseID uuid.UUID `json:"session_exercise_id"`
+	SessionBlockID    uuid.UUID `json:"session_block_id"`
+	ExerciseName      string    `json:"exercise_name,omitempty"`
+	RestSeconds       *int      `json:"rest_seconds,omitempty"` // Rest to take before the next set, if prescribed
+	ReadyAt           time.Time `json:"ready_at"`
+}
+
+// Sync entity types and conflict reasons reported by the session sync endpoint
+const (
+	SyncEntitySession  = "session"
+	SyncEntityBlock    = "block"
+	SyncEntityExercise = "exercise"
+	SyncEntitySet      = "set"
+
+	SyncConflictServerNewer = "server_newer" // the server copy was modified after the client copy
+	SyncConflictIDInUse     = "id_in_use"    // the ID belongs to an entity elsewhere
+	SyncConflictDeleted     = "deleted"      // the entity was deleted on the server
+)
+
+// SyncConflict describes an entity of a synced session tree that was not applied
+// The server copy is kept; entities inside it are still synced.
+type SyncConflict struct {
+	Entity          string     `json:"entity"`
+	ID              uuid.UUID  `json:"id"`
+	Reason          string     `json:"reason"`
+	ServerUpdatedAt *time.Time `json:"server_updated_at,omitempty"`
+	ClientUpdatedAt time.Time  `json:"client_updated_at"`
+}
+
+// SyncWorkoutSessionResponse represents the result of syncing a session tree
+type SyncWorkoutSessionResponse struct {
+	Session   WorkoutSessionResponse `json:"session"`
+	Created   int                    `json:"created"`
+	Updated   int                    `json:"updated"`
+	Unchanged int                    `json:"unchanged"`
+	Conflicts []SyncConflict         `json:"conflicts"`
 }
 
 // SessionExerciseResponse represents an exercise in the response
@@ -227,22 +437,31 @@ type PrescriptionBrief struct {
 	Reps         *int          `json:"reps,omitempty"`
 	HoldSeconds  *int          `json:"hold_seconds,omitempty"`
 	TargetWeight *WeightOutput `json:"target_weight,omitempty"`
+
+	TargetDistance       *DistanceOutput `json:"target_distance,omitempty"`
+	TargetPace           *PaceOutput     `json:"target_pace,omitempty"`
+	TargetHeartRateMin   *int            `json:"target_heart_rate_min,omitempty"`
+	TargetHeartRateMax   *int            `json:"target_heart_rate_max,omitempty"`
+	TargetInclinePercent *float64        `json:"target_incline_percent,omitempty"`
 }
 
 // SessionBlockResponse represents a block in the response
 type SessionBlockResponse struct {
-	ID                uuid.UUID                 `json:"id"`
-	GroupID           uuid.UUID                 `json:"group_id"`
-	BlockOrder        int                       `json:"block_order"`
-	Type              PrescriptionType          `json:"type,omitempty"`
-	GroupName         string                    `json:"group_name,omitempty"`
-	GroupRounds       *int                      `json:"group_rounds,omitempty"`
-	RestBetweenSets   *int                      `json:"rest_between_sets,omitempty"`
-	StartedAt         *time.Time                `json:"started_at"`
-	CompletedAt       *time.Time                `json:"completed_at"`
-	Skipped           bool                      `json:"skipped"`
-	PerceivedExertion *int                      `json:"perceived_exertion,omitempty"`
-	Exercises         []SessionExerciseResponse `json:"exercises"`
+	ID                 uuid.UUID                 `json:"id"`
+	GroupID            uuid.UUID                 `json:"group_id"`
+	BlockOrder         int                       `json:"block_order"`
+	Type               PrescriptionType          `json:"type,omitempty"`
+	GroupName          string                    `json:"group_name,omitempty"`
+	GroupRounds        *int                      `json:"group_rounds,omitempty"`
+	RestBetweenSets    *int                      `json:"rest_between_sets,omitempty"`
+	IntervalSeconds    *int                      `json:"interval_seconds,omitempty"`
+	AverageRestSeconds *int                      `json:"average_rest_seconds,omitempty"` // Average rest taken where rest was prescribed
+	RestDeltaSeconds   *int                      `json:"rest_delta_seconds,omitempty"`   // Average rest taken minus average rest prescribed
+	StartedAt          *time.Time                `json:"started_at"`
+	CompletedAt        *time.Time                `json:"completed_at"`
+	Skipped            bool                      `json:"skipped"`
+	PerceivedExertion  *int                      `json:"perceived_exertion,omitempty"`
+	Exercises          []SessionExerciseResponse `json:"exercises"`
 }
 
 // WorkoutSessionResponse represents the full session response
@@ -253,6 +472,8 @@ type WorkoutSessionResponse struct {
 	CreatedByName      string                 `json:"created_by_name,omitempty"`
 	WorkoutID          *uuid.UUID             `json:"workout_id,omitempty"`
 	WorkoutTitle       string                 `json:"workout_title,omitempty"`
+	EnrollmentID       *uuid.UUID             `json:"enrollment_id,omitempty"`
+	ScheduledIndex     *int                   `json:"scheduled_index,omitempty"`
 	StartedAt          time.Time              `json:"started_at"`
 	EndedAt            *time.Time             `json:"ended_at,omitempty"`
 	DurationSeconds    *int                   `json:"duration_seconds,omitempty"`
@@ -263,6 +484,39 @@ type WorkoutSessionResponse struct {
 	Blocks             []SessionBlockResponse `json:"blocks"`
 	CreatedAt          time.Time              `json:"created_at"`
 	UpdatedAt          time.Time              `json:"updated_at"`
+
+	// Personal records achieved during this session (only populated when ending a session)
+	NewRecords []PersonalRecordResponse `json:"new_records,omitempty"`
+
+	// Prescribed-vs-actual comparison (only populated when the session is fetched on its own)
+	Compliance *SessionComplianceResponse `json:"compliance,omitempty"`
+
+	// What was done in the session (only populated when ending a session)
+	Summary *SessionSummaryResponse `json:"summary,omitempty"`
+
+	// Recorded track summary (only for sessions imported from an activity file)
+	Activity *SessionActivityResponse `json:"activity,omitempty"`
+}
+
+// BlockDurationResponse represents the time spent on one block of a session
+type BlockDurationResponse struct {
+	SessionBlockID  uuid.UUID `json:"session_block_id"`
+	BlockOrder      int       `json:"block_order"`
+	Skipped         bool      `json:"skipped"`
+	DurationSeconds *int      `json:"duration_seconds,omitempty"` // Omitted if the block wasn't timed
+}
+
+// SessionSummaryResponse summarizes the completed sets of a session.
+// Muscle group sets are weighted like the muscle volume analytics.
+type SessionSummaryResponse struct {
+	TotalSets         int                     `json:"total_sets"`
+	TotalReps         int                     `json:"total_reps"`
+	Tonnage           *WeightOutput           `json:"tonnage"` // Sum of reps × weight
+	MuscleGroups      []MuscleGroupVolume     `json:"muscle_groups"`
+	Blocks            []BlockDurationResponse `json:"blocks"`
+	NewRecordCount    int                     `json:"new_record_count"`
+	BodyWeight        *WeightOutput           `json:"body_weight,omitempty"`        // Body weight used for the estimate
+	EstimatedCalories *float64                `json:"estimated_calories,omitempty"` // kcal, omitted without a known body weight
 }
 
 // WorkoutSessionListResponse represents a session in list view (without nested details)
@@ -271,6 +525,8 @@ type WorkoutSessionListResponse struct {
 	UserID             uuid.UUID  `json:"user_id"`
 	WorkoutID          *uuid.UUID `json:"workout_id,omitempty"`
 	WorkoutTitle       string     `json:"workout_title,omitempty"`
+	EnrollmentID       *uuid.UUID `json:"enrollment_id,omitempty"`
+	ScheduledIndex     *int       `json:"scheduled_index,omitempty"`
 	StartedAt          time.Time  `json:"started_at"`
 	EndedAt            *time.Time `json:"ended_at,omitempty"`
 	DurationMinutes    *int       `json:"duration_minutes,omitempty"`
@@ -289,6 +545,8 @@ func (ws *WorkoutSession) ToListResponse() WorkoutSessionListResponse {
 		ID:                 ws.ID,
 		UserID:             ws.UserID,
 		WorkoutID:          ws.WorkoutID,
+		EnrollmentID:       ws.EnrollmentID,
+		ScheduledIndex:     ws.ScheduledIndex,
 		StartedAt:          ws.StartedAt,
 		EndedAt:            ws.EndedAt,
 		PerceivedIntensity: ws.PerceivedIntensity,
@@ -316,12 +574,14 @@ func (ws *WorkoutSession) ToListResponse() WorkoutSessionListResponse {
 }
 
 // BuildSessionResponse builds a full session response with nested blocks, exercises, and sets
-func BuildSessionResponse(session WorkoutSession, weightUnit string) WorkoutSessionResponse {
+func BuildSessionResponse(session WorkoutSession, weightUnit string, distanceUnit string) WorkoutSessionResponse {
 	response := WorkoutSessionResponse{
 		ID:                 session.ID,
 		UserID:             session.UserID,
 		CreatedByID:        session.CreatedByID,
 		WorkoutID:          session.WorkoutID,
+		EnrollmentID:       session.EnrollmentID,
+		ScheduledIndex:     session.ScheduledIndex,
 		StartedAt:          session.StartedAt,
 		EndedAt:            session.EndedAt,
 		DurationSeconds:    session.DurationSeconds,
@@ -351,115 +611,19 @@ func BuildSessionResponse(session WorkoutSession, weightUnit string) WorkoutSess
 
 	// Build nested block responses
 	for _, block := range session.SessionBlocks {
-		blockResp := SessionBlockResponse{
-			ID:                block.ID,
-			GroupID:           block.GroupID,
-			BlockOrder:        block.BlockOrder,
-			StartedAt:         block.StartedAt,
-			CompletedAt:       block.CompletedAt,
-			Skipped:           block.Skipped,
-			PerceivedExertion: block.PerceivedExertion,
-			Exercises:         []SessionExerciseResponse{},
-		}
-
-		// Get group metadata from first prescription in block (if available)
-		if len(block.SessionExercises) > 0 && block.SessionExercises[0].Prescription != nil {
-			p := block.SessionExercises[0].Prescription
-			blockResp.Type = p.Type
-			if p.GroupName != nil {
-				blockResp.GroupName = *p.GroupName
-			}
-			blockResp.GroupRounds = p.GroupRounds
-			blockResp.RestBetweenSets = p.RestBetweenSets
-		}
-
-		// Build nested exercise responses
-		for _, exercise := range block.SessionExercises {
-			exerciseResp := SessionExerciseResponse{
-				ID:             exercise.ID,
-				PrescriptionID: exercise.PrescriptionID,
-				ExerciseID:     exercise.ExerciseID,
-				ExerciseName:   exercise.Exercise.Name,
-				ExerciseOrder:  exercise.ExerciseOrder,
-				StartedAt:      exercise.StartedAt,
-				CompletedAt:    exercise.CompletedAt,
-				Skipped:        exercise.Skipped,
-				Notes:          exercise.Notes,
-				Sets:           []SessionSetResponse{},
-			}
-
-			// Include prescription details if available
-			if exercise.Prescription != nil {
-				exerciseResp.Prescription = &PrescriptionBrief{
-					Sets:        exercise.Prescription.Sets,
-					Reps:        exercise.Prescription.Reps,
-					HoldSeconds: exercise.Prescription.HoldSeconds,
-				}
-				// Convert target weight to user's preferred unit
-				if exercise.Prescription.TargetWeightKg != nil {
-					convertedValue := *exercise.Prescription.TargetWeightKg
-					if weightUnit == "lb" {
-						convertedValue = convertedValue * 2.20462262185
-					}
-					unit := weightUnit
-					exerciseResp.Prescription.TargetWeight = &WeightOutput{
-						WeightValue: &convertedValue,
-						WeightUnit:  &unit,
-					}
-				}
-			}
-
-			// Build nested set responses
-			for _, set := range exercise.SessionSets {
-				setResp := SessionSetResponse{
-					ID:                    set.ID,
-					SetNumber:             set.SetNumber,
-					Completed:             set.Completed,
-					ActualReps:            set.ActualReps,
-					ActualDurationSeconds: set.ActualDurationSeconds,
-					RPEValueID:            set.RPEValueID,
-					WasFailure:            set.WasFailure,
-					Notes:                 set.Notes,
-					CreatedAt:             set.CreatedAt,
-				}
-
-				// Convert weight to user's preferred unit
-				if set.ActualWeightKg != nil {
-					convertedValue := *set.ActualWeightKg
-					if weightUnit == "lb" {
-						convertedValue = convertedValue * 2.20462262185
-					}
-					unit := weightUnit
-					setResp.ActualWeight = &WeightOutput{
-						WeightValue: &convertedValue,
-						WeightUnit:  &unit,
-					}
-				}
-
-				// Include RPE value details if available
-				if set.RPEValue != nil {
-					setResp.RPEValue = &RPEValueBrief{
-						ID:          set.RPEValue.ID,
-						Value:       set.RPEValue.Value,
-						Label:       set.RPEValue.Label,
-						Description: set.RPEValue.Description,
-					}
-				}
-
-				exerciseResp.Sets = append(exerciseResp.Sets, setResp)
-			}
-
-			blockResp.Exercises = append(blockResp.Exercises, exerciseResp)
-		}
+		response.Blocks = append(response.Blocks, block.ToResponse(weightUnit, distanceUnit))
+	}
 
-		response.Blocks = append(response.Blocks, blockResp)
+	if session.Activity != nil {
+		activity := session.Activity.ToResponse(distanceUnit)
+		response.Activity = &activity
 	}
 
 	return response
 }
 
-// ToResponse converts a SessionBlock to a response with weight converted to user's preferred unit
-func (sb *SessionBlock) ToResponse(preferredWeightUnit string) SessionBlockResponse {
+// ToResponse converts a SessionBlock to a response with weight and distance converted to user's preferred units
+func (sb *SessionBlock) ToResponse(preferredWeightUnit string, preferredDistanceUnit string) SessionBlockResponse {
 	blockResp := SessionBlockResponse{
 		ID:                sb.ID,
 		GroupID:           sb.GroupID,
@@ -480,18 +644,44 @@ func (sb *SessionBlock) ToResponse(preferredWeightUnit string) SessionBlockRespo
 		}
 		blockResp.GroupRounds = p.GroupRounds
 		blockResp.RestBetweenSets = p.RestBetweenSets
+		blockResp.IntervalSeconds = p.IntervalSeconds
 	}
+	blockResp.AverageRestSeconds, blockResp.RestDeltaSeconds = compareBlockRest(sb.SessionExercises)
 
 	// Build nested exercise responses
 	for _, exercise := range sb.SessionExercises {
-		blockResp.Exercises = append(blockResp.Exercises, exercise.ToResponse(preferredWeightUnit))
+		blockResp.Exercises = append(blockResp.Exercises, exercise.ToResponse(preferredWeightUnit, preferredDistanceUnit))
 	}
 
 	return blockResp
 }
 
-// ToResponse converts a SessionExercise to a response with weight converted to user's preferred unit
-func (se *SessionExercise) ToResponse(preferredWeightUnit string) SessionExerciseResponse {
+// compareBlockRest averages the rest taken before the block's sets that had a prescribed rest and
+// returns it with its difference from the average prescribed rest. Both are nil if no such set has
+// been completed yet.
+func compareBlockRest(exercises []SessionExercise) (*int, *int) {
+	count, actualTotal, prescribedTotal := 0, 0, 0
+	for _, exercise := range exercises {
+		for _, set := range exercise.SessionSets {
+			if set.RestSeconds == nil || set.PrescribedRestSeconds == nil {
+				continue
+			}
+			count++
+			actualTotal += *set.RestSeconds
+			prescribedTotal += *set.PrescribedRestSeconds
+		}
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	average := actualTotal / count
+	delta := (actualTotal - prescribedTotal) / count
+	return &average, &delta
+}
+
+// ToResponse converts a SessionExercise to a response with weight and distance converted to user's preferred units
+func (se *SessionExercise) ToResponse(preferredWeightUnit string, preferredDistanceUnit string) SessionExerciseResponse {
 	exerciseResp := SessionExerciseResponse{
 		ID:             se.ID,
 		PrescriptionID: se.PrescriptionID,
@@ -508,9 +698,18 @@ func (se *SessionExercise) ToResponse(preferredWeightUnit string) SessionExercis
 	// Include prescription details if available
 	if se.Prescription != nil {
 		exerciseResp.Prescription = &PrescriptionBrief{
-			Sets:        se.Prescription.Sets,
-			Reps:        se.Prescription.Reps,
-			HoldSeconds: se.Prescription.HoldSeconds,
+			Sets:                 se.Prescription.Sets,
+			Reps:                 se.Prescription.Reps,
+			HoldSeconds:          se.Prescription.HoldSeconds,
+			TargetHeartRateMin:   se.Prescription.TargetHeartRateMin,
+			TargetHeartRateMax:   se.Prescription.TargetHeartRateMax,
+			TargetInclinePercent: se.Prescription.TargetInclinePercent,
+		}
+		if se.Prescription.TargetDistanceMeters != nil {
+			exerciseResp.Prescription.TargetDistance = NewDistanceOutput(*se.Prescription.TargetDistanceMeters, preferredDistanceUnit)
+		}
+		if se.Prescription.TargetPaceSecondsPerKm != nil {
+			exerciseResp.Prescription.TargetPace = NewPaceOutput(*se.Prescription.TargetPaceSecondsPerKm, preferredDistanceUnit)
 		}
 		// Convert target weight to user's preferred unit
 		if se.Prescription.TargetWeightKg != nil {
@@ -528,24 +727,46 @@ func (se *SessionExercise) ToResponse(preferredWeightUnit string) SessionExercis
 
 	// Build nested set responses
 	for _, set := range se.SessionSets {
-		exerciseResp.Sets = append(exerciseResp.Sets, set.ToResponse(preferredWeightUnit))
+		exerciseResp.Sets = append(exerciseResp.Sets, set.ToResponse(preferredWeightUnit, preferredDistanceUnit))
 	}
 
 	return exerciseResp
 }
 
-// ToResponse converts a SessionSet to a response (with default kg weight unit)
-func (ss *SessionSet) ToResponse(preferredWeightUnit string) SessionSetResponse {
+// ToResponse converts a SessionSet to a response with weight and distance converted to user's preferred units
+func (ss *SessionSet) ToResponse(preferredWeightUnit string, preferredDistanceUnit string) SessionSetResponse {
 	setResp := SessionSetResponse{
 		ID:                    ss.ID,
 		SetNumber:             ss.SetNumber,
+		Round:                 ss.Round,
+		SequenceOrder:         ss.SequenceOrder,
+		SubSetNumber:          ss.SubSetNumber,
+		IntervalStartSeconds:  ss.IntervalStartSeconds,
+		IntervalSeconds:       ss.IntervalSeconds,
 		Completed:             ss.Completed,
+		StartedAt:             ss.StartedAt,
+		CompletedAt:           ss.CompletedAt,
+		RestSeconds:           ss.RestSeconds,
+		PrescribedRestSeconds: ss.PrescribedRestSeconds,
 		ActualReps:            ss.ActualReps,
 		ActualDurationSeconds: ss.ActualDurationSeconds,
 		RPEValueID:            ss.RPEValueID,
 		WasFailure:            ss.WasFailure,
 		Notes:                 ss.Notes,
 		CreatedAt:             ss.CreatedAt,
+
+		ActualAverageHeartRate: ss.ActualAverageHeartRate,
+		ActualMaxHeartRate:     ss.ActualMaxHeartRate,
+		ActualInclinePercent:   ss.ActualInclinePercent,
+	}
+
+	// Convert distance to user's preferred unit and derive the pace
+	if ss.ActualDistanceMeters != nil {
+		setResp.ActualDistance = NewDistanceOutput(*ss.ActualDistanceMeters, preferredDistanceUnit)
+		if *ss.ActualDistanceMeters > 0 && ss.ActualDurationSeconds != nil && *ss.ActualDurationSeconds > 0 {
+			secondsPerKm := float64(*ss.ActualDurationSeconds) / (*ss.ActualDistanceMeters / 1000)
+			setResp.ActualPace = NewPaceOutput(secondsPerKm, preferredDistanceUnit)
+		}
 	}
 
 	// Convert weight to user's preferred unit
@@ -52,6 +52,45 @@ func IsValidPrescriptionType(t PrescriptionType) bool {
 	return false
 }
 
+// ProgressionType represents how a prescription group's targets progress between sessions
+type ProgressionType string
+
+const (
+	ProgressionTypeLinear     ProgressionType = "linear"             // add load after every session where all target reps were hit
+	ProgressionTypeDouble     ProgressionType = "double_progression" // add reps up to a ceiling, then add load and reset reps
+	ProgressionTypeRPE        ProgressionType = "rpe"                // pick the load expected to land on a target RPE
+	ProgressionTypePercent1RM ProgressionType = "percent_1rm"        // load as a percentage of the estimated one-rep max
+)
+
+// ValidProgressionTypes contains all valid progression types
+var ValidProgressionTypes = []ProgressionType{
+	ProgressionTypeLinear,
+	ProgressionTypeDouble,
+	ProgressionTypeRPE,
+	ProgressionTypePercent1RM,
+}
+
+// IsValidProgressionType checks if the given progression type is valid
+func IsValidProgressionType(t ProgressionType) bool {
+	for _, valid := range ValidProgressionTypes {
+		if t == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// ProgressionRule describes how a prescription group's load and reps progress between sessions
+// Stored as group-level columns (progression_*) on every prescription row of the group
+type ProgressionRule struct {
+	Type         *ProgressionType `gorm:"type:varchar(30)" json:"type,omitempty"`
+	IncrementKg  *float64         `gorm:"type:decimal(6,2)" json:"-"`   // load step; also used to round computed loads
+	MinReps      *int             `gorm:"" json:"min_reps,omitempty"`   // double progression rep floor
+	MaxReps      *int             `gorm:"" json:"max_reps,omitempty"`   // double progression rep ceiling
+	TargetRPE    *int             `gorm:"" json:"target_rpe,omitempty"` // RPE autoregulation target (1-10)
+	PercentOneRM *float64         `gorm:"type:decimal(5,2)" json:"percent_1rm,omitempty"`
+}
+
 // WorkoutPrescription represents a unified prescription for exercises in a workout
 // This single table handles all set types: straight sets, supersets, circuits, drop sets, etc.
 type WorkoutPrescription struct {
@@ -71,8 +110,10 @@ type WorkoutPrescription struct {
 	GroupOrder      int              `gorm:"not null" json:"group_order"`
 	GroupRounds     *int             `gorm:"default:1" json:"group_rounds,omitempty"`
 	RestBetweenSets *int             `gorm:"" json:"rest_between_sets,omitempty"`
+	IntervalSeconds *int             `gorm:"" json:"interval_seconds,omitempty"` // EMOM interval, HIIT work interval or AMRAP time cap
 	GroupName       *string          `gorm:"type:varchar(255)" json:"group_name,omitempty"`
 	GroupNotes      *string          `gorm:"type:text" json:"group_notes,omitempty"`
+	Progression     ProgressionRule  `gorm:"embedded;embeddedPrefix:progression_" json:"-"`
 
 	// Exercise-level fields (individual prescription row inside a group)
 	ExerciseOrder             int      `gorm:"not null" json:"exercise_order"`
@@ -84,6 +125,15 @@ type WorkoutPrescription struct {
 	OriginalTargetWeightUnit  *string  `gorm:"type:varchar(2)" json:"-"`
 	Notes                     *string  `gorm:"type:text" json:"notes,omitempty"`
 
+	// Cardio targets: distance is stored in metres with the original value/unit, pace in seconds per km
+	TargetDistanceMeters        *float64 `gorm:"type:decimal(9,2)" json:"-"`
+	OriginalTargetDistanceValue *float64 `gorm:"type:decimal(9,3)" json:"-"`
+	OriginalTargetDistanceUnit  *string  `gorm:"type:varchar(2)" json:"-"`
+	TargetPaceSecondsPerKm      *float64 `gorm:"type:decimal(7,2)" json:"-"`
+	TargetHeartRateMin          *int     `gorm:"" json:"target_heart_rate_min,omitempty"` // bpm
+	TargetHeartRateMax          *int     `gorm:"" json:"target_heart_rate_max,omitempty"` // bpm
+	TargetInclinePercent        *float64 `gorm:"type:decimal(4,1)" json:"target_incline_percent,omitempty"`
+
 	// Relationships
 	Workout  Workout        `gorm:"foreignKey:WorkoutID" json:"-"`
 	Exercise Exercise       `gorm:"foreignKey:ExerciseID" json:"exercise,omitempty"`
@@ -111,15 +161,22 @@ func (wp *WorkoutPrescription) BeforeCreate(tx *gorm.DB) error {
 	}
 
 	// Validate mutual exclusivity of reps and hold_seconds
+	// A target distance alone is enough for cardio (e.g. a 5 km run)
 	hasReps := wp.Reps != nil && *wp.Reps > 0
 	hasHold := wp.HoldSeconds != nil && *wp.HoldSeconds > 0
+	hasDistance := wp.TargetDistanceMeters != nil && *wp.TargetDistanceMeters > 0
 
 	if hasReps && hasHold {
 		return errors.New("prescription cannot have both reps and hold_seconds")
 	}
 
-	if !hasReps && !hasHold {
-		return errors.New("prescription must have either reps or hold_seconds")
+	if !hasReps && !hasHold && !hasDistance {
+		return errors.New("prescription must have reps, hold_seconds or a target distance")
+	}
+
+	// Validate heart rate range
+	if wp.TargetHeartRateMin != nil && wp.TargetHeartRateMax != nil && *wp.TargetHeartRateMin > *wp.TargetHeartRateMax {
+		return errors.New("target_heart_rate_min cannot be above target_heart_rate_max")
 	}
 
 	// Validate group_order is positive
@@ -147,6 +204,7 @@ type PrescriptionExerciseRequest struct {
 	TargetWeight  *WeightInput `json:"target_weight,omitempty"`
 	RPEValueID    *uuid.UUID   `json:"rpe_value_id,omitempty"`
 	Notes         *string      `json:"notes,omitempty"`
+	CardioTargetsRequest
 }
 
 // CreatePrescriptionGroupRequest represents the request to create a prescription group
@@ -156,8 +214,10 @@ type CreatePrescriptionGroupRequest struct {
 	GroupOrder      int                           `json:"group_order" binding:"required,min=1"`
 	GroupRounds     *int                          `json:"group_rounds,omitempty"`
 	RestBetweenSets *int                          `json:"rest_between_sets,omitempty"`
+	IntervalSeconds *int                          `json:"interval_seconds,omitempty" binding:"omitempty,min=1"`
 	GroupName       *string                       `json:"group_name,omitempty"`
 	GroupNotes      *string                       `json:"group_notes,omitempty"`
+	Progression     *ProgressionRuleRequest       `json:"progression,omitempty"`
 	Exercises       []PrescriptionExerciseRequest `json:"exercises" binding:"required,min=1,dive"`
 }
 
@@ -167,11 +227,28 @@ type UpdatePrescriptionGroupRequest struct {
 	GroupOrder      *int                          `json:"group_order,omitempty"`
 	GroupRounds     *int                          `json:"group_rounds,omitempty"`
 	RestBetweenSets *int                          `json:"rest_between_sets,omitempty"`
+	IntervalSeconds *int                          `json:"interval_seconds,omitempty" binding:"omitempty,min=1"`
 	GroupName       *string                       `json:"group_name,omitempty"`
 	GroupNotes      *string                       `json:"group_notes,omitempty"`
+	Progression     *ProgressionRuleRequest       `json:"progression,omitempty"` // Set type to "" to remove the rule
 	Exercises       []PrescriptionExerciseRequest `json:"exercises,omitempty"`
 }
 
+// ProgressionRuleRequest represents a progression rule attached to a prescription group
+// Required fields depend on the type:
+//   - linear: increment
+//   - double_progression: increment, min_reps, max_reps
+//   - rpe: target_rpe (increment optionally rounds the load)
+//   - percent_1rm: percent_1rm (increment optionally rounds the load)
+type ProgressionRuleRequest struct {
+	Type         ProgressionType `json:"type"`
+	Increment    *WeightInput    `json:"increment,omitempty"`
+	MinReps      *int            `json:"min_reps,omitempty" binding:"omitempty,min=1"`
+	MaxReps      *int            `json:"max_reps,omitempty" binding:"omitempty,min=1"`
+	TargetRPE    *int            `json:"target_rpe,omitempty" binding:"omitempty,min=1,max=10"`
+	PercentOneRM *float64        `json:"percent_1rm,omitempty" binding:"omitempty,gt=0,lte=100"`
+}
+
 // ReorderPrescriptionGroupsRequest represents the request to reorder groups
 type ReorderPrescriptionGroupsRequest struct {
 	GroupOrders []GroupOrderItem `json:"group_orders" binding:"required,min=1,dive"`
@@ -192,6 +269,16 @@ type AddExerciseToPrescriptionRequest struct {
 	TargetWeight *WeightInput `json:"target_weight,omitempty"`
 	RPEValueID   *uuid.UUID   `json:"rpe_value_id,omitempty"`
 	Notes        *string      `json:"notes,omitempty"`
+	CardioTargetsRequest
+}
+
+// CardioTargetsRequest represents the distance, pace, heart rate and incline targets of a prescription
+type CardioTargetsRequest struct {
+	TargetDistance       *DistanceInput `json:"target_distance,omitempty"`
+	TargetPace           *PaceInput     `json:"target_pace,omitempty"`
+	TargetHeartRateMin   *int           `json:"target_heart_rate_min,omitempty" binding:"omitempty,min=30,max=250"`
+	TargetHeartRateMax   *int           `json:"target_heart_rate_max,omitempty" binding:"omitempty,min=30,max=250"`
+	TargetInclinePercent *float64       `json:"target_incline_percent,omitempty" binding:"omitempty,gte=-20,lte=40"`
 }
 
 // ===== Response DTOs =====
@@ -209,6 +296,12 @@ type PrescriptionExerciseResponse struct {
 	Notes         *string        `json:"notes,omitempty"`
 	Exercise      *ExerciseBrief `json:"exercise,omitempty"`
 	RPEValue      *RPEValueBrief `json:"rpe_value,omitempty"`
+
+	TargetDistance       *DistanceOutput `json:"target_distance,omitempty"`
+	TargetPace           *PaceOutput     `json:"target_pace,omitempty"`
+	TargetHeartRateMin   *int            `json:"target_heart_rate_min,omitempty"`
+	TargetHeartRateMax   *int            `json:"target_heart_rate_max,omitempty"`
+	TargetInclinePercent *float64        `json:"target_incline_percent,omitempty"`
 }
 
 // PrescriptionGroupResponse represents a group of prescriptions in the response
@@ -218,11 +311,23 @@ type PrescriptionGroupResponse struct {
 	GroupOrder      int                            `json:"group_order"`
 	GroupRounds     *int                           `json:"group_rounds,omitempty"`
 	RestBetweenSets *int                           `json:"rest_between_sets,omitempty"`
+	IntervalSeconds *int                           `json:"interval_seconds,omitempty"`
 	GroupName       *string                        `json:"group_name,omitempty"`
 	GroupNotes      *string                        `json:"group_notes,omitempty"`
+	Progression     *ProgressionRuleResponse       `json:"progression,omitempty"`
 	Exercises       []PrescriptionExerciseResponse `json:"exercises"`
 }
 
+// ProgressionRuleResponse represents a prescription group's progression rule in the response
+type ProgressionRuleResponse struct {
+	Type         ProgressionType `json:"type"`
+	Increment    *WeightOutput   `json:"increment,omitempty"`
+	MinReps      *int            `json:"min_reps,omitempty"`
+	MaxReps      *int            `json:"max_reps,omitempty"`
+	TargetRPE    *int            `json:"target_rpe,omitempty"`
+	PercentOneRM *float64        `json:"percent_1rm,omitempty"`
+}
+
 // ExerciseBrief is a brief representation of an exercise for responses
 type ExerciseBrief struct {
 	ID          uuid.UUID `json:"id"`
@@ -260,8 +365,10 @@ func GroupPrescriptionsByGroupID(prescriptions []WorkoutPrescription) []Prescrip
 				GroupOrder:      p.GroupOrder,
 				GroupRounds:     p.GroupRounds,
 				RestBetweenSets: p.RestBetweenSets,
+				IntervalSeconds: p.IntervalSeconds,
 				GroupName:       p.GroupName,
 				GroupNotes:      p.GroupNotes,
+				Progression:     p.Progression.ToResponse(),
 				Exercises:       []PrescriptionExerciseResponse{},
 			}
 			groupOrder = append(groupOrder, p.GroupID)
@@ -277,6 +384,10 @@ func GroupPrescriptionsByGroupID(prescriptions []WorkoutPrescription) []Prescrip
 			TargetWeight:  nil, // Will be populated by controller with user's preferred unit
 			RPEValueID:    p.RPEValueID,
 			Notes:         p.Notes,
+
+			TargetHeartRateMin:   p.TargetHeartRateMin,
+			TargetHeartRateMax:   p.TargetHeartRateMax,
+			TargetInclinePercent: p.TargetInclinePercent,
 		}
 
 		// Add exercise brief if loaded
@@ -311,6 +422,21 @@ func GroupPrescriptionsByGroupID(prescriptions []WorkoutPrescription) []Prescrip
 	return result
 }
 
+// ToResponse converts a ProgressionRule to its response, or nil if no rule is set
+// The increment is populated by PopulatePrescriptionWeights in the user's preferred unit
+func (r ProgressionRule) ToResponse() *ProgressionRuleResponse {
+	if r.Type == nil || *r.Type == "" {
+		return nil
+	}
+	return &ProgressionRuleResponse{
+		Type:         *r.Type,
+		MinReps:      r.MinReps,
+		MaxReps:      r.MaxReps,
+		TargetRPE:    r.TargetRPE,
+		PercentOneRM: r.PercentOneRM,
+	}
+}
+
 // PopulatePrescriptionWeights populates the TargetWeight fields in prescription responses
 // by converting from canonical kg storage to the user's preferred unit
 func PopulatePrescriptionWeights(groupedResponse []PrescriptionGroupResponse, prescriptions []WorkoutPrescription, preferredWeightUnit string) {
@@ -326,28 +452,63 @@ func PopulatePrescriptionWeights(groupedResponse []PrescriptionGroupResponse, pr
 			exercise := &groupedResponse[i].Exercises[j]
 			if prescription, exists := prescriptionMap[exercise.ID]; exists {
 				if prescription.TargetWeightKg != nil {
-					// Convert from canonical kg to user's preferred unit
-					var weightValue float64
-					var weightUnit string
-
-					if preferredWeightUnit == "lb" {
-						weightValue = *prescription.TargetWeightKg * 2.20462262
-						weightUnit = "lb"
-					} else {
-						weightValue = *prescription.TargetWeightKg
-						weightUnit = "kg"
-					}
-
-					exercise.TargetWeight = &WeightOutput{
-						WeightValue: &weightValue,
-						WeightUnit:  &weightUnit,
-					}
+					exercise.TargetWeight = prescriptionWeightOutput(*prescription.TargetWeightKg, preferredWeightUnit)
 				}
+
+				// Progression increment is a group-level field shared by every row
+				progression := groupedResponse[i].Progression
+				if progression != nil && progression.Increment == nil && prescription.Progression.IncrementKg != nil {
+					progression.Increment = prescriptionWeightOutput(*prescription.Progression.IncrementKg, preferredWeightUnit)
+				}
+			}
+		}
+	}
+}
+
+// PopulatePrescriptionDistances populates the TargetDistance and TargetPace fields in prescription
+// responses by converting from canonical metres and seconds per km to the user's preferred unit
+func PopulatePrescriptionDistances(groupedResponse []PrescriptionGroupResponse, prescriptions []WorkoutPrescription, preferredDistanceUnit string) {
+	prescriptionMap := make(map[uuid.UUID]WorkoutPrescription)
+	for _, p := range prescriptions {
+		prescriptionMap[p.ID] = p
+	}
+
+	for i := range groupedResponse {
+		for j := range groupedResponse[i].Exercises {
+			exercise := &groupedResponse[i].Exercises[j]
+			prescription, exists := prescriptionMap[exercise.ID]
+			if !exists {
+				continue
+			}
+			if prescription.TargetDistanceMeters != nil {
+				exercise.TargetDistance = NewDistanceOutput(*prescription.TargetDistanceMeters, preferredDistanceUnit)
+			}
+			if prescription.TargetPaceSecondsPerKm != nil {
+				exercise.TargetPace = NewPaceOutput(*prescription.TargetPaceSecondsPerKm, preferredDistanceUnit)
 			}
 		}
 	}
 }
 
+// prescriptionWeightOutput converts from canonical kg to the user's preferred unit
+func prescriptionWeightOutput(kg float64, preferredWeightUnit string) *WeightOutput {
+	var weightValue float64
+	var weightUnit string
+
+	if preferredWeightUnit == "lb" {
+		weightValue = kg * 2.20462262
+		weightUnit = "lb"
+	} else {
+		weightValue = kg
+		weightUnit = "kg"
+	}
+
+	return &WeightOutput{
+		WeightValue: &weightValue,
+		WeightUnit:  &weightUnit,
+	}
+}
+
 // GetNextGroupOrder returns the next available group order for a workout
 func GetNextGroupOrder(db *gorm.DB, workoutID uuid.UUID) (int, error) {
 	var maxOrder int
@@ -97,6 +97,7 @@ func SetupRoutes(r *gin.Engine) {
 				exercises.GET("/", controllers.GetExercises)
 				exercises.GET("/by-slug/:slug", controllers.GetExerciseBySlug)
 				exercises.GET("/:id", controllers.GetExercise)
+				exercises.GET("/:id/records", controllers.GetExerciseRecords)
 				exercises.PUT("/:id", controllers.UpdateExercise)
 				exercises.DELETE("/:id", controllers.DeleteExercise)
 
@@ -180,6 +181,12 @@ func SetupRoutes(r *gin.Engine) {
 				weightLogs.DELETE("/:id", controllers.DeleteWeightLog)
 			}
 
+			// Personal Records
+			userRecords := protected.Group("/user/records")
+			{
+				userRecords.GET("", controllers.GetUserRecords)
+			}
+
 			// User Equipment
 			userEquipment := protected.Group("/user/equipment")
 			{
@@ -221,6 +228,7 @@ func SetupRoutes(r *gin.Engine) {
 			{
 				workouts.POST("/", controllers.CreateWorkout)
 				workouts.GET("/", controllers.GetUserWorkouts)
+				workouts.GET("/shared-with-me", controllers.GetWorkoutsSharedWithMe)
 				workouts.GET("/:id", controllers.GetWorkout)
 				workouts.PUT("/:id", controllers.UpdateWorkout)
 				workouts.DELETE("/:id", controllers.DeleteWorkout)
@@ -233,6 +241,22 @@ func SetupRoutes(r *gin.Engine) {
 				workouts.PUT("/:id/prescriptions/:group_id", controllers.UpdatePrescriptionGroup)
 				workouts.DELETE("/:id/prescriptions/:group_id", controllers.DeletePrescriptionGroup)
 				workouts.POST("/:id/prescriptions/:group_id/exercises", controllers.AddExerciseToPrescriptionGroup)
+
+				// Workout Sharing
+				workouts.POST("/:id/shares", controllers.ShareWorkout)
+				workouts.GET("/:id/shares", controllers.GetWorkoutShares)
+				workouts.PUT("/:id/shares/:share_id", controllers.UpdateWorkoutShare)
+				workouts.DELETE("/:id/shares/:share_id", controllers.UnshareWorkout)
+			}
+
+			// Shared Workout Comments
+			sharedWorkouts := protected.Group("/shared-workouts")
+			{
+				sharedWorkouts.GET("/:id/comments", controllers.GetWorkoutComments)
+				sharedWorkouts.POST("/:id/comments", controllers.CreateWorkoutComment)
+				sharedWorkouts.PUT("/:id/comments/:comment_id", controllers.UpdateWorkoutComment)
+				sharedWorkouts.DELETE("/:id/comments/:comment_id", controllers.DeleteWorkoutComment)
+				sharedWorkouts.POST("/:id/comments/:comment_id/reactions", controllers.ToggleCommentReaction)
 			}
 
 			// Plan Enrollments
@@ -241,10 +265,20 @@ func SetupRoutes(r *gin.Engine) {
 				enrollments.POST("/", controllers.EnrollInPlan)
 				enrollments.GET("/", controllers.GetUserEnrollments)
 				enrollments.GET("/:id", controllers.GetEnrollment)
+				enrollments.GET("/:id/schedule", controllers.GetEnrollmentSchedule)
+				enrollments.GET("/:id/adherence", controllers.GetEnrollmentAdherence)
 				enrollments.PUT("/:id", controllers.UpdateEnrollment)
 				enrollments.DELETE("/:id", controllers.CancelEnrollment)
 			}
 
+			// Analytics
+			analytics := protected.Group("/analytics")
+			{
+				analytics.GET("/exercises/:id/history", controllers.GetExerciseHistory)
+				analytics.GET("/muscle-volume", controllers.GetMuscleVolume)
+				analytics.GET("/compliance", controllers.GetComplianceTrend)
+			}
+
 			// Friends
 			friends := protected.Group("/friends")
 			{
@@ -274,7 +308,14 @@ func SetupRoutes(r *gin.Engine) {
 				// Client management (trainer side)
 				trainers.POST("/clients", controllers.InviteClient)
 				trainers.GET("/clients", controllers.GetTrainerClients)
+				trainers.GET("/clients/roster", controllers.GetClientRoster)
 				trainers.DELETE("/clients/:id", controllers.RemoveClient)
+				trainers.GET("/clients/:id/overview", controllers.GetClientOverview)
+				trainers.GET("/clients/:id/muscle-volume", controllers.GetClientMuscleVolume)
+				trainers.GET("/clients/:id/compliance", controllers.GetClientComplianceTrend)
+				trainers.POST("/clients/:id/assignments", controllers.CreateClientAssignment)
+				trainers.GET("/clients/:id/assignments", controllers.GetClientAssignments)
+				trainers.DELETE("/clients/:id/assignments/:assignment_id", controllers.DeleteClientAssignment)
 
 				// Email invitations (trainer side)
 				trainers.POST("/email-invitations", controllers.CreateEmailInvitation)
@@ -285,6 +326,14 @@ func SetupRoutes(r *gin.Engine) {
 				// Public trainer endpoints
 				trainers.GET("/", controllers.ListTrainers)
 				trainers.GET("/:id", controllers.GetTrainerPublicProfile)
+
+				// Trainer reviews
+				trainers.GET("/:id/reviews", controllers.ListTrainerReviews)
+				trainers.POST("/:id/reviews", controllers.CreateTrainerReview)
+				trainers.PUT("/:id/reviews/:review_id", controllers.UpdateTrainerReview)
+				trainers.DELETE("/:id/reviews/:review_id", controllers.DeleteTrainerReview)
+				trainers.PUT("/:id/reviews/:review_id/reply", controllers.ReplyToTrainerReview)
+				trainers.PUT("/:id/reviews/:review_id/moderation", controllers.ModerateTrainerReview)
 			}
 
 			// User's trainer relationships (client side)
@@ -293,6 +342,24 @@ func SetupRoutes(r *gin.Engine) {
 				me.GET("/trainers", controllers.GetMyTrainers)
 				me.GET("/trainer-invitations", controllers.GetMyTrainerInvitations)
 				me.PUT("/trainer-invitations/:id", controllers.RespondToInvitation)
+				me.PUT("/trainers/:id/consent", controllers.UpdateTrainerConsent)
+				me.GET("/assignments", controllers.GetMyAssignments)
+				me.GET("/unread-messages", controllers.GetUnreadMessageCounts)
+				me.GET("/today", controllers.GetTodayWorkouts)
+				me.GET("/export", controllers.ExportUserData)
+				me.DELETE("", controllers.DeleteAccount)
+			}
+
+			// Messaging between trainers and clients, and between friends
+			conversations := protected.Group("/conversations")
+			{
+				conversations.GET("", controllers.GetConversations)
+				conversations.POST("", controllers.StartConversation)
+				conversations.GET("/:id", controllers.GetConversation)
+				conversations.GET("/:id/messages", controllers.GetMessages)
+				conversations.POST("/:id/messages", controllers.SendMessage)
+				conversations.GET("/:id/messages/poll", controllers.PollMessages)
+				conversations.PUT("/:id/read", controllers.MarkConversationRead)
 			}
 
 			// Specialties
@@ -324,10 +391,18 @@ func SetupRoutes(r *gin.Engine) {
 			workoutSessions := protected.Group("/workout-sessions")
 			{
 				workoutSessions.POST("", controllers.CreateWorkoutSession)
+				workoutSessions.POST("/sync", controllers.SyncWorkoutSession)
+				workoutSessions.POST("/import", controllers.ImportWorkoutSession)
+				workoutSessions.POST("/import-history/preview", controllers.PreviewTrainingHistoryImport)
+				workoutSessions.POST("/import-history", controllers.ImportTrainingHistory)
 				workoutSessions.GET("", controllers.GetWorkoutSessions)
 				workoutSessions.GET("/:id", controllers.GetWorkoutSession)
+				workoutSessions.GET("/:id/activity", controllers.GetSessionActivity)
 				workoutSessions.PUT("/:id", controllers.UpdateWorkoutSession)
 				workoutSessions.PUT("/:id/end", controllers.EndWorkoutSession)
+				workoutSessions.POST("/:id/blocks", controllers.CreateSessionBlock)
+				workoutSessions.PUT("/:id/blocks/reorder", controllers.ReorderSessionBlocks)
+				workoutSessions.POST("/:id/save-as-workout", controllers.SaveSessionAsWorkout)
 				workoutSessions.DELETE("/:id", controllers.DeleteWorkoutSession)
 			}
 
@@ -338,6 +413,9 @@ func SetupRoutes(r *gin.Engine) {
 				sessionBlocks.PUT("/:id/complete", controllers.CompleteSessionBlock)
 				sessionBlocks.PUT("/:id/skip", controllers.SkipSessionBlock)
 				sessionBlocks.PUT("/:id/rpe", controllers.UpdateSessionBlockRPE)
+				sessionBlocks.POST("/:id/exercises", controllers.AddExerciseToSessionBlock)
+				sessionBlocks.PUT("/:id/exercises/reorder", controllers.ReorderSessionExercises)
+				sessionBlocks.DELETE("/:id", controllers.DeleteSessionBlock)
 			}
 
 			// Session Exercises
@@ -348,6 +426,8 @@ func SetupRoutes(r *gin.Engine) {
 				sessionExercises.PUT("/:id/skip", controllers.SkipSessionExercise)
 				sessionExercises.PUT("/:id/notes", controllers.UpdateSessionExerciseNotes)
 				sessionExercises.POST("/:id/sets", controllers.AddSetToExercise)
+				sessionExercises.PUT("/:id/swap", controllers.SwapSessionExercise)
+				sessionExercises.DELETE("/:id", controllers.DeleteSessionExercise)
 			}
 
 			// Session Sets
@@ -355,6 +435,7 @@ func SetupRoutes(r *gin.Engine) {
 			{
 				sessionSets.GET("/:id", controllers.GetSessionSet)
 				sessionSets.PUT("/:id", controllers.UpdateSessionSet)
+				sessionSets.PUT("/:id/start", controllers.StartSessionSet)
 				sessionSets.PUT("/:id/complete", controllers.CompleteSessionSet)
 				sessionSets.DELETE("/:id", controllers.DeleteSessionSet)
 			}
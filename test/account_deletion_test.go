@@ -0,0 +1,199 @@
+package test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/google/uuid"
+)
+
+func TestAccountDeletion(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Delete And Restore", func(t *testing.T) {
+		CleanDatabase(t)
+		testDeleteAndRestoreAccount(t, e)
+	})
+
+	t.Run("Purge After Grace Period", func(t *testing.T) {
+		CleanDatabase(t)
+		testPurgeDeletedAccount(t, e)
+	})
+}
+
+// loginForRefreshToken logs in and returns the access and refresh tokens
+func loginForRefreshToken(e *httpexpect.Expect, email string, password string) (string, string) {
+	data := e.POST("/api/v1/auth/login").
+		WithJSON(map[string]interface{}{"email": email, "password": password}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("data").Object()
+	return data.Value("access_token").String().Raw(), data.Value("refresh_token").String().Raw()
+}
+
+// deleteAccount deletes the account of the token's user
+func deleteAccount(e *httpexpect.Expect, token string, password string) *httpexpect.Object {
+	return e.DELETE("/api/v1/me").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{"password": password}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("data").Object()
+}
+
+func testDeleteAndRestoreAccount(t *testing.T, e *httpexpect.Expect) {
+	trainerToken := createTestUserAndGetToken(e, "trainer@example.com", "TrainerPass123!", "John", "Trainer")
+	clientToken := createTestUserAndGetToken(e, "leaving@example.com", "LeavingPass123!", "Lea", "Ving")
+	createActiveTrainerClientLink(t, e, trainerToken, clientToken)
+	accessToken, refreshToken := loginForRefreshToken(e, "leaving@example.com", "LeavingPass123!")
+
+	t.Run("Password Is Checked", func(t *testing.T) {
+		e.DELETE("/api/v1/me").
+			WithHeader("Authorization", "Bearer "+accessToken).
+			Expect().
+			Status(http.StatusBadRequest)
+
+		e.DELETE("/api/v1/me").
+			WithHeader("Authorization", "Bearer "+accessToken).
+			WithJSON(map[string]interface{}{"password": "wrong-password"}).
+			Expect().
+			Status(http.StatusBadRequest)
+
+		// A chunked body has no content length but is still checked
+		e.DELETE("/api/v1/me").
+			WithHeader("Authorization", "Bearer "+accessToken).
+			WithHeader("Content-Type", "application/json").
+			WithChunked(strings.NewReader(`{"password": "wrong-password"}`)).
+			Expect().
+			Status(http.StatusBadRequest).
+			JSON().Object().
+			Value("errors").Object().
+			Value("password").Array().Value(0).String().IsEqual("Password is incorrect")
+	})
+
+	deletion := deleteAccount(e, accessToken, "LeavingPass123!")
+	scheduledAt, err := time.Parse(time.RFC3339, deletion.Value("deletion_scheduled_at").String().Raw())
+	if err != nil || scheduledAt.Before(time.Now().Add(24*time.Hour)) {
+		t.Errorf("deletion_scheduled_at = %v (%v), want the end of the grace period", scheduledAt, err)
+	}
+
+	t.Run("Account Is Locked", func(t *testing.T) {
+		e.GET("/api/v1/auth/profile").
+			WithHeader("Authorization", "Bearer "+accessToken).
+			Expect().
+			Status(http.StatusUnauthorized)
+
+		e.POST("/api/v1/auth/refresh").
+			WithJSON(map[string]interface{}{"refresh_token": refreshToken}).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("Trainer Link Is Ended", func(t *testing.T) {
+		e.GET("/api/v1/trainers/clients").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array().
+			Length().IsEqual(0)
+	})
+
+	t.Run("Logging In Restores The Account", func(t *testing.T) {
+		token, _ := loginForRefreshToken(e, "leaving@example.com", "LeavingPass123!")
+		e.GET("/api/v1/auth/profile").
+			WithHeader("Authorization", "Bearer "+token).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			NotContainsKey("deletion_scheduled_at")
+	})
+}
+
+func testPurgeDeletedAccount(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "purged@example.com", "PurgedPass123!", "Pur", "Ged")
+	friendToken := createTestUserAndGetToken(e, "friend@example.com", "FriendPass123!", "Fri", "End")
+	userID := uuid.MustParse(getTestUserID(e, token))
+	friendID := uuid.MustParse(getTestUserID(e, friendToken))
+
+	seedExportData(e, token)
+
+	// A comment on a workout the friend shared must outlive the account
+	friendWorkout := models.Workout{UserID: friendID, Title: "Friend's workout"}
+	testDB.Create(&friendWorkout)
+	share := models.SharedWorkout{WorkoutID: friendWorkout.ID, SharedByID: friendID, SharedWithID: userID}
+	testDB.Create(&share)
+	comment := models.WorkoutComment{SharedWorkoutID: share.ID, UserID: userID, Content: "Great session!"}
+	testDB.Create(&comment)
+
+	// Private messages go with the account, the friend's replies included
+	conversation := models.Conversation{UserOneID: userID, UserTwoID: friendID}
+	testDB.Create(&conversation)
+	testDB.Create(&models.Message{ConversationID: conversation.ID, SenderID: userID, Content: "Hi"})
+	testDB.Create(&models.Message{ConversationID: conversation.ID, SenderID: friendID, Content: "Hello"})
+
+	deleteAccount(e, token, "PurgedPass123!")
+
+	// Nothing is purged during the grace period
+	purged, err := database.PurgeDeletedAccounts(testDB, time.Now())
+	if err != nil || purged != 0 {
+		t.Fatalf("PurgeDeletedAccounts() during the grace period = %d, %v, want 0", purged, err)
+	}
+
+	purged, err = database.PurgeDeletedAccounts(testDB, time.Now().Add(31*24*time.Hour))
+	if err != nil || purged != 1 {
+		t.Fatalf("PurgeDeletedAccounts() = %d, %v, want 1", purged, err)
+	}
+
+	var user models.User
+	if err := testDB.First(&user, "id = ?", userID).Error; err != nil {
+		t.Fatalf("anonymised user row is missing: %v", err)
+	}
+	if user.Email == "purged@example.com" || user.FirstName != "Deleted" || user.PurgedAt == nil {
+		t.Errorf("user was not anonymised: %+v", user)
+	}
+
+	for name, model := range map[string]interface{}{
+		"workout sessions": &models.WorkoutSession{},
+		"weight logs":      &models.WeightLog{},
+		"refresh tokens":   &models.RefreshToken{},
+		"personal records": &models.PersonalRecord{},
+	} {
+		var count int64
+		testDB.Unscoped().Model(model).Where("user_id = ?", userID).Count(&count)
+		if count != 0 {
+			t.Errorf("%d %s left after purge", count, name)
+		}
+	}
+
+	var messageCount, conversationCount int64
+	testDB.Unscoped().Model(&models.Message{}).Where("conversation_id = ?", conversation.ID).Count(&messageCount)
+	testDB.Unscoped().Model(&models.Conversation{}).Where("id = ?", conversation.ID).Count(&conversationCount)
+	if messageCount != 0 || conversationCount != 0 {
+		t.Errorf("%d messages and %d conversations left after purge", messageCount, conversationCount)
+	}
+
+	var kept models.WorkoutComment
+	if err := testDB.First(&kept, "id = ?", comment.ID).Error; err != nil || kept.Content != "Great session!" {
+		t.Errorf("comment on another user's workout was not kept: %v", err)
+	}
+
+	// Purging again is a no-op, and the old credentials no longer work
+	purged, err = database.PurgeDeletedAccounts(testDB, time.Now().Add(31*24*time.Hour))
+	if err != nil || purged != 0 {
+		t.Errorf("second PurgeDeletedAccounts() = %d, %v, want 0", purged, err)
+	}
+	e.POST("/api/v1/auth/login").
+		WithJSON(map[string]interface{}{"email": "purged@example.com", "password": "PurgedPass123!"}).
+		Expect().
+		Status(http.StatusUnauthorized)
+}
@@ -0,0 +1,158 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+)
+
+const importTestGPX = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="Watch" xmlns="http://www.topografix.com/GPX/1/1"
+  xmlns:gpxtpx="http://www.garmin.com/xmlschemas/TrackPointExtension/v1">
+  <trk>
+    <type>running</type>
+    <trkseg>
+      <trkpt lat="48.8566" lon="2.3522"><ele>35.0</ele><time>2024-05-01T07:00:00Z</time>
+        <extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>120</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions>
+      </trkpt>
+      <trkpt lat="48.8656" lon="2.3522"><ele>45.0</ele><time>2024-05-01T07:05:00Z</time>
+        <extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>150</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions>
+      </trkpt>
+      <trkpt lat="48.8746" lon="2.3522"><ele>40.0</ele><time>2024-05-01T07:10:00Z</time>
+        <extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>162</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions>
+      </trkpt>
+    </trkseg>
+  </trk>
+</gpx>`
+
+func TestActivityImport(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Import GPX", func(t *testing.T) {
+		CleanDatabase(t)
+		testImportGPX(t, e)
+	})
+
+	t.Run("Reject Invalid Files", func(t *testing.T) {
+		CleanDatabase(t)
+		testImportInvalidFiles(t, e)
+	})
+}
+
+func testImportGPX(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "watch@example.com", "password123", "Watch", "Wearer")
+	runID := createAnalyticsExercise(e, token, "Outdoor Run")
+
+	session := e.POST("/api/v1/workout-sessions/import").
+		WithHeader("Authorization", "Bearer "+token).
+		WithMultipart().
+		WithFileBytes("file", "morning-run.gpx", []byte(importTestGPX)).
+		WithFormField("exercise_id", runID).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().
+		Value("data").Object()
+
+	session.Value("completed").Boolean().IsTrue()
+	session.Value("duration_seconds").Number().IsEqual(600)
+	sessionID := session.Value("id").String().Raw()
+
+	activity := session.Value("activity").Object()
+	activity.Value("source").String().IsEqual("gpx")
+	activity.Value("sport").String().IsEqual("running")
+	activity.Value("distance").Object().Value("distance_value").Number().InRange(1.99, 2.01)
+	activity.Value("elevation_gain_meters").Number().IsEqual(10)
+	activity.Value("elevation_loss_meters").Number().IsEqual(5)
+	activity.Value("average_heart_rate").Number().IsEqual(144)
+	activity.Value("max_heart_rate").Number().IsEqual(162)
+
+	// The run is logged as a completed set of the exercise
+	set := session.Value("blocks").Array().Value(0).Object().
+		Value("exercises").Array().Value(0).Object().
+		Value("sets").Array().Value(0).Object()
+	set.Value("completed").Boolean().IsTrue()
+	set.Value("actual_duration_seconds").Number().IsEqual(600)
+	set.Value("actual_average_heart_rate").Number().IsEqual(144)
+
+	// The same recording can't be imported twice
+	e.POST("/api/v1/workout-sessions/import").
+		WithHeader("Authorization", "Bearer "+token).
+		WithMultipart().
+		WithFileBytes("file", "morning-run-copy.gpx", []byte(importTestGPX)).
+		Expect().
+		Status(http.StatusConflict).
+		JSON().Object().
+		Value("errors").Object().
+		HasValue("session_id", sessionID)
+
+	samples := e.GET("/api/v1/workout-sessions/"+sessionID+"/activity").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("data").Object().
+		Value("samples").Array()
+	samples.Length().IsEqual(3)
+	samples.Value(1).Object().Value("offset_seconds").Number().IsEqual(300)
+	samples.Value(2).Object().Value("heart_rate").Number().IsEqual(162)
+
+	// Imported sessions show up in the session history
+	e.GET("/api/v1/workout-sessions").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("data").Array().
+		Value(0).Object().
+		Value("activity").Object().
+		HasValue("source", "gpx")
+
+	// Other users can't see the track
+	otherToken := createTestUserAndGetToken(e, "other@example.com", "password123", "Other", "User")
+	e.GET("/api/v1/workout-sessions/"+sessionID+"/activity").
+		WithHeader("Authorization", "Bearer "+otherToken).
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+func testImportInvalidFiles(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "badfile@example.com", "password123", "Bad", "File")
+
+	t.Run("Missing File", func(t *testing.T) {
+		e.POST("/api/v1/workout-sessions/import").
+			WithHeader("Authorization", "Bearer "+token).
+			WithMultipart().
+			WithFormField("notes", "no file").
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("Unsupported Format", func(t *testing.T) {
+		e.POST("/api/v1/workout-sessions/import").
+			WithHeader("Authorization", "Bearer "+token).
+			WithMultipart().
+			WithFileBytes("file", "notes.txt", []byte("just some text")).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("Track Without Points", func(t *testing.T) {
+		e.POST("/api/v1/workout-sessions/import").
+			WithHeader("Authorization", "Bearer "+token).
+			WithMultipart().
+			WithFileBytes("file", "empty.gpx", []byte(`<gpx><trk><trkseg></trkseg></trk></gpx>`)).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("Unknown Exercise", func(t *testing.T) {
+		e.POST("/api/v1/workout-sessions/import").
+			WithHeader("Authorization", "Bearer "+token).
+			WithMultipart().
+			WithFileBytes("file", "run.gpx", []byte(importTestGPX)).
+			WithFormField("exercise_id", "00000000-0000-0000-0000-000000000001").
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,507 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+)
+
+func TestAnalyticsEndpoints(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Exercise History", func(t *testing.T) {
+		CleanDatabase(t)
+		testExerciseHistory(t, e)
+	})
+
+	t.Run("Exercise History Validation", func(t *testing.T) {
+		CleanDatabase(t)
+		testExerciseHistoryValidation(t, e)
+	})
+
+	t.Run("Muscle Volume", func(t *testing.T) {
+		CleanDatabase(t)
+		testMuscleVolume(t, e)
+	})
+
+	t.Run("Trainer Views Client Muscle Volume", func(t *testing.T) {
+		CleanDatabase(t)
+		testClientMuscleVolume(t, e)
+	})
+}
+
+// createAnalyticsExercise creates an exercise and returns its ID
+func createAnalyticsExercise(e *httpexpect.Expect, token string, name string) string {
+	return e.POST("/api/v1/exercises/").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"name": name,
+		}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+}
+
+// createWorkout creates an empty workout and returns its ID
+func createWorkout(e *httpexpect.Expect, token string, title string) string {
+	return e.POST("/api/v1/workouts/").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"title": title,
+		}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+}
+
+// addPrescriptionGroup adds the workout's first prescription group and returns the created group
+func addPrescriptionGroup(e *httpexpect.Expect, token string, workoutID string, group map[string]interface{}) *httpexpect.Object {
+	group["group_order"] = 1
+	return e.POST("/api/v1/workouts/"+workoutID+"/prescriptions").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(group).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object()
+}
+
+// createAnalyticsWorkout creates a workout with a single straight-set prescription group
+func createAnalyticsWorkout(e *httpexpect.Expect, token string, title string, exercises []map[string]interface{}) string {
+	workoutID := createWorkout(e, token, title)
+	addPrescriptionGroup(e, token, workoutID, map[string]interface{}{
+		"type":      "straight",
+		"exercises": exercises,
+	})
+	return workoutID
+}
+
+// startWorkoutSession starts a session from a workout, at startedAt unless it is empty,
+// and returns the session
+func startWorkoutSession(e *httpexpect.Expect, token string, workoutID string, startedAt string) *httpexpect.Object {
+	body := map[string]interface{}{
+		"workout_id": workoutID,
+	}
+	if startedAt != "" {
+		body["started_at"] = startedAt
+	}
+
+	return e.POST("/api/v1/workout-sessions").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(body).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object()
+}
+
+// startAnalyticsSession starts a session from a workout at the given time and
+// returns the prefilled set IDs of each exercise in the first block
+func startAnalyticsSession(e *httpexpect.Expect, token string, workoutID string, startedAt string) [][]string {
+	blocks := startWorkoutSession(e, token, workoutID, startedAt).Value("blocks").Array()
+
+	var setIDs [][]string
+	for _, exercise := range blocks.Value(0).Object().Value("exercises").Array().Iter() {
+		var ids []string
+		for _, set := range exercise.Object().Value("sets").Array().Iter() {
+			ids = append(ids, set.Object().Value("id").String().Raw())
+		}
+		setIDs = append(setIDs, ids)
+	}
+	return setIDs
+}
+
+// completeAnalyticsSet completes a set with the given reps, weight (kg) and optional RPE value ID
+func completeAnalyticsSet(e *httpexpect.Expect, token string, setID string, reps int, weightKg float64, rpeValueID string) {
+	body := map[string]interface{}{
+		"actual_reps": reps,
+		"actual_weight": map[string]interface{}{
+			"weight_value": weightKg,
+			"weight_unit":  "kg",
+		},
+	}
+	if rpeValueID != "" {
+		body["rpe_value_id"] = rpeValueID
+	}
+
+	e.PUT("/api/v1/session-sets/"+setID+"/complete").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(body).
+		Expect().
+		Status(200)
+}
+
+func testExerciseHistory(t *testing.T, e *httpexpect.Expect) {
+	userToken := createTestUserAndGetToken(e, "user@example.com", "UserPass123!", "Test", "User")
+	SeedTestGlobalRPEScale(t)
+	rpe7 := GetRPEValueID(t, 7)
+	rpe9 := GetRPEValueID(t, 9)
+
+	exerciseID := createAnalyticsExercise(e, userToken, "Bench Press")
+	workoutID := createAnalyticsWorkout(e, userToken, "Push Day", []map[string]interface{}{
+		{
+			"exercise_id":    exerciseID,
+			"exercise_order": 1,
+			"sets":           2,
+			"reps":           5,
+		},
+	})
+
+	// Monday 2025-01-13: 100x5 @7, 100x5 @9
+	week1 := startAnalyticsSession(e, userToken, workoutID, "2025-01-13T10:00:00Z")
+	completeAnalyticsSet(e, userToken, week1[0][0], 5, 100, rpe7)
+	completeAnalyticsSet(e, userToken, week1[0][1], 5, 100, rpe9)
+
+	// Thursday 2025-01-16: 105x3 (second set left incomplete)
+	week1b := startAnalyticsSession(e, userToken, workoutID, "2025-01-16T10:00:00Z")
+	completeAnalyticsSet(e, userToken, week1b[0][0], 3, 105, "")
+
+	// Monday 2025-02-03: 110x2, 100x6
+	week2 := startAnalyticsSession(e, userToken, workoutID, "2025-02-03T10:00:00Z")
+	completeAnalyticsSet(e, userToken, week2[0][0], 2, 110, "")
+	completeAnalyticsSet(e, userToken, week2[0][1], 6, 100, "")
+
+	t.Run("Daily History", func(t *testing.T) {
+		response := e.GET("/api/v1/analytics/exercises/"+exerciseID+"/history").
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+
+		response.Value("success").Boolean().IsTrue()
+		data := response.Value("data").Object()
+		data.Value("exercise_name").String().IsEqual("Bench Press")
+		data.Value("group_by").String().IsEqual("day")
+
+		history := data.Value("history").Array()
+		history.Length().IsEqual(3)
+
+		day1 := history.Value(0).Object()
+		day1.Value("period_start").String().IsEqual("2025-01-13")
+		day1.Value("sessions").Number().IsEqual(1)
+		day1.Value("sets").Number().IsEqual(2)
+		day1.Value("total_reps").Number().IsEqual(10)
+		day1.Value("total_volume").Object().Value("weight_value").Number().IsEqual(1000)
+		day1.Value("top_set").Object().Value("weight").Object().Value("weight_value").Number().IsEqual(100)
+		day1.Value("average_rpe").Number().IsEqual(8)
+		// Epley: 100 * (1 + 5/30) = 116.67
+		day1.Value("estimated_1rm").Object().Value("weight_value").Number().InDelta(116.67, 0.01)
+
+		day2 := history.Value(1).Object()
+		day2.Value("period_start").String().IsEqual("2025-01-16")
+		day2.Value("sets").Number().IsEqual(1)
+		day2.NotContainsKey("average_rpe")
+	})
+
+	t.Run("Weekly History", func(t *testing.T) {
+		response := e.GET("/api/v1/analytics/exercises/"+exerciseID+"/history").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithQuery("group_by", "week").
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+
+		history := response.Value("data").Object().Value("history").Array()
+		history.Length().IsEqual(2)
+
+		week := history.Value(0).Object()
+		week.Value("period_start").String().IsEqual("2025-01-13")
+		week.Value("sessions").Number().IsEqual(2)
+		week.Value("sets").Number().IsEqual(3)
+		// Top set is the heaviest: 105 x 3
+		topSet := week.Value("top_set").Object()
+		topSet.Value("weight").Object().Value("weight_value").Number().IsEqual(105)
+		topSet.Value("reps").Number().IsEqual(3)
+	})
+
+	t.Run("Monthly History With Date Range", func(t *testing.T) {
+		response := e.GET("/api/v1/analytics/exercises/"+exerciseID+"/history").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithQuery("group_by", "month").
+			WithQuery("from", "2025-02-01").
+			WithQuery("to", "2025-02-28").
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+
+		data := response.Value("data").Object()
+		data.Value("from").String().IsEqual("2025-02-01")
+		history := data.Value("history").Array()
+		history.Length().IsEqual(1)
+
+		month := history.Value(0).Object()
+		month.Value("period_start").String().IsEqual("2025-02-01")
+		// 110*2 + 100*6 = 820
+		month.Value("total_volume").Object().Value("weight_value").Number().IsEqual(820)
+		// Best e1RM: max(110 * 1.0667, 100 * 1.2) = 120
+		month.Value("estimated_1rm").Object().Value("weight_value").Number().InDelta(120, 0.01)
+	})
+
+	t.Run("History In Preferred Unit", func(t *testing.T) {
+		e.PUT("/api/v1/user/settings").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithJSON(map[string]interface{}{
+				"preferred_weight_unit": "lb",
+			}).
+			Expect().
+			Status(200)
+
+		response := e.GET("/api/v1/analytics/exercises/"+exerciseID+"/history").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithQuery("to", "2025-01-13").
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+
+		history := response.Value("data").Object().Value("history").Array()
+		history.Length().IsEqual(1)
+		volume := history.Value(0).Object().Value("total_volume").Object()
+		volume.Value("weight_value").Number().InDelta(2204.62, 0.01)
+		volume.Value("weight_unit").String().IsEqual("lb")
+	})
+
+	t.Run("History Is Private To The User", func(t *testing.T) {
+		otherToken := createTestUserAndGetToken(e, "other@example.com", "OtherPass123!", "Other", "User")
+
+		response := e.GET("/api/v1/analytics/exercises/"+exerciseID+"/history").
+			WithHeader("Authorization", "Bearer "+otherToken).
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+
+		response.Value("data").Object().Value("history").Array().Length().IsEqual(0)
+	})
+}
+
+func testExerciseHistoryValidation(t *testing.T, e *httpexpect.Expect) {
+	userToken := createTestUserAndGetToken(e, "user@example.com", "UserPass123!", "Test", "User")
+	exerciseID := createAnalyticsExercise(e, userToken, "Squat")
+
+	t.Run("Invalid Group By", func(t *testing.T) {
+		e.GET("/api/v1/analytics/exercises/"+exerciseID+"/history").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithQuery("group_by", "year").
+			Expect().
+			Status(400)
+	})
+
+	t.Run("Invalid Date", func(t *testing.T) {
+		e.GET("/api/v1/analytics/exercises/"+exerciseID+"/history").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithQuery("from", "13/01/2025").
+			Expect().
+			Status(400)
+	})
+
+	t.Run("Reversed Date Range", func(t *testing.T) {
+		e.GET("/api/v1/analytics/exercises/"+exerciseID+"/history").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithQuery("from", "2025-02-01").
+			WithQuery("to", "2025-01-01").
+			Expect().
+			Status(400)
+	})
+
+	t.Run("Unknown Exercise", func(t *testing.T) {
+		e.GET("/api/v1/analytics/exercises/00000000-0000-0000-0000-000000000000/history").
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(404)
+	})
+}
+
+// createAnalyticsMuscleGroup creates a muscle group and assigns it to an exercise
+func createAnalyticsMuscleGroup(e *httpexpect.Expect, token string, name string, exerciseID string, primary bool) string {
+	muscleGroupID := e.POST("/api/v1/muscle-groups/").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"name":     name,
+			"category": "upper",
+		}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+
+	assignAnalyticsMuscleGroup(e, token, muscleGroupID, exerciseID, primary)
+	return muscleGroupID
+}
+
+// assignAnalyticsMuscleGroup links an existing muscle group to an exercise
+func assignAnalyticsMuscleGroup(e *httpexpect.Expect, token string, muscleGroupID string, exerciseID string, primary bool) {
+	e.POST("/api/v1/exercises/"+exerciseID+"/muscle-groups").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"muscle_group_id": muscleGroupID,
+			"primary":         primary,
+		}).
+		Expect().
+		Status(201)
+}
+
+// seedMuscleVolumeHistory logs bench press and overhead press sessions for a user
+func seedMuscleVolumeHistory(e *httpexpect.Expect, token string) {
+	benchID := createAnalyticsExercise(e, token, "Bench Press")
+	pressID := createAnalyticsExercise(e, token, "Overhead Press")
+
+	createAnalyticsMuscleGroup(e, token, "Chest", benchID, true)
+	createAnalyticsMuscleGroup(e, token, "Shoulders", pressID, true)
+	tricepsID := createAnalyticsMuscleGroup(e, token, "Triceps", benchID, false)
+	assignAnalyticsMuscleGroup(e, token, tricepsID, pressID, false)
+
+	workoutID := createAnalyticsWorkout(e, token, "Push Day", []map[string]interface{}{
+		{
+			"exercise_id":    benchID,
+			"exercise_order": 1,
+			"sets":           2,
+			"reps":           10,
+		},
+		{
+			"exercise_id":    pressID,
+			"exercise_order": 2,
+			"sets":           1,
+			"reps":           10,
+		},
+	})
+
+	// Week of 2025-01-13: bench 2 x 10 x 100kg, press 1 x 10 x 50kg
+	week1 := startAnalyticsSession(e, token, workoutID, "2025-01-14T10:00:00Z")
+	completeAnalyticsSet(e, token, week1[0][0], 10, 100, "")
+	completeAnalyticsSet(e, token, week1[0][1], 10, 100, "")
+	completeAnalyticsSet(e, token, week1[1][0], 10, 50, "")
+
+	// Week of 2025-01-27: bench 1 x 10 x 100kg
+	week3 := startAnalyticsSession(e, token, workoutID, "2025-01-28T10:00:00Z")
+	completeAnalyticsSet(e, token, week3[0][0], 10, 100, "")
+}
+
+// findMuscleGroupVolume returns the volume entry with the given name from a week
+func findMuscleGroupVolume(t *testing.T, week *httpexpect.Object, name string) *httpexpect.Object {
+	for _, value := range week.Value("muscle_groups").Array().Iter() {
+		entry := value.Object()
+		if entry.Value("name").String().Raw() == name {
+			return entry
+		}
+	}
+	t.Fatalf("Muscle group %s not found in week %s", name, week.Value("week_start").String().Raw())
+	return nil
+}
+
+func testMuscleVolume(t *testing.T, e *httpexpect.Expect) {
+	userToken := createTestUserAndGetToken(e, "user@example.com", "UserPass123!", "Test", "User")
+	seedMuscleVolumeHistory(e, userToken)
+
+	t.Run("Weekly Volume With Default Weighting", func(t *testing.T) {
+		response := e.GET("/api/v1/analytics/muscle-volume").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithQuery("from", "2025-01-13").
+			WithQuery("to", "2025-02-02").
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+
+		response.Value("success").Boolean().IsTrue()
+		data := response.Value("data").Object()
+		data.Value("secondary_weight").Number().IsEqual(0.5)
+
+		// Three ISO weeks, including the empty one in between
+		weeks := data.Value("weeks").Array()
+		weeks.Length().IsEqual(3)
+
+		week1 := weeks.Value(0).Object()
+		week1.Value("week_start").String().IsEqual("2025-01-13")
+		week1.Value("iso_week").Number().IsEqual(3)
+
+		chest := findMuscleGroupVolume(t, week1, "Chest")
+		chest.Value("sets").Number().IsEqual(2)
+		chest.Value("primary_sets").Number().IsEqual(2)
+		chest.Value("tonnage").Object().Value("weight_value").Number().IsEqual(2000)
+
+		// Triceps: 3 secondary sets at 0.5 = 1.5 sets; (2000 + 500) * 0.5 = 1250
+		triceps := findMuscleGroupVolume(t, week1, "Triceps")
+		triceps.Value("sets").Number().IsEqual(1.5)
+		triceps.Value("secondary_sets").Number().IsEqual(3)
+		triceps.Value("tonnage").Object().Value("weight_value").Number().IsEqual(1250)
+
+		shoulders := findMuscleGroupVolume(t, week1, "Shoulders")
+		shoulders.Value("sets").Number().IsEqual(1)
+
+		weeks.Value(1).Object().Value("muscle_groups").Array().Length().IsEqual(0)
+		weeks.Value(2).Object().Value("week_start").String().IsEqual("2025-01-27")
+	})
+
+	t.Run("Custom Secondary Weight", func(t *testing.T) {
+		response := e.GET("/api/v1/analytics/muscle-volume").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithQuery("from", "2025-01-13").
+			WithQuery("to", "2025-01-19").
+			WithQuery("secondary_weight", "0").
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+
+		weeks := response.Value("data").Object().Value("weeks").Array()
+		weeks.Length().IsEqual(1)
+		triceps := findMuscleGroupVolume(t, weeks.Value(0).Object(), "Triceps")
+		triceps.Value("sets").Number().IsEqual(0)
+		triceps.Value("secondary_sets").Number().IsEqual(3)
+	})
+
+	t.Run("Invalid Secondary Weight", func(t *testing.T) {
+		e.GET("/api/v1/analytics/muscle-volume").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithQuery("secondary_weight", "2").
+			Expect().
+			Status(400)
+	})
+
+	t.Run("Date Range Too Long", func(t *testing.T) {
+		e.GET("/api/v1/analytics/muscle-volume").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithQuery("from", "2024-01-01").
+			WithQuery("to", "2025-02-02").
+			Expect().
+			Status(400)
+	})
+}
+
+func testClientMuscleVolume(t *testing.T, e *httpexpect.Expect) {
+	trainerToken := createTestUserAndGetToken(e, "trainer@example.com", "TrainerPass123!", "John", "Trainer")
+	clientToken := createTestUserAndGetToken(e, "client@example.com", "ClientPass123!", "Jane", "Client")
+	strangerToken := createTestUserAndGetToken(e, "stranger@example.com", "StrangerPass123!", "Sam", "Stranger")
+
+	clientID := createActiveTrainerClientLink(t, e, trainerToken, clientToken)
+	seedMuscleVolumeHistory(e, clientToken)
+
+	t.Run("Trainer Can View Linked Client Volume", func(t *testing.T) {
+		response := e.GET("/api/v1/trainers/clients/"+clientID+"/muscle-volume").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithQuery("from", "2025-01-13").
+			WithQuery("to", "2025-01-19").
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+
+		data := response.Value("data").Object()
+		data.Value("user_id").String().IsEqual(clientID)
+		chest := findMuscleGroupVolume(t, data.Value("weeks").Array().Value(0).Object(), "Chest")
+		chest.Value("sets").Number().IsEqual(2)
+	})
+
+	t.Run("Unlinked User Cannot View Client Volume", func(t *testing.T) {
+		e.GET("/api/v1/trainers/clients/"+clientID+"/muscle-volume").
+			WithHeader("Authorization", "Bearer "+strangerToken).
+			Expect().
+			Status(403)
+	})
+}
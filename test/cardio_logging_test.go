@@ -0,0 +1,169 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+)
+
+func TestCardioLogging(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Distance Prescriptions And Actuals", func(t *testing.T) {
+		CleanDatabase(t)
+		testDistancePrescriptionsAndActuals(t, e)
+	})
+
+	t.Run("Cardio Prescription Validation", func(t *testing.T) {
+		CleanDatabase(t)
+		testCardioPrescriptionValidation(t, e)
+	})
+}
+
+func testDistancePrescriptionsAndActuals(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "runner@example.com", "password123", "Run", "Ner")
+	runID := createAnalyticsExercise(e, token, "Outdoor Run")
+
+	e.PUT("/api/v1/user/settings").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"preferred_distance_unit": "mi",
+		}).
+		Expect().
+		Status(200)
+
+	workoutID := createWorkout(e, token, "Tempo Run")
+
+	// A distance is enough to prescribe a run
+	group := addPrescriptionGroup(e, token, workoutID, map[string]interface{}{
+		"type": "straight",
+		"exercises": []map[string]interface{}{
+			{
+				"exercise_id":    runID,
+				"exercise_order": 1,
+				"sets":           1,
+				"target_distance": map[string]interface{}{
+					"distance_value": 5,
+					"distance_unit":  "km",
+				},
+				"target_pace": map[string]interface{}{
+					"seconds_per_unit": 300,
+					"distance_unit":    "km",
+				},
+				"target_heart_rate_min":  140,
+				"target_heart_rate_max":  160,
+				"target_incline_percent": 1,
+			},
+		},
+	})
+
+	// Targets come back in miles
+	run := group.Value("exercises").Array().Value(0).Object()
+	run.Value("target_distance").Object().Value("distance_value").Number().IsEqual(3.107)
+	run.Value("target_distance").Object().Value("distance_unit").String().IsEqual("mi")
+	run.Value("target_pace").Object().Value("seconds_per_unit").Number().IsEqual(482.8)
+	run.Value("target_heart_rate_min").Number().IsEqual(140)
+	run.Value("target_heart_rate_max").Number().IsEqual(160)
+
+	sessionID := startWorkoutSession(e, token, workoutID, "").Value("id").String().Raw()
+
+	exercise := e.GET("/api/v1/workout-sessions/"+sessionID).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object().
+		Value("blocks").Array().Value(0).Object().
+		Value("exercises").Array().Value(0).Object()
+	exercise.Value("prescription").Object().Value("target_distance").Object().Value("distance_value").Number().IsEqual(3.107)
+
+	// The set is pre-filled with the target distance
+	set := exercise.Value("sets").Array().Value(0).Object()
+	set.Value("actual_distance").Object().Value("distance_value").Number().IsEqual(3.107)
+	setID := set.Value("id").String().Raw()
+
+	// Log 3 miles in 24 minutes
+	logged := e.PUT("/api/v1/session-sets/"+setID).
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"actual_duration_seconds": 1440,
+			"actual_distance": map[string]interface{}{
+				"distance_value": 3,
+				"distance_unit":  "mi",
+			},
+			"actual_average_heart_rate": 152,
+			"actual_max_heart_rate":     171,
+			"completed":                 true,
+		}).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object()
+	logged.Value("actual_distance").Object().Value("distance_value").Number().IsEqual(3)
+	logged.Value("actual_pace").Object().Value("seconds_per_unit").Number().IsEqual(480)
+	logged.Value("actual_average_heart_rate").Number().IsEqual(152)
+	logged.Value("actual_max_heart_rate").Number().IsEqual(171)
+
+	// The session can be viewed in kilometres instead
+	e.GET("/api/v1/workout-sessions/"+sessionID).
+		WithHeader("Authorization", "Bearer "+token).
+		WithQuery("distance_unit", "km").
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object().
+		Value("blocks").Array().Value(0).Object().
+		Value("exercises").Array().Value(0).Object().
+		Value("sets").Array().Value(0).Object().
+		Value("actual_distance").Object().
+		HasValue("distance_value", 4.828).
+		HasValue("distance_unit", "km")
+}
+
+func testCardioPrescriptionValidation(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "rower@example.com", "password123", "Row", "Er")
+	rowID := createAnalyticsExercise(e, token, "Rowing Machine")
+
+	workoutID := createWorkout(e, token, "Erg Day")
+
+	prescribe := func(exercise map[string]interface{}) *httpexpect.Response {
+		exercise["exercise_id"] = rowID
+		exercise["exercise_order"] = 1
+		return e.POST("/api/v1/workouts/"+workoutID+"/prescriptions").
+			WithHeader("Authorization", "Bearer "+token).
+			WithJSON(map[string]interface{}{
+				"type":        "straight",
+				"group_order": 1,
+				"exercises":   []map[string]interface{}{exercise},
+			}).
+			Expect()
+	}
+
+	t.Run("Reject Unknown Distance Unit", func(t *testing.T) {
+		prescribe(map[string]interface{}{
+			"target_distance": map[string]interface{}{"distance_value": 2000, "distance_unit": "yd"},
+		}).Status(400)
+	})
+
+	t.Run("Reject Inverted Heart Rate Range", func(t *testing.T) {
+		prescribe(map[string]interface{}{
+			"target_distance":       map[string]interface{}{"distance_value": 2000, "distance_unit": "m"},
+			"target_heart_rate_min": 170,
+			"target_heart_rate_max": 150,
+		}).Status(400)
+	})
+
+	t.Run("Accept Intervals In Metres", func(t *testing.T) {
+		prescribe(map[string]interface{}{
+			"sets":            4,
+			"target_distance": map[string]interface{}{"distance_value": 500, "distance_unit": "m"},
+		}).
+			Status(201).
+			JSON().Object().
+			Value("data").Object().
+			Value("exercises").Array().Value(0).Object().
+			Value("target_distance").Object().
+			HasValue("distance_value", 0.5).
+			HasValue("distance_unit", "km")
+	})
+}
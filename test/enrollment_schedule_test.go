@@ -0,0 +1,481 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect/v2"
+)
+
+func TestEnrollmentScheduleEndpoints(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Rolling Schedule", func(t *testing.T) {
+		CleanDatabase(t)
+		testRollingSchedule(t, e)
+	})
+
+	t.Run("Calendar Schedule", func(t *testing.T) {
+		CleanDatabase(t)
+		testCalendarSchedule(t, e)
+	})
+
+	t.Run("Schedule Validation", func(t *testing.T) {
+		CleanDatabase(t)
+		testScheduleValidation(t, e)
+	})
+
+	t.Run("Enrollment Adherence", func(t *testing.T) {
+		CleanDatabase(t)
+		testEnrollmentAdherence(t, e)
+	})
+
+	t.Run("Enrollment Auto Completes", func(t *testing.T) {
+		CleanDatabase(t)
+		testEnrollmentAutoCompletes(t, e)
+	})
+}
+
+// createSchedulePlan creates a workout plan with the given workouts per week index
+func createSchedulePlan(e *httpexpect.Expect, token string, title string, workoutsByWeek map[int][]string) string {
+	planID := e.POST("/api/v1/workout-plans/").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"title": title,
+		}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+
+	for week := 0; week < len(workoutsByWeek); week++ {
+		for _, workoutID := range workoutsByWeek[week] {
+			e.POST("/api/v1/workout-plans/"+planID+"/workouts").
+				WithHeader("Authorization", "Bearer "+token).
+				WithJSON(map[string]interface{}{
+					"workout_id": workoutID,
+					"week_index": week,
+				}).
+				Expect().
+				Status(201)
+		}
+	}
+
+	return planID
+}
+
+// completeScheduleSession starts and ends a session of the workout
+func completeScheduleSession(e *httpexpect.Expect, token string, workoutID string) {
+	completeEnrollmentSession(e, token, map[string]interface{}{
+		"workout_id": workoutID,
+	})
+}
+
+// completeEnrollmentSession starts a session with the given request body, ends it
+// and returns the ended session
+func completeEnrollmentSession(e *httpexpect.Expect, token string, body map[string]interface{}) *httpexpect.Object {
+	sessionID := e.POST("/api/v1/workout-sessions").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(body).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+
+	return e.PUT("/api/v1/workout-sessions/"+sessionID+"/end").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{}).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object()
+}
+
+func testRollingSchedule(t *testing.T, e *httpexpect.Expect) {
+	userToken := createTestUserAndGetToken(e, "user@example.com", "UserPass123!", "Test", "User")
+
+	pushID := createWorkout(e, userToken, "Push")
+	pullID := createWorkout(e, userToken, "Pull")
+	deloadID := createWorkout(e, userToken, "Deload")
+	planID := createSchedulePlan(e, userToken, "Push Pull", map[int][]string{
+		0: {pushID, pullID},
+		1: {deloadID},
+	})
+
+	today := time.Now().UTC()
+	enrollmentID := e.POST("/api/v1/enrollments/").
+		WithHeader("Authorization", "Bearer "+userToken).
+		WithJSON(map[string]interface{}{
+			"plan_id":       planID,
+			"start_date":    today.Format("2006-01-02"),
+			"days_per_week": 2,
+		}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+
+	t.Run("Projects Workouts From Today", func(t *testing.T) {
+		data := e.GET("/api/v1/enrollments/"+enrollmentID+"/schedule").
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(200).
+			JSON().Object().
+			Value("data").Object()
+
+		data.Value("schedule_mode").String().IsEqual("rolling")
+		data.Value("total_workouts").Number().IsEqual(4)
+		data.Value("from").String().IsEqual(today.Format("2006-01-02"))
+
+		workouts := data.Value("workouts").Array()
+		workouts.Length().IsEqual(4)
+
+		first := workouts.Value(0).Object()
+		first.Value("date").String().IsEqual(today.Format("2006-01-02"))
+		first.Value("workout_id").String().IsEqual(pushID)
+		first.Value("status").String().IsEqual("today")
+
+		second := workouts.Value(1).Object()
+		second.Value("date").String().IsEqual(today.AddDate(0, 0, 3).Format("2006-01-02"))
+		second.Value("workout_id").String().IsEqual(pullID)
+		second.Value("status").String().IsEqual("upcoming")
+
+		// Week 1 has a single workout that fills both training days
+		third := workouts.Value(2).Object()
+		third.Value("week_index").Number().IsEqual(1)
+		third.Value("workout_id").String().IsEqual(deloadID)
+		workouts.Value(3).Object().Value("workout_id").String().IsEqual(deloadID)
+	})
+
+	t.Run("Today Lists Scheduled Workout", func(t *testing.T) {
+		workouts := e.GET("/api/v1/me/today").
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(200).
+			JSON().Object().
+			Value("data").Object().Value("workouts").Array()
+
+		workouts.Length().IsEqual(1)
+		workout := workouts.Value(0).Object()
+		workout.Value("enrollment_id").String().IsEqual(enrollmentID)
+		workout.Value("plan_title").String().IsEqual("Push Pull")
+		workout.Value("workout_id").String().IsEqual(pushID)
+	})
+
+	t.Run("Unscheduled Workout Does Not Advance", func(t *testing.T) {
+		completeScheduleSession(e, userToken, pullID)
+
+		e.GET("/api/v1/enrollments/"+enrollmentID).
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(200).
+			JSON().Object().
+			Value("data").Object().Value("current_index").Number().IsEqual(0)
+	})
+
+	t.Run("Completing Scheduled Workout Advances", func(t *testing.T) {
+		completeScheduleSession(e, userToken, pushID)
+
+		e.GET("/api/v1/enrollments/"+enrollmentID).
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(200).
+			JSON().Object().
+			Value("data").Object().Value("current_index").Number().IsEqual(1)
+
+		data := e.GET("/api/v1/enrollments/"+enrollmentID+"/schedule").
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(200).
+			JSON().Object().
+			Value("data").Object()
+
+		workouts := data.Value("workouts").Array()
+		workouts.Length().IsEqual(3)
+		next := workouts.Value(0).Object()
+		next.Value("index").Number().IsEqual(1)
+		next.Value("workout_id").String().IsEqual(pullID)
+		next.Value("date").String().IsEqual(today.AddDate(0, 0, 1).Format("2006-01-02"))
+
+		e.GET("/api/v1/me/today").
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(200).
+			JSON().Object().
+			Value("data").Object().Value("workouts").Array().IsEmpty()
+	})
+}
+
+func testCalendarSchedule(t *testing.T, e *httpexpect.Expect) {
+	userToken := createTestUserAndGetToken(e, "user@example.com", "UserPass123!", "Test", "User")
+
+	fullBodyID := createWorkout(e, userToken, "Full Body")
+	planID := createSchedulePlan(e, userToken, "Daily", map[int][]string{
+		0: {fullBodyID},
+	})
+
+	today := time.Now().UTC()
+	start := today.AddDate(0, 0, -2)
+	enrollmentID := e.POST("/api/v1/enrollments/").
+		WithHeader("Authorization", "Bearer "+userToken).
+		WithJSON(map[string]interface{}{
+			"plan_id":            planID,
+			"start_date":         start.Format("2006-01-02"),
+			"days_per_week":      7,
+			"schedule_mode":      "calendar",
+			"preferred_weekdays": []int{0, 1, 2, 3, 4, 5, 6},
+		}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+
+	scheduleURL := "/api/v1/enrollments/" + enrollmentID + "/schedule"
+
+	t.Run("Past Workouts Are Missed", func(t *testing.T) {
+		workouts := e.GET(scheduleURL).
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithQuery("from", start.Format("2006-01-02")).
+			WithQuery("to", today.AddDate(0, 0, 1).Format("2006-01-02")).
+			Expect().
+			Status(200).
+			JSON().Object().
+			Value("data").Object().Value("workouts").Array()
+
+		workouts.Length().IsEqual(4)
+		workouts.Value(0).Object().Value("status").String().IsEqual("missed")
+		workouts.Value(1).Object().Value("status").String().IsEqual("missed")
+		workouts.Value(2).Object().Value("status").String().IsEqual("today")
+		workouts.Value(2).Object().Value("index").Number().IsEqual(2)
+		workouts.Value(3).Object().Value("status").String().IsEqual("upcoming")
+	})
+
+	t.Run("Completing Today Advances Past Today", func(t *testing.T) {
+		completeScheduleSession(e, userToken, fullBodyID)
+
+		e.GET("/api/v1/enrollments/"+enrollmentID).
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(200).
+			JSON().Object().
+			Value("data").Object().Value("current_index").Number().IsEqual(3)
+
+		workouts := e.GET("/api/v1/me/today").
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(200).
+			JSON().Object().
+			Value("data").Object().Value("workouts").Array()
+
+		workouts.Length().IsEqual(1)
+		workouts.Value(0).Object().Value("status").String().IsEqual("completed")
+	})
+}
+
+func testScheduleValidation(t *testing.T, e *httpexpect.Expect) {
+	userToken := createTestUserAndGetToken(e, "user@example.com", "UserPass123!", "Test", "User")
+	otherToken := createTestUserAndGetToken(e, "other@example.com", "OtherPass123!", "Other", "User")
+
+	workoutID := createWorkout(e, userToken, "Full Body")
+	planID := createSchedulePlan(e, userToken, "Plan", map[int][]string{
+		0: {workoutID},
+	})
+
+	enrollmentID := e.POST("/api/v1/enrollments/").
+		WithHeader("Authorization", "Bearer "+userToken).
+		WithJSON(map[string]interface{}{
+			"plan_id":       planID,
+			"start_date":    time.Now().UTC().Format("2006-01-02"),
+			"days_per_week": 3,
+		}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+
+	scheduleURL := "/api/v1/enrollments/" + enrollmentID + "/schedule"
+
+	t.Run("Invalid Date", func(t *testing.T) {
+		e.GET(scheduleURL).
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithQuery("from", "not-a-date").
+			Expect().
+			Status(400)
+	})
+
+	t.Run("Range Too Long", func(t *testing.T) {
+		e.GET(scheduleURL).
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithQuery("from", "2025-01-01").
+			WithQuery("to", "2026-06-01").
+			Expect().
+			Status(400)
+	})
+
+	t.Run("Other User's Enrollment", func(t *testing.T) {
+		e.GET(scheduleURL).
+			WithHeader("Authorization", "Bearer "+otherToken).
+			Expect().
+			Status(404)
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		e.GET(scheduleURL).
+			Expect().
+			Status(401)
+	})
+}
+
+func testEnrollmentAdherence(t *testing.T, e *httpexpect.Expect) {
+	userToken := createTestUserAndGetToken(e, "user@example.com", "UserPass123!", "Test", "User")
+	otherToken := createTestUserAndGetToken(e, "other@example.com", "OtherPass123!", "Other", "User")
+
+	fullBodyID := createWorkout(e, userToken, "Full Body")
+	otherWorkoutID := createWorkout(e, userToken, "Mobility")
+	planID := createSchedulePlan(e, userToken, "Daily", map[int][]string{
+		0: {fullBodyID},
+	})
+
+	today := time.Now().UTC()
+	enrollmentID := e.POST("/api/v1/enrollments/").
+		WithHeader("Authorization", "Bearer "+userToken).
+		WithJSON(map[string]interface{}{
+			"plan_id":            planID,
+			"start_date":         today.AddDate(0, 0, -3).Format("2006-01-02"),
+			"days_per_week":      7,
+			"schedule_mode":      "calendar",
+			"preferred_weekdays": []int{0, 1, 2, 3, 4, 5, 6},
+		}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+
+	t.Run("Session Linked To Scheduled Day", func(t *testing.T) {
+		session := completeEnrollmentSession(e, userToken, map[string]interface{}{
+			"enrollment_id":   enrollmentID,
+			"scheduled_index": 1,
+			"started_at":      today.AddDate(0, 0, -2).Format(time.RFC3339),
+		})
+
+		session.Value("enrollment_id").String().IsEqual(enrollmentID)
+		session.Value("scheduled_index").Number().IsEqual(1)
+		session.Value("workout_id").String().IsEqual(fullBodyID)
+	})
+
+	t.Run("Session Matched To Today", func(t *testing.T) {
+		session := completeEnrollmentSession(e, userToken, map[string]interface{}{
+			"enrollment_id": enrollmentID,
+		})
+
+		session.Value("scheduled_index").Number().IsEqual(3)
+	})
+
+	t.Run("Adherence Report", func(t *testing.T) {
+		data := e.GET("/api/v1/enrollments/"+enrollmentID+"/adherence").
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(200).
+			JSON().Object().
+			Value("data").Object()
+
+		data.Value("plan_title").String().IsEqual("Daily")
+		data.Value("total_workouts").Number().IsEqual(7)
+		data.Value("scheduled_workouts").Number().IsEqual(4)
+		data.Value("completed_workouts").Number().IsEqual(2)
+		data.Value("missed_workouts").Number().IsEqual(2)
+		data.Value("remaining_workouts").Number().IsEqual(5)
+		data.Value("completion_percent").Number().IsEqual(28.6)
+		data.Value("adherence_percent").Number().IsEqual(50)
+		data.Value("current_streak").Number().IsEqual(1)
+		data.Value("longest_streak").Number().IsEqual(1)
+
+		workouts := data.Value("workouts").Array()
+		workouts.Length().IsEqual(4)
+		workouts.Value(0).Object().Value("status").String().IsEqual("missed")
+		workouts.Value(1).Object().Value("status").String().IsEqual("completed")
+		workouts.Value(1).Object().Value("session_id").String().NotEmpty()
+		workouts.Value(2).Object().Value("status").String().IsEqual("missed")
+		workouts.Value(3).Object().Value("status").String().IsEqual("completed")
+	})
+
+	t.Run("Invalid Enrollment Links", func(t *testing.T) {
+		e.POST("/api/v1/workout-sessions").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithJSON(map[string]interface{}{
+				"enrollment_id":   enrollmentID,
+				"scheduled_index": 99,
+			}).
+			Expect().
+			Status(400)
+
+		e.POST("/api/v1/workout-sessions").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithJSON(map[string]interface{}{
+				"enrollment_id":   enrollmentID,
+				"scheduled_index": 4,
+				"workout_id":      otherWorkoutID,
+			}).
+			Expect().
+			Status(400)
+
+		e.POST("/api/v1/workout-sessions").
+			WithHeader("Authorization", "Bearer "+otherToken).
+			WithJSON(map[string]interface{}{
+				"enrollment_id": enrollmentID,
+			}).
+			Expect().
+			Status(404)
+
+		e.GET("/api/v1/enrollments/"+enrollmentID+"/adherence").
+			WithHeader("Authorization", "Bearer "+otherToken).
+			Expect().
+			Status(404)
+	})
+}
+
+func testEnrollmentAutoCompletes(t *testing.T, e *httpexpect.Expect) {
+	userToken := createTestUserAndGetToken(e, "user@example.com", "UserPass123!", "Test", "User")
+
+	workoutID := createWorkout(e, userToken, "Full Body")
+	planID := createSchedulePlan(e, userToken, "Single Session", map[int][]string{
+		0: {workoutID},
+	})
+
+	enrollmentID := e.POST("/api/v1/enrollments/").
+		WithHeader("Authorization", "Bearer "+userToken).
+		WithJSON(map[string]interface{}{
+			"plan_id":       planID,
+			"start_date":    time.Now().UTC().Format("2006-01-02"),
+			"days_per_week": 1,
+		}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+
+	completeEnrollmentSession(e, userToken, map[string]interface{}{
+		"enrollment_id": enrollmentID,
+	})
+
+	data := e.GET("/api/v1/enrollments/"+enrollmentID+"/adherence").
+		WithHeader("Authorization", "Bearer "+userToken).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object()
+
+	data.Value("status").String().IsEqual("completed")
+	data.Value("completed_workouts").Number().IsEqual(1)
+	data.Value("completion_percent").Number().IsEqual(100)
+	data.Value("adherence_percent").Number().IsEqual(100)
+
+	// Completed enrollments no longer accept sessions
+	e.POST("/api/v1/workout-sessions").
+		WithHeader("Authorization", "Bearer "+userToken).
+		WithJSON(map[string]interface{}{
+			"enrollment_id": enrollmentID,
+		}).
+		Expect().
+		Status(400)
+}
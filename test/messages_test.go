@@ -0,0 +1,350 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect/v2"
+)
+
+func TestMessages(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Trainer And Client Conversation", func(t *testing.T) {
+		CleanDatabase(t)
+		testTrainerClientConversation(t, e)
+	})
+
+	t.Run("Friends Conversation", func(t *testing.T) {
+		CleanDatabase(t)
+		testFriendsConversation(t, e)
+	})
+
+	t.Run("Polling For Messages", func(t *testing.T) {
+		CleanDatabase(t)
+		testPollMessages(t, e)
+	})
+}
+
+// startConversation opens the conversation with another user and returns its ID
+func startConversation(e *httpexpect.Expect, token string, userID string) string {
+	return e.POST("/api/v1/conversations").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{"user_id": userID}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+}
+
+// sendMessage sends a message in a conversation and returns it
+func sendMessage(e *httpexpect.Expect, token string, conversationID string, body map[string]interface{}) *httpexpect.Object {
+	return e.POST("/api/v1/conversations/"+conversationID+"/messages").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(body).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().
+		Value("data").Object()
+}
+
+func testTrainerClientConversation(t *testing.T, e *httpexpect.Expect) {
+	trainerToken := createTestUserAndGetToken(e, "trainer@example.com", "password123", "Tina", "Trainer")
+	clientToken := createTestUserAndGetToken(e, "client@example.com", "password123", "Carl", "Client")
+	strangerToken := createTestUserAndGetToken(e, "stranger@example.com", "password123", "Sam", "Stranger")
+	clientID := createActiveTrainerClientLink(t, e, trainerToken, clientToken)
+	trainerID := getTestUserID(e, trainerToken)
+
+	t.Run("Only Trainers, Clients And Friends", func(t *testing.T) {
+		e.POST("/api/v1/conversations").
+			WithHeader("Authorization", "Bearer "+strangerToken).
+			WithJSON(map[string]interface{}{"user_id": clientID}).
+			Expect().
+			Status(http.StatusForbidden)
+
+		e.POST("/api/v1/conversations").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			WithJSON(map[string]interface{}{"user_id": clientID}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	conversationID := startConversation(e, trainerToken, clientID)
+
+	t.Run("One Conversation Per Pair", func(t *testing.T) {
+		e.POST("/api/v1/conversations").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			WithJSON(map[string]interface{}{"user_id": trainerID}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			HasValue("id", conversationID).
+			Value("other_user").Object().HasValue("first_name", "Tina")
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		e.POST("/api/v1/conversations/"+conversationID+"/messages").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithJSON(map[string]interface{}{"content": "   "}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	sendMessage(e, trainerToken, conversationID, map[string]interface{}{"content": "How did the squats feel?"}).
+		HasValue("sender_id", trainerID).
+		NotContainsKey("read_at")
+
+	t.Run("Attachments", func(t *testing.T) {
+		workoutID := createWorkout(e, trainerToken, "Leg Day")
+		sendMessage(e, trainerToken, conversationID, map[string]interface{}{"workout_id": workoutID}).
+			Value("workout").Object().HasValue("title", "Leg Day")
+
+		// The attached workout is shared with the client
+		e.GET("/api/v1/workouts/"+workoutID).
+			WithHeader("Authorization", "Bearer "+clientToken).
+			Expect().
+			Status(http.StatusOK)
+
+		// Only the sender's own workouts
+		e.POST("/api/v1/conversations/"+conversationID+"/messages").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			WithJSON(map[string]interface{}{"workout_id": createWorkout(e, strangerToken, "Not Mine")}).
+			Expect().
+			Status(http.StatusNotFound)
+
+		session := completeEnrollmentSession(e, clientToken, map[string]interface{}{"workout_id": workoutID})
+		attachment := sendMessage(e, clientToken, conversationID, map[string]interface{}{
+			"content":            "Done!",
+			"workout_session_id": session.Value("id").String().Raw(),
+		}).Value("workout_session").Object()
+		attachment.HasValue("user_id", clientID)
+		attachment.HasValue("completed", true)
+
+		e.POST("/api/v1/conversations/"+conversationID+"/messages").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			WithJSON(map[string]interface{}{
+				"workout_id":         workoutID,
+				"workout_session_id": session.Value("id").String().Raw(),
+			}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("Only Participants See The Conversation", func(t *testing.T) {
+		e.GET("/api/v1/conversations/"+conversationID+"/messages").
+			WithHeader("Authorization", "Bearer "+strangerToken).
+			Expect().
+			Status(http.StatusNotFound)
+
+		e.POST("/api/v1/conversations/"+conversationID+"/messages").
+			WithHeader("Authorization", "Bearer "+strangerToken).
+			WithJSON(map[string]interface{}{"content": "Hi"}).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+
+	t.Run("Unread Counts", func(t *testing.T) {
+		unread := e.GET("/api/v1/me/unread-messages").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object()
+		unread.HasValue("total", 2)
+		unread.Value("conversations").Array().Value(0).Object().
+			HasValue("conversation_id", conversationID).
+			HasValue("unread_count", 2)
+
+		conversations := e.GET("/api/v1/conversations").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object()
+		conversations.Value("meta").Object().HasValue("total_items", 1)
+		conversation := conversations.Value("data").Array().Value(0).Object()
+		conversation.HasValue("unread_count", 2)
+		conversation.HasValue("can_send", true)
+		conversation.Value("last_message").Object().HasValue("content", "Done!")
+	})
+
+	t.Run("Read Receipts", func(t *testing.T) {
+		e.PUT("/api/v1/conversations/"+conversationID+"/read").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			HasValue("read_count", 2)
+
+		e.GET("/api/v1/me/unread-messages").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			HasValue("total", 0)
+
+		// Newest first: the client's message is still unread by the trainer
+		messages := e.GET("/api/v1/conversations/"+conversationID+"/messages").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array()
+		messages.Length().IsEqual(3)
+		messages.Value(0).Object().HasValue("content", "Done!").NotContainsKey("read_at")
+		messages.Value(2).Object().HasValue("content", "How did the squats feel?").ContainsKey("read_at")
+	})
+
+	t.Run("Ended Links Keep The History Read-Only", func(t *testing.T) {
+		linkID := e.GET("/api/v1/trainers/clients").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array().Value(0).Object().Value("id").String().Raw()
+
+		e.DELETE("/api/v1/trainers/clients/"+linkID).
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusOK)
+
+		e.POST("/api/v1/conversations/"+conversationID+"/messages").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			WithJSON(map[string]interface{}{"content": "Are you there?"}).
+			Expect().
+			Status(http.StatusForbidden)
+
+		e.GET("/api/v1/conversations/"+conversationID).
+			WithHeader("Authorization", "Bearer "+clientToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			HasValue("can_send", false)
+
+		e.GET("/api/v1/conversations/"+conversationID+"/messages").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array().Length().IsEqual(3)
+	})
+}
+
+func testFriendsConversation(t *testing.T, e *httpexpect.Expect) {
+	userToken := createTestUserAndGetToken(e, "user@example.com", "password123", "Uma", "User")
+	friendToken := createTestUserAndGetToken(e, "friend@example.com", "password123", "Fred", "Friend")
+	makeFriends(e, userToken, friendToken)
+
+	conversationID := startConversation(e, userToken, getTestUserID(e, friendToken))
+	sendMessage(e, userToken, conversationID, map[string]interface{}{"content": "Gym tonight?"})
+	sendMessage(e, friendToken, conversationID, map[string]interface{}{"content": "Sure"})
+
+	t.Run("Friends Cannot See Each Other's Sessions", func(t *testing.T) {
+		session := completeEnrollmentSession(e, userToken, map[string]interface{}{})
+		e.POST("/api/v1/conversations/"+conversationID+"/messages").
+			WithHeader("Authorization", "Bearer "+friendToken).
+			WithJSON(map[string]interface{}{"workout_session_id": session.Value("id").String().Raw()}).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+
+	e.GET("/api/v1/me/unread-messages").
+		WithHeader("Authorization", "Bearer "+friendToken).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("data").Object().
+		HasValue("total", 1)
+}
+
+func testPollMessages(t *testing.T, e *httpexpect.Expect) {
+	trainerToken := createTestUserAndGetToken(e, "trainer@example.com", "password123", "Tina", "Trainer")
+	clientToken := createTestUserAndGetToken(e, "client@example.com", "password123", "Carl", "Client")
+	clientID := createActiveTrainerClientLink(t, e, trainerToken, clientToken)
+
+	conversationID := startConversation(e, trainerToken, clientID)
+	before := time.Now().UTC().Add(-time.Minute).Format(time.RFC3339Nano)
+	sendMessage(e, trainerToken, conversationID, map[string]interface{}{"content": "First"})
+
+	t.Run("Validation", func(t *testing.T) {
+		e.GET("/api/v1/conversations/"+conversationID+"/messages/poll").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			Expect().
+			Status(http.StatusBadRequest)
+
+		e.GET("/api/v1/conversations/"+conversationID+"/messages/poll").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			WithQuery("since", "yesterday").
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	poll := e.GET("/api/v1/conversations/"+conversationID+"/messages/poll").
+		WithHeader("Authorization", "Bearer "+clientToken).
+		WithQuery("since", before).
+		WithQuery("timeout", 0).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("data").Object()
+	poll.Value("messages").Array().Length().IsEqual(1)
+	polledAt := poll.Value("polled_at").String().Raw()
+
+	t.Run("Times Out Without New Messages", func(t *testing.T) {
+		e.GET("/api/v1/conversations/"+conversationID+"/messages/poll").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			WithQuery("since", polledAt).
+			WithQuery("timeout", 1).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			Value("messages").Array().IsEmpty()
+	})
+
+	t.Run("Returns As Soon As A Message Arrives", func(t *testing.T) {
+		go func() {
+			time.Sleep(500 * time.Millisecond)
+			sendMessage(e, trainerToken, conversationID, map[string]interface{}{"content": "Second"})
+		}()
+
+		started := time.Now()
+		messages := e.GET("/api/v1/conversations/"+conversationID+"/messages/poll").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			WithQuery("since", polledAt).
+			WithQuery("timeout", 10).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			Value("messages").Array()
+		messages.Length().IsEqual(1)
+		messages.Value(0).Object().HasValue("content", "Second")
+
+		if waited := time.Since(started); waited > 5*time.Second {
+			t.Errorf("poll waited %s for a message sent after 500ms", waited)
+		}
+	})
+
+	t.Run("Read Receipts Are Polled Too", func(t *testing.T) {
+		since := time.Now().UTC().Format(time.RFC3339Nano)
+		e.PUT("/api/v1/conversations/"+conversationID+"/read").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			Expect().
+			Status(http.StatusOK)
+
+		e.GET("/api/v1/conversations/"+conversationID+"/messages/poll").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithQuery("since", since).
+			WithQuery("timeout", 0).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			Value("messages").Array().Length().IsEqual(2)
+	})
+}
@@ -0,0 +1,485 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+)
+
+func TestPersonalRecordEndpoints(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Records Detected On Set Completion", func(t *testing.T) {
+		CleanDatabase(t)
+		testRecordsOnSetCompletion(t, e)
+	})
+
+	t.Run("Records Detected On Session End", func(t *testing.T) {
+		CleanDatabase(t)
+		testRecordsOnSessionEnd(t, e)
+	})
+
+	t.Run("Records Recomputed On Set Edit And Delete", func(t *testing.T) {
+		CleanDatabase(t)
+		testRecordsRecomputed(t, e)
+	})
+
+	t.Run("Records Removed With Deleted Session Data", func(t *testing.T) {
+		CleanDatabase(t)
+		testRecordsRemovedWithDeletedData(t, e)
+	})
+
+	t.Run("Records Use Preferred Weight Unit", func(t *testing.T) {
+		CleanDatabase(t)
+		testRecordsPreferredUnit(t, e)
+	})
+
+	t.Run("Records Validation", func(t *testing.T) {
+		CleanDatabase(t)
+		testRecordsValidation(t, e)
+	})
+}
+
+// createRecordsTestSession creates an exercise, a workout prescribing it and a session
+// started from that workout. Returns the exercise ID, session ID and prefilled set IDs.
+func createRecordsTestSession(e *httpexpect.Expect, token string, exerciseName string, prescription map[string]interface{}) (string, string, []string) {
+	exerciseID := createAnalyticsExercise(e, token, exerciseName)
+	prescription["exercise_id"] = exerciseID
+	prescription["exercise_order"] = 1
+	workoutID := createAnalyticsWorkout(e, token, exerciseName+" Day", []map[string]interface{}{prescription})
+
+	session := startWorkoutSession(e, token, workoutID, "")
+	sets := session.Value("blocks").Array().Value(0).Object().
+		Value("exercises").Array().Value(0).Object().
+		Value("sets").Array()
+
+	return exerciseID, session.Value("id").String().Raw(), setIDs(sets)
+}
+
+func testRecordsOnSetCompletion(t *testing.T, e *httpexpect.Expect) {
+	userToken := createTestUserAndGetToken(e, "user@example.com", "UserPass123!", "Test", "User")
+
+	exerciseID, _, setIDs := createRecordsTestSession(e, userToken, "Bench Press", map[string]interface{}{
+		"sets": 3,
+		"reps": 5,
+		"target_weight": map[string]interface{}{
+			"weight_value": 100.0,
+			"weight_unit":  "kg",
+		},
+	})
+
+	t.Run("First Completed Set Sets All Records", func(t *testing.T) {
+		response := e.PUT("/api/v1/session-sets/"+setIDs[0]+"/complete").
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+
+		data := response.Value("data").Object()
+		data.Value("completed").Boolean().IsTrue()
+		// max_weight, rep_max and one estimated_1rm per formula
+		data.Value("new_records").Array().Length().IsEqual(4)
+	})
+
+	t.Run("Lighter Set Does Not Set Records", func(t *testing.T) {
+		response := e.PUT("/api/v1/session-sets/"+setIDs[1]+"/complete").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithJSON(map[string]interface{}{
+				"actual_weight": map[string]interface{}{
+					"weight_value": 90.0,
+					"weight_unit":  "kg",
+				},
+				"actual_reps": 5,
+			}).
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+
+		response.Value("data").Object().NotContainsKey("new_records")
+	})
+
+	t.Run("Heavier Set Beats Weight Records", func(t *testing.T) {
+		response := e.PUT("/api/v1/session-sets/"+setIDs[2]+"/complete").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithJSON(map[string]interface{}{
+				"actual_weight": map[string]interface{}{
+					"weight_value": 110.0,
+					"weight_unit":  "kg",
+				},
+				"actual_reps": 3,
+			}).
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+
+		records := response.Value("data").Object().Value("new_records").Array()
+		// max_weight and both estimated_1rm records, but not rep_max (3 < 5)
+		records.Length().IsEqual(3)
+		for _, record := range records.Iter() {
+			record.Object().Value("record_type").String().NotEqual("rep_max")
+		}
+	})
+
+	t.Run("Get User Records", func(t *testing.T) {
+		response := e.GET("/api/v1/user/records").
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+
+		response.Value("success").Boolean().IsTrue()
+		records := response.Value("data").Array()
+		// Only the default (epley) estimated_1rm is returned
+		records.Length().IsEqual(3)
+
+		for _, value := range records.Iter() {
+			record := value.Object()
+			record.Value("exercise_name").String().IsEqual("Bench Press")
+			switch record.Value("record_type").String().Raw() {
+			case "max_weight":
+				record.Value("weight").Object().Value("weight_value").Number().IsEqual(110)
+				record.Value("reps").Number().IsEqual(3)
+			case "rep_max":
+				record.Value("reps").Number().IsEqual(5)
+				record.Value("weight").Object().Value("weight_value").Number().IsEqual(100)
+			case "estimated_1rm":
+				record.Value("formula").String().IsEqual("epley")
+				// 110kg x 3 (121.0) beats 100kg x 5 (116.67)
+				record.Value("estimated_1rm").Object().Value("weight_value").Number().InDelta(121.0, 0.01)
+			}
+		}
+	})
+
+	t.Run("Get User Records With Brzycki Formula", func(t *testing.T) {
+		response := e.GET("/api/v1/user/records").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithQuery("formula", "brzycki").
+			WithQuery("type", "estimated_1rm").
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+
+		records := response.Value("data").Array()
+		records.Length().IsEqual(1)
+		record := records.Value(0).Object()
+		record.Value("formula").String().IsEqual("brzycki")
+		// 110 * 36 / 34 = 116.47
+		record.Value("estimated_1rm").Object().Value("weight_value").Number().InDelta(116.47, 0.01)
+	})
+
+	t.Run("Get Exercise Records", func(t *testing.T) {
+		response := e.GET("/api/v1/exercises/"+exerciseID+"/records").
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+
+		response.Value("data").Array().Length().IsEqual(3)
+	})
+
+	t.Run("Records Are Private To The User", func(t *testing.T) {
+		otherToken := createTestUserAndGetToken(e, "other@example.com", "OtherPass123!", "Other", "User")
+
+		response := e.GET("/api/v1/exercises/"+exerciseID+"/records").
+			WithHeader("Authorization", "Bearer "+otherToken).
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+
+		response.Value("data").Array().Length().IsEqual(0)
+	})
+}
+
+func testRecordsRecomputed(t *testing.T, e *httpexpect.Expect) {
+	userToken := createTestUserAndGetToken(e, "user@example.com", "UserPass123!", "Test", "User")
+
+	exerciseID, _, setIDs := createRecordsTestSession(e, userToken, "Deadlift", map[string]interface{}{
+		"sets": 2,
+		"reps": 5,
+		"target_weight": map[string]interface{}{
+			"weight_value": 100.0,
+			"weight_unit":  "kg",
+		},
+	})
+
+	e.PUT("/api/v1/session-sets/"+setIDs[0]+"/complete").
+		WithHeader("Authorization", "Bearer "+userToken).
+		Expect().
+		Status(200)
+
+	e.PUT("/api/v1/session-sets/"+setIDs[1]+"/complete").
+		WithHeader("Authorization", "Bearer "+userToken).
+		WithJSON(map[string]interface{}{
+			"actual_weight": map[string]interface{}{
+				"weight_value": 110.0,
+				"weight_unit":  "kg",
+			},
+			"actual_reps": 3,
+		}).
+		Expect().
+		Status(200)
+
+	getRecord := func(recordType string) *httpexpect.Array {
+		return e.GET("/api/v1/exercises/"+exerciseID+"/records").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithQuery("type", recordType).
+			Expect().
+			Status(200).
+			JSON().Object().
+			Value("data").Array()
+	}
+
+	getRecord("max_weight").Value(0).Object().
+		Value("weight").Object().Value("weight_value").Number().IsEqual(110)
+
+	t.Run("Lowering A Record Set Restores The Previous Best", func(t *testing.T) {
+		e.PUT("/api/v1/session-sets/"+setIDs[1]).
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithJSON(map[string]interface{}{
+				"actual_weight": map[string]interface{}{
+					"weight_value": 95.0,
+					"weight_unit":  "kg",
+				},
+			}).
+			Expect().
+			Status(200)
+
+		record := getRecord("max_weight").Value(0).Object()
+		record.Value("weight").Object().Value("weight_value").Number().IsEqual(100)
+		record.Value("session_set_id").String().IsEqual(setIDs[0])
+	})
+
+	t.Run("Uncompleting A Record Set Removes Its Records", func(t *testing.T) {
+		e.PUT("/api/v1/session-sets/"+setIDs[0]).
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithJSON(map[string]interface{}{
+				"completed": false,
+			}).
+			Expect().
+			Status(200)
+
+		record := getRecord("max_weight").Value(0).Object()
+		record.Value("weight").Object().Value("weight_value").Number().IsEqual(95)
+		record.Value("session_set_id").String().IsEqual(setIDs[1])
+		getRecord("rep_max").Value(0).Object().Value("reps").Number().IsEqual(3)
+	})
+
+	t.Run("Deleting The Last Completed Set Removes All Records", func(t *testing.T) {
+		e.DELETE("/api/v1/session-sets/"+setIDs[1]).
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(204)
+
+		e.GET("/api/v1/exercises/"+exerciseID+"/records").
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(200).
+			JSON().Object().
+			Value("data").Array().Length().IsEqual(0)
+	})
+}
+
+func testRecordsRemovedWithDeletedData(t *testing.T, e *httpexpect.Expect) {
+	userToken := createTestUserAndGetToken(e, "user@example.com", "UserPass123!", "Test", "User")
+
+	// completedSession starts a session for a new exercise and completes its first set
+	completedSession := func(exerciseName string) (string, string) {
+		exerciseID, sessionID, setIDs := createRecordsTestSession(e, userToken, exerciseName, map[string]interface{}{
+			"sets": 1,
+			"reps": 5,
+			"target_weight": map[string]interface{}{
+				"weight_value": 100.0,
+				"weight_unit":  "kg",
+			},
+		})
+		e.PUT("/api/v1/session-sets/"+setIDs[0]+"/complete").
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(200)
+		return exerciseID, sessionID
+	}
+
+	getBlock := func(sessionID string) *httpexpect.Object {
+		return e.GET("/api/v1/workout-sessions/"+sessionID).
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(200).
+			JSON().Object().
+			Value("data").Object().
+			Value("blocks").Array().Value(0).Object()
+	}
+
+	expectNoRecords := func(exerciseID string) {
+		e.GET("/api/v1/exercises/"+exerciseID+"/records").
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(200).
+			JSON().Object().
+			Value("data").Array().Length().IsEqual(0)
+	}
+
+	t.Run("Deleting A Session Exercise", func(t *testing.T) {
+		exerciseID, sessionID := completedSession("Bench Press")
+		sessionExerciseID := getBlock(sessionID).Value("exercises").Array().Value(0).Object().Value("id").String().Raw()
+
+		e.DELETE("/api/v1/session-exercises/"+sessionExerciseID).
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(204)
+
+		expectNoRecords(exerciseID)
+	})
+
+	t.Run("Deleting A Session Block", func(t *testing.T) {
+		exerciseID, sessionID := completedSession("Overhead Press")
+		blockID := getBlock(sessionID).Value("id").String().Raw()
+
+		e.DELETE("/api/v1/session-blocks/"+blockID).
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(204)
+
+		expectNoRecords(exerciseID)
+	})
+
+	t.Run("Deleting A Session", func(t *testing.T) {
+		exerciseID, sessionID := completedSession("Barbell Row")
+
+		e.DELETE("/api/v1/workout-sessions/"+sessionID).
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(204)
+
+		expectNoRecords(exerciseID)
+	})
+}
+
+func testRecordsOnSessionEnd(t *testing.T, e *httpexpect.Expect) {
+	userToken := createTestUserAndGetToken(e, "user@example.com", "UserPass123!", "Test", "User")
+
+	exerciseID, sessionID, setIDs := createRecordsTestSession(e, userToken, "Plank", map[string]interface{}{
+		"sets":         2,
+		"hold_seconds": 60,
+	})
+
+	// Mark a set completed through the regular update endpoint (no record detection)
+	e.PUT("/api/v1/session-sets/"+setIDs[0]).
+		WithHeader("Authorization", "Bearer "+userToken).
+		WithJSON(map[string]interface{}{
+			"actual_duration_seconds": 75,
+			"completed":               true,
+		}).
+		Expect().
+		Status(200)
+
+	t.Run("End Session Detects Records", func(t *testing.T) {
+		response := e.PUT("/api/v1/workout-sessions/"+sessionID+"/end").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithJSON(map[string]interface{}{}).
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+
+		records := response.Value("data").Object().Value("new_records").Array()
+		records.Length().IsEqual(1)
+		record := records.Value(0).Object()
+		record.Value("record_type").String().IsEqual("longest_hold")
+		record.Value("duration_seconds").Number().IsEqual(75)
+		record.Value("session_id").String().IsEqual(sessionID)
+	})
+
+	t.Run("Get Exercise Records Filtered By Type", func(t *testing.T) {
+		response := e.GET("/api/v1/exercises/"+exerciseID+"/records").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithQuery("type", "longest_hold").
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+
+		records := response.Value("data").Array()
+		records.Length().IsEqual(1)
+		records.Value(0).Object().Value("duration_seconds").Number().IsEqual(75)
+	})
+}
+
+func testRecordsPreferredUnit(t *testing.T, e *httpexpect.Expect) {
+	userToken := createTestUserAndGetToken(e, "user@example.com", "UserPass123!", "Test", "User")
+
+	e.PUT("/api/v1/user/settings").
+		WithHeader("Authorization", "Bearer "+userToken).
+		WithJSON(map[string]interface{}{
+			"preferred_weight_unit": "lb",
+		}).
+		Expect().
+		Status(200)
+
+	_, _, setIDs := createRecordsTestSession(e, userToken, "Squat", map[string]interface{}{
+		"sets": 1,
+		"reps": 1,
+		"target_weight": map[string]interface{}{
+			"weight_value": 225.0,
+			"weight_unit":  "lb",
+		},
+	})
+
+	e.PUT("/api/v1/session-sets/"+setIDs[0]+"/complete").
+		WithHeader("Authorization", "Bearer "+userToken).
+		Expect().
+		Status(200)
+
+	response := e.GET("/api/v1/user/records").
+		WithHeader("Authorization", "Bearer "+userToken).
+		WithQuery("type", "max_weight").
+		Expect().
+		Status(200).
+		JSON().
+		Object()
+
+	records := response.Value("data").Array()
+	records.Length().IsEqual(1)
+	weight := records.Value(0).Object().Value("weight").Object()
+	weight.Value("weight_value").Number().InDelta(225.0, 0.1)
+	weight.Value("weight_unit").String().IsEqual("lb")
+}
+
+func testRecordsValidation(t *testing.T, e *httpexpect.Expect) {
+	userToken := createTestUserAndGetToken(e, "user@example.com", "UserPass123!", "Test", "User")
+
+	t.Run("Invalid Formula", func(t *testing.T) {
+		e.GET("/api/v1/user/records").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithQuery("formula", "lombardi").
+			Expect().
+			Status(400)
+	})
+
+	t.Run("Invalid Type", func(t *testing.T) {
+		e.GET("/api/v1/user/records").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithQuery("type", "fastest_mile").
+			Expect().
+			Status(400)
+	})
+
+	t.Run("Unknown Exercise", func(t *testing.T) {
+		e.GET("/api/v1/exercises/00000000-0000-0000-0000-000000000000/records").
+			WithHeader("Authorization", "Bearer "+userToken).
+			Expect().
+			Status(404)
+	})
+
+	t.Run("Requires Authentication", func(t *testing.T) {
+		e.GET("/api/v1/user/records").
+			Expect().
+			Status(401)
+	})
+}
@@ -0,0 +1,296 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+)
+
+func TestProgressionRules(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Linear Progression", func(t *testing.T) {
+		CleanDatabase(t)
+		testLinearProgression(t, e)
+	})
+
+	t.Run("Double Progression", func(t *testing.T) {
+		CleanDatabase(t)
+		testDoubleProgression(t, e)
+	})
+
+	t.Run("RPE Progression", func(t *testing.T) {
+		CleanDatabase(t)
+		testRPEProgression(t, e)
+	})
+
+	t.Run("Percent Of 1RM Progression", func(t *testing.T) {
+		CleanDatabase(t)
+		testPercentOneRepMaxProgression(t, e)
+	})
+
+	t.Run("Progression Validation", func(t *testing.T) {
+		CleanDatabase(t)
+		testProgressionValidation(t, e)
+	})
+}
+
+// createProgressionWorkout creates a workout with a single straight-set group using the given
+// progression rule and returns the workout ID and the created group
+func createProgressionWorkout(e *httpexpect.Expect, token string, exerciseID string, exercise map[string]interface{}, progression map[string]interface{}) (string, *httpexpect.Object) {
+	workoutID := createWorkout(e, token, "Progression Day")
+
+	exercise["exercise_id"] = exerciseID
+	exercise["exercise_order"] = 1
+
+	group := addPrescriptionGroup(e, token, workoutID, map[string]interface{}{
+		"type":        "straight",
+		"progression": progression,
+		"exercises":   []map[string]interface{}{exercise},
+	})
+
+	return workoutID, group
+}
+
+// startProgressionSession starts a session from a workout and returns the prefilled sets
+// of the first exercise
+func startProgressionSession(e *httpexpect.Expect, token string, workoutID string, startedAt string) *httpexpect.Array {
+	return startWorkoutSession(e, token, workoutID, startedAt).
+		Value("blocks").Array().Value(0).Object().
+		Value("exercises").Array().Value(0).Object().
+		Value("sets").Array()
+}
+
+// setIDs returns the IDs of the given session sets
+func setIDs(sets *httpexpect.Array) []string {
+	var ids []string
+	for _, set := range sets.Iter() {
+		ids = append(ids, set.Object().Value("id").String().Raw())
+	}
+	return ids
+}
+
+func testLinearProgression(t *testing.T, e *httpexpect.Expect) {
+	userToken := createTestUserAndGetToken(e, "user@example.com", "UserPass123!", "Test", "User")
+	exerciseID := createAnalyticsExercise(e, userToken, "Squat")
+
+	workoutID, group := createProgressionWorkout(e, userToken, exerciseID, map[string]interface{}{
+		"sets": 2,
+		"reps": 5,
+		"target_weight": map[string]interface{}{
+			"weight_value": 100,
+			"weight_unit":  "kg",
+		},
+	}, map[string]interface{}{
+		"type": "linear",
+		"increment": map[string]interface{}{
+			"weight_value": 2.5,
+			"weight_unit":  "kg",
+		},
+	})
+
+	progression := group.Value("progression").Object()
+	progression.Value("type").String().IsEqual("linear")
+	progression.Value("increment").Object().Value("weight_value").Number().IsEqual(2.5)
+
+	t.Run("First Session Uses Target", func(t *testing.T) {
+		sets := startProgressionSession(e, userToken, workoutID, "2025-01-06T10:00:00Z")
+		sets.Length().IsEqual(2)
+		sets.Value(0).Object().Value("actual_weight").Object().Value("weight_value").Number().IsEqual(100)
+		sets.Value(0).Object().Value("actual_reps").Number().IsEqual(5)
+
+		for _, setID := range setIDs(sets) {
+			completeAnalyticsSet(e, userToken, setID, 5, 100, "")
+		}
+	})
+
+	t.Run("Hitting Target Adds Increment", func(t *testing.T) {
+		sets := startProgressionSession(e, userToken, workoutID, "2025-01-08T10:00:00Z")
+		sets.Value(0).Object().Value("actual_weight").Object().Value("weight_value").Number().IsEqual(102.5)
+		sets.Value(1).Object().Value("actual_weight").Object().Value("weight_value").Number().IsEqual(102.5)
+
+		ids := setIDs(sets)
+		completeAnalyticsSet(e, userToken, ids[0], 5, 102.5, "")
+		completeAnalyticsSet(e, userToken, ids[1], 3, 102.5, "")
+	})
+
+	t.Run("Missing Target Repeats Load", func(t *testing.T) {
+		sets := startProgressionSession(e, userToken, workoutID, "2025-01-10T10:00:00Z")
+		sets.Value(0).Object().Value("actual_weight").Object().Value("weight_value").Number().IsEqual(102.5)
+	})
+}
+
+func testDoubleProgression(t *testing.T, e *httpexpect.Expect) {
+	userToken := createTestUserAndGetToken(e, "user@example.com", "UserPass123!", "Test", "User")
+	exerciseID := createAnalyticsExercise(e, userToken, "Dumbbell Row")
+
+	workoutID, _ := createProgressionWorkout(e, userToken, exerciseID, map[string]interface{}{
+		"sets": 2,
+		"reps": 10,
+		"target_weight": map[string]interface{}{
+			"weight_value": 40,
+			"weight_unit":  "kg",
+		},
+	}, map[string]interface{}{
+		"type":     "double_progression",
+		"min_reps": 8,
+		"max_reps": 12,
+		"increment": map[string]interface{}{
+			"weight_value": 2.5,
+			"weight_unit":  "kg",
+		},
+	})
+
+	sets := startProgressionSession(e, userToken, workoutID, "2025-01-06T10:00:00Z")
+	sets.Value(0).Object().Value("actual_reps").Number().IsEqual(8)
+	ids := setIDs(sets)
+	completeAnalyticsSet(e, userToken, ids[0], 10, 40, "")
+	completeAnalyticsSet(e, userToken, ids[1], 9, 40, "")
+
+	// Worst set at 40 kg was 9 reps: keep the load, aim for one more rep
+	sets = startProgressionSession(e, userToken, workoutID, "2025-01-08T10:00:00Z")
+	sets.Value(0).Object().Value("actual_weight").Object().Value("weight_value").Number().IsEqual(40)
+	sets.Value(0).Object().Value("actual_reps").Number().IsEqual(10)
+	for _, setID := range setIDs(sets) {
+		completeAnalyticsSet(e, userToken, setID, 12, 40, "")
+	}
+
+	// Every set reached the ceiling: add load and reset reps
+	sets = startProgressionSession(e, userToken, workoutID, "2025-01-10T10:00:00Z")
+	sets.Value(0).Object().Value("actual_weight").Object().Value("weight_value").Number().IsEqual(42.5)
+	sets.Value(0).Object().Value("actual_reps").Number().IsEqual(8)
+}
+
+func testRPEProgression(t *testing.T, e *httpexpect.Expect) {
+	userToken := createTestUserAndGetToken(e, "user@example.com", "UserPass123!", "Test", "User")
+	SeedTestGlobalRPEScale(t)
+	rpe7 := GetRPEValueID(t, 7)
+
+	exerciseID := createAnalyticsExercise(e, userToken, "Bench Press")
+	workoutID, _ := createProgressionWorkout(e, userToken, exerciseID, map[string]interface{}{
+		"sets": 1,
+		"reps": 5,
+		"target_weight": map[string]interface{}{
+			"weight_value": 100,
+			"weight_unit":  "kg",
+		},
+	}, map[string]interface{}{
+		"type":       "rpe",
+		"target_rpe": 8,
+		"increment": map[string]interface{}{
+			"weight_value": 2.5,
+			"weight_unit":  "kg",
+		},
+	})
+
+	sets := startProgressionSession(e, userToken, workoutID, "2025-01-06T10:00:00Z")
+	completeAnalyticsSet(e, userToken, setIDs(sets)[0], 5, 100, rpe7)
+
+	// 5 reps at RPE 7 was easier than the RPE 8 target: ~102.7 kg rounded to the increment
+	sets = startProgressionSession(e, userToken, workoutID, "2025-01-08T10:00:00Z")
+	sets.Value(0).Object().Value("actual_weight").Object().Value("weight_value").Number().IsEqual(102.5)
+	sets.Value(0).Object().Value("actual_reps").Number().IsEqual(5)
+}
+
+func testPercentOneRepMaxProgression(t *testing.T, e *httpexpect.Expect) {
+	userToken := createTestUserAndGetToken(e, "user@example.com", "UserPass123!", "Test", "User")
+	exerciseID := createAnalyticsExercise(e, userToken, "Deadlift")
+
+	workoutID, _ := createProgressionWorkout(e, userToken, exerciseID, map[string]interface{}{
+		"sets": 1,
+		"reps": 3,
+		"target_weight": map[string]interface{}{
+			"weight_value": 60,
+			"weight_unit":  "kg",
+		},
+	}, map[string]interface{}{
+		"type":        "percent_1rm",
+		"percent_1rm": 75,
+		"increment": map[string]interface{}{
+			"weight_value": 2.5,
+			"weight_unit":  "kg",
+		},
+	})
+
+	t.Run("No Estimate Uses Target", func(t *testing.T) {
+		sets := startProgressionSession(e, userToken, workoutID, "2025-01-06T10:00:00Z")
+		sets.Value(0).Object().Value("actual_weight").Object().Value("weight_value").Number().IsEqual(60)
+
+		// 100 kg x 5 → estimated 1RM of 116.67 kg (Epley)
+		completeAnalyticsSet(e, userToken, setIDs(sets)[0], 5, 100, "")
+	})
+
+	t.Run("Load From Estimated 1RM", func(t *testing.T) {
+		sets := startProgressionSession(e, userToken, workoutID, "2025-01-08T10:00:00Z")
+		sets.Value(0).Object().Value("actual_weight").Object().Value("weight_value").Number().IsEqual(87.5)
+	})
+}
+
+func testProgressionValidation(t *testing.T, e *httpexpect.Expect) {
+	userToken := createTestUserAndGetToken(e, "user@example.com", "UserPass123!", "Test", "User")
+	exerciseID := createAnalyticsExercise(e, userToken, "Squat")
+
+	workoutID := e.POST("/api/v1/workouts/").
+		WithHeader("Authorization", "Bearer "+userToken).
+		WithJSON(map[string]interface{}{
+			"title": "Validation Day",
+		}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+
+	createGroup := func(progression map[string]interface{}) *httpexpect.Response {
+		return e.POST("/api/v1/workouts/"+workoutID+"/prescriptions").
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithJSON(map[string]interface{}{
+				"type":        "straight",
+				"group_order": 1,
+				"progression": progression,
+				"exercises": []map[string]interface{}{
+					{"exercise_id": exerciseID, "exercise_order": 1, "sets": 3, "reps": 5},
+				},
+			}).
+			Expect()
+	}
+
+	t.Run("Invalid Type", func(t *testing.T) {
+		createGroup(map[string]interface{}{"type": "wave"}).Status(400)
+	})
+
+	t.Run("Linear Requires Increment", func(t *testing.T) {
+		createGroup(map[string]interface{}{"type": "linear"}).Status(400)
+	})
+
+	t.Run("Double Progression Requires Valid Range", func(t *testing.T) {
+		createGroup(map[string]interface{}{
+			"type":      "double_progression",
+			"min_reps":  12,
+			"max_reps":  8,
+			"increment": map[string]interface{}{"weight_value": 2.5, "weight_unit": "kg"},
+		}).Status(400)
+	})
+
+	t.Run("Percent Out Of Range", func(t *testing.T) {
+		createGroup(map[string]interface{}{"type": "percent_1rm", "percent_1rm": 150}).Status(400)
+	})
+
+	t.Run("Rule Can Be Removed", func(t *testing.T) {
+		groupID := createGroup(map[string]interface{}{"type": "rpe", "target_rpe": 8}).
+			Status(201).
+			JSON().Object().
+			Value("data").Object().Value("group_id").String().Raw()
+
+		data := e.PUT("/api/v1/workouts/"+workoutID+"/prescriptions/"+groupID).
+			WithHeader("Authorization", "Bearer "+userToken).
+			WithJSON(map[string]interface{}{
+				"progression": map[string]interface{}{"type": ""},
+			}).
+			Expect().
+			Status(200).
+			JSON().Object().
+			Value("data").Object()
+
+		data.NotContainsKey("progression")
+	})
+}
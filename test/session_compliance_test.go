@@ -0,0 +1,192 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect/v2"
+)
+
+func TestSessionCompliance(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Session Compliance", func(t *testing.T) {
+		CleanDatabase(t)
+		testSessionCompliance(t, e)
+	})
+
+	t.Run("Compliance Trend", func(t *testing.T) {
+		CleanDatabase(t)
+		testComplianceTrend(t, e)
+	})
+}
+
+func testSessionCompliance(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "compliant@example.com", "password123", "Comp", "Liant")
+	squatID := createAnalyticsExercise(e, token, "Back Squat")
+	curlID := createAnalyticsExercise(e, token, "Dumbbell Curl")
+
+	block := startStructuredSession(e, token, map[string]interface{}{
+		"type": "straight",
+		"exercises": []map[string]interface{}{
+			{"exercise_id": squatID, "exercise_order": 1, "sets": 3, "reps": 5},
+		},
+	})
+	squat := setIDs(blockExerciseSets(block, 0))
+
+	// Two of three sets, the second one short on reps
+	logSessionSet(e, token, squat[0], 5, 100)
+	logSessionSet(e, token, squat[1], 3, 100)
+
+	sessions := e.GET("/api/v1/workout-sessions").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Array()
+	sessionID := sessions.Value(0).Object().Value("id").String().Raw()
+
+	// An improvised block is counted but not scored
+	addSessionBlock(e, token, sessionID, map[string]interface{}{
+		"exercises": []map[string]interface{}{
+			{"exercise_id": curlID, "sets": 2},
+		},
+	})
+
+	compliance := e.GET("/api/v1/workout-sessions/"+sessionID).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object().
+		Value("compliance").Object()
+	compliance.Value("prescribed_sets").Number().IsEqual(3)
+	compliance.Value("completed_sets").Number().IsEqual(2)
+	compliance.Value("completion_percent").Number().IsEqual(66.7)
+	compliance.Value("unprescribed_exercises").Number().IsEqual(1)
+	compliance.Value("skipped_exercises").Number().IsEqual(0)
+
+	blocks := compliance.Value("blocks").Array()
+	blocks.Length().IsEqual(1)
+	exercise := blocks.Value(0).Object().Value("exercises").Array().Value(0).Object()
+	exercise.Value("exercise_id").String().IsEqual(squatID)
+	exercise.Value("sets_delta").Number().IsEqual(-1)
+	exercise.Value("prescribed_reps").Number().IsEqual(5)
+	exercise.Value("average_reps").Number().IsEqual(4)
+	exercise.Value("reps_delta").Number().IsEqual(-1)
+	// 2/3 of the sets at 4/5 of the reps
+	exercise.Value("score").Number().IsEqual(53.3)
+
+	// A free-form session has nothing to comply with
+	freeFormID := startFreeFormSession(e, token)
+	e.GET("/api/v1/workout-sessions/"+freeFormID).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object().
+		NotContainsKey("compliance")
+}
+
+func testComplianceTrend(t *testing.T, e *httpexpect.Expect) {
+	trainerToken := createTestUserAndGetToken(e, "trainer@example.com", "TrainerPass123!", "John", "Trainer")
+	clientToken := createTestUserAndGetToken(e, "client@example.com", "ClientPass123!", "Jane", "Client")
+	strangerToken := createTestUserAndGetToken(e, "stranger@example.com", "StrangerPass123!", "Sam", "Stranger")
+	clientID := createActiveTrainerClientLink(t, e, trainerToken, clientToken)
+	squatID := createAnalyticsExercise(e, clientToken, "Back Squat")
+
+	block := startStructuredSession(e, clientToken, map[string]interface{}{
+		"type": "straight",
+		"exercises": []map[string]interface{}{
+			{"exercise_id": squatID, "exercise_order": 1, "sets": 2, "reps": 5},
+		},
+	})
+	logSessionSet(e, clientToken, setIDs(blockExerciseSets(block, 0))[0], 5, 100)
+
+	sessionID := e.GET("/api/v1/workout-sessions").
+		WithHeader("Authorization", "Bearer "+clientToken).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Array().
+		Value(0).Object().Value("id").String().Raw()
+
+	// Sessions in progress are not part of the trend
+	e.GET("/api/v1/analytics/compliance").
+		WithHeader("Authorization", "Bearer "+clientToken).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object().
+		Value("trend").Array().IsEmpty()
+
+	e.PUT("/api/v1/workout-sessions/"+sessionID+"/end").
+		WithHeader("Authorization", "Bearer "+clientToken).
+		WithJSON(map[string]interface{}{}).
+		Expect().
+		Status(200)
+
+	t.Run("User Views Own Trend", func(t *testing.T) {
+		data := e.GET("/api/v1/analytics/compliance").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			WithQuery("group_by", "day").
+			Expect().
+			Status(200).
+			JSON().Object().
+			Value("data").Object()
+		data.Value("group_by").String().IsEqual("day")
+		trend := data.Value("trend").Array()
+		trend.Length().IsEqual(1)
+		bucket := trend.Value(0).Object()
+		bucket.Value("sessions").Number().IsEqual(1)
+		bucket.Value("average_completion_percent").Number().IsEqual(50)
+		bucket.Value("average_score").Number().IsEqual(50)
+	})
+
+	t.Run("Invalid Grouping", func(t *testing.T) {
+		e.GET("/api/v1/analytics/compliance").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			WithQuery("group_by", "year").
+			Expect().
+			Status(400)
+	})
+
+	t.Run("Trend Defaults To The Last 90 Days", func(t *testing.T) {
+		today := time.Now().UTC()
+		data := e.GET("/api/v1/analytics/compliance").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			Expect().
+			Status(200).
+			JSON().Object().
+			Value("data").Object()
+		data.Value("to").String().IsEqual(today.Format("2006-01-02"))
+		data.Value("from").String().IsEqual(today.AddDate(0, 0, -89).Format("2006-01-02"))
+	})
+
+	t.Run("Trend Date Range Too Long", func(t *testing.T) {
+		e.GET("/api/v1/analytics/compliance").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			WithQuery("from", "2024-01-01").
+			WithQuery("to", "2025-02-02").
+			Expect().
+			Status(400)
+	})
+
+	t.Run("Trainer Views Client Trend", func(t *testing.T) {
+		data := e.GET("/api/v1/trainers/clients/"+clientID+"/compliance").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(200).
+			JSON().Object().
+			Value("data").Object()
+		data.Value("user_id").String().IsEqual(clientID)
+		data.Value("trend").Array().Length().IsEqual(1)
+	})
+
+	t.Run("Unlinked User Cannot View Client Trend", func(t *testing.T) {
+		e.GET("/api/v1/trainers/clients/"+clientID+"/compliance").
+			WithHeader("Authorization", "Bearer "+strangerToken).
+			Expect().
+			Status(403)
+	})
+}
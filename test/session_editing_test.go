@@ -0,0 +1,443 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+)
+
+func TestSessionEditing(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Build Free-Form Session", func(t *testing.T) {
+		CleanDatabase(t)
+		testBuildFreeFormSession(t, e)
+	})
+
+	t.Run("Reorder And Delete Blocks", func(t *testing.T) {
+		CleanDatabase(t)
+		testReorderAndDeleteBlocks(t, e)
+	})
+
+	t.Run("Reorder And Delete Exercises", func(t *testing.T) {
+		CleanDatabase(t)
+		testReorderAndDeleteExercises(t, e)
+	})
+
+	t.Run("Swap Exercise Mid-Session", func(t *testing.T) {
+		CleanDatabase(t)
+		testSwapSessionExercise(t, e)
+	})
+
+	t.Run("Session Editing Authorization", func(t *testing.T) {
+		CleanDatabase(t)
+		testSessionEditingAuthorization(t, e)
+	})
+}
+
+// startFreeFormSession starts a session without a workout and returns its ID
+func startFreeFormSession(e *httpexpect.Expect, token string) string {
+	return e.POST("/api/v1/workout-sessions").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+}
+
+// addSessionBlock adds a block to a session and returns it
+func addSessionBlock(e *httpexpect.Expect, token string, sessionID string, body map[string]interface{}) *httpexpect.Object {
+	return e.POST("/api/v1/workout-sessions/"+sessionID+"/blocks").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(body).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object()
+}
+
+// getSessionBlocks returns the blocks of a session
+func getSessionBlocks(e *httpexpect.Expect, token string, sessionID string) *httpexpect.Array {
+	return e.GET("/api/v1/workout-sessions/"+sessionID).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object().Value("blocks").Array()
+}
+
+// getSessionBlock returns a single session block
+func getSessionBlock(e *httpexpect.Expect, token string, blockID string) *httpexpect.Object {
+	return e.GET("/api/v1/session-blocks/"+blockID).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object()
+}
+
+func testBuildFreeFormSession(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "freeform@example.com", "password123", "Free", "Form")
+	squatID := createAnalyticsExercise(e, token, "Back Squat")
+	lungeID := createAnalyticsExercise(e, token, "Lunge")
+	curlID := createAnalyticsExercise(e, token, "Dumbbell Curl")
+
+	sessionID := startFreeFormSession(e, token)
+	getSessionBlocks(e, token, sessionID).IsEmpty()
+
+	// A block with an exercise and its empty sets
+	block := addSessionBlock(e, token, sessionID, map[string]interface{}{
+		"exercises": []map[string]interface{}{
+			{"exercise_id": squatID, "sets": 3},
+		},
+	})
+	block.Value("block_order").Number().IsEqual(1)
+	sets := blockExerciseSets(block, 0)
+	sets.Length().IsEqual(3)
+	for i := 0; i < 3; i++ {
+		set := sets.Value(i).Object()
+		set.Value("set_number").Number().IsEqual(i + 1)
+		set.Value("sequence_order").Number().IsEqual(i + 1)
+		set.Value("completed").Boolean().IsFalse()
+	}
+
+	// Exercises are appended, or inserted at the requested position
+	blockID := block.Value("id").String().Raw()
+	e.POST("/api/v1/session-blocks/"+blockID+"/exercises").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"exercise_id": curlID,
+			"sets":        2,
+		}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object().
+		HasValue("exercise_order", 2)
+
+	e.POST("/api/v1/session-blocks/"+blockID+"/exercises").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"exercise_id":    lungeID,
+			"exercise_order": 2,
+			"sets":           1,
+			"notes":          "Walking lunges",
+		}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object().
+		HasValue("exercise_order", 2).
+		HasValue("notes", "Walking lunges")
+
+	exercises := getSessionBlocks(e, token, sessionID).Value(0).Object().Value("exercises").Array()
+	exercises.Length().IsEqual(3)
+	for i, name := range []string{"Back Squat", "Lunge", "Dumbbell Curl"} {
+		exercise := exercises.Value(i).Object()
+		exercise.Value("exercise_order").Number().IsEqual(i + 1)
+		exercise.Value("exercise_name").String().IsEqual(name)
+	}
+
+	// Sets are performed exercise by exercise: squat 1-3, lunge 4, curl 5-6
+	exercises.Value(1).Object().Value("sets").Array().Value(0).Object().Value("sequence_order").Number().IsEqual(4)
+	exercises.Value(2).Object().Value("sets").Array().Value(1).Object().Value("sequence_order").Number().IsEqual(6)
+
+	// A block inserted at the front pushes the others down
+	addSessionBlock(e, token, sessionID, map[string]interface{}{
+		"block_order": 1,
+		"exercises": []map[string]interface{}{
+			{"exercise_id": lungeID},
+		},
+	}).Value("block_order").Number().IsEqual(1)
+
+	blocks := getSessionBlocks(e, token, sessionID)
+	blocks.Length().IsEqual(2)
+	blocks.Value(0).Object().Value("block_order").Number().IsEqual(1)
+	blocks.Value(1).Object().Value("block_order").Number().IsEqual(2)
+	blocks.Value(1).Object().Value("id").String().IsEqual(blockID)
+}
+
+func testReorderAndDeleteBlocks(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "blocks@example.com", "password123", "Block", "Editor")
+	squatID := createAnalyticsExercise(e, token, "Back Squat")
+
+	sessionID := startFreeFormSession(e, token)
+	var blockIDs []string
+	for i := 0; i < 3; i++ {
+		block := addSessionBlock(e, token, sessionID, map[string]interface{}{
+			"exercises": []map[string]interface{}{
+				{"exercise_id": squatID, "sets": 1},
+			},
+		})
+		blockIDs = append(blockIDs, block.Value("id").String().Raw())
+	}
+
+	// Reverse the blocks
+	blocks := e.PUT("/api/v1/workout-sessions/"+sessionID+"/blocks/reorder").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"block_ids": []string{blockIDs[2], blockIDs[1], blockIDs[0]},
+		}).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object().Value("blocks").Array()
+	for i, id := range []string{blockIDs[2], blockIDs[1], blockIDs[0]} {
+		blocks.Value(i).Object().HasValue("id", id).HasValue("block_order", i+1)
+	}
+
+	// The order must list every block exactly once
+	e.PUT("/api/v1/workout-sessions/"+sessionID+"/blocks/reorder").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"block_ids": []string{blockIDs[0], blockIDs[0], blockIDs[1]},
+		}).
+		Expect().
+		Status(400)
+
+	e.PUT("/api/v1/workout-sessions/"+sessionID+"/blocks/reorder").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"block_ids": []string{blockIDs[0], blockIDs[1]},
+		}).
+		Expect().
+		Status(400)
+
+	// Deleting the middle block closes the gap
+	e.DELETE("/api/v1/session-blocks/"+blockIDs[1]).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(204)
+
+	blocks = getSessionBlocks(e, token, sessionID)
+	blocks.Length().IsEqual(2)
+	blocks.Value(0).Object().HasValue("id", blockIDs[2]).HasValue("block_order", 1)
+	blocks.Value(1).Object().HasValue("id", blockIDs[0]).HasValue("block_order", 2)
+
+	e.GET("/api/v1/session-blocks/"+blockIDs[1]).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(404)
+}
+
+func testReorderAndDeleteExercises(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "exercises@example.com", "password123", "Exercise", "Editor")
+	benchID := createAnalyticsExercise(e, token, "Bench Press")
+	rowID := createAnalyticsExercise(e, token, "Barbell Row")
+	flyID := createAnalyticsExercise(e, token, "Cable Fly")
+
+	block := startStructuredSession(e, token, map[string]interface{}{
+		"type": "superset",
+		"exercises": []map[string]interface{}{
+			{"exercise_id": benchID, "exercise_order": 1, "sets": 2, "reps": 8},
+			{"exercise_id": rowID, "exercise_order": 2, "sets": 2, "reps": 10},
+		},
+	})
+	blockID := block.Value("id").String().Raw()
+	benchExerciseID := block.Value("exercises").Array().Value(0).Object().Value("id").String().Raw()
+	rowExerciseID := block.Value("exercises").Array().Value(1).Object().Value("id").String().Raw()
+
+	// Row first: the superset now alternates row, bench within each round
+	reordered := e.PUT("/api/v1/session-blocks/"+blockID+"/exercises/reorder").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"session_exercise_ids": []string{rowExerciseID, benchExerciseID},
+		}).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object()
+	reordered.Value("type").String().IsEqual("superset")
+
+	row := reordered.Value("exercises").Array().Value(0).Object()
+	row.HasValue("id", rowExerciseID).HasValue("exercise_order", 1)
+	row.Value("sets").Array().Value(0).Object().HasValue("sequence_order", 1)
+	row.Value("sets").Array().Value(1).Object().HasValue("sequence_order", 3)
+
+	bench := reordered.Value("exercises").Array().Value(1).Object()
+	bench.HasValue("id", benchExerciseID).HasValue("exercise_order", 2)
+	bench.Value("sets").Array().Value(0).Object().HasValue("sequence_order", 2)
+	bench.Value("sets").Array().Value(1).Object().HasValue("sequence_order", 4)
+
+	e.PUT("/api/v1/session-blocks/"+blockID+"/exercises/reorder").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"session_exercise_ids": []string{rowExerciseID},
+		}).
+		Expect().
+		Status(400)
+
+	// Add a third exercise at the front, then delete the row
+	e.POST("/api/v1/session-blocks/"+blockID+"/exercises").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"exercise_id":    flyID,
+			"exercise_order": 1,
+		}).
+		Expect().
+		Status(201)
+
+	e.DELETE("/api/v1/session-exercises/"+rowExerciseID).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(204)
+
+	exercises := getSessionBlock(e, token, blockID).Value("exercises").Array()
+	exercises.Length().IsEqual(2)
+	exercises.Value(0).Object().HasValue("exercise_name", "Cable Fly").HasValue("exercise_order", 1)
+	exercises.Value(1).Object().HasValue("id", benchExerciseID).HasValue("exercise_order", 2)
+
+	e.GET("/api/v1/session-exercises/"+rowExerciseID).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(404)
+}
+
+func testSwapSessionExercise(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "swap@example.com", "password123", "Swap", "User")
+	squatID := createAnalyticsExercise(e, token, "Back Squat")
+	legPressID := createAnalyticsExercise(e, token, "Leg Press")
+	hackSquatID := createAnalyticsExercise(e, token, "Hack Squat")
+	curlID := createAnalyticsExercise(e, token, "Leg Curl")
+
+	block := startStructuredSession(e, token, map[string]interface{}{
+		"type":              "straight",
+		"rest_between_sets": 120,
+		"exercises": []map[string]interface{}{
+			{
+				"exercise_id":    squatID,
+				"exercise_order": 1,
+				"sets":           3,
+				"reps":           5,
+				"target_weight": map[string]interface{}{
+					"weight_value": 100.0,
+					"weight_unit":  "kg",
+				},
+			},
+			{"exercise_id": curlID, "exercise_order": 2, "sets": 2, "reps": 12},
+		},
+	})
+	squatExerciseID := block.Value("exercises").Array().Value(0).Object().Value("id").String().Raw()
+	squatSets := setIDs(blockExerciseSets(block, 0))
+
+	// Nothing done yet: the exercise is replaced in place and the planned load cleared
+	swapped := e.PUT("/api/v1/session-exercises/"+squatExerciseID+"/swap").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"exercise_id": legPressID,
+		}).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object()
+	swapped.HasValue("id", squatExerciseID).HasValue("exercise_name", "Leg Press")
+	swapped.Value("sets").Array().Length().IsEqual(3)
+	swapped.Value("sets").Array().Value(0).Object().NotContainsKey("actual_weight")
+	swapped.Value("sets").Array().Value(0).Object().HasValue("actual_reps", 5)
+
+	// Swapping to the same exercise is rejected
+	e.PUT("/api/v1/session-exercises/"+squatExerciseID+"/swap").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"exercise_id": legPressID,
+		}).
+		Expect().
+		Status(400)
+
+	// One set done: the rest of the sets move to the substitute, right after the original
+	e.PUT("/api/v1/session-sets/"+squatSets[0]+"/complete").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(200)
+
+	substitute := e.PUT("/api/v1/session-exercises/"+squatExerciseID+"/swap").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"exercise_id": hackSquatID,
+		}).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object()
+	substitute.Value("id").String().NotEqual(squatExerciseID)
+	substitute.HasValue("exercise_name", "Hack Squat").HasValue("exercise_order", 2)
+	substitute.ContainsKey("prescription_id")
+	moved := substitute.Value("sets").Array()
+	moved.Length().IsEqual(2)
+	for i := 0; i < 2; i++ {
+		moved.Value(i).Object().HasValue("id", squatSets[i+1]).HasValue("set_number", i+1)
+	}
+
+	exercises := getSessionBlock(e, token, block.Value("id").String().Raw()).Value("exercises").Array()
+	exercises.Length().IsEqual(3)
+	original := exercises.Value(0).Object()
+	original.HasValue("exercise_name", "Leg Press").HasValue("exercise_order", 1)
+	original.ContainsKey("completed_at")
+	original.Value("sets").Array().Length().IsEqual(1)
+	original.Value("sets").Array().Value(0).Object().HasValue("id", squatSets[0]).HasValue("sequence_order", 1)
+	exercises.Value(1).Object().Value("sets").Array().Value(0).Object().HasValue("sequence_order", 2)
+	exercises.Value(2).Object().HasValue("exercise_name", "Leg Curl").HasValue("exercise_order", 3)
+	exercises.Value(2).Object().Value("sets").Array().Value(0).Object().HasValue("sequence_order", 4)
+
+	// Unknown exercises are rejected
+	e.PUT("/api/v1/session-exercises/"+squatExerciseID+"/swap").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"exercise_id": "00000000-0000-0000-0000-000000000000",
+		}).
+		Expect().
+		Status(400)
+}
+
+func testSessionEditingAuthorization(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "owner@example.com", "password123", "Session", "Owner")
+	otherToken := createTestUserAndGetToken(e, "intruder@example.com", "password123", "Other", "User")
+	squatID := createAnalyticsExercise(e, token, "Back Squat")
+
+	sessionID := startFreeFormSession(e, token)
+	block := addSessionBlock(e, token, sessionID, map[string]interface{}{
+		"exercises": []map[string]interface{}{
+			{"exercise_id": squatID, "sets": 1},
+		},
+	})
+	blockID := block.Value("id").String().Raw()
+	exerciseID := block.Value("exercises").Array().Value(0).Object().Value("id").String().Raw()
+
+	e.POST("/api/v1/workout-sessions/"+sessionID+"/blocks").
+		WithHeader("Authorization", "Bearer "+otherToken).
+		WithJSON(map[string]interface{}{}).
+		Expect().
+		Status(403)
+
+	e.POST("/api/v1/session-blocks/"+blockID+"/exercises").
+		WithHeader("Authorization", "Bearer "+otherToken).
+		WithJSON(map[string]interface{}{
+			"exercise_id": squatID,
+		}).
+		Expect().
+		Status(403)
+
+	e.DELETE("/api/v1/session-exercises/"+exerciseID).
+		WithHeader("Authorization", "Bearer "+otherToken).
+		Expect().
+		Status(403)
+
+	e.DELETE("/api/v1/session-blocks/"+blockID).
+		WithHeader("Authorization", "Bearer "+otherToken).
+		Expect().
+		Status(403)
+
+	// Unknown exercises are rejected without creating the block
+	e.POST("/api/v1/workout-sessions/"+sessionID+"/blocks").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"exercises": []map[string]interface{}{
+				{"exercise_id": "00000000-0000-0000-0000-000000000000"},
+			},
+		}).
+		Expect().
+		Status(400)
+
+	getSessionBlocks(e, token, sessionID).Length().IsEqual(1)
+}
@@ -0,0 +1,306 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+)
+
+func TestSessionStructure(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Superset Interleaves By Round", func(t *testing.T) {
+		CleanDatabase(t)
+		testSupersetStructure(t, e)
+	})
+
+	t.Run("Circuit Uses Group Rounds", func(t *testing.T) {
+		CleanDatabase(t)
+		testCircuitStructure(t, e)
+	})
+
+	t.Run("Drop Set Sub-Sets", func(t *testing.T) {
+		CleanDatabase(t)
+		testDropSetStructure(t, e)
+	})
+
+	t.Run("Pyramid Loads", func(t *testing.T) {
+		CleanDatabase(t)
+		testPyramidStructure(t, e)
+	})
+
+	t.Run("EMOM Intervals", func(t *testing.T) {
+		CleanDatabase(t)
+		testEMOMStructure(t, e)
+	})
+
+	t.Run("HIIT Intervals", func(t *testing.T) {
+		CleanDatabase(t)
+		testHIITStructure(t, e)
+	})
+
+	t.Run("AMRAP Time Cap", func(t *testing.T) {
+		CleanDatabase(t)
+		testAMRAPStructure(t, e)
+	})
+}
+
+// startStructuredSession creates a workout with a single prescription group, starts a session
+// from it and returns the session's only block
+func startStructuredSession(e *httpexpect.Expect, token string, group map[string]interface{}) *httpexpect.Object {
+	workoutID := createWorkout(e, token, "Structured Day")
+	addPrescriptionGroup(e, token, workoutID, group)
+	sessionID := startWorkoutSession(e, token, workoutID, "").Value("id").String().Raw()
+
+	blocks := e.GET("/api/v1/workout-sessions/"+sessionID).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object().Value("blocks").Array()
+	blocks.Length().IsEqual(1)
+
+	block := blocks.Value(0).Object()
+	block.Value("type").String().IsEqual(group["type"].(string))
+	return block
+}
+
+// blockExerciseSets returns the sets of the block's exercise at index
+func blockExerciseSets(block *httpexpect.Object, index int) *httpexpect.Array {
+	return block.Value("exercises").Array().Value(index).Object().Value("sets").Array()
+}
+
+func testSupersetStructure(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "superset@example.com", "password123", "Super", "Set")
+	benchID := createAnalyticsExercise(e, token, "Bench Press")
+	rowID := createAnalyticsExercise(e, token, "Barbell Row")
+
+	block := startStructuredSession(e, token, map[string]interface{}{
+		"type": "superset",
+		"exercises": []map[string]interface{}{
+			{"exercise_id": benchID, "exercise_order": 1, "sets": 3, "reps": 8},
+			{"exercise_id": rowID, "exercise_order": 2, "sets": 2, "reps": 10},
+		},
+	})
+
+	// Bench 1, Row 1, Bench 2, Row 2, Bench 3
+	bench := blockExerciseSets(block, 0)
+	bench.Length().IsEqual(3)
+	for i, sequence := range []int{1, 3, 5} {
+		set := bench.Value(i).Object()
+		set.Value("round").Number().IsEqual(i + 1)
+		set.Value("sequence_order").Number().IsEqual(sequence)
+		set.Value("actual_reps").Number().IsEqual(8)
+	}
+
+	row := blockExerciseSets(block, 1)
+	row.Length().IsEqual(2)
+	for i, sequence := range []int{2, 4} {
+		set := row.Value(i).Object()
+		set.Value("round").Number().IsEqual(i + 1)
+		set.Value("sequence_order").Number().IsEqual(sequence)
+	}
+}
+
+func testCircuitStructure(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "circuit@example.com", "password123", "Circuit", "User")
+	squatID := createAnalyticsExercise(e, token, "Air Squat")
+	pushupID := createAnalyticsExercise(e, token, "Push Up")
+	lungeID := createAnalyticsExercise(e, token, "Lunge")
+
+	block := startStructuredSession(e, token, map[string]interface{}{
+		"type":         "circuit",
+		"group_rounds": 3,
+		"exercises": []map[string]interface{}{
+			{"exercise_id": squatID, "exercise_order": 1, "reps": 20},
+			{"exercise_id": pushupID, "exercise_order": 2, "reps": 15},
+			{"exercise_id": lungeID, "exercise_order": 3, "reps": 10},
+		},
+	})
+	block.Value("group_rounds").Number().IsEqual(3)
+
+	// Every exercise appears once per round, in exercise order within the round
+	for exercise := 0; exercise < 3; exercise++ {
+		sets := blockExerciseSets(block, exercise)
+		sets.Length().IsEqual(3)
+		for round := 0; round < 3; round++ {
+			set := sets.Value(round).Object()
+			set.Value("round").Number().IsEqual(round + 1)
+			set.Value("sequence_order").Number().IsEqual(round*3 + exercise + 1)
+		}
+	}
+}
+
+func testDropSetStructure(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "dropset@example.com", "password123", "Drop", "Set")
+	curlID := createAnalyticsExercise(e, token, "Dumbbell Curl")
+
+	block := startStructuredSession(e, token, map[string]interface{}{
+		"type": "drop_set",
+		"exercises": []map[string]interface{}{
+			{
+				"exercise_id":    curlID,
+				"exercise_order": 1,
+				"sets":           2,
+				"reps":           10,
+				"target_weight": map[string]interface{}{
+					"weight_value": 20.0,
+					"weight_unit":  "kg",
+				},
+			},
+		},
+	})
+
+	// Each working set is followed by two drops, 20% lighter each, reps left open
+	sets := blockExerciseSets(block, 0)
+	sets.Length().IsEqual(6)
+
+	expected := []struct {
+		round  int
+		subSet int
+		weight float64
+	}{
+		{1, 0, 20}, {1, 1, 16}, {1, 2, 13},
+		{2, 0, 20}, {2, 1, 16}, {2, 2, 13},
+	}
+	for i, exp := range expected {
+		set := sets.Value(i).Object()
+		set.Value("set_number").Number().IsEqual(i + 1)
+		set.Value("round").Number().IsEqual(exp.round)
+		set.Value("sub_set_number").Number().IsEqual(exp.subSet)
+		set.Value("actual_weight").Object().Value("weight_value").Number().IsEqual(exp.weight)
+		if exp.subSet == 0 {
+			set.Value("actual_reps").Number().IsEqual(10)
+		} else {
+			set.NotContainsKey("actual_reps")
+		}
+	}
+}
+
+func testPyramidStructure(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "pyramid@example.com", "password123", "Pyra", "Mid")
+	squatID := createAnalyticsExercise(e, token, "Back Squat")
+
+	block := startStructuredSession(e, token, map[string]interface{}{
+		"type": "pyramid",
+		"exercises": []map[string]interface{}{
+			{
+				"exercise_id":    squatID,
+				"exercise_order": 1,
+				"sets":           3,
+				"reps":           5,
+				"target_weight": map[string]interface{}{
+					"weight_value": 100.0,
+					"weight_unit":  "kg",
+				},
+			},
+		},
+	})
+
+	sets := blockExerciseSets(block, 0)
+	sets.Length().IsEqual(3)
+	for i, exp := range []struct {
+		weight float64
+		reps   int
+	}{{100, 5}, {90, 7}, {80, 9}} {
+		set := sets.Value(i).Object()
+		set.Value("actual_weight").Object().Value("weight_value").Number().IsEqual(exp.weight)
+		set.Value("actual_reps").Number().IsEqual(exp.reps)
+	}
+}
+
+func testEMOMStructure(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "emom@example.com", "password123", "Emom", "User")
+	swingID := createAnalyticsExercise(e, token, "Kettlebell Swing")
+	burpeeID := createAnalyticsExercise(e, token, "Burpee")
+
+	block := startStructuredSession(e, token, map[string]interface{}{
+		"type":         "emom",
+		"group_rounds": 2,
+		"exercises": []map[string]interface{}{
+			{"exercise_id": swingID, "exercise_order": 1, "reps": 15},
+			{"exercise_id": burpeeID, "exercise_order": 2, "reps": 8},
+		},
+	})
+
+	// Minutes alternate between the exercises: swing 0:00, burpee 1:00, swing 2:00, burpee 3:00
+	swings := blockExerciseSets(block, 0)
+	swings.Length().IsEqual(2)
+	swings.Value(0).Object().Value("interval_start_seconds").Number().IsEqual(0)
+	swings.Value(1).Object().Value("interval_start_seconds").Number().IsEqual(120)
+
+	burpees := blockExerciseSets(block, 1)
+	burpees.Length().IsEqual(2)
+	burpees.Value(0).Object().Value("interval_start_seconds").Number().IsEqual(60)
+	burpees.Value(1).Object().Value("interval_start_seconds").Number().IsEqual(180)
+
+	for _, sets := range []*httpexpect.Array{swings, burpees} {
+		for i := 0; i < 2; i++ {
+			sets.Value(i).Object().Value("interval_seconds").Number().IsEqual(60)
+		}
+	}
+}
+
+func testHIITStructure(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "hiit@example.com", "password123", "Hiit", "User")
+	sprintID := createAnalyticsExercise(e, token, "Bike Sprint")
+
+	block := startStructuredSession(e, token, map[string]interface{}{
+		"type":              "hiit",
+		"group_rounds":      4,
+		"interval_seconds":  40,
+		"rest_between_sets": 20,
+		"exercises": []map[string]interface{}{
+			{"exercise_id": sprintID, "exercise_order": 1, "reps": 10},
+		},
+	})
+	block.Value("interval_seconds").Number().IsEqual(40)
+
+	sets := blockExerciseSets(block, 0)
+	sets.Length().IsEqual(4)
+	for i := 0; i < 4; i++ {
+		set := sets.Value(i).Object()
+		set.Value("interval_start_seconds").Number().IsEqual(i * 60)
+		set.Value("interval_seconds").Number().IsEqual(40)
+		set.Value("actual_duration_seconds").Number().IsEqual(40)
+	}
+}
+
+func testAMRAPStructure(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "amrap@example.com", "password123", "Amrap", "User")
+	pullupID := createAnalyticsExercise(e, token, "Pull Up")
+	squatID := createAnalyticsExercise(e, token, "Air Squat")
+
+	block := startStructuredSession(e, token, map[string]interface{}{
+		"type":             "amrap",
+		"interval_seconds": 900,
+		"exercises": []map[string]interface{}{
+			{"exercise_id": pullupID, "exercise_order": 1, "reps": 5},
+			{"exercise_id": squatID, "exercise_order": 2, "reps": 15},
+		},
+	})
+
+	// One round is laid out; every set shares the 15 minute cap
+	for i := 0; i < 2; i++ {
+		sets := blockExerciseSets(block, i)
+		sets.Length().IsEqual(1)
+		set := sets.Value(0).Object()
+		set.Value("round").Number().IsEqual(1)
+		set.Value("interval_start_seconds").Number().IsEqual(0)
+		set.Value("interval_seconds").Number().IsEqual(900)
+	}
+
+	// Extra rounds are added as the athlete goes and continue the block's sequence
+	setsURL := "/api/v1/session-exercises/" + block.Value("exercises").Array().Value(0).Object().Value("id").String().Raw() + "/sets"
+	e.POST(setsURL).
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"actual_reps": 5,
+		}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object().
+		HasValue("round", 2).
+		HasValue("sequence_order", 3)
+}
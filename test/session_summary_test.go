@@ -0,0 +1,103 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect/v2"
+)
+
+func TestSessionSummary(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Summary On End", func(t *testing.T) {
+		CleanDatabase(t)
+		testSessionSummary(t, e)
+	})
+
+	t.Run("Summary Without Body Weight", func(t *testing.T) {
+		CleanDatabase(t)
+		testSessionSummaryWithoutBodyWeight(t, e)
+	})
+}
+
+func testSessionSummary(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "summary@example.com", "password123", "Sum", "Mary")
+	squatID := createAnalyticsExercise(e, token, "Back Squat")
+	quadsID := createAnalyticsMuscleGroup(e, token, "Quadriceps", squatID, true)
+	createAnalyticsMuscleGroup(e, token, "Glutes", squatID, false)
+
+	e.POST("/api/v1/user/weight-logs").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"weight_kg": 80,
+		}).
+		Expect().
+		Status(http.StatusCreated)
+
+	block := startStructuredSession(e, token, map[string]interface{}{
+		"type": "straight",
+		"exercises": []map[string]interface{}{
+			{"exercise_id": squatID, "exercise_order": 1, "sets": 3, "reps": 5},
+		},
+	})
+	squat := setIDs(blockExerciseSets(block, 0))
+	logSessionSet(e, token, squat[0], 5, 100)
+	logSessionSet(e, token, squat[1], 5, 100)
+
+	sessionID := e.GET("/api/v1/workout-sessions").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Array().
+		Value(0).Object().Value("id").String().Raw()
+
+	data := e.PUT("/api/v1/workout-sessions/"+sessionID+"/end").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"ended_at": time.Now().Add(time.Hour).Format(time.RFC3339),
+		}).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object()
+
+	summary := data.Value("summary").Object()
+	summary.Value("total_sets").Number().IsEqual(2)
+	summary.Value("total_reps").Number().IsEqual(10)
+	summary.Value("tonnage").Object().Value("weight_value").Number().IsEqual(1000)
+	summary.Value("new_record_count").Number().Gt(0)
+	summary.Value("blocks").Array().Length().IsEqual(1)
+
+	// Primary muscles count fully, secondary muscles by half
+	muscleGroups := summary.Value("muscle_groups").Array()
+	muscleGroups.Length().IsEqual(2)
+	quads := muscleGroups.Value(0).Object()
+	quads.Value("muscle_group_id").String().IsEqual(quadsID)
+	quads.Value("sets").Number().IsEqual(2)
+	muscleGroups.Value(1).Object().Value("sets").Number().IsEqual(1)
+
+	// An hour at the default MET of 5 for an 80 kg lifter
+	summary.Value("body_weight").Object().Value("weight_value").Number().IsEqual(80)
+	summary.Value("estimated_calories").Number().InRange(399, 401)
+}
+
+func testSessionSummaryWithoutBodyWeight(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "weightless@example.com", "password123", "Weight", "Less")
+	sessionID := startFreeFormSession(e, token)
+
+	summary := e.PUT("/api/v1/workout-sessions/"+sessionID+"/end").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{}).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object().
+		Value("summary").Object()
+	summary.Value("total_sets").Number().IsEqual(0)
+	summary.Value("muscle_groups").Array().IsEmpty()
+	summary.NotContainsKey("estimated_calories")
+	summary.NotContainsKey("body_weight")
+}
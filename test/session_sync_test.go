@@ -0,0 +1,304 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/google/uuid"
+)
+
+func TestWorkoutSessionSync(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Sync Creates Session Tree", func(t *testing.T) {
+		CleanDatabase(t)
+		testSyncCreatesSessionTree(t, e)
+	})
+
+	t.Run("Sync Is Idempotent", func(t *testing.T) {
+		CleanDatabase(t)
+		testSyncIsIdempotent(t, e)
+	})
+
+	t.Run("Sync Reports Conflicts", func(t *testing.T) {
+		CleanDatabase(t)
+		testSyncReportsConflicts(t, e)
+	})
+
+	t.Run("Sync Validation", func(t *testing.T) {
+		CleanDatabase(t)
+		testSyncValidation(t, e)
+	})
+}
+
+// syncTree holds the client-generated IDs of a synced session tree
+type syncTree struct {
+	SessionID  string
+	BlockID    string
+	ExerciseID string
+	SetIDs     []string
+}
+
+func newSyncTree() syncTree {
+	return syncTree{
+		SessionID:  uuid.New().String(),
+		BlockID:    uuid.New().String(),
+		ExerciseID: uuid.New().String(),
+		SetIDs:     []string{uuid.New().String(), uuid.New().String()},
+	}
+}
+
+// syncPayload builds a session with one block, one exercise and two sets (the first one completed),
+// all modified by the client at updatedAt
+func syncPayload(tree syncTree, exerciseID string, startedAt time.Time, updatedAt time.Time, firstSetReps int) map[string]interface{} {
+	clientUpdatedAt := updatedAt.Format(time.RFC3339)
+	return map[string]interface{}{
+		"id":                tree.SessionID,
+		"started_at":        startedAt.Format(time.RFC3339),
+		"notes":             "Basement gym",
+		"client_updated_at": clientUpdatedAt,
+		"blocks": []map[string]interface{}{
+			{
+				"id":                tree.BlockID,
+				"block_order":       1,
+				"client_updated_at": clientUpdatedAt,
+				"exercises": []map[string]interface{}{
+					{
+						"id":                tree.ExerciseID,
+						"exercise_id":       exerciseID,
+						"exercise_order":    1,
+						"client_updated_at": clientUpdatedAt,
+						"sets": []map[string]interface{}{
+							{
+								"id":           tree.SetIDs[0],
+								"set_number":   1,
+								"completed":    true,
+								"started_at":   startedAt.Add(time.Minute).Format(time.RFC3339),
+								"completed_at": startedAt.Add(2 * time.Minute).Format(time.RFC3339),
+								"actual_reps":  firstSetReps,
+								"actual_weight": map[string]interface{}{
+									"weight_value": 100,
+									"weight_unit":  "kg",
+								},
+								"client_updated_at": clientUpdatedAt,
+							},
+							{
+								"id":                tree.SetIDs[1],
+								"set_number":        2,
+								"completed":         false,
+								"client_updated_at": clientUpdatedAt,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func syncSession(e *httpexpect.Expect, token string, payload map[string]interface{}) *httpexpect.Object {
+	return e.POST("/api/v1/workout-sessions/sync").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(payload).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object()
+}
+
+func testSyncCreatesSessionTree(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "sync@example.com", "password123", "Sync", "User")
+	squatID := createAnalyticsExercise(e, token, "Back Squat")
+
+	tree := newSyncTree()
+	startedAt := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	payload := syncPayload(tree, squatID, startedAt, startedAt.Add(time.Hour), 5)
+	payload["ended_at"] = startedAt.Add(time.Hour).Format(time.RFC3339)
+
+	result := syncSession(e, token, payload)
+	result.Value("created").Number().IsEqual(5)
+	result.Value("updated").Number().IsEqual(0)
+	result.Value("conflicts").Array().IsEmpty()
+
+	session := result.Value("session").Object()
+	session.Value("id").String().IsEqual(tree.SessionID)
+	session.Value("completed").Boolean().IsTrue()
+	session.Value("duration_seconds").Number().IsEqual(3600)
+
+	sets := session.Value("blocks").Array().Value(0).Object().
+		Value("exercises").Array().Value(0).Object().
+		Value("sets").Array()
+	sets.Length().IsEqual(2)
+	first := sets.Value(0).Object()
+	first.Value("id").String().IsEqual(tree.SetIDs[0])
+	first.Value("completed").Boolean().IsTrue()
+	first.Value("actual_reps").Number().IsEqual(5)
+	first.Value("actual_weight").Object().Value("weight_value").Number().IsEqual(100)
+	sets.Value(1).Object().Value("completed").Boolean().IsFalse()
+
+	// The synced session is a regular session afterwards
+	e.GET("/api/v1/workout-sessions/"+tree.SessionID).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(200)
+
+	// Records are picked up from the synced sets
+	e.GET("/api/v1/user/records").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Array().NotEmpty()
+}
+
+func testSyncIsIdempotent(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "replay@example.com", "password123", "Replay", "User")
+	squatID := createAnalyticsExercise(e, token, "Back Squat")
+
+	tree := newSyncTree()
+	startedAt := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	payload := syncPayload(tree, squatID, startedAt, startedAt.Add(time.Hour), 5)
+
+	syncSession(e, token, payload).Value("created").Number().IsEqual(5)
+
+	// The same payload again changes nothing
+	replay := syncSession(e, token, payload)
+	replay.Value("created").Number().IsEqual(0)
+	replay.Value("updated").Number().IsEqual(0)
+	replay.Value("unchanged").Number().IsEqual(5)
+	replay.Value("conflicts").Array().IsEmpty()
+	replay.Value("session").Object().Value("blocks").Array().Length().IsEqual(1)
+
+	// A newer client copy is applied
+	newer := syncPayload(tree, squatID, startedAt, startedAt.Add(90*time.Minute), 6)
+	result := syncSession(e, token, newer)
+	result.Value("updated").Number().IsEqual(5)
+	result.Value("session").Object().
+		Value("blocks").Array().Value(0).Object().
+		Value("exercises").Array().Value(0).Object().
+		Value("sets").Array().Value(0).Object().
+		Value("actual_reps").Number().IsEqual(6)
+
+	// Un-completing the only completed set in a newer copy removes the records it set
+	uncompleted := syncPayload(tree, squatID, startedAt, startedAt.Add(2*time.Hour), 6)
+	exercise := uncompleted["blocks"].([]map[string]interface{})[0]["exercises"].([]map[string]interface{})[0]
+	exercise["sets"].([]map[string]interface{})[0]["completed"] = false
+	syncSession(e, token, uncompleted).Value("updated").Number().IsEqual(5)
+
+	e.GET("/api/v1/user/records").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Array().IsEmpty()
+}
+
+func testSyncReportsConflicts(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "conflict@example.com", "password123", "Conflict", "User")
+	squatID := createAnalyticsExercise(e, token, "Back Squat")
+
+	tree := newSyncTree()
+	startedAt := time.Now().Add(-3 * time.Hour).Truncate(time.Second)
+	syncSession(e, token, syncPayload(tree, squatID, startedAt, startedAt.Add(time.Minute), 5))
+
+	// The set is edited online after the client's last offline edit
+	e.PUT("/api/v1/session-sets/"+tree.SetIDs[0]).
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"actual_reps": 3,
+		}).
+		Expect().
+		Status(200)
+
+	// The client's older offline edit of the same set is not applied
+	stale := syncSession(e, token, syncPayload(tree, squatID, startedAt, startedAt.Add(time.Hour), 8))
+	conflicts := stale.Value("conflicts").Array()
+	conflicts.Length().IsEqual(1)
+	conflict := conflicts.Value(0).Object()
+	conflict.Value("entity").String().IsEqual("set")
+	conflict.Value("id").String().IsEqual(tree.SetIDs[0])
+	conflict.Value("reason").String().IsEqual("server_newer")
+	conflict.ContainsKey("server_updated_at")
+
+	stale.Value("session").Object().
+		Value("blocks").Array().Value(0).Object().
+		Value("exercises").Array().Value(0).Object().
+		Value("sets").Array().Value(0).Object().
+		Value("actual_reps").Number().IsEqual(3)
+
+	// IDs that belong to another session are reported, not moved
+	other := newSyncTree()
+	other.BlockID = tree.BlockID
+	result := syncSession(e, token, syncPayload(other, squatID, startedAt, startedAt.Add(time.Hour), 5))
+	result.Value("created").Number().IsEqual(1)
+	blockConflict := result.Value("conflicts").Array().Value(0).Object()
+	blockConflict.Value("entity").String().IsEqual("block")
+	blockConflict.Value("reason").String().IsEqual("id_in_use")
+}
+
+func testSyncValidation(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "syncval@example.com", "password123", "Sync", "Validation")
+	otherToken := createTestUserAndGetToken(e, "syncother@example.com", "password123", "Other", "User")
+	squatID := createAnalyticsExercise(e, token, "Back Squat")
+	startedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	// Duplicate IDs within the tree
+	tree := newSyncTree()
+	tree.SetIDs[1] = tree.SetIDs[0]
+	e.POST("/api/v1/workout-sessions/sync").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(syncPayload(tree, squatID, startedAt, startedAt, 5)).
+		Expect().
+		Status(400)
+
+	// Unknown exercise
+	e.POST("/api/v1/workout-sessions/sync").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(syncPayload(newSyncTree(), uuid.New().String(), startedAt, startedAt, 5)).
+		Expect().
+		Status(400)
+
+	// Unknown prescription and RPE value are named by the exercise and set that use them
+	badRefs := newSyncTree()
+	payload := syncPayload(badRefs, squatID, startedAt, startedAt, 5)
+	exercise := payload["blocks"].([]map[string]interface{})[0]["exercises"].([]map[string]interface{})[0]
+	exercise["prescription_id"] = uuid.New().String()
+	exercise["sets"].([]map[string]interface{})[0]["rpe_value_id"] = uuid.New().String()
+	errors := e.POST("/api/v1/workout-sessions/sync").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(payload).
+		Expect().
+		Status(422).
+		JSON().Object().
+		Value("errors").Object()
+	errors.Value("exercises." + badRefs.ExerciseID + ".prescription_id").Array().Value(0).String().IsEqual("Prescription not found")
+	errors.Value("sets." + badRefs.SetIDs[0] + ".rpe_value_id").Array().Value(0).String().IsEqual("RPE value not found")
+
+	// Missing client timestamps
+	e.POST("/api/v1/workout-sessions/sync").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"id":         uuid.New().String(),
+			"started_at": startedAt.Format(time.RFC3339),
+		}).
+		Expect().
+		Status(400)
+
+	// Nothing is written when the tree is rejected
+	e.GET("/api/v1/workout-sessions").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Array().IsEmpty()
+
+	// Another user's session cannot be synced over
+	owned := newSyncTree()
+	syncSession(e, token, syncPayload(owned, squatID, startedAt, startedAt, 5))
+	e.POST("/api/v1/workout-sessions/sync").
+		WithHeader("Authorization", "Bearer "+otherToken).
+		WithJSON(syncPayload(owned, squatID, startedAt, startedAt.Add(time.Minute), 5)).
+		Expect().
+		Status(403)
+}
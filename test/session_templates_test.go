@@ -0,0 +1,189 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+)
+
+func TestSaveSessionAsWorkout(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Save Session As Workout", func(t *testing.T) {
+		CleanDatabase(t)
+		testSaveSessionAsWorkout(t, e)
+	})
+
+	t.Run("Save Session Validation", func(t *testing.T) {
+		CleanDatabase(t)
+		testSaveSessionValidation(t, e)
+	})
+}
+
+// logSessionSet records what was done in a set and completes it
+func logSessionSet(e *httpexpect.Expect, token string, setID string, reps int, weightKg float64) {
+	e.PUT("/api/v1/session-sets/"+setID).
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"actual_reps": reps,
+			"actual_weight": map[string]interface{}{
+				"weight_value": weightKg,
+				"weight_unit":  "kg",
+			},
+			"completed": true,
+		}).
+		Expect().
+		Status(200)
+}
+
+func testSaveSessionAsWorkout(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "improviser@example.com", "password123", "Impro", "Viser")
+	benchID := createAnalyticsExercise(e, token, "Bench Press")
+	rowID := createAnalyticsExercise(e, token, "Barbell Row")
+	curlID := createAnalyticsExercise(e, token, "Dumbbell Curl")
+
+	block := startStructuredSession(e, token, map[string]interface{}{
+		"type":              "superset",
+		"rest_between_sets": 120,
+		"group_name":        "Push Pull",
+		"exercises": []map[string]interface{}{
+			{"exercise_id": benchID, "exercise_order": 1, "sets": 3, "reps": 8},
+			{"exercise_id": rowID, "exercise_order": 2, "sets": 3, "reps": 10},
+		},
+	})
+	bench := setIDs(blockExerciseSets(block, 0))
+	row := setIDs(blockExerciseSets(block, 1))
+
+	// Bench: two of three sets done, the heavier one sets the target
+	logSessionSet(e, token, bench[0], 8, 60)
+	logSessionSet(e, token, bench[1], 6, 70)
+	// Row: all three done
+	for _, id := range row {
+		logSessionSet(e, token, id, 10, 50)
+	}
+
+	// An improvised block: curls, one of two sets done
+	sessions := e.GET("/api/v1/workout-sessions").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Array()
+	sessionID := sessions.Value(0).Object().Value("id").String().Raw()
+
+	curlBlock := addSessionBlock(e, token, sessionID, map[string]interface{}{
+		"exercises": []map[string]interface{}{
+			{"exercise_id": curlID, "sets": 2, "notes": "Slow negatives"},
+		},
+	})
+	logSessionSet(e, token, setIDs(blockExerciseSets(curlBlock, 0))[0], 12, 15)
+
+	workout := e.POST("/api/v1/workout-sessions/"+sessionID+"/save-as-workout").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"title":       "Improvised Upper",
+			"is_template": true,
+		}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object()
+	workout.Value("title").String().IsEqual("Improvised Upper")
+	workout.Value("is_template").Boolean().IsTrue()
+	workout.Value("visibility").String().IsEqual("private")
+
+	groups := workout.Value("prescriptions").Array()
+	groups.Length().IsEqual(2)
+
+	// The superset keeps its type and group settings; actuals become the targets
+	superset := groups.Value(0).Object()
+	superset.Value("type").String().IsEqual("superset")
+	superset.Value("group_order").Number().IsEqual(1)
+	superset.Value("rest_between_sets").Number().IsEqual(120)
+	superset.Value("group_name").String().IsEqual("Push Pull")
+	exercises := superset.Value("exercises").Array()
+	exercises.Length().IsEqual(2)
+	benchTarget := exercises.Value(0).Object()
+	benchTarget.Value("exercise_id").String().IsEqual(benchID)
+	benchTarget.Value("sets").Number().IsEqual(2)
+	benchTarget.Value("reps").Number().IsEqual(6)
+	benchTarget.Value("target_weight").Object().Value("weight_value").Number().IsEqual(70)
+	rowTarget := exercises.Value(1).Object()
+	rowTarget.Value("sets").Number().IsEqual(3)
+	rowTarget.Value("reps").Number().IsEqual(10)
+
+	// The improvised block becomes straight sets
+	straight := groups.Value(1).Object()
+	straight.Value("type").String().IsEqual("straight")
+	straight.Value("group_order").Number().IsEqual(2)
+	curl := straight.Value("exercises").Array().Value(0).Object()
+	curl.Value("exercise_id").String().IsEqual(curlID)
+	curl.Value("sets").Number().IsEqual(1)
+	curl.Value("reps").Number().IsEqual(12)
+	curl.Value("notes").String().IsEqual("Slow negatives")
+
+	// The new workout is a regular workout of the user
+	e.GET("/api/v1/workouts/"+workout.Value("id").String().Raw()).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object().
+		Value("prescriptions").Array().Length().IsEqual(2)
+
+	// Without a title, the session's workout title is used
+	e.POST("/api/v1/workout-sessions/"+sessionID+"/save-as-workout").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object().
+		HasValue("title", "Structured Day")
+}
+
+func testSaveSessionValidation(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "nothingdone@example.com", "password123", "Nothing", "Done")
+	otherToken := createTestUserAndGetToken(e, "snooper@example.com", "password123", "Snoop", "User")
+	squatID := createAnalyticsExercise(e, token, "Back Squat")
+
+	sessionID := startFreeFormSession(e, token)
+	block := addSessionBlock(e, token, sessionID, map[string]interface{}{
+		"exercises": []map[string]interface{}{
+			{"exercise_id": squatID, "sets": 2},
+		},
+	})
+
+	// Nothing completed yet
+	e.POST("/api/v1/workout-sessions/"+sessionID+"/save-as-workout").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{}).
+		Expect().
+		Status(400)
+
+	logSessionSet(e, token, setIDs(blockExerciseSets(block, 0))[0], 5, 100)
+
+	// Only the session's owner can save it
+	e.POST("/api/v1/workout-sessions/"+sessionID+"/save-as-workout").
+		WithHeader("Authorization", "Bearer "+otherToken).
+		WithJSON(map[string]interface{}{}).
+		Expect().
+		Status(404)
+
+	e.POST("/api/v1/workout-sessions/"+sessionID+"/save-as-workout").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"visibility": "everyone",
+		}).
+		Expect().
+		Status(400)
+
+	// A free-form session is named after its date
+	e.POST("/api/v1/workout-sessions/"+sessionID+"/save-as-workout").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object().
+		Value("title").String().HasPrefix("Workout ")
+}
@@ -0,0 +1,215 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect/v2"
+)
+
+func TestSessionSetTiming(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Straight Sets Rest Timer", func(t *testing.T) {
+		CleanDatabase(t)
+		testStraightSetRestTimer(t, e)
+	})
+
+	t.Run("Superset Moves Straight On", func(t *testing.T) {
+		CleanDatabase(t)
+		testSupersetRestTimer(t, e)
+	})
+
+	t.Run("EMOM Next Set On The Clock", func(t *testing.T) {
+		CleanDatabase(t)
+		testEMOMRestTimer(t, e)
+	})
+
+	t.Run("Set Timing Validation", func(t *testing.T) {
+		CleanDatabase(t)
+		testSetTimingValidation(t, e)
+	})
+}
+
+// startTimedSet starts a set at the given time
+func startTimedSet(e *httpexpect.Expect, token string, setID string, at time.Time) *httpexpect.Object {
+	return e.PUT("/api/v1/session-sets/"+setID+"/start").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"started_at": at.Format(time.RFC3339),
+		}).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object()
+}
+
+// completeTimedSet completes a set at the given time and returns the completed set
+func completeTimedSet(e *httpexpect.Expect, token string, setID string, at time.Time) *httpexpect.Object {
+	return e.PUT("/api/v1/session-sets/"+setID+"/complete").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"completed_at": at.Format(time.RFC3339),
+		}).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object()
+}
+
+func testStraightSetRestTimer(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "rest@example.com", "password123", "Rest", "Timer")
+	squatID := createAnalyticsExercise(e, token, "Back Squat")
+
+	block := startStructuredSession(e, token, map[string]interface{}{
+		"type":              "straight",
+		"rest_between_sets": 90,
+		"exercises": []map[string]interface{}{
+			{"exercise_id": squatID, "exercise_order": 1, "sets": 3, "reps": 5},
+		},
+	})
+	ids := setIDs(blockExerciseSets(block, 0))
+	sessionBlockID := block.Value("id").String().Raw()
+
+	start := time.Date(2025, 3, 3, 18, 0, 0, 0, time.UTC)
+
+	// Set 1: 30 seconds of work, then 90 seconds of prescribed rest
+	started := startTimedSet(e, token, ids[0], start)
+	started.Value("started_at").String().AsDateTime(time.RFC3339Nano).IsEqual(start)
+
+	first := completeTimedSet(e, token, ids[0], start.Add(30*time.Second))
+	first.NotContainsKey("rest_seconds")
+	next := first.Value("next_set").Object()
+	next.Value("set_id").String().IsEqual(ids[1])
+	next.Value("session_block_id").String().IsEqual(sessionBlockID)
+	next.Value("exercise_name").String().IsEqual("Back Squat")
+	next.Value("rest_seconds").Number().IsEqual(90)
+	next.Value("ready_at").String().AsDateTime(time.RFC3339Nano).IsEqual(start.Add(120 * time.Second))
+
+	// Set 2 starts after 120 seconds of rest
+	startTimedSet(e, token, ids[1], start.Add(150*time.Second))
+	second := completeTimedSet(e, token, ids[1], start.Add(180*time.Second))
+	second.Value("rest_seconds").Number().IsEqual(120)
+	second.Value("prescribed_rest_seconds").Number().IsEqual(90)
+
+	// Set 3 is completed without being started: rest runs until its completion
+	third := completeTimedSet(e, token, ids[2], start.Add(300*time.Second))
+	third.Value("rest_seconds").Number().IsEqual(120)
+	third.NotContainsKey("next_set")
+
+	// The block compares the rest taken with the rest prescribed
+	sessionBlock := e.GET("/api/v1/session-blocks/"+sessionBlockID).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object()
+	sessionBlock.Value("started_at").String().AsDateTime(time.RFC3339Nano).IsEqual(start)
+	sessionBlock.Value("average_rest_seconds").Number().IsEqual(120)
+	sessionBlock.Value("rest_delta_seconds").Number().IsEqual(30)
+}
+
+func testSupersetRestTimer(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "superrest@example.com", "password123", "Super", "Rest")
+	benchID := createAnalyticsExercise(e, token, "Bench Press")
+	rowID := createAnalyticsExercise(e, token, "Barbell Row")
+
+	block := startStructuredSession(e, token, map[string]interface{}{
+		"type":              "superset",
+		"rest_between_sets": 120,
+		"exercises": []map[string]interface{}{
+			{"exercise_id": benchID, "exercise_order": 1, "sets": 2, "reps": 8},
+			{"exercise_id": rowID, "exercise_order": 2, "sets": 2, "reps": 10},
+		},
+	})
+	bench := setIDs(blockExerciseSets(block, 0))
+	row := setIDs(blockExerciseSets(block, 1))
+
+	start := time.Date(2025, 3, 3, 18, 0, 0, 0, time.UTC)
+
+	// Bench → row within a round: no rest
+	next := completeTimedSet(e, token, bench[0], start.Add(30*time.Second)).Value("next_set").Object()
+	next.Value("set_id").String().IsEqual(row[0])
+	next.Value("rest_seconds").Number().IsEqual(0)
+	next.Value("ready_at").String().AsDateTime(time.RFC3339Nano).IsEqual(start.Add(30 * time.Second))
+
+	// Row → bench starts a new round: rest between sets
+	next = completeTimedSet(e, token, row[0], start.Add(60*time.Second)).Value("next_set").Object()
+	next.Value("set_id").String().IsEqual(bench[1])
+	next.Value("rest_seconds").Number().IsEqual(120)
+	next.Value("ready_at").String().AsDateTime(time.RFC3339Nano).IsEqual(start.Add(180 * time.Second))
+}
+
+func testEMOMRestTimer(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "emomrest@example.com", "password123", "Emom", "Rest")
+	swingID := createAnalyticsExercise(e, token, "Kettlebell Swing")
+
+	block := startStructuredSession(e, token, map[string]interface{}{
+		"type":         "emom",
+		"group_rounds": 3,
+		"exercises": []map[string]interface{}{
+			{"exercise_id": swingID, "exercise_order": 1, "reps": 15},
+		},
+	})
+	ids := setIDs(blockExerciseSets(block, 0))
+
+	start := time.Date(2025, 3, 3, 18, 0, 0, 0, time.UTC)
+	startTimedSet(e, token, ids[0], start)
+
+	// The next set is due on the next minute, however fast the first one went
+	next := completeTimedSet(e, token, ids[0], start.Add(20*time.Second)).Value("next_set").Object()
+	next.Value("set_id").String().IsEqual(ids[1])
+	next.Value("rest_seconds").Number().IsEqual(40)
+	next.Value("ready_at").String().AsDateTime(time.RFC3339Nano).IsEqual(start.Add(60 * time.Second))
+
+	// A set that overruns its minute makes the next one due immediately
+	next = completeTimedSet(e, token, ids[1], start.Add(125*time.Second)).Value("next_set").Object()
+	next.Value("set_id").String().IsEqual(ids[2])
+	next.Value("rest_seconds").Number().IsEqual(0)
+	next.Value("ready_at").String().AsDateTime(time.RFC3339Nano).IsEqual(start.Add(125 * time.Second))
+}
+
+func testSetTimingValidation(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "timingval@example.com", "password123", "Timing", "Validation")
+	squatID := createAnalyticsExercise(e, token, "Back Squat")
+
+	block := startStructuredSession(e, token, map[string]interface{}{
+		"type": "straight",
+		"exercises": []map[string]interface{}{
+			{"exercise_id": squatID, "exercise_order": 1, "sets": 1, "reps": 5},
+		},
+	})
+	ids := setIDs(blockExerciseSets(block, 0))
+
+	start := time.Date(2025, 3, 3, 18, 0, 0, 0, time.UTC)
+	startTimedSet(e, token, ids[0], start)
+
+	// Completed before it was started
+	e.PUT("/api/v1/session-sets/"+ids[0]+"/complete").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"completed_at": start.Add(-time.Minute).Format(time.RFC3339),
+		}).
+		Expect().
+		Status(400)
+
+	completeTimedSet(e, token, ids[0], start.Add(time.Minute))
+
+	// A completed set cannot be started again
+	e.PUT("/api/v1/session-sets/"+ids[0]+"/start").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(400)
+
+	// Un-completing a set clears its completion time
+	e.PUT("/api/v1/session-sets/"+ids[0]).
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{
+			"completed": false,
+		}).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object().
+		NotContainsKey("completed_at")
+}
@@ -100,13 +100,19 @@ func CleanDatabase(t *testing.T) {
 
 	// Delete all data in reverse order to respect foreign key constraints
 	tables := []string{
+		"messages",
+		"conversations",
 		"workout_comment_reactions",
 		"workout_comments",
 		"shared_workouts",
+		"personal_records",
+		"activity_samples",
+		"session_activities",
 		"session_sets",
 		"session_exercises",
 		"session_blocks",
 		"workout_sessions",
+		"trainer_assignments",
 		"plan_enrollments",
 		"workout_prescriptions",
 		"workout_plan_items",
@@ -0,0 +1,288 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect/v2"
+)
+
+func TestTrainerAssignments(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Assign Workouts", func(t *testing.T) {
+		CleanDatabase(t)
+		testAssignWorkout(t, e)
+	})
+
+	t.Run("Assign Plans", func(t *testing.T) {
+		CleanDatabase(t)
+		testAssignPlan(t, e)
+	})
+}
+
+// assignToClient creates an assignment for a client and returns it
+func assignToClient(e *httpexpect.Expect, trainerToken string, clientID string, body map[string]interface{}) *httpexpect.Object {
+	return e.POST("/api/v1/trainers/clients/"+clientID+"/assignments").
+		WithHeader("Authorization", "Bearer "+trainerToken).
+		WithJSON(body).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().
+		Value("data").Object()
+}
+
+func testAssignWorkout(t *testing.T, e *httpexpect.Expect) {
+	trainerToken := createTestUserAndGetToken(e, "trainer@example.com", "password123", "Tina", "Trainer")
+	clientToken := createTestUserAndGetToken(e, "client@example.com", "password123", "Carl", "Client")
+	strangerToken := createTestUserAndGetToken(e, "stranger@example.com", "password123", "Sam", "Stranger")
+	clientID := createActiveTrainerClientLink(t, e, trainerToken, clientToken)
+
+	workoutID := createWorkout(e, trainerToken, "Conditioning")
+	today := time.Now().UTC().Format("2006-01-02")
+	yesterday := time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02")
+
+	t.Run("Only Active Clients", func(t *testing.T) {
+		e.POST("/api/v1/trainers/clients/"+getTestUserID(e, strangerToken)+"/assignments").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithJSON(map[string]interface{}{"workout_id": workoutID, "due_date": today}).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		// Neither a workout nor a plan
+		e.POST("/api/v1/trainers/clients/"+clientID+"/assignments").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithJSON(map[string]interface{}{"due_date": today}).
+			Expect().
+			Status(http.StatusBadRequest)
+
+		// Workouts need a due date that has not passed
+		e.POST("/api/v1/trainers/clients/"+clientID+"/assignments").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithJSON(map[string]interface{}{"workout_id": workoutID}).
+			Expect().
+			Status(http.StatusBadRequest)
+
+		e.POST("/api/v1/trainers/clients/"+clientID+"/assignments").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithJSON(map[string]interface{}{"workout_id": workoutID, "due_date": yesterday}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("Only The Trainer's Workouts", func(t *testing.T) {
+		clientWorkoutID := createWorkout(e, clientToken, "Client Workout")
+		e.POST("/api/v1/trainers/clients/"+clientID+"/assignments").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithJSON(map[string]interface{}{"workout_id": clientWorkoutID, "due_date": today}).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+
+	assignment := assignToClient(e, trainerToken, clientID, map[string]interface{}{
+		"workout_id": workoutID,
+		"due_date":   today,
+		"notes":      "Keep the rest short",
+	})
+	assignment.HasValue("type", "workout")
+	assignment.HasValue("title", "Conditioning")
+	assignment.HasValue("status", "pending")
+	assignment.HasValue("notes", "Keep the rest short")
+	assignment.Value("client").Object().HasValue("id", clientID)
+
+	t.Run("The Workout Is Shared With The Client", func(t *testing.T) {
+		e.GET("/api/v1/workouts/"+workoutID).
+			WithHeader("Authorization", "Bearer "+clientToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			HasValue("permission", "view")
+	})
+
+	t.Run("Client Sees Their Assignments", func(t *testing.T) {
+		assignments := e.GET("/api/v1/me/assignments").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array()
+		assignments.Length().IsEqual(1)
+		mine := assignments.Value(0).Object()
+		mine.HasValue("title", "Conditioning")
+		mine.Value("trainer").Object().HasValue("first_name", "Tina")
+	})
+
+	t.Run("Completing The Workout Completes The Assignment", func(t *testing.T) {
+		completeScheduleSession(e, clientToken, workoutID)
+
+		assignment := e.GET("/api/v1/trainers/clients/"+clientID+"/assignments").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array().Value(0).Object()
+		assignment.HasValue("status", "completed")
+		assignment.HasValue("completed_sessions", 1)
+		assignment.ContainsKey("completed_at")
+	})
+
+	t.Run("The Share Is Removed With The Last Assignment", func(t *testing.T) {
+		second := assignToClient(e, trainerToken, clientID, map[string]interface{}{
+			"workout_id": workoutID,
+			"due_date":   today,
+		})
+
+		e.DELETE("/api/v1/trainers/clients/"+clientID+"/assignments/"+assignment.Value("id").String().Raw()).
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusOK)
+
+		// The second assignment still needs the share
+		e.GET("/api/v1/workouts/"+workoutID).
+			WithHeader("Authorization", "Bearer "+clientToken).
+			Expect().
+			Status(http.StatusOK)
+
+		e.DELETE("/api/v1/trainers/clients/"+clientID+"/assignments/"+second.Value("id").String().Raw()).
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusOK)
+
+		e.GET("/api/v1/workouts/"+workoutID).
+			WithHeader("Authorization", "Bearer "+clientToken).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+
+	t.Run("A Share Made By The Trainer Is Kept", func(t *testing.T) {
+		sharedWorkoutID := createWorkout(e, trainerToken, "Mobility")
+		shareTestWorkout(e, trainerToken, sharedWorkoutID, clientID, "copy")
+
+		assigned := assignToClient(e, trainerToken, clientID, map[string]interface{}{
+			"workout_id": sharedWorkoutID,
+			"due_date":   today,
+		})
+		e.DELETE("/api/v1/trainers/clients/"+clientID+"/assignments/"+assigned.Value("id").String().Raw()).
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusOK)
+
+		e.GET("/api/v1/workouts/"+sharedWorkoutID).
+			WithHeader("Authorization", "Bearer "+clientToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			HasValue("permission", "copy")
+	})
+
+	t.Run("Other Users Cannot List A Client's Assignments", func(t *testing.T) {
+		e.GET("/api/v1/trainers/clients/"+clientID+"/assignments").
+			WithHeader("Authorization", "Bearer "+strangerToken).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+}
+
+func testAssignPlan(t *testing.T, e *httpexpect.Expect) {
+	trainerToken := createTestUserAndGetToken(e, "trainer@example.com", "password123", "Tina", "Trainer")
+	clientToken := createTestUserAndGetToken(e, "client@example.com", "password123", "Carl", "Client")
+	clientID := createActiveTrainerClientLink(t, e, trainerToken, clientToken)
+
+	pushID := createWorkout(e, trainerToken, "Push")
+	pullID := createWorkout(e, trainerToken, "Pull")
+	planID := createSchedulePlan(e, trainerToken, "Push Pull", map[int][]string{0: {pushID, pullID}})
+
+	t.Run("Days Per Week Is Required", func(t *testing.T) {
+		e.POST("/api/v1/trainers/clients/"+clientID+"/assignments").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithJSON(map[string]interface{}{"plan_id": planID}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	assignment := assignToClient(e, trainerToken, clientID, map[string]interface{}{
+		"plan_id":       planID,
+		"days_per_week": 2,
+	})
+	assignment.HasValue("type", "plan")
+	assignment.HasValue("title", "Push Pull")
+	assignment.HasValue("status", "pending")
+	assignmentID := assignment.Value("id").String().Raw()
+	enrollmentID := assignment.Value("enrollment_id").String().Raw()
+
+	t.Run("The Client Is Enrolled", func(t *testing.T) {
+		e.GET("/api/v1/enrollments/"+enrollmentID).
+			WithHeader("Authorization", "Bearer "+clientToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			HasValue("plan_id", planID).
+			HasValue("status", "active")
+
+		e.POST("/api/v1/trainers/clients/"+clientID+"/assignments").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithJSON(map[string]interface{}{"plan_id": planID, "days_per_week": 2}).
+			Expect().
+			Status(http.StatusConflict)
+	})
+
+	t.Run("Progress Through The Plan", func(t *testing.T) {
+		completeEnrollmentSession(e, clientToken, map[string]interface{}{"enrollment_id": enrollmentID})
+
+		assignment := e.GET("/api/v1/trainers/clients/"+clientID+"/assignments").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array().Value(0).Object()
+		assignment.HasValue("status", "in_progress")
+		assignment.HasValue("completed_sessions", 1)
+
+		completeEnrollmentSession(e, clientToken, map[string]interface{}{"enrollment_id": enrollmentID})
+
+		e.GET("/api/v1/trainers/clients/"+clientID+"/assignments").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array().Value(0).Object().
+			HasValue("status", "completed")
+	})
+
+	t.Run("Deleting An Assignment Cancels The Enrollment", func(t *testing.T) {
+		otherPlanID := createSchedulePlan(e, trainerToken, "Pull Only", map[int][]string{0: {pullID}})
+		other := assignToClient(e, trainerToken, clientID, map[string]interface{}{
+			"plan_id":       otherPlanID,
+			"days_per_week": 1,
+		})
+
+		e.DELETE("/api/v1/trainers/clients/"+clientID+"/assignments/"+other.Value("id").String().Raw()).
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusOK)
+
+		e.GET("/api/v1/enrollments/"+other.Value("enrollment_id").String().Raw()).
+			WithHeader("Authorization", "Bearer "+clientToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			HasValue("status", "cancelled")
+
+		// The finished plan's assignment is unaffected
+		assignments := e.GET("/api/v1/trainers/clients/"+clientID+"/assignments").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array()
+		assignments.Length().IsEqual(1)
+		assignments.Value(0).Object().HasValue("id", assignmentID)
+	})
+}
@@ -0,0 +1,225 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/google/uuid"
+)
+
+func TestTrainerClientOverview(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Client Overview", func(t *testing.T) {
+		CleanDatabase(t)
+		testClientOverview(t, e)
+	})
+
+	t.Run("Client Roster", func(t *testing.T) {
+		CleanDatabase(t)
+		testClientRoster(t, e)
+	})
+}
+
+// createTestFitnessProfile gives the user a fitness profile with a weekly workout target and injury notes
+func createTestFitnessProfile(e *httpexpect.Expect, token string, targetWeeklyWorkouts int, injuriesNotes string) {
+	database.DB.Create(&models.UserFitnessProfile{
+		UserID:               uuid.MustParse(getTestUserID(e, token)),
+		DateOfBirth:          time.Date(1990, 5, 1, 0, 0, 0, 0, time.UTC),
+		Gender:               "female",
+		HeightCm:             170,
+		TargetWeeklyWorkouts: targetWeeklyWorkouts,
+		InjuriesNotes:        injuriesNotes,
+	})
+}
+
+func testClientOverview(t *testing.T, e *httpexpect.Expect) {
+	trainerToken := createTestUserAndGetToken(e, "trainer@example.com", "password123", "Tina", "Trainer")
+	clientToken := createTestUserAndGetToken(e, "client@example.com", "password123", "Carl", "Client")
+	strangerToken := createTestUserAndGetToken(e, "stranger@example.com", "password123", "Sam", "Stranger")
+	clientID := createActiveTrainerClientLink(t, e, trainerToken, clientToken)
+	setTrainerConsent(e, clientToken, "sessions", "log_sessions", "body_weight", "health")
+
+	createTestFitnessProfile(e, clientToken, 4, "Recovering from a left knee sprain")
+	seedExportData(e, clientToken)
+	e.POST("/api/v1/user/weight-logs").
+		WithHeader("Authorization", "Bearer "+clientToken).
+		WithJSON(map[string]interface{}{"weight_kg": 78.5}).
+		Expect().
+		Status(http.StatusCreated)
+
+	workoutID := createWorkout(e, trainerToken, "Conditioning")
+	completeScheduleSession(e, clientToken, workoutID)
+	assignToClient(e, trainerToken, clientID, map[string]interface{}{
+		"workout_id": createWorkout(e, trainerToken, "Mobility"),
+		"due_date":   time.Now().UTC().AddDate(0, 0, 7).Format("2006-01-02"),
+	})
+
+	t.Run("Only Trainers Of Active Clients", func(t *testing.T) {
+		e.GET("/api/v1/trainers/clients/"+clientID+"/overview").
+			WithHeader("Authorization", "Bearer "+strangerToken).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	overview := e.GET("/api/v1/trainers/clients/"+clientID+"/overview").
+		WithHeader("Authorization", "Bearer "+trainerToken).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("data").Object()
+
+	t.Run("Activity", func(t *testing.T) {
+		overview.Value("client").Object().HasValue("id", clientID)
+		overview.ContainsKey("linked_since")
+		overview.Value("consent_scopes").Array().ContainsOnly("sessions", "log_sessions", "body_weight", "health")
+		overview.ContainsKey("last_session_at")
+		overview.HasValue("days_since_last_session", 0)
+		overview.HasValue("sessions_this_week", 1)
+		overview.HasValue("target_weekly_workouts", 4)
+	})
+
+	t.Run("Weight Trend In The Trainer's Unit", func(t *testing.T) {
+		trend := overview.Value("weight_trend").Object()
+		trend.Value("entries").Array().Length().IsEqual(2)
+		trend.Value("latest").Object().HasValue("weight_value", 78.5).HasValue("weight_unit", "kg")
+		trend.Value("change").Object().HasValue("weight_value", -1.5)
+	})
+
+	t.Run("Records Assignments And Notes", func(t *testing.T) {
+		overview.Value("recent_records").Array().NotEmpty()
+		assignments := overview.Value("open_assignments").Array()
+		assignments.Length().IsEqual(1)
+		assignments.Value(0).Object().HasValue("title", "Mobility")
+		overview.HasValue("injuries_notes", "Recovering from a left knee sprain")
+	})
+
+	t.Run("Removed Clients Are Hidden", func(t *testing.T) {
+		linkID := e.GET("/api/v1/trainers/clients").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array().Value(0).Object().Value("id").String().Raw()
+
+		e.DELETE("/api/v1/trainers/clients/"+linkID).
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusOK)
+
+		e.GET("/api/v1/trainers/clients/"+clientID+"/overview").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+}
+
+func testClientRoster(t *testing.T, e *httpexpect.Expect) {
+	trainerToken := createTestUserAndGetToken(e, "trainer@example.com", "password123", "Tina", "Trainer")
+	activeToken := createTestUserAndGetToken(e, "active@example.com", "password123", "Ava", "Active")
+	idleToken := createTestUserAndGetToken(e, "idle@example.com", "password123", "Ian", "Idle")
+	newToken := createTestUserAndGetToken(e, "new@example.com", "password123", "Nina", "New")
+	privateToken := createTestUserAndGetToken(e, "private@example.com", "password123", "Pia", "Private")
+	activeID := createActiveTrainerClientLink(t, e, trainerToken, activeToken)
+	idleID := createActiveTrainerClientLink(t, e, trainerToken, idleToken)
+	newID := createActiveTrainerClientLink(t, e, trainerToken, newToken)
+	privateID := createActiveTrainerClientLink(t, e, trainerToken, privateToken)
+	setTrainerConsent(e, privateToken)
+
+	workoutID := createWorkout(e, trainerToken, "Conditioning")
+	completeScheduleSession(e, activeToken, workoutID)
+	completeScheduleSession(e, idleToken, workoutID)
+	completeScheduleSession(e, privateToken, workoutID)
+
+	// The idle client last trained ten days ago
+	database.DB.Model(&models.WorkoutSession{}).
+		Where("user_id = ?", idleID).
+		Update("started_at", time.Now().AddDate(0, 0, -10))
+
+	createTestFitnessProfile(e, activeToken, 3, "")
+	assignToClient(e, trainerToken, newID, map[string]interface{}{
+		"workout_id": workoutID,
+		"due_date":   time.Now().UTC().Format("2006-01-02"),
+	})
+	assignToClient(e, trainerToken, privateID, map[string]interface{}{
+		"workout_id": workoutID,
+		"due_date":   time.Now().UTC().Format("2006-01-02"),
+	})
+
+	t.Run("Clients Cannot See A Roster", func(t *testing.T) {
+		e.GET("/api/v1/trainers/clients/roster").
+			WithHeader("Authorization", "Bearer "+activeToken).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	roster := e.GET("/api/v1/trainers/clients/roster").
+		WithHeader("Authorization", "Bearer "+trainerToken).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("data").Array()
+	roster.Length().IsEqual(4)
+
+	t.Run("Sorted By Inactivity", func(t *testing.T) {
+		first := roster.Value(0).Object()
+		first.Value("client").Object().HasValue("id", newID)
+		first.NotContainsKey("last_session_at")
+		first.HasValue("open_assignments", 1)
+
+		second := roster.Value(1).Object()
+		second.Value("client").Object().HasValue("id", idleID)
+		second.HasValue("days_since_last_session", 10)
+		second.HasValue("sessions_this_week", 0)
+
+		third := roster.Value(2).Object()
+		third.Value("client").Object().HasValue("id", activeID)
+		third.HasValue("days_since_last_session", 0)
+		third.HasValue("sessions_this_week", 1)
+		third.HasValue("target_weekly_workouts", 3)
+	})
+
+	t.Run("Clients Who Keep Sessions Private Come Last", func(t *testing.T) {
+		last := roster.Value(3).Object()
+		last.Value("client").Object().HasValue("id", privateID)
+		last.Value("consent_scopes").Array().IsEmpty()
+		last.NotContainsKey("last_session_at")
+		last.HasValue("sessions_this_week", 0)
+		last.HasValue("open_assignments", 0)
+		last.HasValue("overdue_assignments", 0)
+	})
+
+	t.Run("Assignment Progress Of Private Clients Is Hidden", func(t *testing.T) {
+		assignment := e.GET("/api/v1/trainers/clients/"+privateID+"/assignments").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array().Value(0).Object()
+		assignment.HasValue("status", "hidden")
+		assignment.HasValue("completed_sessions", 0)
+		assignment.NotContainsKey("completed_at")
+
+		// The client still sees their own progress
+		e.GET("/api/v1/me/assignments").
+			WithHeader("Authorization", "Bearer "+privateToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array().Value(0).Object().
+			HasValue("status", "completed")
+
+		e.GET("/api/v1/trainers/clients/"+privateID+"/overview").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			Value("open_assignments").Array().IsEmpty()
+	})
+}
@@ -465,3 +465,52 @@ func testTrainerClientAuthorization(t *testing.T, e *httpexpect.Expect) {
 		response.Value("message").String().Contains("not found")
 	})
 }
+
+// getTestUserID returns the user ID for the given token
+func getTestUserID(e *httpexpect.Expect, token string) string {
+	return e.GET("/api/v1/auth/profile").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(200).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+}
+
+// createActiveTrainerClientLink creates a trainer profile for the trainer, invites the
+// client and accepts the invitation. Returns the client's user ID.
+func createActiveTrainerClientLink(t *testing.T, e *httpexpect.Expect, trainerToken, clientToken string) string {
+	SeedTestSpecialties(t)
+	specialtyIDs := GetSpecialtyIDs(t, "Strength Training")
+
+	// Trainer profile may already exist when linking several clients
+	e.POST("/api/v1/trainers/profile").
+		WithHeader("Authorization", "Bearer "+trainerToken).
+		WithJSON(map[string]interface{}{
+			"bio":           "Certified personal trainer.",
+			"specialty_ids": specialtyIDs,
+			"hourly_rate":   75.00,
+		}).
+		Expect()
+
+	clientID := getTestUserID(e, clientToken)
+
+	invitationID := e.POST("/api/v1/trainers/clients").
+		WithHeader("Authorization", "Bearer "+trainerToken).
+		WithJSON(map[string]interface{}{
+			"client_id": clientID,
+		}).
+		Expect().
+		Status(201).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+
+	e.PUT("/api/v1/me/trainer-invitations/"+invitationID).
+		WithHeader("Authorization", "Bearer "+clientToken).
+		WithJSON(map[string]interface{}{
+			"action": "accept",
+		}).
+		Expect().
+		Status(200)
+
+	return clientID
+}
@@ -0,0 +1,316 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+)
+
+func TestTrainerConsent(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Manage Consent", func(t *testing.T) {
+		CleanDatabase(t)
+		testManageTrainerConsent(t, e)
+	})
+
+	t.Run("Session Consent", func(t *testing.T) {
+		CleanDatabase(t)
+		testSessionConsent(t, e)
+	})
+
+	t.Run("Body Weight And Health Consent", func(t *testing.T) {
+		CleanDatabase(t)
+		testBodyWeightAndHealthConsent(t, e)
+	})
+}
+
+// setTrainerConsent replaces the consent scopes the client grants their only trainer
+func setTrainerConsent(e *httpexpect.Expect, clientToken string, scopes ...string) {
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	linkID := e.GET("/api/v1/me/trainers").
+		WithHeader("Authorization", "Bearer "+clientToken).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("data").Array().Value(0).Object().Value("id").String().Raw()
+
+	e.PUT("/api/v1/me/trainers/"+linkID+"/consent").
+		WithHeader("Authorization", "Bearer "+clientToken).
+		WithJSON(map[string]interface{}{"scopes": scopes}).
+		Expect().
+		Status(http.StatusOK)
+}
+
+func testManageTrainerConsent(t *testing.T, e *httpexpect.Expect) {
+	trainerToken := createTestUserAndGetToken(e, "trainer@example.com", "password123", "Tina", "Trainer")
+	clientToken := createTestUserAndGetToken(e, "client@example.com", "password123", "Carl", "Client")
+	strangerToken := createTestUserAndGetToken(e, "stranger@example.com", "password123", "Sam", "Stranger")
+	createActiveTrainerClientLink(t, e, trainerToken, clientToken)
+
+	link := e.GET("/api/v1/me/trainers").
+		WithHeader("Authorization", "Bearer "+clientToken).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("data").Array().Value(0).Object()
+	linkID := link.Value("id").String().Raw()
+
+	t.Run("Trainers Start With Session Access", func(t *testing.T) {
+		link.Value("consent_scopes").Array().ContainsOnly("sessions", "log_sessions")
+	})
+
+	t.Run("Unknown Scopes Are Rejected", func(t *testing.T) {
+		e.PUT("/api/v1/me/trainers/"+linkID+"/consent").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			WithJSON(map[string]interface{}{"scopes": []string{"sessions", "everything"}}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("Only The Client Can Change Consent", func(t *testing.T) {
+		e.PUT("/api/v1/me/trainers/"+linkID+"/consent").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithJSON(map[string]interface{}{"scopes": []string{"health"}}).
+			Expect().
+			Status(http.StatusNotFound)
+
+		e.PUT("/api/v1/me/trainers/"+linkID+"/consent").
+			WithHeader("Authorization", "Bearer "+strangerToken).
+			WithJSON(map[string]interface{}{"scopes": []string{"health"}}).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+
+	t.Run("Client Replaces The Scopes", func(t *testing.T) {
+		e.PUT("/api/v1/me/trainers/"+linkID+"/consent").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			WithJSON(map[string]interface{}{"scopes": []string{"body_weight", "health", "health"}}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			Value("consent_scopes").Array().ContainsOnly("body_weight", "health")
+
+		e.PUT("/api/v1/me/trainers/"+linkID+"/consent").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			WithJSON(map[string]interface{}{"scopes": []string{}}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			Value("consent_scopes").Array().IsEmpty()
+	})
+}
+
+func testSessionConsent(t *testing.T, e *httpexpect.Expect) {
+	trainerToken := createTestUserAndGetToken(e, "trainer@example.com", "password123", "Tina", "Trainer")
+	clientToken := createTestUserAndGetToken(e, "client@example.com", "password123", "Carl", "Client")
+	clientID := createActiveTrainerClientLink(t, e, trainerToken, clientToken)
+
+	sessionID := e.POST("/api/v1/workout-sessions").
+		WithHeader("Authorization", "Bearer "+trainerToken).
+		WithJSON(map[string]interface{}{"user_id": clientID, "notes": "PT session"}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+
+	ownSessionID := e.POST("/api/v1/workout-sessions").
+		WithHeader("Authorization", "Bearer "+clientToken).
+		WithJSON(map[string]interface{}{"notes": "Solo session"}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+
+	t.Run("Trainer Sees All Shared Sessions", func(t *testing.T) {
+		e.GET("/api/v1/workout-sessions").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithQuery("client_id", clientID).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array().Length().IsEqual(2)
+
+		e.GET("/api/v1/workout-sessions/"+ownSessionID).
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusOK)
+
+		// Viewing a session does not allow editing it
+		e.PUT("/api/v1/workout-sessions/"+ownSessionID).
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithJSON(map[string]interface{}{"notes": "Edited by trainer"}).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("Without Session Sharing Only Logged Sessions Are Visible", func(t *testing.T) {
+		setTrainerConsent(e, clientToken, "log_sessions")
+
+		sessions := e.GET("/api/v1/workout-sessions").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithQuery("client_id", clientID).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array()
+		sessions.Length().IsEqual(1)
+		sessions.Value(0).Object().HasValue("id", sessionID)
+
+		e.GET("/api/v1/workout-sessions/"+ownSessionID).
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusNotFound)
+
+		e.GET("/api/v1/trainers/clients/"+clientID+"/muscle-volume").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("Without Logging Consent The Trainer Cannot Log Or Edit Sessions", func(t *testing.T) {
+		setTrainerConsent(e, clientToken, "sessions")
+
+		e.POST("/api/v1/workout-sessions").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithJSON(map[string]interface{}{"user_id": clientID}).
+			Expect().
+			Status(http.StatusForbidden)
+
+		e.PUT("/api/v1/workout-sessions/"+sessionID).
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithJSON(map[string]interface{}{"notes": "Edited by trainer"}).
+			Expect().
+			Status(http.StatusForbidden)
+
+		// The session they logged stays visible through session sharing
+		e.GET("/api/v1/workout-sessions/"+sessionID).
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusOK)
+	})
+
+	t.Run("Without Any Consent Sessions Are Private", func(t *testing.T) {
+		setTrainerConsent(e, clientToken)
+
+		e.GET("/api/v1/workout-sessions").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithQuery("client_id", clientID).
+			Expect().
+			Status(http.StatusForbidden)
+
+		e.GET("/api/v1/workout-sessions/"+sessionID).
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+}
+
+func testBodyWeightAndHealthConsent(t *testing.T, e *httpexpect.Expect) {
+	trainerToken := createTestUserAndGetToken(e, "trainer@example.com", "password123", "Tina", "Trainer")
+	clientToken := createTestUserAndGetToken(e, "client@example.com", "password123", "Carl", "Client")
+	strangerToken := createTestUserAndGetToken(e, "stranger@example.com", "password123", "Sam", "Stranger")
+	clientID := createActiveTrainerClientLink(t, e, trainerToken, clientToken)
+
+	createTestFitnessProfile(e, clientToken, 3, "Recovering from a left knee sprain")
+	e.POST("/api/v1/user/weight-logs").
+		WithHeader("Authorization", "Bearer "+clientToken).
+		WithJSON(map[string]interface{}{"weight_kg": 78.5}).
+		Expect().
+		Status(http.StatusCreated)
+
+	t.Run("Body Weight Is Private By Default", func(t *testing.T) {
+		e.GET("/api/v1/user/weight-logs").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithQuery("client_id", clientID).
+			Expect().
+			Status(http.StatusForbidden)
+
+		e.GET("/api/v1/user/weight-logs/stats").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithQuery("client_id", clientID).
+			Expect().
+			Status(http.StatusForbidden)
+
+		e.GET("/api/v1/trainers/clients/"+clientID+"/overview").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			NotContainsKey("weight_trend").
+			NotContainsKey("injuries_notes")
+	})
+
+	t.Run("Profile Hides What Is Not Shared", func(t *testing.T) {
+		profile := e.GET("/api/v1/user/fitness-profile").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithQuery("client_id", clientID).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object()
+		profile.HasValue("target_weekly_workouts", 3)
+		profile.Value("current_weight").Object().NotContainsKey("weight_value")
+		profile.NotContainsKey("injuries_notes")
+
+		e.GET("/api/v1/user/fitness-profile").
+			WithHeader("Authorization", "Bearer "+strangerToken).
+			WithQuery("client_id", clientID).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	setTrainerConsent(e, clientToken, "sessions", "log_sessions", "body_weight", "health")
+
+	t.Run("Shared Body Weight", func(t *testing.T) {
+		e.GET("/api/v1/user/weight-logs").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithQuery("client_id", clientID).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array().Length().IsEqual(1)
+
+		e.GET("/api/v1/user/weight-logs/stats").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithQuery("client_id", clientID).
+			Expect().
+			Status(http.StatusOK)
+
+		e.GET("/api/v1/user/fitness-profile").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithQuery("client_id", clientID).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			Value("current_weight").Object().
+			HasValue("weight_value", 78.5)
+	})
+
+	t.Run("Shared Health Notes", func(t *testing.T) {
+		e.GET("/api/v1/user/fitness-profile").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithQuery("client_id", clientID).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			HasValue("injuries_notes", "Recovering from a left knee sprain")
+	})
+
+	t.Run("Strangers Still See Nothing", func(t *testing.T) {
+		e.GET("/api/v1/user/weight-logs").
+			WithHeader("Authorization", "Bearer "+strangerToken).
+			WithQuery("client_id", clientID).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+}
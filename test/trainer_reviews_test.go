@@ -0,0 +1,304 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+
+	"lamari-fit-api/models"
+
+	"github.com/gavv/httpexpect/v2"
+)
+
+func TestTrainerReviews(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Clients Review Trainers", func(t *testing.T) {
+		CleanDatabase(t)
+		testCreateTrainerReviews(t, e)
+	})
+
+	t.Run("Replies And Moderation", func(t *testing.T) {
+		CleanDatabase(t)
+		testTrainerReviewRepliesAndModeration(t, e)
+	})
+
+	t.Run("Rating Aggregates", func(t *testing.T) {
+		CleanDatabase(t)
+		testTrainerRatingAggregates(t, e)
+	})
+}
+
+// getTrainerProfileID returns the trainer profile ID of the token's user
+func getTrainerProfileID(e *httpexpect.Expect, token string) string {
+	return e.GET("/api/v1/trainers/profile").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+}
+
+// postTrainerReview reviews a trainer and returns the review's ID
+func postTrainerReview(e *httpexpect.Expect, token string, trainerProfileID string, rating int) string {
+	return e.POST("/api/v1/trainers/"+trainerProfileID+"/reviews").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{"rating": rating, "comment": "Great coaching"}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+}
+
+func testCreateTrainerReviews(t *testing.T, e *httpexpect.Expect) {
+	trainerToken := createTestUserAndGetToken(e, "trainer@example.com", "password123", "Tina", "Trainer")
+	clientToken := createTestUserAndGetToken(e, "client@example.com", "password123", "Carl", "Client")
+	strangerToken := createTestUserAndGetToken(e, "stranger@example.com", "password123", "Sam", "Stranger")
+	createActiveTrainerClientLink(t, e, trainerToken, clientToken)
+	trainerProfileID := getTrainerProfileID(e, trainerToken)
+	reviewsPath := "/api/v1/trainers/" + trainerProfileID + "/reviews"
+
+	t.Run("Only Clients Can Review", func(t *testing.T) {
+		e.POST(reviewsPath).
+			WithHeader("Authorization", "Bearer "+strangerToken).
+			WithJSON(map[string]interface{}{"rating": 1}).
+			Expect().
+			Status(http.StatusForbidden)
+
+		e.POST(reviewsPath).
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithJSON(map[string]interface{}{"rating": 5}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("Rating Is Validated", func(t *testing.T) {
+		e.POST(reviewsPath).
+			WithHeader("Authorization", "Bearer "+clientToken).
+			WithJSON(map[string]interface{}{"rating": 6}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	reviewID := postTrainerReview(e, clientToken, trainerProfileID, 4)
+
+	t.Run("One Review Per Client", func(t *testing.T) {
+		e.POST(reviewsPath).
+			WithHeader("Authorization", "Bearer "+clientToken).
+			WithJSON(map[string]interface{}{"rating": 5}).
+			Expect().
+			Status(http.StatusConflict)
+	})
+
+	t.Run("Reviewer Updates Their Review", func(t *testing.T) {
+		e.PUT(reviewsPath+"/"+reviewID).
+			WithHeader("Authorization", "Bearer "+strangerToken).
+			WithJSON(map[string]interface{}{"rating": 1}).
+			Expect().
+			Status(http.StatusForbidden)
+
+		e.PUT(reviewsPath+"/"+reviewID).
+			WithHeader("Authorization", "Bearer "+clientToken).
+			WithJSON(map[string]interface{}{"rating": 5}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			HasValue("rating", 5).
+			HasValue("comment", "Great coaching")
+	})
+
+	t.Run("Former Clients Keep Their Review", func(t *testing.T) {
+		linkID := e.GET("/api/v1/trainers/clients").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array().Value(0).Object().Value("id").String().Raw()
+
+		e.DELETE("/api/v1/trainers/clients/"+linkID).
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			Expect().
+			Status(http.StatusOK)
+
+		e.PUT(reviewsPath+"/"+reviewID).
+			WithHeader("Authorization", "Bearer "+clientToken).
+			WithJSON(map[string]interface{}{"comment": "Great coaching, sad to leave"}).
+			Expect().
+			Status(http.StatusOK)
+	})
+
+	t.Run("List Reviews", func(t *testing.T) {
+		reviews := e.GET(reviewsPath).
+			WithHeader("Authorization", "Bearer "+strangerToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array()
+		reviews.Length().IsEqual(1)
+		review := reviews.Value(0).Object()
+		review.HasValue("rating", 5)
+		review.Value("reviewer").Object().HasValue("first_name", "Carl")
+	})
+
+	t.Run("Reviewer Deletes Their Review", func(t *testing.T) {
+		e.DELETE(reviewsPath+"/"+reviewID).
+			WithHeader("Authorization", "Bearer "+clientToken).
+			Expect().
+			Status(http.StatusOK)
+
+		// A deleted review can be written again
+		postTrainerReview(e, clientToken, trainerProfileID, 3)
+	})
+}
+
+func testTrainerReviewRepliesAndModeration(t *testing.T, e *httpexpect.Expect) {
+	trainerToken := createTestUserAndGetToken(e, "trainer@example.com", "password123", "Tina", "Trainer")
+	clientToken := createTestUserAndGetToken(e, "client@example.com", "password123", "Carl", "Client")
+	adminToken := createTestUserAndGetToken(e, "admin@example.com", "password123", "Ada", "Admin")
+	testDB.Model(&models.User{}).Where("email = ?", "admin@example.com").Update("is_admin", true)
+	createActiveTrainerClientLink(t, e, trainerToken, clientToken)
+	trainerProfileID := getTrainerProfileID(e, trainerToken)
+	reviewPath := "/api/v1/trainers/" + trainerProfileID + "/reviews/" + postTrainerReview(e, clientToken, trainerProfileID, 2)
+
+	t.Run("Trainer Replies", func(t *testing.T) {
+		e.PUT(reviewPath+"/reply").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			WithJSON(map[string]interface{}{"reply": "Replying to myself"}).
+			Expect().
+			Status(http.StatusForbidden)
+
+		review := e.PUT(reviewPath+"/reply").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithJSON(map[string]interface{}{"reply": "Thanks for the feedback"}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object()
+		review.HasValue("reply", "Thanks for the feedback")
+		review.Value("replied_at").String().NotEmpty()
+	})
+
+	t.Run("Only Admins Moderate", func(t *testing.T) {
+		e.PUT(reviewPath+"/moderation").
+			WithHeader("Authorization", "Bearer "+trainerToken).
+			WithJSON(map[string]interface{}{"hidden": true}).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("Hidden Reviews Leave The Rating", func(t *testing.T) {
+		e.PUT(reviewPath+"/moderation").
+			WithHeader("Authorization", "Bearer "+adminToken).
+			WithJSON(map[string]interface{}{"hidden": true, "reason": "Off-topic"}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			HasValue("hidden", true).
+			HasValue("hidden_reason", "Off-topic")
+
+		e.GET("/api/v1/trainers/"+trainerProfileID+"/reviews").
+			WithHeader("Authorization", "Bearer "+clientToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array().Length().IsEqual(0)
+
+		e.GET("/api/v1/trainers/"+trainerProfileID+"/reviews").
+			WithHeader("Authorization", "Bearer "+adminToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array().Length().IsEqual(1)
+
+		e.GET("/api/v1/trainers/"+trainerProfileID).
+			WithHeader("Authorization", "Bearer "+clientToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			HasValue("review_count", 0)
+	})
+
+	t.Run("Unhide", func(t *testing.T) {
+		e.PUT(reviewPath+"/moderation").
+			WithHeader("Authorization", "Bearer "+adminToken).
+			WithJSON(map[string]interface{}{"hidden": false}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			HasValue("hidden", false)
+	})
+}
+
+func testTrainerRatingAggregates(t *testing.T, e *httpexpect.Expect) {
+	topTrainerToken := createTestUserAndGetToken(e, "top@example.com", "password123", "Top", "Trainer")
+	lowTrainerToken := createTestUserAndGetToken(e, "low@example.com", "password123", "Low", "Trainer")
+	newTrainerToken := createTestUserAndGetToken(e, "new@example.com", "password123", "New", "Trainer")
+	client1Token := createTestUserAndGetToken(e, "client1@example.com", "password123", "Client", "One")
+	client2Token := createTestUserAndGetToken(e, "client2@example.com", "password123", "Client", "Two")
+
+	createActiveTrainerClientLink(t, e, topTrainerToken, client1Token)
+	createActiveTrainerClientLink(t, e, topTrainerToken, client2Token)
+	createActiveTrainerClientLink(t, e, lowTrainerToken, client1Token)
+	e.POST("/api/v1/trainers/profile").
+		WithHeader("Authorization", "Bearer "+newTrainerToken).
+		WithJSON(map[string]interface{}{"bio": "Just started."}).
+		Expect().
+		Status(http.StatusCreated)
+
+	topProfileID := getTrainerProfileID(e, topTrainerToken)
+	lowProfileID := getTrainerProfileID(e, lowTrainerToken)
+	postTrainerReview(e, client1Token, topProfileID, 5)
+	postTrainerReview(e, client2Token, topProfileID, 4)
+	postTrainerReview(e, client1Token, lowProfileID, 2)
+
+	t.Run("Public Profile", func(t *testing.T) {
+		e.GET("/api/v1/trainers/"+topProfileID).
+			WithHeader("Authorization", "Bearer "+client1Token).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			HasValue("review_count", 2).
+			HasValue("average_rating", 4.5)
+	})
+
+	t.Run("List Sorted By Rating", func(t *testing.T) {
+		trainers := e.GET("/api/v1/trainers/").
+			WithHeader("Authorization", "Bearer "+client1Token).
+			WithQuery("sort_by", "rating").
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array()
+		trainers.Length().IsEqual(3)
+		trainers.Value(0).Object().HasValue("id", topProfileID).HasValue("review_count", 2)
+		trainers.Value(1).Object().HasValue("id", lowProfileID).HasValue("average_rating", 2)
+		trainers.Value(2).Object().HasValue("review_count", 0)
+	})
+
+	t.Run("List Filtered By Min Rating", func(t *testing.T) {
+		response := e.GET("/api/v1/trainers/").
+			WithHeader("Authorization", "Bearer "+client1Token).
+			WithQuery("min_rating", 4).
+			WithQuery("limit", 1).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object()
+		response.Value("meta").Object().HasValue("total_items", 1)
+		response.Value("data").Array().Value(0).Object().HasValue("id", topProfileID)
+	})
+
+	t.Run("Search Filtered By Min Rating", func(t *testing.T) {
+		response := e.GET("/api/v1/search/trainers").
+			WithHeader("Authorization", "Bearer "+client1Token).
+			WithQuery("min_rating", 1).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object()
+		response.Value("meta").Object().HasValue("total_items", 2)
+		response.Value("data").Array().Value(0).Object().ContainsKey("average_rating")
+	})
+}
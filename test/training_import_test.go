@@ -0,0 +1,166 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+)
+
+const importTestStrongCSV = `Date;Workout Name;Duration;Exercise Name;Set Order;Weight;Reps;Distance;Seconds;Notes;Workout Notes;RPE
+2024-03-02 09:00:00;Legs;1h 5m;Squat (Barbell);W;60;5;0;0;;;
+2024-03-02 09:00:00;Legs;1h 5m;Squat (Barbell);1;100;5;0;0;;;8
+2024-03-02 09:00:00;Legs;1h 5m;Squat (Barbell);2;105;3;0;0;;;9
+2024-03-02 09:00:00;Legs;1h 5m;Leg Press Machine;1;200;10;0;0;;;
+2024-03-02 09:00:00;Legs;1h 5m;Copenhagen Plank;1;0;0;0;30;;;
+2024-02-28 18:30:00;Legs;45m;Squat (Barbell);1;95;5;0;0;;;
+`
+
+func TestTrainingHistoryImport(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Preview And Import Strong Export", func(t *testing.T) {
+		CleanDatabase(t)
+		SeedTestGlobalRPEScale(t)
+		testImportStrongHistory(t, e)
+	})
+
+	t.Run("Reject Invalid Files", func(t *testing.T) {
+		CleanDatabase(t)
+		testImportInvalidHistory(t, e)
+	})
+}
+
+// postTrainingHistory uploads the Strong test export to the given import endpoint
+func postTrainingHistory(e *httpexpect.Expect, token string, path string, mappings map[string]string) *httpexpect.Response {
+	request := e.POST("/api/v1/workout-sessions/"+path).
+		WithHeader("Authorization", "Bearer "+token).
+		WithMultipart().
+		WithFileBytes("file", "strong.csv", []byte(importTestStrongCSV)).
+		WithFormField("weight_unit", "kg").
+		WithFormField("timezone", "Europe/Paris")
+	if mappings != nil {
+		encoded, _ := json.Marshal(mappings)
+		request = request.WithFormField("mappings", string(encoded))
+	}
+	return request.Expect()
+}
+
+func testImportStrongHistory(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "strong@example.com", "password123", "Strong", "Lifter")
+	squatID := createAnalyticsExercise(e, token, "Barbell Squat")
+	legPressID := createAnalyticsExercise(e, token, "Leg Press")
+	createAnalyticsExercise(e, token, "Plank")
+
+	preview := postTrainingHistory(e, token, "import-history/preview", nil).
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("data").Object()
+	preview.Value("format").String().IsEqual("strong")
+	preview.Value("workout_count").Number().IsEqual(2)
+	preview.Value("set_count").Number().IsEqual(5)
+	preview.Value("duplicate_count").Number().IsEqual(0)
+	preview.Value("ready").Boolean().IsFalse()
+
+	exercises := preview.Value("exercises").Array()
+	exercises.Length().IsEqual(3)
+	// Names in order of first appearance, oldest workout first
+	squat := exercises.Value(0).Object()
+	squat.Value("name").String().IsEqual("Squat (Barbell)")
+	squat.Value("status").String().IsEqual("matched")
+	squat.Value("set_count").Number().IsEqual(3)
+	squat.Value("exercise").Object().HasValue("exercise_id", squatID)
+
+	// An extra word needs confirming, but the exercise is suggested
+	legPress := exercises.Value(1).Object()
+	legPress.Value("status").String().IsEqual("unmatched")
+	legPress.Value("exercise").IsNull()
+	legPress.Value("suggestions").Array().Value(0).Object().HasValue("exercise_id", legPressID)
+
+	// Importing with unmatched exercises is refused
+	postTrainingHistory(e, token, "import-history", nil).
+		Status(http.StatusBadRequest).
+		JSON().Object().
+		Value("errors").Array().Length().IsEqual(2)
+
+	// Mappings must point to existing exercises
+	postTrainingHistory(e, token, "import-history", map[string]string{
+		"Leg Press Machine": "00000000-0000-0000-0000-000000000001",
+		"Copenhagen Plank":  "",
+	}).Status(http.StatusBadRequest)
+
+	result := postTrainingHistory(e, token, "import-history", map[string]string{
+		"Leg Press Machine": legPressID,
+		"Copenhagen Plank":  "",
+	}).
+		Status(http.StatusCreated).
+		JSON().Object().
+		Value("data").Object()
+	result.Value("imported_count").Number().IsEqual(2)
+	result.Value("skipped_sets").Number().IsEqual(1)
+	sessionIDs := result.Value("session_ids").Array()
+	sessionIDs.Length().IsEqual(2)
+
+	// Sessions keep their original dates, in the export's time zone
+	session := e.GET("/api/v1/workout-sessions/"+sessionIDs.Value(1).String().Raw()).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("data").Object()
+	session.Value("started_at").String().HasPrefix("2024-03-02T08:00:00")
+	session.Value("completed").Boolean().IsTrue()
+	session.Value("duration_seconds").Number().IsEqual(3900)
+
+	blocks := session.Value("blocks").Array()
+	blocks.Length().IsEqual(2)
+	squatSets := blocks.Value(0).Object().Value("exercises").Array().Value(0).Object().Value("sets").Array()
+	// The warm-up is left out
+	squatSets.Length().IsEqual(2)
+	squatSets.Value(1).Object().Value("actual_reps").Number().IsEqual(3)
+	squatSets.Value(1).Object().Value("actual_weight").Object().Value("weight_value").Number().IsEqual(105)
+
+	// Importing the same export again skips the workouts
+	postTrainingHistory(e, token, "import-history", map[string]string{
+		"Leg Press Machine": legPressID,
+		"Copenhagen Plank":  "",
+	}).
+		Status(http.StatusCreated).
+		JSON().Object().
+		Value("data").Object().
+		HasValue("imported_count", 0).
+		HasValue("duplicate_count", 2)
+}
+
+func testImportInvalidHistory(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "badcsv@example.com", "password123", "Bad", "Csv")
+
+	t.Run("Missing File", func(t *testing.T) {
+		e.POST("/api/v1/workout-sessions/import-history/preview").
+			WithHeader("Authorization", "Bearer "+token).
+			WithMultipart().
+			WithFormField("weight_unit", "kg").
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("Unknown Format", func(t *testing.T) {
+		e.POST("/api/v1/workout-sessions/import-history/preview").
+			WithHeader("Authorization", "Bearer "+token).
+			WithMultipart().
+			WithFileBytes("file", "other.csv", []byte("a,b,c\n1,2,3\n")).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("Unknown Time Zone", func(t *testing.T) {
+		e.POST("/api/v1/workout-sessions/import-history/preview").
+			WithHeader("Authorization", "Bearer "+token).
+			WithMultipart().
+			WithFileBytes("file", "strong.csv", []byte(importTestStrongCSV)).
+			WithFormField("timezone", "Mars/Olympus").
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,162 @@
+package test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+)
+
+func TestUserExport(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("JSON Export", func(t *testing.T) {
+		CleanDatabase(t)
+		testUserExportJSON(t, e)
+	})
+
+	t.Run("CSV Export", func(t *testing.T) {
+		CleanDatabase(t)
+		testUserExportCSV(t, e)
+	})
+
+	t.Run("Invalid Format", func(t *testing.T) {
+		CleanDatabase(t)
+		token := createTestUserAndGetToken(e, "export@example.com", "password123", "Ex", "Port")
+		e.GET("/api/v1/me/export").
+			WithHeader("Authorization", "Bearer "+token).
+			WithQuery("format", "xml").
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+}
+
+// seedExportData logs a weight and a session with two squat sets for the user
+func seedExportData(e *httpexpect.Expect, token string) {
+	e.PUT("/api/v1/user/settings").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{"preferred_weight_unit": "lb"}).
+		Expect().
+		Status(http.StatusOK)
+
+	e.POST("/api/v1/user/weight-logs").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{"weight_kg": 80}).
+		Expect().
+		Status(http.StatusCreated)
+
+	squatID := createAnalyticsExercise(e, token, "Back Squat")
+	block := startStructuredSession(e, token, map[string]interface{}{
+		"type": "straight",
+		"exercises": []map[string]interface{}{
+			{"exercise_id": squatID, "exercise_order": 1, "sets": 2, "reps": 5},
+		},
+	})
+	squat := setIDs(blockExerciseSets(block, 0))
+	logSessionSet(e, token, squat[0], 5, 100)
+	logSessionSet(e, token, squat[1], 5, 100)
+}
+
+// readExport downloads the user's export and returns its files by name
+func readExport(t *testing.T, e *httpexpect.Expect, token string, format string) map[string][]byte {
+	response := e.GET("/api/v1/me/export").
+		WithHeader("Authorization", "Bearer "+token).
+		WithQuery("format", format).
+		Expect().
+		Status(http.StatusOK)
+	response.Header("Content-Type").IsEqual("application/zip")
+
+	body := []byte(response.Body().Raw())
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("export is not a valid zip archive: %v", err)
+	}
+
+	files := make(map[string][]byte)
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", file.Name, err)
+		}
+		content, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file.Name, err)
+		}
+		files[file.Name] = content
+	}
+	return files
+}
+
+func testUserExportJSON(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "export@example.com", "password123", "Ex", "Port")
+	seedExportData(e, token)
+
+	files := readExport(t, e, token, "json")
+	for _, name := range []string{"account.json", "fitness_profile.json", "weight_logs.json", "workouts.json", "workout_plans.json", "favorites.json", "workout_sessions.json"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("export is missing %s", name)
+		}
+	}
+
+	var weightLogs []map[string]interface{}
+	if err := json.Unmarshal(files["weight_logs.json"], &weightLogs); err != nil {
+		t.Fatalf("weight_logs.json: %v", err)
+	}
+	if len(weightLogs) != 1 || weightLogs[0]["weight"].(map[string]interface{})["weight_unit"] != "lb" {
+		t.Errorf("weight_logs.json = %v, want one entry in lb", weightLogs)
+	}
+
+	var sessions []map[string]interface{}
+	if err := json.Unmarshal(files["workout_sessions.json"], &sessions); err != nil {
+		t.Fatalf("workout_sessions.json: %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("workout_sessions.json has %d sessions, want 1", len(sessions))
+	}
+	sets := sessions[0]["blocks"].([]interface{})[0].(map[string]interface{})["exercises"].([]interface{})[0].(map[string]interface{})["sets"].([]interface{})
+	weight := sets[0].(map[string]interface{})["actual_weight"].(map[string]interface{})
+	if weight["weight_unit"] != "lb" {
+		t.Errorf("set weight unit = %v, want lb", weight["weight_unit"])
+	}
+}
+
+func testUserExportCSV(t *testing.T, e *httpexpect.Expect) {
+	token := createTestUserAndGetToken(e, "export@example.com", "password123", "Ex", "Port")
+	seedExportData(e, token)
+
+	files := readExport(t, e, token, "csv")
+
+	sets, err := csv.NewReader(bytes.NewReader(files["session_sets.csv"])).ReadAll()
+	if err != nil {
+		t.Fatalf("session_sets.csv: %v", err)
+	}
+	// Header plus the two sets
+	if len(sets) != 3 {
+		t.Fatalf("session_sets.csv has %d rows, want 3", len(sets))
+	}
+	header := sets[0]
+	for i, column := range header {
+		if column == "weight_lb" && sets[1][i] == "" {
+			t.Errorf("weight_lb is empty for a logged set")
+		}
+		if column == "exercise_name" && sets[1][i] != "Back Squat" {
+			t.Errorf("exercise_name = %q, want Back Squat", sets[1][i])
+		}
+	}
+
+	sessions, err := csv.NewReader(bytes.NewReader(files["workout_sessions.csv"])).ReadAll()
+	if err != nil || len(sessions) != 2 {
+		t.Errorf("workout_sessions.csv = %v rows (err %v), want header and one session", len(sessions), err)
+	}
+
+	weightLogs, err := csv.NewReader(bytes.NewReader(files["weight_logs.csv"])).ReadAll()
+	if err != nil || len(weightLogs) != 2 || weightLogs[0][2] != "weight_lb" {
+		t.Errorf("weight_logs.csv = %v (err %v), want a weight_lb column and one entry", weightLogs, err)
+	}
+}
@@ -0,0 +1,194 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+)
+
+func TestWorkoutComments(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Threaded Comments", func(t *testing.T) {
+		CleanDatabase(t)
+		testThreadedWorkoutComments(t, e)
+	})
+
+	t.Run("Reactions", func(t *testing.T) {
+		CleanDatabase(t)
+		testWorkoutCommentReactions(t, e)
+	})
+}
+
+// setupSharedWorkout shares a workout between two friends and returns the share's ID
+func setupSharedWorkout(e *httpexpect.Expect, ownerToken string, friendToken string) string {
+	makeFriends(e, ownerToken, friendToken)
+	workoutID := createWorkout(e, ownerToken, "Shared Workout")
+	return shareTestWorkout(e, ownerToken, workoutID, getTestUserID(e, friendToken), "view")
+}
+
+// postWorkoutComment posts a comment on a shared workout and returns its ID
+func postWorkoutComment(e *httpexpect.Expect, token string, shareID string, content string, parentID string) string {
+	body := map[string]interface{}{"content": content}
+	if parentID != "" {
+		body["parent_id"] = parentID
+	}
+	return e.POST("/api/v1/shared-workouts/"+shareID+"/comments").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(body).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+}
+
+func testThreadedWorkoutComments(t *testing.T, e *httpexpect.Expect) {
+	ownerToken := createTestUserAndGetToken(e, "owner@example.com", "password123", "Olive", "Owner")
+	friendToken := createTestUserAndGetToken(e, "friend@example.com", "password123", "Fred", "Friend")
+	strangerToken := createTestUserAndGetToken(e, "stranger@example.com", "password123", "Sam", "Stranger")
+	shareID := setupSharedWorkout(e, ownerToken, friendToken)
+
+	commentID := postWorkoutComment(e, friendToken, shareID, "Looks tough!", "")
+	postWorkoutComment(e, ownerToken, shareID, "It is.", commentID)
+	postWorkoutComment(e, friendToken, shareID, "Second thought", "")
+
+	t.Run("Strangers Cannot Comment", func(t *testing.T) {
+		e.POST("/api/v1/shared-workouts/"+shareID+"/comments").
+			WithHeader("Authorization", "Bearer "+strangerToken).
+			WithJSON(map[string]interface{}{"content": "Hi"}).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+
+	t.Run("Empty Comments Are Rejected", func(t *testing.T) {
+		e.POST("/api/v1/shared-workouts/"+shareID+"/comments").
+			WithHeader("Authorization", "Bearer "+friendToken).
+			WithJSON(map[string]interface{}{"content": ""}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("List Top-Level Comments", func(t *testing.T) {
+		response := e.GET("/api/v1/shared-workouts/"+shareID+"/comments").
+			WithHeader("Authorization", "Bearer "+ownerToken).
+			WithQuery("limit", 1).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object()
+		response.Value("meta").Object().HasValue("total_items", 2)
+		comment := response.Value("data").Array().Value(0).Object()
+		comment.HasValue("content", "Looks tough!")
+		comment.HasValue("replies_count", 1)
+		comment.Value("user").Object().HasValue("email", "friend@example.com")
+	})
+
+	t.Run("List Replies", func(t *testing.T) {
+		replies := e.GET("/api/v1/shared-workouts/"+shareID+"/comments").
+			WithHeader("Authorization", "Bearer "+friendToken).
+			WithQuery("parent_id", commentID).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array()
+		replies.Length().IsEqual(1)
+		replies.Value(0).Object().HasValue("content", "It is.")
+	})
+
+	t.Run("Only The Author Edits", func(t *testing.T) {
+		e.PUT("/api/v1/shared-workouts/"+shareID+"/comments/"+commentID).
+			WithHeader("Authorization", "Bearer "+ownerToken).
+			WithJSON(map[string]interface{}{"content": "Edited by someone else"}).
+			Expect().
+			Status(http.StatusForbidden)
+
+		e.PUT("/api/v1/shared-workouts/"+shareID+"/comments/"+commentID).
+			WithHeader("Authorization", "Bearer "+friendToken).
+			WithJSON(map[string]interface{}{"content": "Looks really tough!"}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			HasValue("content", "Looks really tough!")
+	})
+
+	t.Run("Deleting A Comment Deletes Its Replies", func(t *testing.T) {
+		// The owner of the shared workout moderates its comments
+		e.DELETE("/api/v1/shared-workouts/"+shareID+"/comments/"+commentID).
+			WithHeader("Authorization", "Bearer "+ownerToken).
+			Expect().
+			Status(http.StatusOK)
+
+		e.GET("/api/v1/shared-workouts/"+shareID+"/comments").
+			WithHeader("Authorization", "Bearer "+friendToken).
+			WithQuery("parent_id", commentID).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array().Length().IsEqual(0)
+
+		e.GET("/api/v1/shared-workouts/"+shareID+"/comments").
+			WithHeader("Authorization", "Bearer "+friendToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array().Length().IsEqual(1)
+	})
+}
+
+func testWorkoutCommentReactions(t *testing.T, e *httpexpect.Expect) {
+	ownerToken := createTestUserAndGetToken(e, "owner@example.com", "password123", "Olive", "Owner")
+	friendToken := createTestUserAndGetToken(e, "friend@example.com", "password123", "Fred", "Friend")
+	shareID := setupSharedWorkout(e, ownerToken, friendToken)
+	commentID := postWorkoutComment(e, friendToken, shareID, "New PR today", "")
+	reactionsPath := "/api/v1/shared-workouts/" + shareID + "/comments/" + commentID + "/reactions"
+
+	react := func(token string, reaction string) *httpexpect.Object {
+		return e.POST(reactionsPath).
+			WithHeader("Authorization", "Bearer "+token).
+			WithJSON(map[string]interface{}{"reaction": reaction}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object()
+	}
+
+	t.Run("Unknown Reactions Are Rejected", func(t *testing.T) {
+		e.POST(reactionsPath).
+			WithHeader("Authorization", "Bearer "+ownerToken).
+			WithJSON(map[string]interface{}{"reaction": "meh"}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("React", func(t *testing.T) {
+		comment := react(ownerToken, "love")
+		comment.Value("reactions_count").Object().IsEqual(map[string]interface{}{"love": 1})
+		comment.HasValue("user_reaction", "love")
+
+		comment = react(friendToken, "love")
+		comment.Value("reactions_count").Object().HasValue("love", 2)
+	})
+
+	t.Run("A Different Reaction Replaces The Previous One", func(t *testing.T) {
+		comment := react(ownerToken, "wow")
+		comment.Value("reactions_count").Object().IsEqual(map[string]interface{}{"love": 1, "wow": 1})
+		comment.HasValue("user_reaction", "wow")
+	})
+
+	t.Run("The Same Reaction Toggles It Off", func(t *testing.T) {
+		comment := react(ownerToken, "wow")
+		comment.Value("reactions_count").Object().IsEqual(map[string]interface{}{"love": 1})
+		comment.NotContainsKey("user_reaction")
+	})
+
+	t.Run("Listed Comments Show The Viewer's Reaction", func(t *testing.T) {
+		comment := e.GET("/api/v1/shared-workouts/"+shareID+"/comments").
+			WithHeader("Authorization", "Bearer "+friendToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array().Value(0).Object()
+		comment.HasValue("user_reaction", "love")
+	})
+}
@@ -0,0 +1,270 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+
+	"lamari-fit-api/database"
+	"lamari-fit-api/models"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/google/uuid"
+)
+
+func TestWorkoutSharing(t *testing.T) {
+	e := SetupTestApp(t)
+
+	t.Run("Share With Friends And Trainers", func(t *testing.T) {
+		CleanDatabase(t)
+		testShareWorkout(t, e)
+	})
+
+	t.Run("Permission Levels", func(t *testing.T) {
+		CleanDatabase(t)
+		testSharePermissionLevels(t, e)
+	})
+
+	t.Run("Unshare", func(t *testing.T) {
+		CleanDatabase(t)
+		testUnshareWorkout(t, e)
+	})
+}
+
+// makeFriends creates an accepted friendship between two users
+func makeFriends(e *httpexpect.Expect, token string, friendToken string) {
+	database.DB.Create(&models.Friendship{
+		UserID:   uuid.MustParse(getTestUserID(e, token)),
+		FriendID: uuid.MustParse(getTestUserID(e, friendToken)),
+		Status:   "accepted",
+	})
+}
+
+// shareTestWorkout shares a workout and returns the share's ID
+func shareTestWorkout(e *httpexpect.Expect, token string, workoutID string, userID string, permission string) string {
+	return e.POST("/api/v1/workouts/"+workoutID+"/shares").
+		WithHeader("Authorization", "Bearer "+token).
+		WithJSON(map[string]interface{}{"user_id": userID, "permission": permission}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+}
+
+func testShareWorkout(t *testing.T, e *httpexpect.Expect) {
+	ownerToken := createTestUserAndGetToken(e, "owner@example.com", "password123", "Olive", "Owner")
+	friendToken := createTestUserAndGetToken(e, "friend@example.com", "password123", "Fred", "Friend")
+	trainerToken := createTestUserAndGetToken(e, "trainer@example.com", "password123", "Tina", "Trainer")
+	strangerToken := createTestUserAndGetToken(e, "stranger@example.com", "password123", "Sam", "Stranger")
+	makeFriends(e, ownerToken, friendToken)
+	createActiveTrainerClientLink(t, e, trainerToken, ownerToken)
+
+	workoutID := createWorkout(e, ownerToken, "Push Day")
+
+	t.Run("Strangers Cannot Be Shared With", func(t *testing.T) {
+		e.POST("/api/v1/workouts/"+workoutID+"/shares").
+			WithHeader("Authorization", "Bearer "+ownerToken).
+			WithJSON(map[string]interface{}{"user_id": getTestUserID(e, strangerToken)}).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("Only The Owner Can Share", func(t *testing.T) {
+		e.POST("/api/v1/workouts/"+workoutID+"/shares").
+			WithHeader("Authorization", "Bearer "+friendToken).
+			WithJSON(map[string]interface{}{"user_id": getTestUserID(e, ownerToken)}).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+
+	shareTestWorkout(e, ownerToken, workoutID, getTestUserID(e, friendToken), "view")
+	shareTestWorkout(e, ownerToken, workoutID, getTestUserID(e, trainerToken), "edit")
+
+	t.Run("Sharing Twice Conflicts", func(t *testing.T) {
+		e.POST("/api/v1/workouts/"+workoutID+"/shares").
+			WithHeader("Authorization", "Bearer "+ownerToken).
+			WithJSON(map[string]interface{}{"user_id": getTestUserID(e, friendToken)}).
+			Expect().
+			Status(http.StatusConflict)
+	})
+
+	t.Run("Owner Lists Shares", func(t *testing.T) {
+		e.GET("/api/v1/workouts/"+workoutID+"/shares").
+			WithHeader("Authorization", "Bearer "+ownerToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array().Length().IsEqual(2)
+	})
+
+	t.Run("Recipient Lists Shared With Me", func(t *testing.T) {
+		shares := e.GET("/api/v1/workouts/shared-with-me").
+			WithHeader("Authorization", "Bearer "+friendToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array()
+		shares.Length().IsEqual(1)
+		share := shares.Value(0).Object()
+		share.HasValue("workout_title", "Push Day")
+		share.HasValue("permission", "view")
+		share.Value("shared_by").Object().HasValue("email", "owner@example.com")
+	})
+
+	t.Run("Strangers Cannot See The Workout", func(t *testing.T) {
+		e.GET("/api/v1/workouts/"+workoutID).
+			WithHeader("Authorization", "Bearer "+strangerToken).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+}
+
+func testSharePermissionLevels(t *testing.T, e *httpexpect.Expect) {
+	ownerToken := createTestUserAndGetToken(e, "owner@example.com", "password123", "Olive", "Owner")
+	viewerToken := createTestUserAndGetToken(e, "viewer@example.com", "password123", "Vic", "Viewer")
+	copierToken := createTestUserAndGetToken(e, "copier@example.com", "password123", "Cora", "Copier")
+	editorToken := createTestUserAndGetToken(e, "editor@example.com", "password123", "Ed", "Editor")
+	makeFriends(e, ownerToken, viewerToken)
+	makeFriends(e, ownerToken, copierToken)
+	makeFriends(e, ownerToken, editorToken)
+
+	workoutID := e.POST("/api/v1/workouts/").
+		WithHeader("Authorization", "Bearer "+ownerToken).
+		WithJSON(map[string]interface{}{"title": "Leg Day", "visibility": "friends"}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().
+		Value("data").Object().Value("id").String().Raw()
+
+	shareTestWorkout(e, ownerToken, workoutID, getTestUserID(e, viewerToken), "view")
+	shareTestWorkout(e, ownerToken, workoutID, getTestUserID(e, copierToken), "copy")
+	shareTestWorkout(e, ownerToken, workoutID, getTestUserID(e, editorToken), "edit")
+
+	t.Run("View", func(t *testing.T) {
+		e.GET("/api/v1/workouts/"+workoutID).
+			WithHeader("Authorization", "Bearer "+viewerToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			HasValue("permission", "view")
+
+		e.POST("/api/v1/workouts/"+workoutID+"/duplicate").
+			WithHeader("Authorization", "Bearer "+viewerToken).
+			Expect().
+			Status(http.StatusForbidden)
+
+		e.PUT("/api/v1/workouts/"+workoutID).
+			WithHeader("Authorization", "Bearer "+viewerToken).
+			WithJSON(map[string]interface{}{"title": "Renamed"}).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("Copy", func(t *testing.T) {
+		copied := e.POST("/api/v1/workouts/"+workoutID+"/duplicate").
+			WithHeader("Authorization", "Bearer "+copierToken).
+			Expect().
+			Status(http.StatusCreated).
+			JSON().Object().
+			Value("data").Object()
+		copied.HasValue("user_id", getTestUserID(e, copierToken))
+		copied.HasValue("title", "Leg Day (Copy)")
+		copied.HasValue("visibility", "private")
+
+		e.PUT("/api/v1/workouts/"+workoutID).
+			WithHeader("Authorization", "Bearer "+copierToken).
+			WithJSON(map[string]interface{}{"title": "Renamed"}).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("Edit", func(t *testing.T) {
+		e.PUT("/api/v1/workouts/"+workoutID).
+			WithHeader("Authorization", "Bearer "+editorToken).
+			WithJSON(map[string]interface{}{"title": "Leg Day v2"}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			HasValue("title", "Leg Day v2").
+			HasValue("user_id", getTestUserID(e, ownerToken))
+
+		// Visibility stays with the owner
+		e.PUT("/api/v1/workouts/"+workoutID).
+			WithHeader("Authorization", "Bearer "+editorToken).
+			WithJSON(map[string]interface{}{"visibility": "public"}).
+			Expect().
+			Status(http.StatusForbidden)
+
+		e.GET("/api/v1/workouts/"+workoutID).
+			WithHeader("Authorization", "Bearer "+ownerToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			HasValue("title", "Leg Day v2").
+			HasValue("permission", "owner")
+	})
+}
+
+func testUnshareWorkout(t *testing.T, e *httpexpect.Expect) {
+	ownerToken := createTestUserAndGetToken(e, "owner@example.com", "password123", "Olive", "Owner")
+	friendToken := createTestUserAndGetToken(e, "friend@example.com", "password123", "Fred", "Friend")
+	makeFriends(e, ownerToken, friendToken)
+
+	workoutID := createWorkout(e, ownerToken, "Pull Day")
+	shareID := shareTestWorkout(e, ownerToken, workoutID, getTestUserID(e, friendToken), "view")
+
+	t.Run("Update Permission", func(t *testing.T) {
+		e.PUT("/api/v1/workouts/"+workoutID+"/shares/"+shareID).
+			WithHeader("Authorization", "Bearer "+ownerToken).
+			WithJSON(map[string]interface{}{"permission": "copy"}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Object().
+			HasValue("permission", "copy")
+
+		// The recipient cannot raise their own permission
+		e.PUT("/api/v1/workouts/"+workoutID+"/shares/"+shareID).
+			WithHeader("Authorization", "Bearer "+friendToken).
+			WithJSON(map[string]interface{}{"permission": "edit"}).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+
+	t.Run("Owner Unshares", func(t *testing.T) {
+		e.DELETE("/api/v1/workouts/"+workoutID+"/shares/"+shareID).
+			WithHeader("Authorization", "Bearer "+ownerToken).
+			Expect().
+			Status(http.StatusOK)
+
+		e.GET("/api/v1/workouts/"+workoutID).
+			WithHeader("Authorization", "Bearer "+friendToken).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+
+	t.Run("Removing A Friend Revokes Shares", func(t *testing.T) {
+		shareTestWorkout(e, ownerToken, workoutID, getTestUserID(e, friendToken), "view")
+
+		friendshipID := e.GET("/api/v1/friends/").
+			WithHeader("Authorization", "Bearer "+ownerToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array().Value(0).Object().Value("id").String().Raw()
+
+		e.DELETE("/api/v1/friends/"+friendshipID).
+			WithHeader("Authorization", "Bearer "+ownerToken).
+			Expect().
+			Status(http.StatusOK)
+
+		e.GET("/api/v1/workouts/shared-with-me").
+			WithHeader("Authorization", "Bearer "+friendToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().
+			Value("data").Array().Length().IsEqual(0)
+	})
+}
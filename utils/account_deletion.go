@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"lamari-fit-api/config"
+	"time"
+)
+
+// GetAccountDeletionGracePeriod returns how long a deleted account can be restored before its
+// data is purged
+func GetAccountDeletionGracePeriod() time.Duration {
+	duration, err := time.ParseDuration(config.AppConfig.AccountDeletionGracePeriod)
+	if err != nil || duration < 0 {
+		// Default to 30 days if parsing fails
+		duration = 30 * 24 * time.Hour
+	}
+	return duration
+}
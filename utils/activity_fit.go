@@ -0,0 +1,310 @@
+package utils
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// ErrInvalidFITFile is returned for FIT files that are truncated or fail their checksum
+var ErrInvalidFITFile = errors.New("invalid FIT file")
+
+// fitEpoch is the origin of FIT timestamps
+var fitEpoch = time.Date(1989, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// FIT global message numbers and field numbers read by the importer
+const (
+	fitMessageSession = 18
+	fitMessageRecord  = 20
+
+	fitFieldTimestamp = 253
+
+	fitRecordLatitude         = 0
+	fitRecordLongitude        = 1
+	fitRecordAltitude         = 2
+	fitRecordHeartRate        = 3
+	fitRecordDistance         = 5
+	fitRecordEnhancedAltitude = 78
+
+	fitSessionStartTime        = 2
+	fitSessionSport            = 5
+	fitSessionTotalElapsedTime = 7
+	fitSessionTotalDistance    = 9
+)
+
+// fitSports maps FIT sport enum values to sport names
+var fitSports = map[uint64]string{
+	1:  "running",
+	2:  "cycling",
+	5:  "swimming",
+	11: "walking",
+	15: "rowing",
+	17: "walking", // hiking
+}
+
+// fitCRCTable is the nibble lookup table of the FIT CRC-16
+var fitCRCTable = [16]uint16{
+	0x0000, 0xCC01, 0xD801, 0x1400, 0xF001, 0x3C00, 0x2800, 0xE401,
+	0xA001, 0x6C00, 0x7800, 0xB401, 0x5000, 0x9C01, 0x8801, 0x4400,
+}
+
+// fitCRC computes the FIT CRC-16 of data
+func fitCRC(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		tmp := fitCRCTable[crc&0xF]
+		crc = (crc >> 4) & 0x0FFF
+		crc = crc ^ tmp ^ fitCRCTable[b&0xF]
+
+		tmp = fitCRCTable[crc&0xF]
+		crc = (crc >> 4) & 0x0FFF
+		crc = crc ^ tmp ^ fitCRCTable[(b>>4)&0xF]
+	}
+	return crc
+}
+
+type fitFieldDefinition struct {
+	number byte
+	size   int
+}
+
+type fitMessageDefinition struct {
+	globalNumber   uint16
+	bigEndian      bool
+	fields         []fitFieldDefinition
+	developerBytes int
+}
+
+// parseFIT reads the record and session messages of a FIT activity file. Only the fields
+// needed for the activity summary and samples are decoded; everything else is skipped.
+func parseFIT(data []byte) (*ParsedActivity, error) {
+	if len(data) < 12 {
+		return nil, ErrInvalidFITFile
+	}
+	headerSize := int(data[0])
+	if headerSize < 12 || len(data) < headerSize || string(data[8:12]) != ".FIT" {
+		return nil, ErrInvalidFITFile
+	}
+	dataSize := int(binary.LittleEndian.Uint32(data[4:8]))
+	end := headerSize + dataSize
+	if len(data) < end+2 {
+		return nil, ErrInvalidFITFile
+	}
+	if fitCRC(data[:end]) != binary.LittleEndian.Uint16(data[end:end+2]) {
+		return nil, ErrInvalidFITFile
+	}
+
+	activity := &ParsedActivity{Format: ActivityFormatFIT}
+	definitions := make(map[byte]*fitMessageDefinition)
+	var lastTimestamp uint32
+
+	offset := headerSize
+	for offset < end {
+		header := data[offset]
+		offset++
+
+		// Compressed timestamp header: a data message with a 5 bit time offset
+		if header&0x80 != 0 {
+			definition := definitions[(header>>5)&0x03]
+			if definition == nil {
+				return nil, ErrInvalidFITFile
+			}
+			timeOffset := uint32(header & 0x1F)
+			lastTimestamp += (timeOffset - (lastTimestamp & 0x1F)) & 0x1F
+			values, next, err := readFITMessage(data, offset, end, definition)
+			if err != nil {
+				return nil, err
+			}
+			offset = next
+			if _, ok := values[fitFieldTimestamp]; !ok {
+				values[fitFieldTimestamp] = uint64(lastTimestamp)
+			}
+			applyFITMessage(activity, definition.globalNumber, values)
+			continue
+		}
+
+		localType := header & 0x0F
+		if header&0x40 != 0 {
+			definition, next, err := readFITDefinition(data, offset, end, header&0x20 != 0)
+			if err != nil {
+				return nil, err
+			}
+			definitions[localType] = definition
+			offset = next
+			continue
+		}
+
+		definition := definitions[localType]
+		if definition == nil {
+			return nil, ErrInvalidFITFile
+		}
+		values, next, err := readFITMessage(data, offset, end, definition)
+		if err != nil {
+			return nil, err
+		}
+		offset = next
+		if timestamp, ok := values[fitFieldTimestamp]; ok {
+			lastTimestamp = uint32(timestamp)
+		}
+		applyFITMessage(activity, definition.globalNumber, values)
+	}
+
+	return activity, nil
+}
+
+// readFITDefinition reads a definition message starting after its record header
+func readFITDefinition(data []byte, offset int, end int, hasDeveloperFields bool) (*fitMessageDefinition, int, error) {
+	if offset+5 > end {
+		return nil, 0, ErrInvalidFITFile
+	}
+	definition := &fitMessageDefinition{bigEndian: data[offset+1] == 1}
+	if definition.bigEndian {
+		definition.globalNumber = binary.BigEndian.Uint16(data[offset+2 : offset+4])
+	} else {
+		definition.globalNumber = binary.LittleEndian.Uint16(data[offset+2 : offset+4])
+	}
+	fieldCount := int(data[offset+4])
+	offset += 5
+
+	if offset+fieldCount*3 > end {
+		return nil, 0, ErrInvalidFITFile
+	}
+	for i := 0; i < fieldCount; i++ {
+		definition.fields = append(definition.fields, fitFieldDefinition{
+			number: data[offset],
+			size:   int(data[offset+1]),
+		})
+		offset += 3
+	}
+
+	if hasDeveloperFields {
+		if offset+1 > end {
+			return nil, 0, ErrInvalidFITFile
+		}
+		developerCount := int(data[offset])
+		offset++
+		if offset+developerCount*3 > end {
+			return nil, 0, ErrInvalidFITFile
+		}
+		for i := 0; i < developerCount; i++ {
+			definition.developerBytes += int(data[offset+1])
+			offset += 3
+		}
+	}
+
+	return definition, offset, nil
+}
+
+// readFITMessage reads the 1, 2 and 4 byte fields of a data message as unsigned integers.
+// Fields holding the base type's invalid value are left out.
+func readFITMessage(data []byte, offset int, end int, definition *fitMessageDefinition) (map[byte]uint64, int, error) {
+	values := make(map[byte]uint64)
+	for _, field := range definition.fields {
+		if offset+field.size > end {
+			return nil, 0, ErrInvalidFITFile
+		}
+		raw := data[offset : offset+field.size]
+		offset += field.size
+
+		var value, invalid uint64
+		switch field.size {
+		case 1:
+			value, invalid = uint64(raw[0]), 0xFF
+		case 2:
+			if definition.bigEndian {
+				value = uint64(binary.BigEndian.Uint16(raw))
+			} else {
+				value = uint64(binary.LittleEndian.Uint16(raw))
+			}
+			invalid = 0xFFFF
+		case 4:
+			if definition.bigEndian {
+				value = uint64(binary.BigEndian.Uint32(raw))
+			} else {
+				value = uint64(binary.LittleEndian.Uint32(raw))
+			}
+			invalid = 0xFFFFFFFF
+		default:
+			continue
+		}
+		// Signed positions use 0x7FFFFFFF as their invalid value
+		if value == invalid || (field.size == 4 && value == 0x7FFFFFFF) {
+			continue
+		}
+		values[field.number] = value
+	}
+
+	offset += definition.developerBytes
+	if offset > end {
+		return nil, 0, ErrInvalidFITFile
+	}
+	return values, offset, nil
+}
+
+// applyFITMessage adds a decoded record or session message to the activity
+func applyFITMessage(activity *ParsedActivity, globalNumber uint16, values map[byte]uint64) {
+	switch globalNumber {
+	case fitMessageRecord:
+		timestamp, ok := values[fitFieldTimestamp]
+		if !ok {
+			return
+		}
+		point := ActivityPoint{Time: fitTime(timestamp)}
+		if lat, ok := values[fitRecordLatitude]; ok {
+			if lon, ok := values[fitRecordLongitude]; ok {
+				latitude := fitSemicirclesToDegrees(lat)
+				longitude := fitSemicirclesToDegrees(lon)
+				point.Latitude = &latitude
+				point.Longitude = &longitude
+			}
+		}
+		if altitude, ok := values[fitRecordEnhancedAltitude]; ok {
+			elevation := roundToDecimal(float64(altitude)/5-500, 1)
+			point.ElevationMeters = &elevation
+		} else if altitude, ok := values[fitRecordAltitude]; ok {
+			elevation := roundToDecimal(float64(altitude)/5-500, 1)
+			point.ElevationMeters = &elevation
+		}
+		if distance, ok := values[fitRecordDistance]; ok {
+			meters := float64(distance) / 100
+			point.DistanceMeters = &meters
+		}
+		if heartRate, ok := values[fitRecordHeartRate]; ok {
+			bpm := int(heartRate)
+			point.HeartRate = &bpm
+		}
+		activity.Points = append(activity.Points, point)
+
+	case fitMessageSession:
+		if startTime, ok := values[fitSessionStartTime]; ok && activity.StartedAt.IsZero() {
+			activity.StartedAt = fitTime(startTime)
+		}
+		if sport, ok := values[fitSessionSport]; ok && activity.Sport == "" {
+			activity.Sport = fitSports[sport]
+		}
+		if elapsed, ok := values[fitSessionTotalElapsedTime]; ok {
+			seconds := int(elapsed / 1000)
+			if activity.recordedDurationSeconds != nil {
+				seconds += *activity.recordedDurationSeconds
+			}
+			activity.recordedDurationSeconds = &seconds
+		}
+		if distance, ok := values[fitSessionTotalDistance]; ok {
+			meters := float64(distance) / 100
+			if activity.recordedDistanceMeters != nil {
+				meters += *activity.recordedDistanceMeters
+			}
+			activity.recordedDistanceMeters = &meters
+		}
+	}
+}
+
+// fitTime converts a FIT timestamp to a time
+func fitTime(timestamp uint64) time.Time {
+	return fitEpoch.Add(time.Duration(timestamp) * time.Second)
+}
+
+// fitSemicirclesToDegrees converts a FIT position in semicircles to degrees
+func fitSemicirclesToDegrees(semicircles uint64) float64 {
+	return float64(int32(uint32(semicircles))) * 180 / (1 << 31)
+}
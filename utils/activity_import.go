@@ -0,0 +1,371 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Supported activity file formats
+const (
+	ActivityFormatGPX = "gpx"
+	ActivityFormatTCX = "tcx"
+	ActivityFormatFIT = "fit"
+)
+
+// elevationNoiseMeters is the climb or descent needed before an elevation change counts towards
+// the gain and loss totals, so that GPS and barometer jitter doesn't inflate them
+const elevationNoiseMeters = 2.0
+
+var (
+	// ErrUnsupportedActivityFormat is returned for files that aren't GPX, TCX or FIT
+	ErrUnsupportedActivityFormat = errors.New("unsupported activity file format")
+	// ErrEmptyActivity is returned for files without any timestamped track point
+	ErrEmptyActivity = errors.New("activity file has no timestamped track points")
+)
+
+// ActivityPoint is a single recorded sample of an activity
+type ActivityPoint struct {
+	Time            time.Time
+	Latitude        *float64
+	Longitude       *float64
+	ElevationMeters *float64
+	DistanceMeters  *float64 // Cumulative distance from the start
+	HeartRate       *int     // bpm
+}
+
+// ParsedActivity is an activity read from a GPX, TCX or FIT file
+type ParsedActivity struct {
+	Format              string
+	Sport               string
+	StartedAt           time.Time
+	EndedAt             time.Time
+	DurationSeconds     int
+	DistanceMeters      *float64
+	ElevationGainMeters *float64
+	ElevationLossMeters *float64
+	AverageHeartRate    *int
+	MaxHeartRate        *int
+	Points              []ActivityPoint
+
+	// Totals recorded by the device, preferred over the ones computed from the points
+	recordedDurationSeconds *int
+	recordedDistanceMeters  *float64
+}
+
+// DetectActivityFormat returns the format of an activity file from its name, or from its
+// content when the extension is missing or unknown. Returns "" if the format isn't recognized.
+func DetectActivityFormat(filename string, data []byte) string {
+	switch strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), ".")) {
+	case ActivityFormatGPX:
+		return ActivityFormatGPX
+	case ActivityFormatTCX:
+		return ActivityFormatTCX
+	case ActivityFormatFIT:
+		return ActivityFormatFIT
+	}
+
+	if len(data) >= 12 && string(data[8:12]) == ".FIT" {
+		return ActivityFormatFIT
+	}
+	head := data
+	if len(head) > 512 {
+		head = head[:512]
+	}
+	switch {
+	case bytes.Contains(head, []byte("<gpx")):
+		return ActivityFormatGPX
+	case bytes.Contains(head, []byte("<TrainingCenterDatabase")):
+		return ActivityFormatTCX
+	}
+	return ""
+}
+
+// ParseActivityFile parses a GPX, TCX or FIT file into an activity with its summary computed
+func ParseActivityFile(filename string, data []byte) (*ParsedActivity, error) {
+	var activity *ParsedActivity
+	var err error
+
+	switch DetectActivityFormat(filename, data) {
+	case ActivityFormatGPX:
+		activity, err = parseGPX(data)
+	case ActivityFormatTCX:
+		activity, err = parseTCX(data)
+	case ActivityFormatFIT:
+		activity, err = parseFIT(data)
+	default:
+		return nil, ErrUnsupportedActivityFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := summarizeActivity(activity); err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// summarizeActivity orders the points and fills in the start, duration, distance, elevation and
+// heart rate totals of an activity
+func summarizeActivity(activity *ParsedActivity) error {
+	points := activity.Points[:0]
+	for _, point := range activity.Points {
+		if !point.Time.IsZero() {
+			points = append(points, point)
+		}
+	}
+	if len(points) == 0 {
+		return ErrEmptyActivity
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Time.Before(points[j].Time)
+	})
+	activity.Points = points
+
+	if activity.StartedAt.IsZero() || points[0].Time.Before(activity.StartedAt) {
+		activity.StartedAt = points[0].Time
+	}
+	activity.StartedAt = activity.StartedAt.UTC()
+	activity.EndedAt = points[len(points)-1].Time.UTC()
+	activity.DurationSeconds = int(activity.EndedAt.Sub(activity.StartedAt).Seconds())
+	if activity.recordedDurationSeconds != nil && *activity.recordedDurationSeconds > 0 {
+		activity.DurationSeconds = *activity.recordedDurationSeconds
+		activity.EndedAt = activity.StartedAt.Add(time.Duration(activity.DurationSeconds) * time.Second)
+	}
+
+	fillCumulativeDistance(activity.Points)
+	if last := activity.Points[len(activity.Points)-1].DistanceMeters; last != nil {
+		distance := roundToDecimal(*last, 2)
+		activity.DistanceMeters = &distance
+	}
+	if activity.recordedDistanceMeters != nil && *activity.recordedDistanceMeters > 0 {
+		distance := roundToDecimal(*activity.recordedDistanceMeters, 2)
+		activity.DistanceMeters = &distance
+	}
+
+	var reference *float64
+	gain, loss := 0.0, 0.0
+	hasElevation := false
+	for _, point := range activity.Points {
+		if point.ElevationMeters == nil {
+			continue
+		}
+		hasElevation = true
+		elevation := *point.ElevationMeters
+		if reference == nil {
+			reference = &elevation
+			continue
+		}
+		switch delta := elevation - *reference; {
+		case delta >= elevationNoiseMeters:
+			gain += delta
+			reference = &elevation
+		case delta <= -elevationNoiseMeters:
+			loss -= delta
+			reference = &elevation
+		}
+	}
+	if hasElevation {
+		gain = roundToDecimal(gain, 1)
+		loss = roundToDecimal(loss, 1)
+		activity.ElevationGainMeters = &gain
+		activity.ElevationLossMeters = &loss
+	}
+
+	heartRateTotal, heartRateSamples, maxHeartRate := 0, 0, 0
+	for _, point := range activity.Points {
+		if point.HeartRate == nil || *point.HeartRate <= 0 {
+			continue
+		}
+		heartRateTotal += *point.HeartRate
+		heartRateSamples++
+		if *point.HeartRate > maxHeartRate {
+			maxHeartRate = *point.HeartRate
+		}
+	}
+	if heartRateSamples > 0 {
+		average := int(float64(heartRateTotal)/float64(heartRateSamples) + 0.5)
+		activity.AverageHeartRate = &average
+		activity.MaxHeartRate = &maxHeartRate
+	}
+
+	return nil
+}
+
+// fillCumulativeDistance fills in the distance of points that only have a position, from the
+// distance between consecutive positions. Points recorded with a distance are left as they are.
+func fillCumulativeDistance(points []ActivityPoint) {
+	total := 0.0
+	var previous *ActivityPoint
+	for i := range points {
+		point := &points[i]
+		if point.DistanceMeters != nil {
+			total = *point.DistanceMeters
+		} else if point.Latitude != nil && point.Longitude != nil {
+			if previous != nil {
+				total += HaversineDistance(*previous.Latitude, *previous.Longitude, *point.Latitude, *point.Longitude) * MetersPerKm
+			}
+			distance := total
+			point.DistanceMeters = &distance
+		}
+		if point.Latitude != nil && point.Longitude != nil {
+			previous = point
+		}
+	}
+}
+
+// ===== GPX =====
+
+type gpxFile struct {
+	Tracks []struct {
+		Type     string `xml:"type"`
+		Segments []struct {
+			Points []struct {
+				Lat        float64  `xml:"lat,attr"`
+				Lon        float64  `xml:"lon,attr"`
+				Elevation  *float64 `xml:"ele"`
+				Time       string   `xml:"time"`
+				Extensions struct {
+					// Garmin's TrackPointExtension, matched on the local name whatever the prefix
+					HeartRate *int `xml:"TrackPointExtension>hr"`
+				} `xml:"extensions"`
+			} `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+// parseGPX reads the track points of a GPX file
+func parseGPX(data []byte) (*ParsedActivity, error) {
+	var file gpxFile
+	if err := xml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	activity := &ParsedActivity{Format: ActivityFormatGPX}
+	for _, track := range file.Tracks {
+		if activity.Sport == "" {
+			activity.Sport = normalizeActivitySport(track.Type)
+		}
+		for _, segment := range track.Segments {
+			for _, trackPoint := range segment.Points {
+				recordedAt, err := time.Parse(time.RFC3339, strings.TrimSpace(trackPoint.Time))
+				if err != nil {
+					continue
+				}
+				lat, lon := trackPoint.Lat, trackPoint.Lon
+				activity.Points = append(activity.Points, ActivityPoint{
+					Time:            recordedAt,
+					Latitude:        &lat,
+					Longitude:       &lon,
+					ElevationMeters: trackPoint.Elevation,
+					HeartRate:       trackPoint.Extensions.HeartRate,
+				})
+			}
+		}
+	}
+	return activity, nil
+}
+
+// ===== TCX =====
+
+type tcxFile struct {
+	Activities []struct {
+		Sport string `xml:"Sport,attr"`
+		ID    string `xml:"Id"`
+		Laps  []struct {
+			StartTime        string   `xml:"StartTime,attr"`
+			TotalTimeSeconds *float64 `xml:"TotalTimeSeconds"`
+			DistanceMeters   *float64 `xml:"DistanceMeters"`
+			TrackPoints      []struct {
+				Time           string   `xml:"Time"`
+				Latitude       *float64 `xml:"Position>LatitudeDegrees"`
+				Longitude      *float64 `xml:"Position>LongitudeDegrees"`
+				AltitudeMeters *float64 `xml:"AltitudeMeters"`
+				DistanceMeters *float64 `xml:"DistanceMeters"`
+				HeartRate      *int     `xml:"HeartRateBpm>Value"`
+			} `xml:"Track>Trackpoint"`
+		} `xml:"Lap"`
+	} `xml:"Activities>Activity"`
+}
+
+// parseTCX reads the laps and track points of the first activity of a TCX file
+func parseTCX(data []byte) (*ParsedActivity, error) {
+	var file tcxFile
+	if err := xml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if len(file.Activities) == 0 {
+		return nil, ErrEmptyActivity
+	}
+
+	source := file.Activities[0]
+	activity := &ParsedActivity{
+		Format: ActivityFormatTCX,
+		Sport:  normalizeActivitySport(source.Sport),
+	}
+	if startedAt, err := time.Parse(time.RFC3339, strings.TrimSpace(source.ID)); err == nil {
+		activity.StartedAt = startedAt
+	}
+
+	lapSeconds, lapMeters := 0.0, 0.0
+	hasLapTime, hasLapDistance := false, false
+	for _, lap := range source.Laps {
+		if lap.TotalTimeSeconds != nil {
+			lapSeconds += *lap.TotalTimeSeconds
+			hasLapTime = true
+		}
+		if lap.DistanceMeters != nil {
+			lapMeters += *lap.DistanceMeters
+			hasLapDistance = true
+		}
+		for _, trackPoint := range lap.TrackPoints {
+			recordedAt, err := time.Parse(time.RFC3339, strings.TrimSpace(trackPoint.Time))
+			if err != nil {
+				continue
+			}
+			activity.Points = append(activity.Points, ActivityPoint{
+				Time:            recordedAt,
+				Latitude:        trackPoint.Latitude,
+				Longitude:       trackPoint.Longitude,
+				ElevationMeters: trackPoint.AltitudeMeters,
+				DistanceMeters:  trackPoint.DistanceMeters,
+				HeartRate:       trackPoint.HeartRate,
+			})
+		}
+	}
+	if hasLapTime {
+		seconds := int(lapSeconds + 0.5)
+		activity.recordedDurationSeconds = &seconds
+	}
+	if hasLapDistance {
+		activity.recordedDistanceMeters = &lapMeters
+	}
+
+	return activity, nil
+}
+
+// normalizeActivitySport maps the sport names used by devices and apps to a lowercase sport
+func normalizeActivitySport(sport string) string {
+	sport = strings.ToLower(strings.TrimSpace(sport))
+	switch {
+	case sport == "":
+		return ""
+	case strings.Contains(sport, "run"):
+		return "running"
+	case strings.Contains(sport, "bik"), strings.Contains(sport, "cycl"), strings.Contains(sport, "ride"):
+		return "cycling"
+	case strings.Contains(sport, "swim"):
+		return "swimming"
+	case strings.Contains(sport, "walk"), strings.Contains(sport, "hik"):
+		return "walking"
+	case strings.Contains(sport, "row"):
+		return "rowing"
+	default:
+		return sport
+	}
+}
@@ -0,0 +1,208 @@
+package utils
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+)
+
+const testGPX = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="Watch" xmlns="http://www.topografix.com/GPX/1/1"
+  xmlns:gpxtpx="http://www.garmin.com/xmlschemas/TrackPointExtension/v1">
+  <trk>
+    <type>running</type>
+    <trkseg>
+      <trkpt lat="48.8566" lon="2.3522"><ele>35.0</ele><time>2024-05-01T07:00:00Z</time>
+        <extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>120</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions>
+      </trkpt>
+      <trkpt lat="48.8576" lon="2.3522"><ele>40.0</ele><time>2024-05-01T07:00:30Z</time>
+        <extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>140</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions>
+      </trkpt>
+      <trkpt lat="48.8586" lon="2.3522"><ele>39.0</ele><time>2024-05-01T07:01:00Z</time>
+        <extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>160</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions>
+      </trkpt>
+    </trkseg>
+  </trk>
+</gpx>`
+
+const testTCX = `<?xml version="1.0" encoding="UTF-8"?>
+<TrainingCenterDatabase xmlns="http://www.garmin.com/xmlschemas/TrainingCenterDatabase/v2">
+  <Activities>
+    <Activity Sport="Biking">
+      <Id>2024-05-02T18:30:00Z</Id>
+      <Lap StartTime="2024-05-02T18:30:00Z">
+        <TotalTimeSeconds>600</TotalTimeSeconds>
+        <DistanceMeters>5000</DistanceMeters>
+        <Track>
+          <Trackpoint>
+            <Time>2024-05-02T18:30:00Z</Time>
+            <AltitudeMeters>100</AltitudeMeters>
+            <DistanceMeters>0</DistanceMeters>
+            <HeartRateBpm><Value>110</Value></HeartRateBpm>
+          </Trackpoint>
+          <Trackpoint>
+            <Time>2024-05-02T18:35:00Z</Time>
+            <AltitudeMeters>90</AltitudeMeters>
+            <DistanceMeters>2600</DistanceMeters>
+            <HeartRateBpm><Value>150</Value></HeartRateBpm>
+          </Trackpoint>
+        </Track>
+      </Lap>
+    </Activity>
+  </Activities>
+</TrainingCenterDatabase>`
+
+// buildTestFIT encodes a FIT file with a record message per sample and a running session
+func buildTestFIT(start time.Time, samples [][3]uint32) []byte {
+	timestamp := uint32(start.Sub(fitEpoch).Seconds())
+	var records []byte
+
+	// Definition of local message 0: record with timestamp, heart rate and distance
+	records = append(records, 0x40, 0, 0)
+	records = binary.LittleEndian.AppendUint16(records, fitMessageRecord)
+	records = append(records, 3,
+		fitFieldTimestamp, 4, 0x86,
+		fitRecordHeartRate, 1, 0x02,
+		fitRecordDistance, 4, 0x86)
+	for _, sample := range samples {
+		records = append(records, 0x00)
+		records = binary.LittleEndian.AppendUint32(records, timestamp+sample[0])
+		records = append(records, byte(sample[1]))
+		records = binary.LittleEndian.AppendUint32(records, sample[2]*100)
+	}
+
+	// Definition of local message 1: session with start time and sport
+	records = append(records, 0x41, 0, 0)
+	records = binary.LittleEndian.AppendUint16(records, fitMessageSession)
+	records = append(records, 2,
+		fitSessionStartTime, 4, 0x86,
+		fitSessionSport, 1, 0x00)
+	records = append(records, 0x01)
+	records = binary.LittleEndian.AppendUint32(records, timestamp)
+	records = append(records, 1)
+
+	file := []byte{12, 0x10}
+	file = binary.LittleEndian.AppendUint16(file, 2100)
+	file = binary.LittleEndian.AppendUint32(file, uint32(len(records)))
+	file = append(file, ".FIT"...)
+	file = append(file, records...)
+	return binary.LittleEndian.AppendUint16(file, fitCRC(file))
+}
+
+func TestDetectActivityFormat(t *testing.T) {
+	fit := buildTestFIT(time.Date(2024, 5, 3, 6, 0, 0, 0, time.UTC), [][3]uint32{{0, 100, 0}})
+
+	tests := []struct {
+		name     string
+		filename string
+		data     []byte
+		expected string
+	}{
+		{"GPX by extension", "morning.GPX", nil, ActivityFormatGPX},
+		{"TCX by extension", "ride.tcx", nil, ActivityFormatTCX},
+		{"FIT by extension", "run.fit", nil, ActivityFormatFIT},
+		{"GPX by content", "upload", []byte(testGPX), ActivityFormatGPX},
+		{"TCX by content", "upload", []byte(testTCX), ActivityFormatTCX},
+		{"FIT by content", "upload", fit, ActivityFormatFIT},
+		{"Unknown", "notes.txt", []byte("hello"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := DetectActivityFormat(tt.filename, tt.data); result != tt.expected {
+				t.Errorf("DetectActivityFormat(%q) = %q, want %q", tt.filename, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseGPX(t *testing.T) {
+	activity, err := ParseActivityFile("run.gpx", []byte(testGPX))
+	if err != nil {
+		t.Fatalf("ParseActivityFile() error = %v", err)
+	}
+
+	if activity.Sport != "running" {
+		t.Errorf("Sport = %q, want running", activity.Sport)
+	}
+	if !activity.StartedAt.Equal(time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC)) {
+		t.Errorf("StartedAt = %v", activity.StartedAt)
+	}
+	if activity.DurationSeconds != 60 {
+		t.Errorf("DurationSeconds = %d, want 60", activity.DurationSeconds)
+	}
+	// Two steps of 0.001 degrees of latitude, about 111 m each
+	if activity.DistanceMeters == nil || math.Abs(*activity.DistanceMeters-222.4) > 1 {
+		t.Errorf("DistanceMeters = %v, want about 222.4", activity.DistanceMeters)
+	}
+	// The 1 m drop is below the noise threshold
+	if *activity.ElevationGainMeters != 5 || *activity.ElevationLossMeters != 0 {
+		t.Errorf("elevation = +%v -%v, want +5 -0", *activity.ElevationGainMeters, *activity.ElevationLossMeters)
+	}
+	if *activity.AverageHeartRate != 140 || *activity.MaxHeartRate != 160 {
+		t.Errorf("heart rate = %v avg %v max, want 140 avg 160 max", *activity.AverageHeartRate, *activity.MaxHeartRate)
+	}
+}
+
+func TestParseTCX(t *testing.T) {
+	activity, err := ParseActivityFile("ride.tcx", []byte(testTCX))
+	if err != nil {
+		t.Fatalf("ParseActivityFile() error = %v", err)
+	}
+
+	if activity.Sport != "cycling" {
+		t.Errorf("Sport = %q, want cycling", activity.Sport)
+	}
+	// Lap totals win over the track points
+	if activity.DurationSeconds != 600 {
+		t.Errorf("DurationSeconds = %d, want 600", activity.DurationSeconds)
+	}
+	if *activity.DistanceMeters != 5000 {
+		t.Errorf("DistanceMeters = %v, want 5000", *activity.DistanceMeters)
+	}
+	if *activity.ElevationLossMeters != 10 {
+		t.Errorf("ElevationLossMeters = %v, want 10", *activity.ElevationLossMeters)
+	}
+	if *activity.AverageHeartRate != 130 {
+		t.Errorf("AverageHeartRate = %v, want 130", *activity.AverageHeartRate)
+	}
+}
+
+func TestParseFIT(t *testing.T) {
+	start := time.Date(2024, 5, 3, 6, 0, 0, 0, time.UTC)
+	data := buildTestFIT(start, [][3]uint32{{0, 100, 0}, {60, 150, 250}, {120, 170, 500}})
+
+	activity, err := ParseActivityFile("run.fit", data)
+	if err != nil {
+		t.Fatalf("ParseActivityFile() error = %v", err)
+	}
+
+	if activity.Sport != "running" {
+		t.Errorf("Sport = %q, want running", activity.Sport)
+	}
+	if !activity.StartedAt.Equal(start) || activity.DurationSeconds != 120 {
+		t.Errorf("StartedAt = %v, DurationSeconds = %d", activity.StartedAt, activity.DurationSeconds)
+	}
+	if len(activity.Points) != 3 || *activity.DistanceMeters != 500 {
+		t.Errorf("points = %d, distance = %v, want 3 points and 500 m", len(activity.Points), *activity.DistanceMeters)
+	}
+	if *activity.MaxHeartRate != 170 || activity.ElevationGainMeters != nil {
+		t.Errorf("MaxHeartRate = %v, ElevationGainMeters = %v", *activity.MaxHeartRate, activity.ElevationGainMeters)
+	}
+
+	// A corrupted byte fails the checksum
+	data[20] ^= 0xFF
+	if _, err := ParseActivityFile("run.fit", data); err != ErrInvalidFITFile {
+		t.Errorf("ParseActivityFile() on a corrupted file error = %v, want %v", err, ErrInvalidFITFile)
+	}
+}
+
+func TestParseActivityFileErrors(t *testing.T) {
+	if _, err := ParseActivityFile("notes.txt", []byte("hello")); err != ErrUnsupportedActivityFormat {
+		t.Errorf("error = %v, want %v", err, ErrUnsupportedActivityFormat)
+	}
+	if _, err := ParseActivityFile("empty.gpx", []byte(`<gpx><trk><trkseg></trkseg></trk></gpx>`)); err != ErrEmptyActivity {
+		t.Errorf("error = %v, want %v", err, ErrEmptyActivity)
+	}
+}
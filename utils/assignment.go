@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"time"
+
+	"lamari-fit-api/models"
+)
+
+// AssignmentStatus derives a trainer assignment's status from the client's progress. An
+// assignment is overdue from the day after its due date until it is completed, and in progress
+// once the client has completed at least one session of it.
+func AssignmentStatus(dueDate *time.Time, progress models.AssignmentProgress, now time.Time) string {
+	switch {
+	case progress.CompletedAt != nil:
+		return models.AssignmentStatusCompleted
+	case progress.Cancelled:
+		return models.AssignmentStatusCancelled
+	case dueDate != nil && StartOfDay(now).After(StartOfDay(*dueDate)):
+		return models.AssignmentStatusOverdue
+	case progress.CompletedSessions > 0:
+		return models.AssignmentStatusInProgress
+	default:
+		return models.AssignmentStatusPending
+	}
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"lamari-fit-api/models"
+)
+
+func TestAssignmentStatus(t *testing.T) {
+	now := time.Date(2025, 3, 12, 18, 0, 0, 0, time.UTC)
+	today := time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC)
+	yesterday := today.AddDate(0, 0, -1)
+	completedAt := today.Add(-2 * time.Hour)
+
+	tests := []struct {
+		name     string
+		dueDate  *time.Time
+		progress models.AssignmentProgress
+		expected string
+	}{
+		{"Nothing done", &today, models.AssignmentProgress{}, models.AssignmentStatusPending},
+		{"No due date", nil, models.AssignmentProgress{}, models.AssignmentStatusPending},
+		{"Due today is not overdue", &today, models.AssignmentProgress{}, models.AssignmentStatusPending},
+		{"Past due date", &yesterday, models.AssignmentProgress{}, models.AssignmentStatusOverdue},
+		{"Some sessions done", &today, models.AssignmentProgress{CompletedSessions: 2}, models.AssignmentStatusInProgress},
+		{"Some sessions done past due date", &yesterday, models.AssignmentProgress{CompletedSessions: 2}, models.AssignmentStatusOverdue},
+		{"Completed late", &yesterday, models.AssignmentProgress{CompletedSessions: 1, CompletedAt: &completedAt}, models.AssignmentStatusCompleted},
+		{"Cancelled", &yesterday, models.AssignmentProgress{CompletedSessions: 1, Cancelled: true}, models.AssignmentStatusCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := AssignmentStatus(tt.dueDate, tt.progress, now)
+			if result != tt.expected {
+				t.Errorf("AssignmentStatus() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package utils
+
+import (
+	"math"
+
+	"lamari-fit-api/models"
+)
+
+// BuildSessionCompliance compares a session's logged sets with the prescriptions its exercises
+// came from. Exercises added without a prescription are only counted. Returns nil if nothing in
+// the session was prescribed.
+//
+// An exercise's score is its completion (completed main sets over prescribed sets, capped at 1)
+// times its accuracy: the average, over the reps or hold and load targets it has, of how much of
+// the target the completed sets reached on average (capped at 1). Going over a target is not
+// penalised, and RPE is reported but not scored. Block and session scores weight the exercise
+// scores by their prescribed sets.
+//
+// The session is expected to have its exercises, prescriptions and sets loaded, along with the
+// RPE values of the prescriptions and sets.
+func BuildSessionCompliance(session models.WorkoutSession, preferredWeightUnit string) *models.SessionComplianceResponse {
+	response := &models.SessionComplianceResponse{
+		SessionID: session.ID,
+		Blocks:    []models.BlockComplianceResponse{},
+	}
+
+	var sessionScore complianceScore
+	for _, block := range session.SessionBlocks {
+		blockResp := models.BlockComplianceResponse{
+			SessionBlockID: block.ID,
+			BlockOrder:     block.BlockOrder,
+			Skipped:        block.Skipped,
+			Exercises:      []models.ExerciseComplianceResponse{},
+		}
+
+		var blockScore complianceScore
+		for _, exercise := range block.SessionExercises {
+			if exercise.Prescription == nil {
+				response.UnprescribedExercises++
+				continue
+			}
+			if blockResp.Type == "" {
+				blockResp.Type = exercise.Prescription.Type
+			}
+
+			exerciseResp := buildExerciseCompliance(exercise, block.Skipped, preferredWeightUnit)
+			blockResp.Exercises = append(blockResp.Exercises, exerciseResp)
+			blockResp.PrescribedSets += exerciseResp.PrescribedSets
+			blockResp.CompletedSets += exerciseResp.CompletedSets
+			if exerciseResp.Skipped {
+				blockResp.SkippedExercises++
+			}
+			blockScore.add(exerciseResp)
+			sessionScore.add(exerciseResp)
+		}
+		if len(blockResp.Exercises) == 0 {
+			continue
+		}
+
+		blockResp.CompletionPercent, blockResp.Score = blockScore.result()
+		response.PrescribedSets += blockResp.PrescribedSets
+		response.CompletedSets += blockResp.CompletedSets
+		response.SkippedExercises += blockResp.SkippedExercises
+		if blockResp.Skipped {
+			response.SkippedBlocks++
+		}
+		response.Blocks = append(response.Blocks, blockResp)
+	}
+
+	if len(response.Blocks) == 0 {
+		return nil
+	}
+	response.CompletionPercent, response.Score = sessionScore.result()
+	return response
+}
+
+// buildExerciseCompliance compares one prescribed session exercise with its prescription
+func buildExerciseCompliance(exercise models.SessionExercise, blockSkipped bool, preferredWeightUnit string) models.ExerciseComplianceResponse {
+	prescription := *exercise.Prescription
+	response := models.ExerciseComplianceResponse{
+		SessionExerciseID: exercise.ID,
+		ExerciseID:        exercise.ExerciseID,
+		ExerciseName:      exercise.Exercise.Name,
+		Skipped:           exercise.Skipped || blockSkipped,
+		PrescribedSets:    PrescribedSetCount(prescription),
+	}
+
+	var reps, hold, weight, rpe average
+	for _, set := range exercise.SessionSets {
+		if !set.Completed || set.SubSetNumber > 0 {
+			continue
+		}
+		response.CompletedSets++
+		reps.addInt(set.ActualReps)
+		hold.addInt(set.ActualDurationSeconds)
+		weight.addFloat(set.ActualWeightKg)
+		if set.RPEValue != nil {
+			rpe.addInt(&set.RPEValue.Value)
+		}
+	}
+
+	response.SetsDelta = response.CompletedSets - response.PrescribedSets
+	completion := math.Min(float64(response.CompletedSets)/float64(response.PrescribedSets), 1)
+	response.CompletionPercent = roundToDecimal(completion*100, 1)
+
+	var accuracy average
+	if target := positiveTarget(prescription.Reps); target != nil {
+		response.PrescribedReps = prescription.Reps
+		response.AverageReps, response.RepsDelta = reps.compare(*target)
+		accuracy.addReached(reps, *target)
+	}
+	if target := positiveTarget(prescription.HoldSeconds); target != nil {
+		response.PrescribedHoldSeconds = prescription.HoldSeconds
+		response.AverageHoldSeconds, response.HoldSecondsDelta = hold.compare(*target)
+		accuracy.addReached(hold, *target)
+	}
+	if prescription.TargetWeightKg != nil && *prescription.TargetWeightKg > 0 {
+		target := *prescription.TargetWeightKg
+		response.PrescribedWeight = ConvertWeightForResponse(&target, preferredWeightUnit)
+		if weight.count > 0 {
+			averageKg := weight.mean()
+			deltaKg := averageKg - target
+			response.AverageWeight = ConvertWeightForResponse(&averageKg, preferredWeightUnit)
+			response.WeightDelta = ConvertWeightForResponse(&deltaKg, preferredWeightUnit)
+		}
+		accuracy.addReached(weight, target)
+	}
+	if prescription.RPEValue != nil {
+		response.PrescribedRPE = &prescription.RPEValue.Value
+		response.AverageRPE, response.RPEDelta = rpe.compare(float64(prescription.RPEValue.Value))
+	}
+
+	score := completion
+	if accuracy.count > 0 {
+		score *= accuracy.mean()
+	}
+	response.Score = roundToDecimal(score*100, 1)
+	return response
+}
+
+// positiveTarget returns the target as a float if it is set and positive
+func positiveTarget(value *int) *float64 {
+	if value == nil || *value <= 0 {
+		return nil
+	}
+	target := float64(*value)
+	return &target
+}
+
+// average accumulates values for a mean
+type average struct {
+	total float64
+	count int
+}
+
+func (a *average) addInt(value *int) {
+	if value != nil {
+		a.total += float64(*value)
+		a.count++
+	}
+}
+
+func (a *average) addFloat(value *float64) {
+	if value != nil {
+		a.total += *value
+		a.count++
+	}
+}
+
+func (a average) mean() float64 {
+	return a.total / float64(a.count)
+}
+
+// compare returns the mean and its difference from target, both rounded to one decimal,
+// or nils if nothing was added
+func (a average) compare(target float64) (*float64, *float64) {
+	if a.count == 0 {
+		return nil, nil
+	}
+	mean := roundToDecimal(a.mean(), 1)
+	delta := roundToDecimal(a.mean()-target, 1)
+	return &mean, &delta
+}
+
+// addReached adds how much of target the values reached on average, capped at 1.
+// Nothing logged against a target counts as not reaching it.
+func (a *average) addReached(values average, target float64) {
+	reached := 0.0
+	if values.count > 0 {
+		reached = math.Min(values.mean()/target, 1)
+	}
+	a.total += reached
+	a.count++
+}
+
+// complianceScore accumulates exercise results weighted by their prescribed sets
+type complianceScore struct {
+	prescribedSets int
+	completedSets  int // capped at the prescribed sets of each exercise
+	weightedScore  float64
+}
+
+func (s *complianceScore) add(exercise models.ExerciseComplianceResponse) {
+	s.prescribedSets += exercise.PrescribedSets
+	s.completedSets += int(math.Min(float64(exercise.CompletedSets), float64(exercise.PrescribedSets)))
+	s.weightedScore += exercise.Score * float64(exercise.PrescribedSets)
+}
+
+// result returns the completion percentage and score
+func (s complianceScore) result() (float64, float64) {
+	if s.prescribedSets == 0 {
+		return 0, 0
+	}
+	completion := roundToDecimal(float64(s.completedSets)/float64(s.prescribedSets)*100, 1)
+	score := roundToDecimal(s.weightedScore/float64(s.prescribedSets), 1)
+	return completion, score
+}
@@ -0,0 +1,200 @@
+package utils
+
+import (
+	"testing"
+
+	"lamari-fit-api/models"
+)
+
+func loggedSet(reps int, weight float64) models.SessionSet {
+	return models.SessionSet{Completed: true, ActualReps: intPtr(reps), ActualWeightKg: floatPtr(weight)}
+}
+
+func prescribedExercise(prescription models.WorkoutPrescription, sets ...models.SessionSet) models.SessionExercise {
+	return models.SessionExercise{Prescription: &prescription, SessionSets: sets}
+}
+
+func TestBuildExerciseCompliance(t *testing.T) {
+	straight := models.WorkoutPrescription{
+		Type:           models.PrescriptionTypeStraight,
+		Sets:           intPtr(3),
+		Reps:           intPtr(10),
+		TargetWeightKg: floatPtr(100),
+	}
+
+	tests := []struct {
+		name       string
+		exercise   models.SessionExercise
+		skipped    bool
+		completion float64
+		setsDelta  int
+		repsDelta  *float64
+		score      float64
+	}{
+		{
+			name:       "Everything as prescribed",
+			exercise:   prescribedExercise(straight, loggedSet(10, 100), loggedSet(10, 100), loggedSet(10, 100)),
+			completion: 100, setsDelta: 0, repsDelta: floatPtr(0), score: 100,
+		},
+		{
+			name:       "Extra set and heavier load are not penalised",
+			exercise:   prescribedExercise(straight, loggedSet(10, 110), loggedSet(12, 110), loggedSet(10, 110), loggedSet(10, 110)),
+			completion: 100, setsDelta: 1, repsDelta: floatPtr(0.5), score: 100,
+		},
+		{
+			name:       "Two of three sets at the target",
+			exercise:   prescribedExercise(straight, loggedSet(10, 100), loggedSet(10, 100), models.SessionSet{}),
+			completion: 66.7, setsDelta: -1, repsDelta: floatPtr(0), score: 66.7,
+		},
+		{
+			name:       "Short on reps and load",
+			exercise:   prescribedExercise(straight, loggedSet(8, 90), loggedSet(8, 90), loggedSet(8, 90)),
+			completion: 100, setsDelta: 0, repsDelta: floatPtr(-2), score: 85,
+		},
+		{
+			name:       "Nothing logged",
+			exercise:   prescribedExercise(straight),
+			completion: 0, setsDelta: -3, score: 0,
+		},
+		{
+			name: "Circuit rounds set the prescribed sets",
+			exercise: prescribedExercise(models.WorkoutPrescription{
+				Type:        models.PrescriptionTypeCircuit,
+				GroupRounds: intPtr(4),
+				Reps:        intPtr(15),
+			}, models.SessionSet{Completed: true, ActualReps: intPtr(15)}, models.SessionSet{Completed: true, ActualReps: intPtr(15)}),
+			completion: 50, setsDelta: -2, repsDelta: floatPtr(0), score: 50,
+		},
+		{
+			name: "Drops do not count as sets",
+			exercise: prescribedExercise(models.WorkoutPrescription{
+				Type: models.PrescriptionTypeDropSet,
+				Sets: intPtr(1),
+				Reps: intPtr(10),
+			}, models.SessionSet{Completed: true, ActualReps: intPtr(10)}, models.SessionSet{Completed: true, SubSetNumber: 1, ActualReps: intPtr(4)}),
+			completion: 100, setsDelta: 0, repsDelta: floatPtr(0), score: 100,
+		},
+		{
+			name: "Holds are compared by duration",
+			exercise: prescribedExercise(models.WorkoutPrescription{
+				Type:        models.PrescriptionTypeStraight,
+				Sets:        intPtr(2),
+				HoldSeconds: intPtr(60),
+			}, models.SessionSet{Completed: true, ActualDurationSeconds: intPtr(60)}, models.SessionSet{Completed: true, ActualDurationSeconds: intPtr(30)}),
+			completion: 100, setsDelta: 0, score: 75,
+		},
+		{
+			name:       "Skipped block marks its exercises skipped",
+			exercise:   prescribedExercise(straight),
+			skipped:    true,
+			completion: 0, setsDelta: -3, score: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := buildExerciseCompliance(tt.exercise, tt.skipped, "kg")
+			if result.CompletionPercent != tt.completion {
+				t.Errorf("CompletionPercent = %v, want %v", result.CompletionPercent, tt.completion)
+			}
+			if result.SetsDelta != tt.setsDelta {
+				t.Errorf("SetsDelta = %v, want %v", result.SetsDelta, tt.setsDelta)
+			}
+			if tt.repsDelta != nil && (result.RepsDelta == nil || *result.RepsDelta != *tt.repsDelta) {
+				t.Errorf("RepsDelta = %v, want %v", result.RepsDelta, *tt.repsDelta)
+			}
+			if result.Score != tt.score {
+				t.Errorf("Score = %v, want %v", result.Score, tt.score)
+			}
+			if tt.skipped && !result.Skipped {
+				t.Errorf("Skipped = false, want true")
+			}
+		})
+	}
+}
+
+func TestBuildExerciseComplianceDeltas(t *testing.T) {
+	rpeSeven := models.RPEScaleValue{Value: 7}
+	rpeNine := models.RPEScaleValue{Value: 9}
+	prescription := models.WorkoutPrescription{
+		Type:           models.PrescriptionTypeStraight,
+		Sets:           intPtr(2),
+		Reps:           intPtr(5),
+		TargetWeightKg: floatPtr(100),
+		RPEValue:       &rpeSeven,
+	}
+	first := loggedSet(5, 100)
+	first.RPEValue = &rpeNine
+	second := loggedSet(4, 95)
+	second.RPEValue = &rpeNine
+
+	result := buildExerciseCompliance(prescribedExercise(prescription, first, second), false, "kg")
+	if result.AverageReps == nil || *result.AverageReps != 4.5 {
+		t.Errorf("AverageReps = %v, want 4.5", result.AverageReps)
+	}
+	if result.WeightDelta == nil || *result.WeightDelta.WeightValue != -2.5 {
+		t.Errorf("WeightDelta = %v, want -2.5", result.WeightDelta)
+	}
+	if result.PrescribedRPE == nil || *result.PrescribedRPE != 7 {
+		t.Errorf("PrescribedRPE = %v, want 7", result.PrescribedRPE)
+	}
+	if result.RPEDelta == nil || *result.RPEDelta != 2 {
+		t.Errorf("RPEDelta = %v, want 2", result.RPEDelta)
+	}
+
+	lb := buildExerciseCompliance(prescribedExercise(prescription, first, second), false, "lb")
+	if *lb.PrescribedWeight.WeightUnit != "lb" || *lb.PrescribedWeight.WeightValue != KgToLbs(100) {
+		t.Errorf("PrescribedWeight = %v %v, want %v lb", *lb.PrescribedWeight.WeightValue, *lb.PrescribedWeight.WeightUnit, KgToLbs(100))
+	}
+}
+
+func TestBuildSessionCompliance(t *testing.T) {
+	squat := models.WorkoutPrescription{Type: models.PrescriptionTypeStraight, Sets: intPtr(3), Reps: intPtr(5)}
+	curl := models.WorkoutPrescription{Type: models.PrescriptionTypeStraight, Sets: intPtr(1), Reps: intPtr(10)}
+	done := models.SessionSet{Completed: true, ActualReps: intPtr(5)}
+
+	session := models.WorkoutSession{
+		SessionBlocks: []models.SessionBlock{
+			{
+				BlockOrder: 1,
+				SessionExercises: []models.SessionExercise{
+					prescribedExercise(squat, done, done, done),
+					{ExerciseOrder: 2}, // added without a prescription
+				},
+			},
+			{
+				BlockOrder:       2,
+				Skipped:          true,
+				SessionExercises: []models.SessionExercise{prescribedExercise(curl)},
+			},
+		},
+	}
+
+	result := BuildSessionCompliance(session, "kg")
+	if result == nil {
+		t.Fatal("BuildSessionCompliance() = nil")
+	}
+	if len(result.Blocks) != 2 {
+		t.Fatalf("len(Blocks) = %d, want 2", len(result.Blocks))
+	}
+	if result.PrescribedSets != 4 || result.CompletedSets != 3 {
+		t.Errorf("sets = %d/%d, want 3/4", result.CompletedSets, result.PrescribedSets)
+	}
+	if result.CompletionPercent != 75 || result.Score != 75 {
+		t.Errorf("completion, score = %v, %v, want 75, 75", result.CompletionPercent, result.Score)
+	}
+	if result.SkippedBlocks != 1 || result.SkippedExercises != 1 || result.UnprescribedExercises != 1 {
+		t.Errorf("skipped blocks, skipped exercises, unprescribed = %d, %d, %d, want 1, 1, 1",
+			result.SkippedBlocks, result.SkippedExercises, result.UnprescribedExercises)
+	}
+	if result.Blocks[0].Score != 100 || result.Blocks[1].Score != 0 {
+		t.Errorf("block scores = %v, %v, want 100, 0", result.Blocks[0].Score, result.Blocks[1].Score)
+	}
+
+	freeForm := models.WorkoutSession{
+		SessionBlocks: []models.SessionBlock{{SessionExercises: []models.SessionExercise{{}}}},
+	}
+	if result := BuildSessionCompliance(freeForm, "kg"); result != nil {
+		t.Errorf("BuildSessionCompliance() of a free-form session = %+v, want nil", result)
+	}
+}
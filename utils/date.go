@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DateFormat is the date-only format used in query parameters and responses
+const DateFormat = "2006-01-02"
+
+// Time bucket groupings for analytics
+const (
+	GroupByDay   = "day"
+	GroupByWeek  = "week"
+	GroupByMonth = "month"
+)
+
+// ValidGroupings defines acceptable group_by values
+var ValidGroupings = map[string]bool{
+	GroupByDay:   true,
+	GroupByWeek:  true,
+	GroupByMonth: true,
+}
+
+// StartOfDay returns midnight UTC of the given time's date
+func StartOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// StartOfISOWeek returns midnight UTC of the Monday starting the ISO week containing t
+func StartOfISOWeek(t time.Time) time.Time {
+	day := StartOfDay(t)
+	// time.Weekday is 0=Sunday; ISO weeks start on Monday
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// DaysBetween returns the number of calendar days from from's date to to's date, in UTC
+func DaysBetween(from, to time.Time) int {
+	return int(StartOfDay(to).Sub(StartOfDay(from)).Hours() / 24)
+}
+
+// BucketStart returns the start of the day, ISO week or month containing t
+// Unknown groupings fall back to day
+func BucketStart(t time.Time, groupBy string) time.Time {
+	switch groupBy {
+	case GroupByWeek:
+		return StartOfISOWeek(t)
+	case GroupByMonth:
+		t = t.UTC()
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return StartOfDay(t)
+	}
+}
+
+// ParseDateQuery parses an optional YYYY-MM-DD query parameter.
+// Returns nil and true if the parameter is absent.
+// Automatically sends a validation error response if the value is malformed.
+func ParseDateQuery(c *gin.Context, key string) (*time.Time, bool) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, true
+	}
+
+	parsed, err := time.Parse(DateFormat, value)
+	if err != nil {
+		ValidationErrorResponse(c, ValidationErrors{
+			key: []string{fmt.Sprintf("%s must be a date in YYYY-MM-DD format", key)},
+		})
+		return nil, false
+	}
+
+	return &parsed, true
+}
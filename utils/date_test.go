@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBucketStart(t *testing.T) {
+	// Wednesday, 2025-01-15 14:30 UTC
+	wednesday := time.Date(2025, 1, 15, 14, 30, 0, 0, time.UTC)
+	// Sunday, 2025-01-19 23:59 UTC (still ISO week starting Monday 2025-01-13)
+	sunday := time.Date(2025, 1, 19, 23, 59, 0, 0, time.UTC)
+	// Wednesday, 2025-01-01 (ISO week starts Monday 2024-12-30)
+	newYear := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		input    time.Time
+		groupBy  string
+		expected time.Time
+	}{
+		{"Day", wednesday, GroupByDay, time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"Week from Wednesday", wednesday, GroupByWeek, time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC)},
+		{"Week from Sunday", sunday, GroupByWeek, time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC)},
+		{"Week across year boundary", newYear, GroupByWeek, time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)},
+		{"Month", wednesday, GroupByMonth, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"Unknown grouping defaults to day", sunday, "year", time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"Non-UTC input", time.Date(2025, 1, 13, 1, 0, 0, 0, time.FixedZone("UTC+3", 3*3600)), GroupByDay, time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := BucketStart(tt.input, tt.groupBy)
+			if !result.Equal(tt.expected) {
+				t.Errorf("BucketStart(%v, %q) = %v, want %v", tt.input, tt.groupBy, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDaysBetween(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     time.Time
+		to       time.Time
+		expected int
+	}{
+		{"Same day", time.Date(2025, 1, 15, 6, 0, 0, 0, time.UTC), time.Date(2025, 1, 15, 23, 0, 0, 0, time.UTC), 0},
+		{"Late evening to early morning", time.Date(2025, 1, 15, 23, 0, 0, 0, time.UTC), time.Date(2025, 1, 16, 1, 0, 0, 0, time.UTC), 1},
+		{"Across months", time.Date(2025, 1, 30, 12, 0, 0, 0, time.UTC), time.Date(2025, 2, 2, 12, 0, 0, 0, time.UTC), 3},
+		{"Backwards", time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := DaysBetween(tt.from, tt.to)
+			if result != tt.expected {
+				t.Errorf("DaysBetween(%v, %v) = %d, want %d", tt.from, tt.to, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package utils
+
+import "lamari-fit-api/models"
+
+// Distance conversion constants
+const (
+	MetersPerKm   = 1000.0
+	MetersPerMile = models.MetersPerMile
+)
+
+// ValidDistanceUnits defines acceptable distance unit values
+var ValidDistanceUnits = map[string]bool{
+	"m":  true,
+	"km": true,
+	"mi": true,
+}
+
+// ConvertToMeters converts a distance from the given unit to metres
+// Supported units: "m", "km", "mi"
+// If unit is empty or unrecognized, assumes km
+func ConvertToMeters(distance float64, unit string) float64 {
+	switch unit {
+	case "m":
+		return roundToDecimal(distance, 2)
+	case "mi":
+		return roundToDecimal(distance*MetersPerMile, 2)
+	default:
+		return roundToDecimal(distance*MetersPerKm, 2)
+	}
+}
+
+// ConvertFromMeters converts a distance in metres to the target unit
+// Kilometres and miles are rounded to 3 decimals (metre precision), metres to 2
+// If unit is empty or unrecognized, returns km
+func ConvertFromMeters(meters float64, unit string) float64 {
+	switch unit {
+	case "m":
+		return roundToDecimal(meters, 2)
+	case "mi":
+		return roundToDecimal(meters/MetersPerMile, 3)
+	default:
+		return roundToDecimal(meters/MetersPerKm, 3)
+	}
+}
+
+// NormalizeDistanceUnit normalizes distance unit strings to a standard format
+// Returns "m", "km" or "mi"
+func NormalizeDistanceUnit(unit string) string {
+	if ValidDistanceUnits[unit] {
+		return unit
+	}
+	return "km"
+}
+
+// GetUserPreferredDistanceUnit returns the user's preferred distance unit
+// Returns "km" as default if empty or invalid
+func GetUserPreferredDistanceUnit(preferredDistanceUnit string) string {
+	switch preferredDistanceUnit {
+	case "km", "mi":
+		return preferredDistanceUnit
+	default:
+		return "km"
+	}
+}
+
+// ProcessDistanceInput processes a DistanceInput and returns the canonical metre value,
+// the original value, and the normalized unit for storage
+// Returns (distanceMeters, originalValue, originalUnit)
+func ProcessDistanceInput(input *models.DistanceInput) (*float64, *float64, *string) {
+	if input == nil || input.DistanceValue == nil {
+		return nil, nil, nil
+	}
+
+	unit := "km" // default
+	if input.DistanceUnit != nil {
+		unit = NormalizeDistanceUnit(*input.DistanceUnit)
+	}
+
+	meters := ConvertToMeters(*input.DistanceValue, unit)
+
+	return &meters, input.DistanceValue, &unit
+}
+
+// ProcessPaceInput processes a PaceInput and returns the canonical pace in seconds per km
+func ProcessPaceInput(input *models.PaceInput) *float64 {
+	if input == nil || input.SecondsPerUnit == nil {
+		return nil
+	}
+
+	secondsPerKm := *input.SecondsPerUnit
+	if input.DistanceUnit != nil && *input.DistanceUnit == "mi" {
+		secondsPerKm = *input.SecondsPerUnit * MetersPerKm / MetersPerMile
+	}
+	secondsPerKm = roundToDecimal(secondsPerKm, 2)
+
+	return &secondsPerKm
+}
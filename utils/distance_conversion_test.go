@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"testing"
+
+	"lamari-fit-api/models"
+)
+
+func TestConvertToMeters(t *testing.T) {
+	tests := []struct {
+		name     string
+		distance float64
+		unit     string
+		expected float64
+	}{
+		{"Metres", 400, "m", 400},
+		{"Kilometres", 5, "km", 5000},
+		{"Miles", 1, "mi", 1609.34},
+		{"Half marathon in miles", 13.1, "mi", 21082.41},
+		{"Empty unit defaults to km", 2.5, "", 2500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := ConvertToMeters(tt.distance, tt.unit); result != tt.expected {
+				t.Errorf("ConvertToMeters(%v, %q) = %v, want %v", tt.distance, tt.unit, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertFromMeters(t *testing.T) {
+	tests := []struct {
+		name     string
+		meters   float64
+		unit     string
+		expected float64
+	}{
+		{"Kilometres", 5000, "km", 5},
+		{"Miles", 5000, "mi", 3.107},
+		{"Metres", 400, "m", 400},
+		{"Unknown unit defaults to km", 1500, "yd", 1.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := ConvertFromMeters(tt.meters, tt.unit); result != tt.expected {
+				t.Errorf("ConvertFromMeters(%v, %q) = %v, want %v", tt.meters, tt.unit, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProcessDistanceInput(t *testing.T) {
+	value := 3.0
+	unit := "mi"
+	meters, originalValue, originalUnit := ProcessDistanceInput(&models.DistanceInput{DistanceValue: &value, DistanceUnit: &unit})
+	if meters == nil || *meters != 4828.03 {
+		t.Errorf("meters = %v, want 4828.03", meters)
+	}
+	if originalValue == nil || *originalValue != 3 || originalUnit == nil || *originalUnit != "mi" {
+		t.Errorf("original = %v %v, want 3 mi", originalValue, originalUnit)
+	}
+
+	if meters, _, _ := ProcessDistanceInput(&models.DistanceInput{}); meters != nil {
+		t.Errorf("ProcessDistanceInput() without a value = %v, want nil", *meters)
+	}
+}
+
+func TestPaceConversion(t *testing.T) {
+	perMile := 480.0
+	mi := "mi"
+	secondsPerKm := ProcessPaceInput(&models.PaceInput{SecondsPerUnit: &perMile, DistanceUnit: &mi})
+	if secondsPerKm == nil || *secondsPerKm != 298.26 {
+		t.Fatalf("ProcessPaceInput(480 s/mi) = %v, want 298.26 s/km", secondsPerKm)
+	}
+
+	output := models.NewPaceOutput(*secondsPerKm, "mi")
+	if *output.SecondsPerUnit != 480 || *output.DistanceUnit != "mi" {
+		t.Errorf("NewPaceOutput() = %v %v, want 480 mi", *output.SecondsPerUnit, *output.DistanceUnit)
+	}
+
+	output = models.NewPaceOutput(*secondsPerKm, "")
+	if *output.SecondsPerUnit != 298.3 || *output.DistanceUnit != "km" {
+		t.Errorf("NewPaceOutput() = %v %v, want 298.3 km", *output.SecondsPerUnit, *output.DistanceUnit)
+	}
+
+	distance := models.NewDistanceOutput(5000, "mi")
+	if *distance.DistanceValue != 3.107 || *distance.DistanceUnit != "mi" {
+		t.Errorf("NewDistanceOutput() = %v %v, want 3.107 mi", *distance.DistanceValue, *distance.DistanceUnit)
+	}
+}
@@ -3,10 +3,13 @@ package utils
 import (
 	"crypto/tls"
 	"fmt"
+	"html"
 	"lamari-fit-api/config"
 	"log"
+	"mime"
 	"net/smtp"
 	"strings"
+	"time"
 )
 
 // EmailService handles sending emails
@@ -81,6 +84,47 @@ func (e *EmailService) SendTrainerInvitation(toEmail, trainerName, invitationTok
 	return e.sendEmail(toEmail, subject, body)
 }
 
+// SendTrainerAssignment notifies a client that their trainer assigned them a workout or plan
+func (e *EmailService) SendTrainerAssignment(toEmail, trainerName, title string, dueDate *time.Time) error {
+	subject := fmt.Sprintf("%s assigned you %s", trainerName, title)
+
+	dueLine := ""
+	if dueDate != nil {
+		dueLine = fmt.Sprintf("<p>It is due on <strong>%s</strong>.</p>", dueDate.Format(DateFormat))
+	}
+
+	assignmentsLink := fmt.Sprintf("%s/assignments", e.appURL)
+
+	body := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>New Assignment</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
+    <div style="max-width: 600px; margin: 0 auto; padding: 20px;">
+        <h2 style="color: #2c3e50;">You Have a New Assignment</h2>
+
+        <p>Hi there,</p>
+
+        <p><strong>%s</strong> has assigned you <strong>%s</strong> on LamariFit.</p>
+
+        %s
+
+        <div style="margin: 30px 0;">
+            <a href="%s" style="background-color: #3498db; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">
+                View Assignment
+            </a>
+        </div>
+    </div>
+</body>
+</html>
+`, html.EscapeString(trainerName), html.EscapeString(title), dueLine, assignmentsLink)
+
+	return e.sendEmail(toEmail, subject, body)
+}
+
 // sendEmail sends an email using SMTP
 func (e *EmailService) sendEmail(to, subject, body string) error {
 	// Check if email is configured
@@ -94,7 +138,7 @@ func (e *EmailService) sendEmail(to, subject, body string) error {
 	headers := make(map[string]string)
 	headers["From"] = from
 	headers["To"] = to
-	headers["Subject"] = subject
+	headers["Subject"] = encodeHeader(subject)
 	headers["MIME-Version"] = "1.0"
 	headers["Content-Type"] = "text/html; charset=UTF-8"
 
@@ -118,6 +162,13 @@ func (e *EmailService) sendEmail(to, subject, body string) error {
 	return smtp.SendMail(addr, auth, e.fromEmail, []string{to}, []byte(message))
 }
 
+// encodeHeader makes a user-provided value safe for a header: line breaks, which would start new
+// headers, are replaced by spaces and non-ASCII text is MIME-encoded
+func encodeHeader(value string) string {
+	value = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(value)
+	return mime.QEncoding.Encode("utf-8", value)
+}
+
 // sendEmailTLS sends email using TLS (for port 465)
 func (e *EmailService) sendEmailTLS(to, message string, auth smtp.Auth, addr string) error {
 	tlsConfig := &tls.Config{
@@ -0,0 +1,24 @@
+package utils
+
+import "testing"
+
+func TestEncodeHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{"Plain text is unchanged", "Jane assigned you Leg Day", "Jane assigned you Leg Day"},
+		{"Line breaks cannot start new headers", "Leg Day\r\nBcc: victim@example.com", "Leg Day Bcc: victim@example.com"},
+		{"Bare line feeds are replaced too", "Leg\nDay\rPlan", "Leg Day Plan"},
+		{"Non-ASCII text is encoded", "Séance", "=?utf-8?q?S=C3=A9ance?="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := encodeHeader(tt.value); result != tt.expected {
+				t.Errorf("encodeHeader(%q) = %q, want %q", tt.value, result, tt.expected)
+			}
+		})
+	}
+}
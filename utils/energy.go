@@ -0,0 +1,71 @@
+package utils
+
+// DefaultExerciseMET is the MET of general resistance training, used for exercises
+// without a known exercise type
+const DefaultExerciseMET = 5.0
+
+// exerciseTypeMETs maps exercise type slugs to metabolic equivalents (MET), based on the
+// Compendium of Physical Activities. Movement patterns such as push and pull don't say
+// anything about intensity and are left out.
+var exerciseTypeMETs = map[string]float64{
+	"plyometric": 8.0,
+	"cardio":     7.0,
+	"compound":   6.0,
+	"isolation":  3.5,
+	"isometric":  3.0,
+	"mobility":   2.5,
+	"stretching": 2.3,
+}
+
+// ExerciseMET returns the MET of an exercise from its exercise type slugs: the highest MET of
+// its known types, or DefaultExerciseMET if none are known
+func ExerciseMET(typeSlugs []string) float64 {
+	met := 0.0
+	for _, slug := range typeSlugs {
+		if value, ok := exerciseTypeMETs[slug]; ok && value > met {
+			met = value
+		}
+	}
+	if met == 0 {
+		return DefaultExerciseMET
+	}
+	return met
+}
+
+// BlockEnergy is the time spent on a session block and the MET of the work done in it.
+// Seconds is 0 if the block wasn't timed; MET is 0 if no work was done in it.
+type BlockEnergy struct {
+	Seconds int
+	MET     float64
+}
+
+// EstimateCalories returns the energy in kcal spent doing an activity of the given MET for seconds:
+// MET × body weight (kg) × hours
+func EstimateCalories(met float64, bodyWeightKg float64, seconds int) float64 {
+	return met * bodyWeightKg * float64(seconds) / 3600
+}
+
+// EstimateSessionCalories estimates the kcal spent in a session, rounded to a whole number.
+// Timed blocks count at their own MET. The rest of the session (untimed blocks, transitions)
+// counts at the average MET of the blocks where work was done.
+func EstimateSessionCalories(blocks []BlockEnergy, sessionSeconds int, bodyWeightKg float64) float64 {
+	total := 0.0
+	timedSeconds := 0
+	var met average
+	for _, block := range blocks {
+		if block.MET <= 0 {
+			continue
+		}
+		met.addFloat(&block.MET)
+		if block.Seconds > 0 {
+			total += EstimateCalories(block.MET, bodyWeightKg, block.Seconds)
+			timedSeconds += block.Seconds
+		}
+	}
+
+	if remaining := sessionSeconds - timedSeconds; remaining > 0 && met.count > 0 {
+		total += EstimateCalories(met.mean(), bodyWeightKg, remaining)
+	}
+
+	return roundToDecimal(total, 0)
+}
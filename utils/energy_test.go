@@ -0,0 +1,80 @@
+package utils
+
+import "testing"
+
+func TestExerciseMET(t *testing.T) {
+	tests := []struct {
+		name     string
+		slugs    []string
+		expected float64
+	}{
+		{"No types", nil, DefaultExerciseMET},
+		{"Only movement patterns", []string{"push"}, DefaultExerciseMET},
+		{"Compound push", []string{"compound", "push"}, 6.0},
+		{"Isolation", []string{"isolation", "pull"}, 3.5},
+		{"Highest type wins", []string{"isometric", "cardio"}, 7.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := ExerciseMET(tt.slugs); result != tt.expected {
+				t.Errorf("ExerciseMET(%v) = %v, want %v", tt.slugs, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEstimateSessionCalories(t *testing.T) {
+	tests := []struct {
+		name           string
+		blocks         []BlockEnergy
+		sessionSeconds int
+		bodyWeightKg   float64
+		expected       float64
+	}{
+		{
+			name:           "One hour of compound lifting at 80 kg",
+			blocks:         []BlockEnergy{{MET: 6}},
+			sessionSeconds: 3600,
+			bodyWeightKg:   80,
+			expected:       480,
+		},
+		{
+			name:           "Timed blocks at their own MET, the rest at the average",
+			blocks:         []BlockEnergy{{Seconds: 1200, MET: 7}, {Seconds: 600, MET: 3}},
+			sessionSeconds: 2400,
+			bodyWeightKg:   60,
+			expected:       140 + 30 + 50,
+		},
+		{
+			name:           "Blocks without work are ignored",
+			blocks:         []BlockEnergy{{Seconds: 1800, MET: 0}, {MET: 5}},
+			sessionSeconds: 1800,
+			bodyWeightKg:   72,
+			expected:       180,
+		},
+		{
+			name:           "No work done",
+			blocks:         []BlockEnergy{{Seconds: 600}},
+			sessionSeconds: 1800,
+			bodyWeightKg:   70,
+			expected:       0,
+		},
+		{
+			name:           "Timed blocks longer than the session",
+			blocks:         []BlockEnergy{{Seconds: 3600, MET: 5}},
+			sessionSeconds: 1800,
+			bodyWeightKg:   70,
+			expected:       350,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := EstimateSessionCalories(tt.blocks, tt.sessionSeconds, tt.bodyWeightKg)
+			if result != tt.expected {
+				t.Errorf("EstimateSessionCalories() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// ExerciseMatchThreshold is the similarity from which an imported exercise name is matched to a
+// catalogue exercise without the user confirming it. High enough that an extra word
+// ("Incline Bench Press" vs "Bench Press") needs confirming, low enough to allow a typo.
+const ExerciseMatchThreshold = 0.9
+
+// exerciseNameAbbreviations expands the abbreviations other apps use in exercise names
+var exerciseNameAbbreviations = map[string]string{
+	"bb":    "barbell",
+	"db":    "dumbbell",
+	"dbs":   "dumbbell",
+	"kb":    "kettlebell",
+	"ohp":   "overhead press",
+	"rdl":   "romanian deadlift",
+	"bw":    "bodyweight",
+	"incl":  "incline",
+	"decl":  "decline",
+	"ext":   "extension",
+	"extn":  "extension",
+	"machn": "machine",
+}
+
+// exerciseNameStopWords are left out when comparing exercise names
+var exerciseNameStopWords = map[string]bool{
+	"the":  true,
+	"a":    true,
+	"with": true,
+	"on":   true,
+	"of":   true,
+	"and":  true,
+}
+
+// ExerciseCandidate is a catalogue exercise with every name it can be matched by
+// (name, slug and translated names)
+type ExerciseCandidate struct {
+	ID    uuid.UUID
+	Name  string
+	Names []string
+}
+
+// ExerciseMatch is a catalogue exercise matched to an imported exercise name
+type ExerciseMatch struct {
+	ExerciseID   uuid.UUID
+	ExerciseName string
+	Score        float64 // 0..1
+}
+
+// MatchExerciseName returns the best matching catalogue exercises for an imported exercise name,
+// best first, with at most limit results. Exercises with no word in common are left out.
+func MatchExerciseName(name string, candidates []ExerciseCandidate, limit int) []ExerciseMatch {
+	tokens := exerciseNameTokens(name)
+
+	var matches []ExerciseMatch
+	for _, candidate := range candidates {
+		best := 0.0
+		for _, candidateName := range candidate.Names {
+			if score := exerciseTokenSimilarity(tokens, exerciseNameTokens(candidateName)); score > best {
+				best = score
+			}
+		}
+		if best > 0 {
+			matches = append(matches, ExerciseMatch{
+				ExerciseID:   candidate.ID,
+				ExerciseName: candidate.Name,
+				Score:        roundToDecimal(best, 2),
+			})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ExerciseName < matches[j].ExerciseName
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// ExerciseNameSimilarity scores how alike two exercise names are, from 0 to 1. Word order,
+// case, punctuation, plurals and common abbreviations are ignored, and small typos are tolerated.
+func ExerciseNameSimilarity(a string, b string) float64 {
+	return exerciseTokenSimilarity(exerciseNameTokens(a), exerciseNameTokens(b))
+}
+
+// exerciseTokenSimilarity scores two tokenized names: the share of words in common (Dice
+// coefficient), where words count as shared if they differ by at most one typo
+func exerciseTokenSimilarity(a []string, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	used := make([]bool, len(b))
+	shared := 0.0
+	for _, tokenA := range a {
+		bestIndex, bestScore := -1, 0.0
+		for j, tokenB := range b {
+			if used[j] {
+				continue
+			}
+			if score := tokenSimilarity(tokenA, tokenB); score > bestScore {
+				bestIndex, bestScore = j, score
+			}
+		}
+		if bestIndex >= 0 {
+			used[bestIndex] = true
+			shared += bestScore
+		}
+	}
+
+	return 2 * shared / float64(len(a)+len(b))
+}
+
+// tokenSimilarity scores two words: 1 if equal, 0.8 if one typo apart (for words long enough for
+// that to be meaningful), 0 otherwise
+func tokenSimilarity(a string, b string) float64 {
+	if a == b {
+		return 1
+	}
+	if len(a) >= 5 && len(b) >= 5 && levenshteinDistance(a, b) == 1 {
+		return 0.8
+	}
+	return 0
+}
+
+// exerciseNameTokens lowercases a name, splits it into words, expands abbreviations, drops stop
+// words and singularizes plurals, and sorts the words
+func exerciseNameTokens(name string) []string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var tokens []string
+	for _, word := range words {
+		if expanded, ok := exerciseNameAbbreviations[word]; ok {
+			tokens = append(tokens, strings.Fields(expanded)...)
+			continue
+		}
+		if exerciseNameStopWords[word] {
+			continue
+		}
+		tokens = append(tokens, singularize(word))
+	}
+
+	sort.Strings(tokens)
+	return tokens
+}
+
+// singularize strips the plural ending of an English word ("curls", "raises", "flies")
+func singularize(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us"):
+		return word[:len(word)-1]
+	default:
+		return word
+	}
+}
+
+// levenshteinDistance returns the number of single character edits between two strings
+func levenshteinDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
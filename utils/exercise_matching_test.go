@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestExerciseNameSimilarity(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      string
+		autoMatch bool
+	}{
+		{name: "Equipment in brackets", a: "Bench Press (Barbell)", b: "Barbell Bench Press", autoMatch: true},
+		{name: "Case and punctuation", a: "pull-up", b: "Pull Up", autoMatch: true},
+		{name: "Plurals", a: "Bicep Curls (Dumbbell)", b: "Dumbbell Bicep Curl", autoMatch: true},
+		{name: "Abbreviations", a: "DB Lateral Raise", b: "Dumbbell Lateral Raises", autoMatch: true},
+		{name: "Typo", a: "Barbel Squat", b: "Barbell Squat", autoMatch: true},
+		{name: "Extra word needs confirming", a: "Incline Bench Press (Barbell)", b: "Barbell Bench Press", autoMatch: false},
+		{name: "Different equipment", a: "Bench Press (Dumbbell)", b: "Barbell Bench Press", autoMatch: false},
+		{name: "Unrelated", a: "Deadlift", b: "Plank", autoMatch: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := ExerciseNameSimilarity(tt.a, tt.b)
+			if got := score >= ExerciseMatchThreshold; got != tt.autoMatch {
+				t.Errorf("ExerciseNameSimilarity(%q, %q) = %v, auto match %v, want %v", tt.a, tt.b, score, got, tt.autoMatch)
+			}
+		})
+	}
+}
+
+func TestMatchExerciseName(t *testing.T) {
+	squat := ExerciseCandidate{ID: uuid.New(), Name: "Back Squat", Names: []string{"Back Squat", "back squat"}}
+	frontSquat := ExerciseCandidate{ID: uuid.New(), Name: "Front Squat", Names: []string{"Front Squat"}}
+	bench := ExerciseCandidate{ID: uuid.New(), Name: "Bench Press", Names: []string{"Bench Press", "Press de banca"}}
+	candidates := []ExerciseCandidate{frontSquat, bench, squat}
+
+	matches := MatchExerciseName("Squat (Back)", candidates, 5)
+	if len(matches) != 2 {
+		t.Fatalf("got %d matches, want 2 (bench press shares no word)", len(matches))
+	}
+	if matches[0].ExerciseID != squat.ID || matches[0].Score != 1 {
+		t.Errorf("best match = %+v, want Back Squat with score 1", matches[0])
+	}
+	if matches[1].ExerciseID != frontSquat.ID {
+		t.Errorf("second match = %+v, want Front Squat", matches[1])
+	}
+
+	// Translated names are matched too
+	matches = MatchExerciseName("press de banca", candidates, 1)
+	if len(matches) != 1 || matches[0].ExerciseID != bench.ID || matches[0].Score != 1 {
+		t.Errorf("translated match = %+v, want Bench Press with score 1", matches)
+	}
+
+	if matches := MatchExerciseName("Burpee", candidates, 3); len(matches) != 0 {
+		t.Errorf("got %+v, want no matches", matches)
+	}
+}
+
+func TestLevenshteinDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"squat", "squat", 0},
+		{"squat", "squats", 1},
+		{"barbel", "barbell", 1},
+		{"curl", "crul", 2},
+		{"", "row", 3},
+	}
+
+	for _, tt := range tests {
+		if got := levenshteinDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshteinDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package utils
+
+// One-rep max estimation formulas
+const (
+	OneRepMaxFormulaEpley   = "epley"
+	OneRepMaxFormulaBrzycki = "brzycki"
+
+	// MaxRepsForOneRepMaxEstimate is the highest rep count used for 1RM estimation.
+	// Both formulas lose accuracy quickly beyond this range.
+	MaxRepsForOneRepMaxEstimate = 12
+)
+
+// ValidOneRepMaxFormulas defines acceptable 1RM formula values
+var ValidOneRepMaxFormulas = map[string]bool{
+	OneRepMaxFormulaEpley:   true,
+	OneRepMaxFormulaBrzycki: true,
+}
+
+// OneRepMaxFormulas lists all supported formulas in a stable order
+var OneRepMaxFormulas = []string{OneRepMaxFormulaEpley, OneRepMaxFormulaBrzycki}
+
+// NormalizeOneRepMaxFormula returns a supported formula name
+// Returns "epley" as default if empty or invalid
+func NormalizeOneRepMaxFormula(formula string) string {
+	if ValidOneRepMaxFormulas[formula] {
+		return formula
+	}
+	return OneRepMaxFormulaEpley
+}
+
+// EstimateOneRepMax estimates the one-rep max for a set using the given formula
+// Epley:   weight * (1 + reps/30)
+// Brzycki: weight * 36 / (37 - reps)
+// A single rep returns the lifted weight. Returns 0 if reps are outside the
+// supported range (1 to MaxRepsForOneRepMaxEstimate) or weight is not positive.
+func EstimateOneRepMax(weight float64, reps int, formula string) float64 {
+	if weight <= 0 || reps < 1 || reps > MaxRepsForOneRepMaxEstimate {
+		return 0
+	}
+
+	if reps == 1 {
+		return roundToDecimal(weight, 2)
+	}
+
+	switch NormalizeOneRepMaxFormula(formula) {
+	case OneRepMaxFormulaBrzycki:
+		return roundToDecimal(weight*36/float64(37-reps), 2)
+	default:
+		return roundToDecimal(weight*(1+float64(reps)/30), 2)
+	}
+}
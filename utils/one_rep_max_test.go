@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimateOneRepMax(t *testing.T) {
+	tests := []struct {
+		name     string
+		weight   float64
+		reps     int
+		formula  string
+		expected float64
+	}{
+		{"Epley single rep", 100, 1, OneRepMaxFormulaEpley, 100},
+		{"Brzycki single rep", 100, 1, OneRepMaxFormulaBrzycki, 100},
+		{"Epley 5 reps", 100, 5, OneRepMaxFormulaEpley, 116.67},
+		{"Brzycki 5 reps", 100, 5, OneRepMaxFormulaBrzycki, 112.5},
+		{"Epley 10 reps", 80, 10, OneRepMaxFormulaEpley, 106.67},
+		{"Brzycki 10 reps", 80, 10, OneRepMaxFormulaBrzycki, 106.67},
+		{"Unknown formula defaults to Epley", 100, 5, "unknown", 116.67},
+		{"Zero reps", 100, 0, OneRepMaxFormulaEpley, 0},
+		{"Too many reps", 100, MaxRepsForOneRepMaxEstimate + 1, OneRepMaxFormulaEpley, 0},
+		{"Zero weight", 0, 5, OneRepMaxFormulaEpley, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := EstimateOneRepMax(tt.weight, tt.reps, tt.formula)
+			if math.Abs(result-tt.expected) > 0.01 {
+				t.Errorf("EstimateOneRepMax(%v, %v, %q) = %v, want %v", tt.weight, tt.reps, tt.formula, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeOneRepMaxFormula(t *testing.T) {
+	tests := []struct {
+		name     string
+		formula  string
+		expected string
+	}{
+		{"Epley", "epley", "epley"},
+		{"Brzycki", "brzycki", "brzycki"},
+		{"Empty defaults to Epley", "", "epley"},
+		{"Invalid defaults to Epley", "lombardi", "epley"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NormalizeOneRepMaxFormula(tt.formula)
+			if result != tt.expected {
+				t.Errorf("NormalizeOneRepMaxFormula(%q) = %q, want %q", tt.formula, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package utils
+
+import "math"
+
+// RoundToIncrement rounds a load to the nearest multiple of increment (e.g. the smallest plate pair)
+// Loads are rounded to two decimals when increment is not positive
+func RoundToIncrement(value float64, increment float64) float64 {
+	if increment <= 0 {
+		return roundToDecimal(value, 2)
+	}
+	return roundToDecimal(math.Round(value/increment)*increment, 2)
+}
+
+// LinearProgressionLoad returns the next load for linear progression:
+// the last load plus the increment if every target rep was hit, otherwise the last load again
+func LinearProgressionLoad(lastWeightKg float64, hitTarget bool, incrementKg float64) float64 {
+	if hitTarget {
+		return roundToDecimal(lastWeightKg+incrementKg, 2)
+	}
+	return roundToDecimal(lastWeightKg, 2)
+}
+
+// DoubleProgressionTarget returns the next load and reps for double progression.
+// Once the worst set at the last load reaches maxReps, the load increases and reps reset to minReps;
+// otherwise the load stays and the target is one more rep, kept within [minReps, maxReps].
+func DoubleProgressionTarget(lastWeightKg float64, lastReps int, minReps int, maxReps int, incrementKg float64) (float64, int) {
+	if lastReps >= maxReps {
+		return roundToDecimal(lastWeightKg+incrementKg, 2), minReps
+	}
+
+	reps := lastReps + 1
+	if reps < minReps {
+		reps = minReps
+	}
+	return roundToDecimal(lastWeightKg, 2), reps
+}
+
+// RPETargetLoad returns the load expected to produce targetReps at targetRPE, based on a set of
+// lastReps at lastRPE. RPE is treated as reps in reserve (RPE 8 = 2 reps left) and loads are
+// related through the Epley formula. Returns 0 if any input is out of range.
+func RPETargetLoad(lastWeightKg float64, lastReps int, lastRPE int, targetReps int, targetRPE int) float64 {
+	if lastWeightKg <= 0 || lastReps < 1 || targetReps < 1 ||
+		lastRPE < 1 || lastRPE > 10 || targetRPE < 1 || targetRPE > 10 {
+		return 0
+	}
+
+	lastRepsToFailure := float64(lastReps + 10 - lastRPE)
+	targetRepsToFailure := float64(targetReps + 10 - targetRPE)
+
+	oneRepMax := lastWeightKg * (1 + lastRepsToFailure/30)
+	return roundToDecimal(oneRepMax/(1+targetRepsToFailure/30), 2)
+}
+
+// PercentOfOneRepMax returns percent (0-100) of the given one-rep max
+func PercentOfOneRepMax(oneRepMaxKg float64, percent float64) float64 {
+	return roundToDecimal(oneRepMaxKg*percent/100, 2)
+}
@@ -0,0 +1,106 @@
+package utils
+
+import "testing"
+
+func TestRoundToIncrement(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     float64
+		increment float64
+		expected  float64
+	}{
+		{"Round down to 2.5", 101.2, 2.5, 100},
+		{"Round up to 2.5", 101.3, 2.5, 102.5},
+		{"Exact multiple", 60, 5, 60},
+		{"No increment", 61.237, 0, 61.24},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RoundToIncrement(tt.value, tt.increment)
+			if result != tt.expected {
+				t.Errorf("RoundToIncrement(%v, %v) = %v, want %v", tt.value, tt.increment, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLinearProgressionLoad(t *testing.T) {
+	tests := []struct {
+		name      string
+		last      float64
+		hitTarget bool
+		increment float64
+		expected  float64
+	}{
+		{"Target hit adds increment", 100, true, 2.5, 102.5},
+		{"Target missed repeats load", 100, false, 2.5, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := LinearProgressionLoad(tt.last, tt.hitTarget, tt.increment)
+			if result != tt.expected {
+				t.Errorf("LinearProgressionLoad(%v, %v, %v) = %v, want %v", tt.last, tt.hitTarget, tt.increment, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDoubleProgressionTarget(t *testing.T) {
+	tests := []struct {
+		name           string
+		lastWeight     float64
+		lastReps       int
+		expectedWeight float64
+		expectedReps   int
+	}{
+		{"Below ceiling adds a rep", 40, 9, 40, 10},
+		{"At ceiling adds load and resets reps", 40, 12, 42.5, 8},
+		{"Below floor targets floor", 40, 5, 40, 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weight, reps := DoubleProgressionTarget(tt.lastWeight, tt.lastReps, 8, 12, 2.5)
+			if weight != tt.expectedWeight || reps != tt.expectedReps {
+				t.Errorf("DoubleProgressionTarget(%v, %v) = (%v, %v), want (%v, %v)",
+					tt.lastWeight, tt.lastReps, weight, reps, tt.expectedWeight, tt.expectedReps)
+			}
+		})
+	}
+}
+
+func TestRPETargetLoad(t *testing.T) {
+	tests := []struct {
+		name       string
+		lastWeight float64
+		lastReps   int
+		lastRPE    int
+		targetReps int
+		targetRPE  int
+		expected   float64
+	}{
+		{"Same reps and RPE keeps load", 100, 5, 8, 5, 8, 100},
+		// 5 @ RPE 7 → 8 reps to failure; 5 @ RPE 8 → 7 reps to failure
+		{"Too easy increases load", 100, 5, 7, 5, 8, 102.7},
+		{"Too hard decreases load", 100, 5, 9, 5, 8, 97.3},
+		{"Invalid RPE", 100, 5, 11, 5, 8, 0},
+		{"No weight", 0, 5, 8, 5, 8, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RPETargetLoad(tt.lastWeight, tt.lastReps, tt.lastRPE, tt.targetReps, tt.targetRPE)
+			if result != tt.expected {
+				t.Errorf("RPETargetLoad() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPercentOfOneRepMax(t *testing.T) {
+	if result := PercentOfOneRepMax(140, 75); result != 105 {
+		t.Errorf("PercentOfOneRepMax(140, 75) = %v, want 105", result)
+	}
+}
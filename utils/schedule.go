@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"sort"
+	"time"
+)
+
+// Schedule modes for plan enrollments
+const (
+	ScheduleModeRolling  = "rolling"
+	ScheduleModeCalendar = "calendar"
+)
+
+// RollingDayOffset returns the number of days after the anchor on which the n-th
+// workout (0-based) falls when daysPerWeek workouts are spread evenly over each week
+func RollingDayOffset(n int, daysPerWeek int) int {
+	if daysPerWeek < 1 {
+		daysPerWeek = 1
+	}
+	if daysPerWeek > 7 {
+		daysPerWeek = 7
+	}
+	week := n / daysPerWeek
+	day := n % daysPerWeek
+	return week*7 + day*7/daysPerWeek
+}
+
+// RollingScheduleDates returns count workout dates starting at anchor,
+// spacing daysPerWeek workouts evenly across each 7-day window
+func RollingScheduleDates(anchor time.Time, daysPerWeek int, count int) []time.Time {
+	anchor = StartOfDay(anchor)
+	dates := make([]time.Time, 0, count)
+	for n := 0; n < count; n++ {
+		dates = append(dates, anchor.AddDate(0, 0, RollingDayOffset(n, daysPerWeek)))
+	}
+	return dates
+}
+
+// CalendarScheduleDates returns the first count dates on or after start whose weekday
+// is one of the given weekdays (0=Monday..6=Sunday). Returns nil if no valid weekday is given.
+func CalendarScheduleDates(start time.Time, weekdays []int32, count int) []time.Time {
+	seen := make(map[int]bool)
+	days := make([]int, 0, len(weekdays))
+	for _, weekday := range weekdays {
+		day := int(weekday)
+		if day < 0 || day > 6 || seen[day] {
+			continue
+		}
+		seen[day] = true
+		days = append(days, day)
+	}
+	if len(days) == 0 {
+		return nil
+	}
+	sort.Ints(days)
+
+	start = StartOfDay(start)
+	weekStart := StartOfISOWeek(start)
+	dates := make([]time.Time, 0, count)
+	for week := 0; len(dates) < count; week++ {
+		for _, day := range days {
+			date := weekStart.AddDate(0, 0, week*7+day)
+			if date.Before(start) {
+				continue
+			}
+			dates = append(dates, date)
+			if len(dates) == count {
+				break
+			}
+		}
+	}
+	return dates
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRollingScheduleDates(t *testing.T) {
+	// Wednesday, 2025-01-15
+	anchor := time.Date(2025, 1, 15, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		daysPerWeek int
+		count       int
+		expected    []string
+	}{
+		{"Three days per week", 3, 6, []string{"2025-01-15", "2025-01-17", "2025-01-19", "2025-01-22", "2025-01-24", "2025-01-26"}},
+		{"Every day", 7, 3, []string{"2025-01-15", "2025-01-16", "2025-01-17"}},
+		{"Once per week", 1, 3, []string{"2025-01-15", "2025-01-22", "2025-01-29"}},
+		{"Four days per week", 4, 5, []string{"2025-01-15", "2025-01-16", "2025-01-18", "2025-01-20", "2025-01-22"}},
+		{"Zero count", 3, 0, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RollingScheduleDates(anchor, tt.daysPerWeek, tt.count)
+			assertScheduleDates(t, result, tt.expected)
+		})
+	}
+}
+
+func TestCalendarScheduleDates(t *testing.T) {
+	// Wednesday, 2025-01-15
+	start := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		weekdays []int32
+		count    int
+		expected []string
+	}{
+		{"Mon/Wed/Fri starting Wednesday", []int32{0, 2, 4}, 4, []string{"2025-01-15", "2025-01-17", "2025-01-20", "2025-01-22"}},
+		{"Unordered weekdays", []int32{4, 0}, 3, []string{"2025-01-17", "2025-01-20", "2025-01-24"}},
+		{"Sunday only", []int32{6}, 2, []string{"2025-01-19", "2025-01-26"}},
+		{"Duplicates ignored", []int32{1, 1}, 2, []string{"2025-01-21", "2025-01-28"}},
+		{"Invalid weekdays", []int32{7, -1}, 3, []string{}},
+		{"No weekdays", nil, 3, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CalendarScheduleDates(start, tt.weekdays, tt.count)
+			assertScheduleDates(t, result, tt.expected)
+		})
+	}
+}
+
+func assertScheduleDates(t *testing.T, result []time.Time, expected []string) {
+	t.Helper()
+	if len(result) != len(expected) {
+		t.Fatalf("got %d dates, want %d", len(result), len(expected))
+	}
+	for i, date := range result {
+		if date.Format(DateFormat) != expected[i] {
+			t.Errorf("date %d = %s, want %s", i, date.Format(DateFormat), expected[i])
+		}
+	}
+}
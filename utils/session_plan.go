@@ -0,0 +1,362 @@
+package utils
+
+import (
+	"math"
+
+	"lamari-fit-api/models"
+)
+
+// Session structure defaults for prescription groups that don't set their own timing
+const (
+	DefaultEMOMIntervalSeconds = 60  // every minute on the minute
+	DefaultHIITWorkSeconds     = 20  // Tabata-style work interval
+	DefaultHIITRestSeconds     = 10  // Tabata-style rest interval
+	DefaultAMRAPTimeCapSeconds = 600 // 10 minute cap
+
+	DropSetDrops         = 2   // drops after each working set of a drop set
+	DropSetLoadFactor    = 0.8 // each drop is 20% lighter than the load before it
+	RestPauseMiniSets    = 2   // mini-sets after each working set of a rest-pause set
+	PyramidLoadStep      = 0.1 // each pyramid set drops 10% of the top load
+	PyramidMinLoadFactor = 0.5 // pyramid sets never drop below half the top load
+	PyramidRepStep       = 2   // each pyramid set adds two reps
+	SubSetLoadIncrement  = 0.5 // derived loads are rounded to the nearest 0.5 kg
+	RestPauseRestSeconds = 15  // pause before each rest-pause mini-set
+)
+
+// PlannedExercise is one exercise of a prescription group, with the targets its sets start from
+type PlannedExercise struct {
+	Sets        *int
+	Reps        *int
+	HoldSeconds *int
+	WeightKg    *float64
+}
+
+// PlannedGroup is a prescription group to generate session sets for
+type PlannedGroup struct {
+	Type            models.PrescriptionType
+	Rounds          *int // group_rounds
+	RestBetweenSets *int // seconds
+	IntervalSeconds *int // EMOM interval, HIIT work interval or AMRAP time cap
+	Exercises       []PlannedExercise
+}
+
+// PlannedSet is a session set generated for a prescription group
+type PlannedSet struct {
+	ExerciseIndex        int // index into PlannedGroup.Exercises
+	SetNumber            int // per-exercise set number
+	Round                int
+	SequenceOrder        int // order in which the set is performed within the block
+	SubSetNumber         int // 0 for the main set, 1.. for drops and mini-sets
+	WeightKg             *float64
+	Reps                 *int
+	DurationSeconds      *int
+	IntervalStartSeconds *int
+	IntervalSeconds      *int
+}
+
+// PlanSessionSets generates the sets of a session block from its prescription group:
+//   - superset, circuit, giant_set: one set per exercise per round, interleaved by round
+//   - drop_set: each working set is followed by lighter drops (reps to failure)
+//   - rest_pause: each working set is followed by mini-sets at the same load
+//   - pyramid: each set is lighter and higher-rep than the one before it
+//   - emom, hiit: rounds of back-to-back time-boxed intervals
+//   - amrap: one round of the exercises, all sharing the time cap
+//   - straight, warmup, cooldown: each exercise's sets in turn
+func PlanSessionSets(group PlannedGroup) []PlannedSet {
+	switch group.Type {
+	case models.PrescriptionTypeSuperset, models.PrescriptionTypeCircuit, models.PrescriptionTypeGiantSet:
+		rounds, everyRound := groupRoundCount(group)
+		return planRounds(group, rounds, everyRound)
+	case models.PrescriptionTypeDropSet:
+		return planSubSets(group, DropSetDrops, DropSetLoadFactor)
+	case models.PrescriptionTypeRestPause:
+		return planSubSets(group, RestPauseMiniSets, 1)
+	case models.PrescriptionTypePyramid:
+		return planPyramid(group)
+	case models.PrescriptionTypeEMOM:
+		return planEMOM(group)
+	case models.PrescriptionTypeHIIT:
+		return planHIIT(group)
+	case models.PrescriptionTypeAMRAP:
+		return planAMRAP(group)
+	default:
+		return planStraight(group)
+	}
+}
+
+// plannedSetCount returns the number of prescribed sets of an exercise (at least 1)
+func plannedSetCount(exercise PlannedExercise) int {
+	if exercise.Sets != nil && *exercise.Sets > 0 {
+		return *exercise.Sets
+	}
+	return 1
+}
+
+// groupRoundCount returns the number of rounds of a grouped block. group_rounds above 1 makes
+// every exercise appear in every round; otherwise each exercise keeps its own set count and
+// drops out once its sets are done.
+func groupRoundCount(group PlannedGroup) (int, bool) {
+	if group.Rounds != nil && *group.Rounds > 1 {
+		return *group.Rounds, true
+	}
+
+	rounds := 1
+	for _, exercise := range group.Exercises {
+		if n := plannedSetCount(exercise); n > rounds {
+			rounds = n
+		}
+	}
+	return rounds, false
+}
+
+// targetSet returns a main set pre-filled with the exercise's targets
+func targetSet(index int, exercise PlannedExercise) PlannedSet {
+	return PlannedSet{
+		ExerciseIndex:   index,
+		WeightKg:        exercise.WeightKg,
+		Reps:            exercise.Reps,
+		DurationSeconds: exercise.HoldSeconds,
+	}
+}
+
+// planStraight performs each exercise's sets in turn
+func planStraight(group PlannedGroup) []PlannedSet {
+	var sets []PlannedSet
+	for i, exercise := range group.Exercises {
+		for n := 1; n <= plannedSetCount(exercise); n++ {
+			set := targetSet(i, exercise)
+			set.SetNumber = n
+			set.Round = n
+			set.SequenceOrder = len(sets) + 1
+			sets = append(sets, set)
+		}
+	}
+	return sets
+}
+
+// planRounds interleaves the exercises' sets by round
+func planRounds(group PlannedGroup, rounds int, everyRound bool) []PlannedSet {
+	var sets []PlannedSet
+	setNumbers := make([]int, len(group.Exercises))
+	for round := 1; round <= rounds; round++ {
+		for i, exercise := range group.Exercises {
+			if !everyRound && round > plannedSetCount(exercise) {
+				continue
+			}
+			setNumbers[i]++
+			set := targetSet(i, exercise)
+			set.SetNumber = setNumbers[i]
+			set.Round = round
+			set.SequenceOrder = len(sets) + 1
+			sets = append(sets, set)
+		}
+	}
+	return sets
+}
+
+// planSubSets follows each working set with subSets sets, each at loadFactor times the load
+// before it and with reps left open
+func planSubSets(group PlannedGroup, subSets int, loadFactor float64) []PlannedSet {
+	var sets []PlannedSet
+	for i, exercise := range group.Exercises {
+		setNumber := 0
+		for round := 1; round <= plannedSetCount(exercise); round++ {
+			set := targetSet(i, exercise)
+			for sub := 0; sub <= subSets; sub++ {
+				if sub > 0 {
+					set = PlannedSet{ExerciseIndex: i, WeightKg: scaleLoad(set.WeightKg, loadFactor)}
+				}
+				setNumber++
+				set.SetNumber = setNumber
+				set.Round = round
+				set.SubSetNumber = sub
+				set.SequenceOrder = len(sets) + 1
+				sets = append(sets, set)
+			}
+		}
+	}
+	return sets
+}
+
+// planPyramid lowers the load and raises the reps with every set of each exercise
+func planPyramid(group PlannedGroup) []PlannedSet {
+	var sets []PlannedSet
+	for i, exercise := range group.Exercises {
+		for n := 1; n <= plannedSetCount(exercise); n++ {
+			set := targetSet(i, exercise)
+			set.SetNumber = n
+			set.Round = n
+			set.SequenceOrder = len(sets) + 1
+			if n > 1 {
+				step := float64(n - 1)
+				set.WeightKg = scaleLoad(exercise.WeightKg, math.Max(1-PyramidLoadStep*step, PyramidMinLoadFactor))
+				if exercise.Reps != nil {
+					reps := *exercise.Reps + PyramidRepStep*(n-1)
+					set.Reps = &reps
+				}
+			}
+			sets = append(sets, set)
+		}
+	}
+	return sets
+}
+
+// planEMOM gives every set of every round its own fixed interval, back to back
+func planEMOM(group PlannedGroup) []PlannedSet {
+	interval := positiveOr(group.IntervalSeconds, DefaultEMOMIntervalSeconds)
+
+	rounds, everyRound := groupRoundCount(group)
+	sets := planRounds(group, rounds, everyRound)
+	for i := range sets {
+		start := i * interval
+		sets[i].IntervalStartSeconds = &start
+		sets[i].IntervalSeconds = &interval
+	}
+	return sets
+}
+
+// planHIIT alternates work intervals with rests. The work interval is the exercise's hold time,
+// falling back to the group interval; the rest is the group's rest between sets.
+func planHIIT(group PlannedGroup) []PlannedSet {
+	groupWork := positiveOr(group.IntervalSeconds, DefaultHIITWorkSeconds)
+	rest := positiveOr(group.RestBetweenSets, DefaultHIITRestSeconds)
+
+	rounds, everyRound := groupRoundCount(group)
+	sets := planRounds(group, rounds, everyRound)
+	start := 0
+	for i := range sets {
+		work := positiveOr(group.Exercises[sets[i].ExerciseIndex].HoldSeconds, groupWork)
+		intervalStart := start
+		sets[i].IntervalStartSeconds = &intervalStart
+		sets[i].IntervalSeconds = &work
+		sets[i].DurationSeconds = &work
+		start += work + rest
+	}
+	return sets
+}
+
+// planAMRAP lays out a single round; further rounds are added while the time cap runs
+func planAMRAP(group PlannedGroup) []PlannedSet {
+	timeCap := positiveOr(group.IntervalSeconds, DefaultAMRAPTimeCapSeconds)
+
+	sets := planRounds(group, 1, true)
+	for i := range sets {
+		start := 0
+		sets[i].IntervalStartSeconds = &start
+		sets[i].IntervalSeconds = &timeCap
+	}
+	return sets
+}
+
+// scaleLoad returns load times factor rounded to SubSetLoadIncrement, or nil for no load
+func scaleLoad(loadKg *float64, factor float64) *float64 {
+	if loadKg == nil {
+		return nil
+	}
+	scaled := RoundToIncrement(*loadKg*factor, SubSetLoadIncrement)
+	return &scaled
+}
+
+// positiveOr returns *value if set and positive, otherwise fallback
+func positiveOr(value *int, fallback int) int {
+	if value != nil && *value > 0 {
+		return *value
+	}
+	return fallback
+}
+
+// IsTimeBoxedType reports whether sets of a prescription type run on a clock rather than on rest
+func IsTimeBoxedType(t models.PrescriptionType) bool {
+	return t == models.PrescriptionTypeEMOM || t == models.PrescriptionTypeHIIT || t == models.PrescriptionTypeAMRAP
+}
+
+// IsInterleavedType reports whether a prescription type's exercises alternate round by round
+func IsInterleavedType(t models.PrescriptionType) bool {
+	return t == models.PrescriptionTypeSuperset || t == models.PrescriptionTypeCircuit || t == models.PrescriptionTypeGiantSet
+}
+
+// RestBeforeSet returns the rest a group's prescription calls for before a set, given whether the
+// set continues the round of the set before it. Returns nil if no rest is prescribed, including
+// for time-boxed types whose sets start on the clock.
+//   - drops follow immediately; rest-pause mini-sets follow a short pause
+//   - superset, circuit and giant_set exercises follow each other immediately within a round
+//   - otherwise the group's rest between sets applies
+func RestBeforeSet(groupType models.PrescriptionType, restBetweenSets *int, sameRound bool, subSetNumber int) *int {
+	if IsTimeBoxedType(groupType) {
+		return nil
+	}
+
+	rest := 0
+	switch {
+	case subSetNumber > 0 && groupType == models.PrescriptionTypeRestPause:
+		rest = RestPauseRestSeconds
+	case subSetNumber > 0:
+		rest = 0
+	case sameRound && IsInterleavedType(groupType):
+		rest = 0
+	case restBetweenSets == nil:
+		return nil
+	default:
+		rest = *restBetweenSets
+	}
+	return &rest
+}
+
+// WorkingSetIndex returns the index of the set a prescription's targets are taken from when a
+// session is saved as a workout: the heaviest completed main set, with more reps and then a
+// longer duration breaking ties, and the earlier set winning any remaining tie. Drops and
+// mini-sets are left out as they are generated from the main sets. Returns -1 if no main set
+// was completed.
+func WorkingSetIndex(sets []models.SessionSet) int {
+	best := -1
+	for i, set := range sets {
+		if !set.Completed || set.SubSetNumber > 0 {
+			continue
+		}
+		if best == -1 || heavierSet(set, sets[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// heavierSet reports whether set a outranks set b by weight, then reps, then duration
+func heavierSet(a models.SessionSet, b models.SessionSet) bool {
+	if weightA, weightB := floatOrZero(a.ActualWeightKg), floatOrZero(b.ActualWeightKg); weightA != weightB {
+		return weightA > weightB
+	}
+	if repsA, repsB := intOrZero(a.ActualReps), intOrZero(b.ActualReps); repsA != repsB {
+		return repsA > repsB
+	}
+	return intOrZero(a.ActualDurationSeconds) > intOrZero(b.ActualDurationSeconds)
+}
+
+func floatOrZero(value *float64) float64 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+func intOrZero(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+// PrescribedSetCount returns the number of main sets a prescription calls for in one session.
+// Grouped and interval types with group_rounds above 1 repeat every exercise each round; an AMRAP
+// prescribes a single round, with further rounds as a bonus.
+func PrescribedSetCount(prescription models.WorkoutPrescription) int {
+	if prescription.Type == models.PrescriptionTypeAMRAP {
+		return 1
+	}
+	repeatsEveryRound := IsInterleavedType(prescription.Type) ||
+		prescription.Type == models.PrescriptionTypeEMOM ||
+		prescription.Type == models.PrescriptionTypeHIIT
+	if repeatsEveryRound && prescription.GroupRounds != nil && *prescription.GroupRounds > 1 {
+		return *prescription.GroupRounds
+	}
+	return plannedSetCount(PlannedExercise{Sets: prescription.Sets})
+}
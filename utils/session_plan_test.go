@@ -0,0 +1,349 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"lamari-fit-api/models"
+)
+
+func intPtr(v int) *int           { return &v }
+func floatPtr(v float64) *float64 { return &v }
+func exercise(sets int, reps int, weight float64) PlannedExercise {
+	return PlannedExercise{Sets: intPtr(sets), Reps: intPtr(reps), WeightKg: floatPtr(weight)}
+}
+
+// describeSets renders planned sets in sequence order as "exercise/set/round/sub" tokens
+func describeSets(sets []PlannedSet) string {
+	parts := make([]string, len(sets))
+	for i, s := range sets {
+		if s.SequenceOrder != i+1 {
+			return fmt.Sprintf("sequence order %d at position %d", s.SequenceOrder, i+1)
+		}
+		parts[i] = fmt.Sprintf("%d/%d/%d/%d", s.ExerciseIndex, s.SetNumber, s.Round, s.SubSetNumber)
+	}
+	return strings.Join(parts, " ")
+}
+
+func describeValues(sets []PlannedSet, value func(PlannedSet) string) string {
+	parts := make([]string, len(sets))
+	for i, s := range sets {
+		parts[i] = value(s)
+	}
+	return strings.Join(parts, " ")
+}
+
+func weightOf(s PlannedSet) string {
+	if s.WeightKg == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%g", *s.WeightKg)
+}
+
+func repsOf(s PlannedSet) string {
+	if s.Reps == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d", *s.Reps)
+}
+
+func intervalOf(s PlannedSet) string {
+	if s.IntervalStartSeconds == nil || s.IntervalSeconds == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d+%d", *s.IntervalStartSeconds, *s.IntervalSeconds)
+}
+
+func TestPlanSessionSetsOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		group    PlannedGroup
+		expected string
+	}{
+		{
+			"Straight sets run exercise by exercise",
+			PlannedGroup{Type: models.PrescriptionTypeStraight, Exercises: []PlannedExercise{exercise(2, 5, 100), exercise(1, 8, 50)}},
+			"0/1/1/0 0/2/2/0 1/1/1/0",
+		},
+		{
+			"Warmup runs like straight sets",
+			PlannedGroup{Type: models.PrescriptionTypeWarmup, Exercises: []PlannedExercise{{}}},
+			"0/1/1/0",
+		},
+		{
+			"Superset interleaves by round",
+			PlannedGroup{Type: models.PrescriptionTypeSuperset, Exercises: []PlannedExercise{exercise(3, 10, 60), exercise(3, 12, 20)}},
+			"0/1/1/0 1/1/1/0 0/2/2/0 1/2/2/0 0/3/3/0 1/3/3/0",
+		},
+		{
+			"Superset exercise with fewer sets drops out",
+			PlannedGroup{Type: models.PrescriptionTypeSuperset, Exercises: []PlannedExercise{exercise(2, 10, 60), exercise(1, 12, 20)}},
+			"0/1/1/0 1/1/1/0 0/2/2/0",
+		},
+		{
+			"Circuit group rounds override set counts",
+			PlannedGroup{Type: models.PrescriptionTypeCircuit, Rounds: intPtr(2), Exercises: []PlannedExercise{exercise(1, 10, 0), exercise(5, 10, 0), exercise(1, 10, 0)}},
+			"0/1/1/0 1/1/1/0 2/1/1/0 0/2/2/0 1/2/2/0 2/2/2/0",
+		},
+		{
+			"Giant set interleaves by round",
+			PlannedGroup{Type: models.PrescriptionTypeGiantSet, Rounds: intPtr(2), Exercises: []PlannedExercise{{}, {}, {}, {}}},
+			"0/1/1/0 1/1/1/0 2/1/1/0 3/1/1/0 0/2/2/0 1/2/2/0 2/2/2/0 3/2/2/0",
+		},
+		{
+			"Drop set follows each working set with drops",
+			PlannedGroup{Type: models.PrescriptionTypeDropSet, Exercises: []PlannedExercise{exercise(2, 10, 50)}},
+			"0/1/1/0 0/2/1/1 0/3/1/2 0/4/2/0 0/5/2/1 0/6/2/2",
+		},
+		{
+			"Rest-pause follows each working set with mini-sets",
+			PlannedGroup{Type: models.PrescriptionTypeRestPause, Exercises: []PlannedExercise{exercise(1, 8, 80)}},
+			"0/1/1/0 0/2/1/1 0/3/1/2",
+		},
+		{
+			"Pyramid runs exercise by exercise",
+			PlannedGroup{Type: models.PrescriptionTypePyramid, Exercises: []PlannedExercise{exercise(3, 6, 100)}},
+			"0/1/1/0 0/2/2/0 0/3/3/0",
+		},
+		{
+			"EMOM interleaves by round",
+			PlannedGroup{Type: models.PrescriptionTypeEMOM, Rounds: intPtr(2), Exercises: []PlannedExercise{exercise(1, 5, 0), exercise(1, 10, 0)}},
+			"0/1/1/0 1/1/1/0 0/2/2/0 1/2/2/0",
+		},
+		{
+			"HIIT interleaves by round",
+			PlannedGroup{Type: models.PrescriptionTypeHIIT, Rounds: intPtr(2), Exercises: []PlannedExercise{{}, {}}},
+			"0/1/1/0 1/1/1/0 0/2/2/0 1/2/2/0",
+		},
+		{
+			"AMRAP lays out a single round",
+			PlannedGroup{Type: models.PrescriptionTypeAMRAP, Rounds: intPtr(5), Exercises: []PlannedExercise{exercise(3, 5, 0), exercise(3, 10, 0)}},
+			"0/1/1/0 1/1/1/0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := describeSets(PlanSessionSets(tt.group))
+			if result != tt.expected {
+				t.Errorf("PlanSessionSets() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPlanSessionSetsLoads(t *testing.T) {
+	tests := []struct {
+		name            string
+		group           PlannedGroup
+		expectedWeights string
+		expectedReps    string
+	}{
+		{
+			"Drops lose 20% each and leave reps open",
+			PlannedGroup{Type: models.PrescriptionTypeDropSet, Exercises: []PlannedExercise{exercise(1, 10, 50)}},
+			"50 40 32", "10 - -",
+		},
+		{
+			"Drops round to 0.5 kg",
+			PlannedGroup{Type: models.PrescriptionTypeDropSet, Exercises: []PlannedExercise{exercise(1, 8, 42.5)}},
+			"42.5 34 27", "8 - -",
+		},
+		{
+			"Bodyweight drop set has no loads",
+			PlannedGroup{Type: models.PrescriptionTypeDropSet, Exercises: []PlannedExercise{{Reps: intPtr(10)}}},
+			"- - -", "10 - -",
+		},
+		{
+			"Rest-pause keeps the load",
+			PlannedGroup{Type: models.PrescriptionTypeRestPause, Exercises: []PlannedExercise{exercise(1, 8, 80)}},
+			"80 80 80", "8 - -",
+		},
+		{
+			"Pyramid lowers load and raises reps",
+			PlannedGroup{Type: models.PrescriptionTypePyramid, Exercises: []PlannedExercise{exercise(3, 6, 100)}},
+			"100 90 80", "6 8 10",
+		},
+		{
+			"Pyramid load stops at half the top load",
+			PlannedGroup{Type: models.PrescriptionTypePyramid, Exercises: []PlannedExercise{exercise(7, 1, 100)}},
+			"100 90 80 70 60 50 50", "1 3 5 7 9 11 13",
+		},
+		{
+			"Superset keeps each exercise's targets",
+			PlannedGroup{Type: models.PrescriptionTypeSuperset, Exercises: []PlannedExercise{exercise(2, 10, 60), exercise(2, 12, 20)}},
+			"60 20 60 20", "10 12 10 12",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sets := PlanSessionSets(tt.group)
+			if weights := describeValues(sets, weightOf); weights != tt.expectedWeights {
+				t.Errorf("weights = %q, want %q", weights, tt.expectedWeights)
+			}
+			if reps := describeValues(sets, repsOf); reps != tt.expectedReps {
+				t.Errorf("reps = %q, want %q", reps, tt.expectedReps)
+			}
+		})
+	}
+}
+
+func TestPlanSessionSetsIntervals(t *testing.T) {
+	tests := []struct {
+		name     string
+		group    PlannedGroup
+		expected string
+	}{
+		{
+			"EMOM defaults to one minute intervals",
+			PlannedGroup{Type: models.PrescriptionTypeEMOM, Rounds: intPtr(2), Exercises: []PlannedExercise{{}, {}}},
+			"0+60 60+60 120+60 180+60",
+		},
+		{
+			"EMOM uses the group interval",
+			PlannedGroup{Type: models.PrescriptionTypeEMOM, IntervalSeconds: intPtr(90), Exercises: []PlannedExercise{{Sets: intPtr(3)}}},
+			"0+90 90+90 180+90",
+		},
+		{
+			"HIIT defaults to 20s work and 10s rest",
+			PlannedGroup{Type: models.PrescriptionTypeHIIT, Rounds: intPtr(3), Exercises: []PlannedExercise{{}}},
+			"0+20 30+20 60+20",
+		},
+		{
+			"HIIT uses hold time and rest between sets",
+			PlannedGroup{
+				Type: models.PrescriptionTypeHIIT, Rounds: intPtr(2), RestBetweenSets: intPtr(15), IntervalSeconds: intPtr(30),
+				Exercises: []PlannedExercise{{HoldSeconds: intPtr(45)}, {}},
+			},
+			"0+45 60+30 105+45 165+30",
+		},
+		{
+			"AMRAP sets share the time cap",
+			PlannedGroup{Type: models.PrescriptionTypeAMRAP, IntervalSeconds: intPtr(720), Exercises: []PlannedExercise{{}, {}}},
+			"0+720 0+720",
+		},
+		{
+			"AMRAP defaults to a 10 minute cap",
+			PlannedGroup{Type: models.PrescriptionTypeAMRAP, Exercises: []PlannedExercise{{}}},
+			"0+600",
+		},
+		{
+			"Straight sets are not time-boxed",
+			PlannedGroup{Type: models.PrescriptionTypeStraight, IntervalSeconds: intPtr(60), Exercises: []PlannedExercise{{Sets: intPtr(2)}}},
+			"- -",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := describeValues(PlanSessionSets(tt.group), intervalOf)
+			if result != tt.expected {
+				t.Errorf("intervals = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPlanSessionSetsHIITDuration(t *testing.T) {
+	sets := PlanSessionSets(PlannedGroup{
+		Type:      models.PrescriptionTypeHIIT,
+		Exercises: []PlannedExercise{{HoldSeconds: intPtr(40)}, {}},
+	})
+	if len(sets) != 2 {
+		t.Fatalf("got %d sets, want 2", len(sets))
+	}
+	if sets[0].DurationSeconds == nil || *sets[0].DurationSeconds != 40 {
+		t.Errorf("first set duration = %v, want 40", sets[0].DurationSeconds)
+	}
+	if sets[1].DurationSeconds == nil || *sets[1].DurationSeconds != DefaultHIITWorkSeconds {
+		t.Errorf("second set duration = %v, want %d", sets[1].DurationSeconds, DefaultHIITWorkSeconds)
+	}
+}
+
+func TestRestBeforeSet(t *testing.T) {
+	tests := []struct {
+		name      string
+		groupType models.PrescriptionType
+		rest      *int
+		sameRound bool
+		subSet    int
+		expected  *int
+	}{
+		{"Straight set rests", models.PrescriptionTypeStraight, intPtr(90), false, 0, intPtr(90)},
+		{"Straight set without prescribed rest", models.PrescriptionTypeStraight, nil, false, 0, nil},
+		{"Superset moves straight to the next exercise", models.PrescriptionTypeSuperset, intPtr(90), true, 0, intPtr(0)},
+		{"Superset rests between rounds", models.PrescriptionTypeSuperset, intPtr(90), false, 0, intPtr(90)},
+		{"Circuit moves straight to the next exercise", models.PrescriptionTypeCircuit, nil, true, 0, intPtr(0)},
+		{"Drop follows immediately", models.PrescriptionTypeDropSet, intPtr(120), false, 1, intPtr(0)},
+		{"Drop set rests before the next working set", models.PrescriptionTypeDropSet, intPtr(120), false, 0, intPtr(120)},
+		{"Rest-pause mini-set pauses briefly", models.PrescriptionTypeRestPause, intPtr(120), false, 2, intPtr(RestPauseRestSeconds)},
+		{"EMOM runs on the clock", models.PrescriptionTypeEMOM, intPtr(30), false, 0, nil},
+		{"HIIT runs on the clock", models.PrescriptionTypeHIIT, intPtr(30), false, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RestBeforeSet(tt.groupType, tt.rest, tt.sameRound, tt.subSet)
+			switch {
+			case result == nil && tt.expected == nil:
+			case result == nil || tt.expected == nil || *result != *tt.expected:
+				t.Errorf("RestBeforeSet() = %v, want %v", describeRest(result), describeRest(tt.expected))
+			}
+		})
+	}
+}
+
+func describeRest(rest *int) string {
+	if rest == nil {
+		return "nil"
+	}
+	return fmt.Sprintf("%d", *rest)
+}
+
+func TestWorkingSetIndex(t *testing.T) {
+	done := func(reps int, weight float64) models.SessionSet {
+		return models.SessionSet{Completed: true, ActualReps: intPtr(reps), ActualWeightKg: floatPtr(weight)}
+	}
+	held := func(seconds int) models.SessionSet {
+		return models.SessionSet{Completed: true, ActualDurationSeconds: intPtr(seconds)}
+	}
+	drop := func(reps int, weight float64) models.SessionSet {
+		set := done(reps, weight)
+		set.SubSetNumber = 1
+		return set
+	}
+	pending := func(reps int, weight float64) models.SessionSet {
+		set := done(reps, weight)
+		set.Completed = false
+		return set
+	}
+
+	tests := []struct {
+		name     string
+		sets     []models.SessionSet
+		expected int
+	}{
+		{"Heaviest set wins", []models.SessionSet{done(8, 60), done(5, 80), done(3, 70)}, 1},
+		{"More reps break a tie on weight", []models.SessionSet{done(5, 80), done(6, 80)}, 1},
+		{"Earlier set wins a full tie", []models.SessionSet{done(5, 80), done(5, 80)}, 0},
+		{"Bodyweight sets use reps", []models.SessionSet{
+			{Completed: true, ActualReps: intPtr(12)},
+			{Completed: true, ActualReps: intPtr(15)},
+		}, 1},
+		{"Holds use duration", []models.SessionSet{held(30), held(45), held(40)}, 1},
+		{"Incomplete sets are ignored", []models.SessionSet{done(5, 80), pending(5, 100)}, 0},
+		{"Drops are ignored", []models.SessionSet{pending(10, 20), drop(8, 16), done(10, 18)}, 2},
+		{"Nothing completed", []models.SessionSet{pending(5, 80)}, -1},
+		{"No sets", nil, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := WorkingSetIndex(tt.sets); result != tt.expected {
+				t.Errorf("WorkingSetIndex() = %d, want %d", result, tt.expected)
+			}
+		})
+	}
+}
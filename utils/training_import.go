@@ -0,0 +1,441 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported training history export formats
+const (
+	TrainingFormatStrong = "strong"
+	TrainingFormatHevy   = "hevy"
+)
+
+var (
+	ErrUnsupportedTrainingFormat = errors.New("unsupported training history format")
+	ErrEmptyTrainingHistory      = errors.New("training history has no logged sets")
+)
+
+// Date layouts used by the exports. Both apps write local wall-clock times without a zone.
+var (
+	strongDateLayouts = []string{"2006-01-02 15:04:05", "2006-01-02 15:04", time.RFC3339}
+	hevyDateLayouts   = []string{"2 Jan 2006, 15:04", "2 January 2006, 15:04", "2006-01-02 15:04:05", time.RFC3339}
+)
+
+// strongDurationPattern matches Strong's workout durations ("1h 5m", "45m", "30s")
+var strongDurationPattern = regexp.MustCompile(`(\d+)\s*([hms])`)
+
+// ImportedSet is a logged set read from a training history export
+type ImportedSet struct {
+	ExerciseName    string
+	Reps            *int
+	WeightValue     *float64
+	WeightUnit      string // kg or lb
+	DistanceValue   *float64
+	DistanceUnit    string // m, km or mi
+	DurationSeconds *int
+	RPE             *float64
+	WasFailure      bool
+	Notes           string
+}
+
+// ImportedWorkout is a workout read from a training history export, with its sets in the order
+// they were logged
+type ImportedWorkout struct {
+	Title           string
+	StartedAt       time.Time
+	DurationSeconds *int
+	Notes           string
+	Sets            []ImportedSet
+}
+
+// ParsedTrainingHistory is the result of parsing a training history export
+type ParsedTrainingHistory struct {
+	Format   string
+	Workouts []ImportedWorkout // Oldest first
+	Skipped  int               // Warm-up and empty rows left out
+}
+
+// ExerciseNames returns the distinct exercise names of the history, in order of first appearance
+func (h *ParsedTrainingHistory) ExerciseNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, workout := range h.Workouts {
+		for _, set := range workout.Sets {
+			if !seen[set.ExerciseName] {
+				seen[set.ExerciseName] = true
+				names = append(names, set.ExerciseName)
+			}
+		}
+	}
+	return names
+}
+
+// trainingCSV gives access to the columns of an export by header name
+type trainingCSV struct {
+	columns map[string]int
+}
+
+// has reports whether the export has any of the given columns
+func (t trainingCSV) has(names ...string) bool {
+	for _, name := range names {
+		if _, ok := t.columns[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// value returns the trimmed value of the first of the given columns present in the export
+func (t trainingCSV) value(row []string, names ...string) string {
+	for _, name := range names {
+		if i, ok := t.columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+	}
+	return ""
+}
+
+// ParseTrainingHistoryCSV parses a Strong or Hevy CSV export. weightUnit and distanceUnit are the
+// units the export was written in, used when its columns don't say (Strong writes weights in the
+// app's unit setting). Times are read in loc.
+func ParseTrainingHistoryCSV(data []byte, weightUnit string, distanceUnit string, loc *time.Location) (*ParsedTrainingHistory, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectCSVDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, ErrEmptyTrainingHistory
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	table := trainingCSV{columns: make(map[string]int, len(header))}
+	for i, name := range header {
+		table.columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	var format string
+	switch {
+	case table.has("exercise name") && table.has("set order"):
+		format = TrainingFormatStrong
+	case table.has("exercise_title") && table.has("set_index"):
+		format = TrainingFormatHevy
+	default:
+		return nil, ErrUnsupportedTrainingFormat
+	}
+
+	history := &ParsedTrainingHistory{Format: format}
+	workoutIndex := make(map[string]int)
+	line := 1
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV on line %d: %w", line, err)
+		}
+
+		var workout ImportedWorkout
+		var set *ImportedSet
+		var key string
+		if format == TrainingFormatStrong {
+			workout, set, err = parseStrongRow(table, row, weightUnit, distanceUnit, loc)
+			key = table.value(row, "date") + "|" + table.value(row, "workout name")
+		} else {
+			workout, set, err = parseHevyRow(table, row, weightUnit, distanceUnit, loc)
+			key = table.value(row, "start_time") + "|" + table.value(row, "title")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if set == nil {
+			history.Skipped++
+			continue
+		}
+
+		i, ok := workoutIndex[key]
+		if !ok {
+			i = len(history.Workouts)
+			workoutIndex[key] = i
+			history.Workouts = append(history.Workouts, workout)
+		}
+		history.Workouts[i].Sets = append(history.Workouts[i].Sets, *set)
+	}
+
+	if len(history.Workouts) == 0 {
+		return nil, ErrEmptyTrainingHistory
+	}
+
+	sort.SliceStable(history.Workouts, func(i, j int) bool {
+		return history.Workouts[i].StartedAt.Before(history.Workouts[j].StartedAt)
+	})
+
+	return history, nil
+}
+
+// parseStrongRow reads a row of a Strong export. The set is nil for warm-up sets, rest timers
+// and rows with nothing logged.
+func parseStrongRow(table trainingCSV, row []string, weightUnit string, distanceUnit string, loc *time.Location) (ImportedWorkout, *ImportedSet, error) {
+	startedAt, err := parseTrainingDate(table.value(row, "date"), strongDateLayouts, loc)
+	if err != nil {
+		return ImportedWorkout{}, nil, err
+	}
+
+	workout := ImportedWorkout{
+		Title:     table.value(row, "workout name"),
+		StartedAt: startedAt,
+		Notes:     table.value(row, "workout notes"),
+	}
+	if seconds := parseStrongDuration(table.value(row, "duration", "duration (sec)", "workout duration")); seconds > 0 {
+		workout.DurationSeconds = &seconds
+	}
+
+	name := table.value(row, "exercise name")
+	order := strings.ToUpper(table.value(row, "set order"))
+	if name == "" || order == "W" {
+		return workout, nil, nil
+	}
+	set := &ImportedSet{
+		ExerciseName: name,
+		WasFailure:   order == "F",
+		Notes:        table.value(row, "notes"),
+	}
+	if _, err := strconv.Atoi(order); err != nil && order != "D" && order != "F" {
+		// Rest timers and other non-set rows
+		return workout, nil, nil
+	}
+
+	// Older exports have unit columns, newer ones the unit in the header or none at all
+	switch {
+	case table.has("weight (kg)"):
+		set.WeightValue, set.WeightUnit = parseTrainingNumber(table.value(row, "weight (kg)")), "kg"
+	case table.has("weight (lbs)"):
+		set.WeightValue, set.WeightUnit = parseTrainingNumber(table.value(row, "weight (lbs)")), "lb"
+	default:
+		set.WeightValue, set.WeightUnit = parseTrainingNumber(table.value(row, "weight")), normalizeImportWeightUnit(table.value(row, "weight unit"), weightUnit)
+	}
+	switch {
+	case table.has("distance (km)"):
+		set.DistanceValue, set.DistanceUnit = parseTrainingNumber(table.value(row, "distance (km)")), "km"
+	case table.has("distance (mi)"):
+		set.DistanceValue, set.DistanceUnit = parseTrainingNumber(table.value(row, "distance (mi)")), "mi"
+	case table.has("distance (m)"):
+		set.DistanceValue, set.DistanceUnit = parseTrainingNumber(table.value(row, "distance (m)")), "m"
+	default:
+		set.DistanceValue, set.DistanceUnit = parseTrainingNumber(table.value(row, "distance")), normalizeImportDistanceUnit(table.value(row, "distance unit"), distanceUnit)
+	}
+	set.Reps = parseTrainingInt(table.value(row, "reps"))
+	set.DurationSeconds = parseTrainingInt(table.value(row, "seconds"))
+	set.RPE = parseTrainingNumber(table.value(row, "rpe"))
+
+	if !set.hasValues() {
+		return workout, nil, nil
+	}
+	return workout, set, nil
+}
+
+// parseHevyRow reads a row of a Hevy export. The set is nil for warm-up sets and rows with
+// nothing logged.
+func parseHevyRow(table trainingCSV, row []string, weightUnit string, distanceUnit string, loc *time.Location) (ImportedWorkout, *ImportedSet, error) {
+	startedAt, err := parseTrainingDate(table.value(row, "start_time"), hevyDateLayouts, loc)
+	if err != nil {
+		return ImportedWorkout{}, nil, err
+	}
+
+	workout := ImportedWorkout{
+		Title:     table.value(row, "title"),
+		StartedAt: startedAt,
+		Notes:     table.value(row, "description"),
+	}
+	if end := table.value(row, "end_time"); end != "" {
+		if endedAt, err := parseTrainingDate(end, hevyDateLayouts, loc); err == nil && endedAt.After(startedAt) {
+			seconds := int(endedAt.Sub(startedAt).Seconds())
+			workout.DurationSeconds = &seconds
+		}
+	}
+
+	name := table.value(row, "exercise_title")
+	setType := strings.ToLower(table.value(row, "set_type"))
+	if name == "" || setType == "warmup" {
+		return workout, nil, nil
+	}
+	set := &ImportedSet{
+		ExerciseName: name,
+		WasFailure:   setType == "failure",
+		Notes:        table.value(row, "exercise_notes"),
+	}
+
+	switch {
+	case table.has("weight_kg"):
+		set.WeightValue, set.WeightUnit = parseTrainingNumber(table.value(row, "weight_kg")), "kg"
+	case table.has("weight_lbs"):
+		set.WeightValue, set.WeightUnit = parseTrainingNumber(table.value(row, "weight_lbs")), "lb"
+	default:
+		set.WeightValue, set.WeightUnit = parseTrainingNumber(table.value(row, "weight")), normalizeImportWeightUnit("", weightUnit)
+	}
+	switch {
+	case table.has("distance_km"):
+		set.DistanceValue, set.DistanceUnit = parseTrainingNumber(table.value(row, "distance_km")), "km"
+	case table.has("distance_miles"):
+		set.DistanceValue, set.DistanceUnit = parseTrainingNumber(table.value(row, "distance_miles")), "mi"
+	case table.has("distance_meters"):
+		set.DistanceValue, set.DistanceUnit = parseTrainingNumber(table.value(row, "distance_meters")), "m"
+	default:
+		set.DistanceValue, set.DistanceUnit = parseTrainingNumber(table.value(row, "distance")), normalizeImportDistanceUnit("", distanceUnit)
+	}
+	set.Reps = parseTrainingInt(table.value(row, "reps"))
+	set.DurationSeconds = parseTrainingInt(table.value(row, "duration_seconds"))
+	set.RPE = parseTrainingNumber(table.value(row, "rpe"))
+
+	if !set.hasValues() {
+		return workout, nil, nil
+	}
+	return workout, set, nil
+}
+
+// hasValues reports whether anything was logged for the set. Exports write zeros for unused
+// fields, so those are cleared as well.
+func (s *ImportedSet) hasValues() bool {
+	if s.Reps != nil && *s.Reps <= 0 {
+		s.Reps = nil
+	}
+	if s.WeightValue != nil && *s.WeightValue < 0 {
+		s.WeightValue = nil
+	}
+	if s.DistanceValue != nil && *s.DistanceValue <= 0 {
+		s.DistanceValue = nil
+	}
+	if s.DurationSeconds != nil && *s.DurationSeconds <= 0 {
+		s.DurationSeconds = nil
+	}
+	if s.RPE != nil && *s.RPE <= 0 {
+		s.RPE = nil
+	}
+	// A weight of zero is kept for logged reps (bodyweight exercises), dropped otherwise
+	if s.WeightValue != nil && *s.WeightValue == 0 && s.Reps == nil {
+		s.WeightValue = nil
+	}
+	return s.Reps != nil || s.WeightValue != nil || s.DistanceValue != nil || s.DurationSeconds != nil
+}
+
+// detectCSVDelimiter picks ';' or ',' from the header line. Exports made with a comma decimal
+// separator use semicolons.
+func detectCSVDelimiter(data []byte) rune {
+	header := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		header = data[:i]
+	}
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+// parseTrainingDate parses an export date in the first matching layout
+func parseTrainingDate(value string, layouts []string, loc *time.Location) (time.Time, error) {
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// parseStrongDuration parses a Strong workout duration: "1h 5m", "45m", "30s" or plain seconds
+func parseStrongDuration(value string) int {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return seconds
+	}
+
+	total := 0
+	for _, match := range strongDurationPattern.FindAllStringSubmatch(strings.ToLower(value), -1) {
+		n, _ := strconv.Atoi(match[1])
+		switch match[2] {
+		case "h":
+			total += n * 3600
+		case "m":
+			total += n * 60
+		case "s":
+			total += n
+		}
+	}
+	return total
+}
+
+// parseTrainingNumber parses a decimal number, accepting a comma decimal separator. Empty or
+// invalid values are nil.
+func parseTrainingNumber(value string) *float64 {
+	if value == "" {
+		return nil
+	}
+	if !strings.Contains(value, ".") {
+		value = strings.Replace(value, ",", ".", 1)
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+		return nil
+	}
+	return &n
+}
+
+// parseTrainingInt parses a whole number, rounding decimals ("8.0"). Empty or invalid values are nil.
+func parseTrainingInt(value string) *int {
+	n := parseTrainingNumber(value)
+	if n == nil {
+		return nil
+	}
+	i := int(math.Round(*n))
+	return &i
+}
+
+// normalizeImportWeightUnit returns "kg" or "lb" for the unit of a row, falling back to the
+// unit of the export
+func normalizeImportWeightUnit(unit string, fallback string) string {
+	switch strings.ToLower(unit) {
+	case "kg", "kgs":
+		return "kg"
+	case "lb", "lbs":
+		return "lb"
+	}
+	if fallback == "lb" || fallback == "lbs" {
+		return "lb"
+	}
+	return "kg"
+}
+
+// normalizeImportDistanceUnit returns "m", "km" or "mi" for the unit of a row, falling back to
+// the unit of the export
+func normalizeImportDistanceUnit(unit string, fallback string) string {
+	switch strings.ToLower(unit) {
+	case "m", "meters", "metres":
+		return "m"
+	case "km", "kms":
+		return "km"
+	case "mi", "miles":
+		return "mi"
+	}
+	if fallback == "mi" {
+		return "mi"
+	}
+	return "km"
+}
@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+const testStrongCSV = "\xef\xbb\xbf" + `Date;Workout Name;Duration;Exercise Name;Set Order;Weight;Reps;Distance;Seconds;Notes;Workout Notes;RPE
+2024-03-02 09:00:00;Legs;1h 5m;Squat (Barbell);W;60;5;0;0;;;
+2024-03-02 09:00:00;Legs;1h 5m;Squat (Barbell);1;100;5;0;0;;Felt strong;8
+2024-03-02 09:00:00;Legs;1h 5m;Squat (Barbell);2;102,5;4;0;0;Last rep slow;Felt strong;9
+2024-03-02 09:00:00;Legs;1h 5m;Squat (Barbell);Rest Timer;0;0;0;90;;;
+2024-03-02 09:00:00;Legs;1h 5m;Running;1;0;0;2,5;900;;Felt strong;
+2024-02-28 18:30:00;Push;45m;Push Up;1;0;20;0;0;;;
+`
+
+const testHevyCSV = `"title","start_time","end_time","description","exercise_title","superset_id","exercise_notes","set_index","set_type","weight_lbs","reps","distance_miles","duration_seconds","rpe"
+"Upper","15 Jan 2024, 08:30","15 Jan 2024, 09:40","","Bench Press (Barbell)",,"",0,"warmup",95,10,,,
+"Upper","15 Jan 2024, 08:30","15 Jan 2024, 09:40","","Bench Press (Barbell)",,"",1,"normal",185,5,,,8.5
+"Upper","15 Jan 2024, 08:30","15 Jan 2024, 09:40","","Bench Press (Barbell)",,"",2,"failure",185,3,,,10
+"Upper","15 Jan 2024, 08:30","15 Jan 2024, 09:40","","Plank",,"",0,"normal",,,,60,
+`
+
+func TestParseTrainingHistoryCSVStrong(t *testing.T) {
+	history, err := ParseTrainingHistoryCSV([]byte(testStrongCSV), "kg", "km", time.UTC)
+	if err != nil {
+		t.Fatalf("ParseTrainingHistoryCSV() error = %v", err)
+	}
+	if history.Format != TrainingFormatStrong {
+		t.Errorf("Format = %q, want %q", history.Format, TrainingFormatStrong)
+	}
+	// The warm-up set and the rest timer are left out
+	if history.Skipped != 2 {
+		t.Errorf("Skipped = %d, want 2", history.Skipped)
+	}
+	if len(history.Workouts) != 2 {
+		t.Fatalf("got %d workouts, want 2", len(history.Workouts))
+	}
+
+	// Oldest first
+	push, legs := history.Workouts[0], history.Workouts[1]
+	if push.Title != "Push" || *push.DurationSeconds != 2700 {
+		t.Errorf("first workout = %q (%v s), want Push (2700 s)", push.Title, *push.DurationSeconds)
+	}
+	if len(push.Sets) != 1 || *push.Sets[0].Reps != 20 || *push.Sets[0].WeightValue != 0 {
+		t.Errorf("push-up set = %+v, want 20 reps at bodyweight", push.Sets)
+	}
+
+	if !legs.StartedAt.Equal(time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("StartedAt = %v", legs.StartedAt)
+	}
+	if *legs.DurationSeconds != 3900 || legs.Notes != "Felt strong" {
+		t.Errorf("legs workout = %v s, notes %q, want 3900 s and the workout notes", *legs.DurationSeconds, legs.Notes)
+	}
+	if len(legs.Sets) != 3 {
+		t.Fatalf("got %d legs sets, want 3", len(legs.Sets))
+	}
+	second := legs.Sets[1]
+	if *second.WeightValue != 102.5 || second.WeightUnit != "kg" || *second.Reps != 4 || *second.RPE != 9 || second.Notes != "Last rep slow" {
+		t.Errorf("second squat set = %+v", second)
+	}
+	run := legs.Sets[2]
+	if *run.DistanceValue != 2.5 || run.DistanceUnit != "km" || *run.DurationSeconds != 900 || run.Reps != nil || run.WeightValue != nil {
+		t.Errorf("run set = %+v, want 2.5 km in 900 s", run)
+	}
+
+	names := history.ExerciseNames()
+	if len(names) != 3 || names[0] != "Push Up" {
+		t.Errorf("ExerciseNames() = %v", names)
+	}
+}
+
+func TestParseTrainingHistoryCSVHevy(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*3600)
+	history, err := ParseTrainingHistoryCSV([]byte(testHevyCSV), "kg", "km", loc)
+	if err != nil {
+		t.Fatalf("ParseTrainingHistoryCSV() error = %v", err)
+	}
+	if history.Format != TrainingFormatHevy {
+		t.Errorf("Format = %q, want %q", history.Format, TrainingFormatHevy)
+	}
+	if len(history.Workouts) != 1 {
+		t.Fatalf("got %d workouts, want 1", len(history.Workouts))
+	}
+
+	workout := history.Workouts[0]
+	if !workout.StartedAt.Equal(time.Date(2024, 1, 15, 6, 30, 0, 0, time.UTC)) {
+		t.Errorf("StartedAt = %v, want 06:30 UTC", workout.StartedAt)
+	}
+	if *workout.DurationSeconds != 4200 {
+		t.Errorf("DurationSeconds = %d, want 4200", *workout.DurationSeconds)
+	}
+	if len(workout.Sets) != 3 {
+		t.Fatalf("got %d sets, want 3 (warm-up left out)", len(workout.Sets))
+	}
+	// The export's columns say the unit, whatever the fallback
+	if set := workout.Sets[0]; *set.WeightValue != 185 || set.WeightUnit != "lb" || *set.RPE != 8.5 {
+		t.Errorf("first set = %+v, want 185 lb at RPE 8.5", set)
+	}
+	if !workout.Sets[1].WasFailure {
+		t.Errorf("failure set not marked as failure")
+	}
+	if plank := workout.Sets[2]; *plank.DurationSeconds != 60 || plank.Reps != nil {
+		t.Errorf("plank set = %+v, want 60 s", plank)
+	}
+}
+
+func TestParseTrainingHistoryCSVErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want error
+	}{
+		{name: "Empty file", data: "", want: ErrEmptyTrainingHistory},
+		{name: "Unknown columns", data: "a,b,c\n1,2,3\n", want: ErrUnsupportedTrainingFormat},
+		{name: "Only warm-ups", data: "Date,Workout Name,Exercise Name,Set Order,Weight,Reps\n2024-01-01 10:00:00,A,Squat,W,60,5\n", want: ErrEmptyTrainingHistory},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseTrainingHistoryCSV([]byte(tt.data), "kg", "km", time.UTC); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseTrainingHistoryCSV([]byte("Date,Workout Name,Exercise Name,Set Order,Weight,Reps\nyesterday,A,Squat,1,60,5\n"), "kg", "km", time.UTC); err == nil {
+		t.Errorf("expected an error for an invalid date")
+	}
+}
+
+func TestParseStrongDuration(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{"1h 5m", 3900},
+		{"45m", 2700},
+		{"52s", 52},
+		{"1h", 3600},
+		{"3600", 3600},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseStrongDuration(tt.value); got != tt.want {
+			t.Errorf("parseStrongDuration(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}